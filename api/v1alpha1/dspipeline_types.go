@@ -17,8 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type DSPASpec struct {
@@ -43,8 +45,337 @@ type DSPASpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:={deploy: false}
 	*MLMD `json:"mlmd"`
+	// Deploy a DaemonSet that pulls pipeline step images onto every node ahead of time, so runs on fresh nodes don't wait on large image pulls.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={deploy: false}
+	*ImagePrepuller `json:"imagePrepuller"`
+	// Deploy the KFP Cache Server, which skips pipeline steps whose results are already cached from an identical
+	// earlier step, instead of running them again.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={deploy: false}
+	*CacheServer `json:"cacheServer"`
+	// Deploy low priority placeholder (balloon) pods holding spare cluster capacity for pipeline steps, so the cluster
+	// autoscaler provisions nodes ahead of bursts of runs, e.g. scheduled retraining, rather than once their steps are pending.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:={deploy: false}
+	*Headroom `json:"headroom"`
+	// Outbound webhooks notified when pipeline runs of this DSPA reach a terminal state, e.g. to gate CI/CD on pipeline success.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	RunStatusWebhooks []RunStatusWebhook `json:"runStatusWebhooks,omitempty"`
+	// Report the status of pipeline runs triggered from CI as commit statuses on GitHub or GitLab.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	CommitStatusReporters []CommitStatusReporter `json:"commitStatusReporters,omitempty"`
+	// Proxy the run status webhooks, step status webhooks and commit status reporters send their requests through, for
+	// clusters without direct internet egress. Default: requests go direct, or through the proxy of the operator's
+	// HTTPS_PROXY environment variable
+	// +kubebuilder:validation:Optional
+	EgressProxy *EgressProxy `json:"egressProxy,omitempty"`
+	// Handle every pipeline step once it finishes, including failed ones: capture its logs, count it in the step
+	// metrics and notify webhooks of failures, so failures are observable without each pipeline adding an exit handler.
+	// +kubebuilder:validation:Optional
+	StepExitHandler *StepExitHandler `json:"stepExitHandler,omitempty"`
+	// Register the DSP API with an API gateway fronting the cluster's APIs, through the custom resources of its
+	// operator or ingress controller, so the gateway routes and authenticates requests to the API Server.
+	// +kubebuilder:validation:Optional
+	APIGateway *APIGateway `json:"apiGateway,omitempty"`
+	// Create OpenShift web console links to the UI and API Server Routes of this DSPA, listed in the dashboard of its
+	// project, so users find their pipeline endpoints from the console.
+	// +kubebuilder:validation:Optional
+	ConsoleLinks *ConsoleLinks `json:"consoleLinks,omitempty"`
+	// Pin all DS Pipelines components to nodes of this CPU architecture. Images that are not overridden in the CR
+	// are resolved from the operator's per-architecture image config. If omitted, components are only restricted to the
+	// architectures listed in the operator config, if any. Allowed Values: "amd64", "arm64"
+	// +kubebuilder:validation:Enum=amd64;arm64
+	// +kubebuilder:validation:Optional
+	Architecture string `json:"architecture,omitempty"`
+	// Run all DS Pipelines containers with a read-only root filesystem. The operator mounts emptyDir volumes on the
+	// paths each component needs to write to, e.g. /tmp. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem"`
+	// Deploy an ephemeral DSPA for local development, e.g. on kind: single replica components, MariaDB and Minio
+	// storing their data in tmpfs emptyDirs instead of PersistentVolumeClaims, relaxed probes, and neither Routes nor
+	// oauth-proxies, which require OpenShift. Data is lost whenever the MariaDB or Minio pod restarts. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	DevMode bool `json:"devMode"`
+	// Take ownership of existing resources with the names of the DSPA's resources that it doesn't own, e.g. from a
+	// manual KFP install, once they're found compatible: not controlled by another object, and without immutable fields
+	// the DSPA would change. Without it, such resources fail the reconcile instead of being overwritten. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	AdoptExistingResources bool `json:"adoptExistingResources"`
+	// Whether component changes brought by an operator upgrade are applied right away, or wait for approval. With
+	// Manual, the pending image changes are listed in the UpgradePending condition, and are applied once the DSPA is
+	// annotated with datasciencepipelinesapplications.opendatahub.io/approved-upgrade set to the new operator version.
+	// Default: "Automatic" - Allowed Values: "Automatic", "Manual"
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	// +kubebuilder:default:=Automatic
+	// +kubebuilder:validation:Optional
+	UpgradeApproval string `json:"upgradeApproval,omitempty"`
+	// Jobs run before and after the components of the DSPA are upgraded, i.e. when an operator upgrade or a change of
+	// the DSPA changes the image of a deployed component, e.g. to notify a change system or warm caches. Default: none
+	// +kubebuilder:validation:Optional
+	LifecycleHooks *LifecycleHooks `json:"lifecycleHooks,omitempty"`
+	// How often the DSPA is resynced and its dependencies health checked when nothing changes. Default: the operator
+	// config DSPO.RunReportMonitor.Interval and DSPO.RunMaintenance.Interval while runs are monitored, and the health
+	// checks run on every reconcile.
+	// +kubebuilder:validation:Optional
+	ReconcileIntervals *ReconcileIntervals `json:"reconcileIntervals,omitempty"`
+	// Delete finished runs and the pods of their steps once they're no longer needed, separately for succeeded and
+	// failed runs, so the namespace neither accumulates Tekton objects nor loses the evidence of recent failures.
+	// Default: runs are deleted by the Persistence Agent after its ttlSecondsAfterWorkflowFinish, along with their pods
+	// +kubebuilder:validation:Optional
+	GarbageCollection *GarbageCollection `json:"garbageCollection,omitempty"`
+	// IP families of the DSP Services and component listeners, for IPv6 single stack and dual-stack clusters.
+	// Default: the Services use the cluster's default IP family, and the components listen on IPv4
+	// +kubebuilder:validation:Optional
+	IPFamilies *IPFamilies `json:"ipFamilies,omitempty"`
+	// Give the Routes of this DSPA, and the Ingress of its API gateway, hostnames in a DNS domain and the annotations
+	// external-dns publishes their records from. Default: Routes get the hostname generated by the router
+	// +kubebuilder:validation:Optional
+	ExternalDNS *ExternalDNS `json:"externalDNS,omitempty"`
+	// Enable or disable experimental DSPO behaviors for this DSPA, by gate name, e.g. {"QueueMetrics": false}. Unknown
+	// gates are rejected. Default: every gate keeps its default, the enabled ones are listed in status.activeFeatureGates
+	// +kubebuilder:validation:XValidation:rule="self.all(gate, gate in ['QueueMetrics'])",message="unknown feature gate, the known gates are: QueueMetrics"
+	// +kubebuilder:validation:Optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// ConfigMap of kustomize patches applied to the manifests rendered for this DSPA before they are applied, e.g. to
+	// set a field DSPO doesn't expose. They are reapplied on every reconcile, so they survive operator upgrades, but
+	// they target the manifests of the running operator version. Default: no patches
+	// +kubebuilder:validation:Optional
+	ManifestPatches *ManifestPatches `json:"manifestPatches,omitempty"`
+	// Limits on the pipeline runs of this DSPA, enforced when their pods are created, so a team's runs can't starve
+	// the cluster it shares. Default: no limits
+	// +kubebuilder:validation:Optional
+	Guardrails *Guardrails `json:"guardrails,omitempty"`
+	// Pause the pipeline runs of this DSPA during blackout windows, e.g. cluster maintenance nights: the Scheduled
+	// Workflow controller is scaled down, so recurring runs aren't triggered, and runs submitted meanwhile are run,
+	// queued until the window ends, or rejected. Default: runs are never paused
+	// +kubebuilder:validation:Optional
+	SchedulePolicy *SchedulePolicy `json:"schedulePolicy,omitempty"`
+	// Label the pods of the pipeline runs of this DSPA, so tools like Kubecost attribute their cost, e.g. to a project
+	// or a cost center. Default: pipeline pods only have the labels of Tekton and KFP
+	// +kubebuilder:validation:Optional
+	CostAttribution *CostAttribution `json:"costAttribution,omitempty"`
+	// Periodically back up the MariaDB database and the Minio bucket deployed by this DSPA to external object storage,
+	// so losing their PersistentVolumeClaims doesn't lose the run history. Backups are restored by annotating the
+	// DSPA. Default: no backups
+	// +kubebuilder:validation:Optional
+	Backup *Backup `json:"backup,omitempty"`
+}
+
+type CostAttribution struct {
+	// Labels set on the PipelineRuns of the DSPA when they're created, which Tekton propagates to their TaskRuns and
+	// pods, keyed by label. Values may refer to the run with $(namespace), $(dspa), $(submitter), the user or
+	// ServiceAccount that created the PipelineRun, $(run.label.<key>) and $(run.annotation.<key>), e.g.
+	// $(run.label.pipeline/runid). Values are made valid label values, invalid characters replaced with "_".
+	// +kubebuilder:validation:MinProperties=1
+	Labels map[string]string `json:"labels"`
+}
+
+type Backup struct {
+	// Cron schedule of the backups, in the timezone of the cluster's controller manager. Default: daily at 02:00
+	// +kubebuilder:default:="0 2 * * *"
+	// +kubebuilder:validation:Optional
+	Schedule string `json:"schedule"`
+	// Stop scheduling backups, without deleting the ones already taken. Default: false
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Object storage the backups are copied to, under <prefix>/<namespace>/<dspa-name>/<backup-id>/. It must not be
+	// the Minio the DSPA deploys, as it's lost along with its PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	Destination *BackupDestination `json:"destination"`
+	// Image providing the mysqldump and mysql clients. Default: the MariaDB image of the DSPA
+	// +kubebuilder:validation:Optional
+	DatabaseClientImage string `json:"databaseClientImage,omitempty"`
+	// Image providing the aws CLI copying the Minio bucket and the backups. Default: the artifact image of the API Server
+	// +kubebuilder:validation:Optional
+	StorageClientImage string `json:"storageClientImage,omitempty"`
+	// Size limit of the emptyDir the backup and restore Jobs stage the database dump and the bucket in, past which
+	// their pods are evicted rather than fill the disk of the node. Default: the sum of the pvcSizes of the MariaDB and
+	// Minio backed up
+	// +kubebuilder:validation:Optional
+	ScratchSizeLimit *resource.Quantity `json:"scratchSizeLimit,omitempty"`
+}
+
+type BackupDestination struct {
+	*ExternalStorage `json:",inline"`
+	// Path the backups are copied under in the bucket. Default: dspa-backups
+	// +kubebuilder:default:=dspa-backups
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9!_.*'()-]+(/[A-Za-z0-9!_.*'()-]+)*$`
+	Prefix string `json:"prefix"`
+}
+
+type SchedulePolicy struct {
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	BlackoutWindows []BlackoutWindow `json:"blackoutWindows"`
+	// What happens to the runs submitted during a blackout window. Allow runs them right away, Queue holds them
+	// pending until the window ends, Reject rejects their submission. Queue and Reject require the operator to serve
+	// its admission webhooks. Default: "Allow" - Allowed Values: "Allow", "Queue", "Reject"
+	// +kubebuilder:validation:Enum=Allow;Queue;Reject
+	// +kubebuilder:default:=Allow
+	// +kubebuilder:validation:Optional
+	SubmittedRuns string `json:"submittedRuns,omitempty"`
+}
+
+// BlackoutWindow is a recurring window of time, e.g. from 22:00 to 06:00 on Saturdays.
+type BlackoutWindow struct {
+	// Days of the week the window starts on. Default: every day
+	// +kubebuilder:validation:items:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+	// +kubebuilder:validation:Optional
+	Days []string `json:"days,omitempty"`
+	// Time of the day the window starts at, e.g. "22:00"
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +kubebuilder:validation:Required
+	Start string `json:"start"`
+	// Time of the day the window ends at, the next day if it's not after the start, e.g. "06:00"
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +kubebuilder:validation:Required
+	End string `json:"end"`
+	// IANA time zone of the start and end, e.g. "Europe/Berlin". Default: "UTC"
+	// +kubebuilder:default:=UTC
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+type Guardrails struct {
+	// Total resources requested by the running pipeline step pods of the DSPA namespace. Step pods that would
+	// request more are rejected by an admission webhook of the operator, and Tekton retries creating them until
+	// running steps finish or the step times out. Default: no limit
+	// +kubebuilder:validation:Optional
+	MaxPipelineResources *PipelineResources `json:"maxPipelineResources,omitempty"`
+}
+
+type PipelineResources struct {
+	// Total CPU requests, e.g. "16". Default: no limit
+	// +kubebuilder:validation:Optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+	// Total memory requests, e.g. "64Gi". Default: no limit
+	// +kubebuilder:validation:Optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+	// Total GPU requests, of the gpuResourceName extended resource. Default: no limit
+	// +kubebuilder:validation:Optional
+	GPU *resource.Quantity `json:"gpu,omitempty"`
+	// Extended resource name of the GPUs. Default: "nvidia.com/gpu"
+	// +kubebuilder:default:=nvidia.com/gpu
+	// +kubebuilder:validation:Optional
+	GPUResourceName string `json:"gpuResourceName,omitempty"`
+}
+
+// ManifestPatches references the kustomization holding the patches of the DSPA manifests. Only its patches field is
+// supported, e.g. "patches: [{target: {kind: Deployment, name: ds-pipeline-sample}, patch: ...}]", with strategic
+// merge and JSON 6902 patches.
+type ManifestPatches struct {
+	// +kubebuilder:validation:Required
+	ConfigMapName string `json:"configMapName"`
+	// Default: "kustomization.yaml"
+	// +kubebuilder:default:=kustomization.yaml
+	// +kubebuilder:validation:Optional
+	ConfigMapKey string `json:"configMapKey,omitempty"`
+}
+
+// ExternalDNS generates the hostnames of the DSPA endpoints, and the external-dns annotations of their Routes and
+// Ingress.
+type ExternalDNS struct {
+	// DNS domain the hostnames are generated in, e.g. "pipelines.example.com". It must be a zone external-dns manages.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	Domain string `json:"domain"`
+	// Go template of the hostname label prepended to the domain, with the fields .Component ("api", "grpc", "ui" or
+	// "minio"), .Name and .Namespace of the DSPA. Hosts set explicitly, e.g. apiServer.grpc.host, are kept.
+	// Default: "{{.Component}}-{{.Name}}-{{.Namespace}}"
+	// +kubebuilder:validation:Optional
+	HostnameTemplate string `json:"hostnameTemplate,omitempty"`
+	// TTL of the DNS records, in seconds. Default: the TTL of the external-dns provider
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	TTL int32 `json:"ttl,omitempty"`
+	// Annotations added to every Route and Ingress, e.g. external-dns.alpha.kubernetes.io/target or
+	// external-dns.alpha.kubernetes.io/set-identifier. Values are Go templates with the fields of hostnameTemplate and
+	// .Hostname.
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.policy) || self.policy == 'SingleStack' || !has(self.families) || size(self.families) == 2",message="families must list both IPv4 and IPv6 for dual-stack policies"
+type IPFamilies struct {
+	// IP family policy of the DSP Services. Allowed Values: "SingleStack", "PreferDualStack", "RequireDualStack"
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	// +kubebuilder:validation:Optional
+	Policy string `json:"policy,omitempty"`
+	// IP families of the DSP Services, the first one being the primary family. Allowed Values: "IPv4", "IPv6"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=2
+	// +kubebuilder:validation:XValidation:rule="self.all(f, self.exists_one(g, g == f))",message="families must be unique"
+	// +listType=atomic
+	Families []IPFamily `json:"families,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=IPv4;IPv6
+type IPFamily string
+
+type ReconcileIntervals struct {
+	// Time after which the DSPA is reconciled again when none of its resources change, e.g. "10m". Must be between
+	// 30s and 24h. Runs monitored at a shorter interval of the operator config are still monitored at that interval.
+	// Default: the operator config DSPO.RunReportMonitor.Interval and DSPO.RunMaintenance.Interval while runs are
+	// monitored, otherwise the DSPA is only reconciled on change
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('30s') && duration(self) <= duration('24h')",message="resyncPeriod must be between 30s and 24h"
+	// +kubebuilder:validation:Optional
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+	// Minimum time between the Database and Object Storage health checks, e.g. "5m". Reconciles in between report the
+	// last results, unless the DSPA spec changed or a verification was requested. Must be between 10s and 24h.
+	// Default: the health checks run on every reconcile
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('10s') && duration(self) <= duration('24h')",message="healthCheckPeriod must be between 10s and 24h"
+	// +kubebuilder:validation:Optional
+	HealthCheckPeriod *metav1.Duration `json:"healthCheckPeriod,omitempty"`
 }
 
+// GarbageCollection configures when finished runs and the pods of their steps are deleted.
+type GarbageCollection struct {
+	// Delete succeeded runs this long after they finish, e.g. "1h". Default: the Persistence Agent's ttlSecondsAfterWorkflowFinish
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="ttlAfterSucceeded must be greater than 0s"
+	// +kubebuilder:validation:Optional
+	TTLAfterSucceeded *metav1.Duration `json:"ttlAfterSucceeded,omitempty"`
+	// Delete failed runs this long after they finish, e.g. "168h". Default: the Persistence Agent's ttlSecondsAfterWorkflowFinish
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="ttlAfterFailed must be greater than 0s"
+	// +kubebuilder:validation:Optional
+	TTLAfterFailed *metav1.Duration `json:"ttlAfterFailed,omitempty"`
+	// Delete the pods of steps before their run is deleted: as soon as the step finishes (OnPodCompletion) or
+	// succeeds (OnPodSuccess), or once the whole run finishes (OnWorkflowCompletion) or succeeds (OnWorkflowSuccess).
+	// Default: pods are kept until their run is deleted
+	// +kubebuilder:validation:Enum=OnPodCompletion;OnPodSuccess;OnWorkflowCompletion;OnWorkflowSuccess
+	// +kubebuilder:validation:Optional
+	PodGCStrategy string `json:"podGCStrategy,omitempty"`
+	// Number of most recently finished runs whose pods are kept regardless of the PodGCStrategy. Default: 0
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	RetainedRunPods int `json:"retainedRunPods,omitempty"`
+}
+
+// RunRetryPolicy bounds the retries of failed runs.
+type RunRetryPolicy struct {
+	// Number of times a failed run is retried before it is left failed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	MaxRetries int `json:"maxRetries"`
+	// Time waited after a run fails before retrying it, doubled after every retry of the run, e.g. "1m". Default: 1m
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="backoff must be greater than 0s"
+	// +kubebuilder:validation:Optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+	// Upper bound of the time waited before a retry. Default: 1h
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="maxBackoff must be greater than 0s"
+	// +kubebuilder:validation:Optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.preStopDrainSeconds) || !has(self.terminationGracePeriodSeconds) || self.preStopDrainSeconds < self.terminationGracePeriodSeconds",message="preStopDrainSeconds must be lower than terminationGracePeriodSeconds"
 type APIServer struct {
 	// Enable DS Pipelines Operator management of DSP API Server. Setting Deploy to false disables operator reconciliation. Default: true
 	// +kubebuilder:default:=true
@@ -92,6 +423,9 @@ type APIServer struct {
 	// +kubebuilder:default:=true
 	// +kubebuilder:validation:Optional
 	EnableRoute bool `json:"enableOauth"`
+	// TLS termination and certificate of the API Server Route. Default: Reencrypt, serving the router's certificate
+	// +kubebuilder:validation:Optional
+	RouteTLS *RouteTLS `json:"routeTLS,omitempty"`
 	// Include sample pipelines with the deployment of this DSP API Server. Default: true
 	// +kubebuilder:default:=true
 	// +kubebuilder:validation:Optional
@@ -102,6 +436,45 @@ type APIServer struct {
 	AutoUpdatePipelineDefaultVersion bool `json:"autoUpdatePipelineDefaultVersion"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Number of API Server replicas. Default: the replicas of the Deployment are left to the cluster, i.e. 1 unless
+	// scaled by a user or an autoscaler
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// Seconds the API Server pod is given to finish in-flight requests, e.g. pipeline uploads, before it is killed on
+	// rolling updates. Default: 60
+	// +kubebuilder:default:=60
+	// +kubebuilder:validation:Minimum=1
+	TerminationGracePeriodSeconds int `json:"terminationGracePeriodSeconds,omitempty"`
+	// Seconds the API Server keeps serving after termination starts, so it is removed from the Service endpoints
+	// before it stops accepting requests. Must be lower than TerminationGracePeriodSeconds. Default: 15
+	// +kubebuilder:default:=15
+	// +kubebuilder:validation:Minimum=1
+	PreStopDrainSeconds int `json:"preStopDrainSeconds,omitempty"`
+	// Terminate runs still running after this long, e.g. "72h", with the TerminateStatus. Only applies to runs whose
+	// pipeline doesn't set a timeout, explicit pipeline timeouts take precedence. Default: runs without a timeout are
+	// never terminated
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="defaultRunTimeout must be greater than 0s"
+	// +kubebuilder:validation:Optional
+	DefaultRunTimeout *metav1.Duration `json:"defaultRunTimeout,omitempty"`
+	// Cancel steps still running after this long, e.g. "24h", failing their run. Only applies to steps without a
+	// timeout, i.e. of runs whose pipeline sets neither a timeout for the step nor for the run. Default: steps without
+	// a timeout are never cancelled
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="defaultStepTimeout must be greater than 0s"
+	// +kubebuilder:validation:Optional
+	DefaultStepTimeout *metav1.Duration `json:"defaultStepTimeout,omitempty"`
+	// Times the failed steps of runs are retried, so flaky infrastructure doesn't fail whole runs. Only applies to
+	// steps whose pipeline doesn't set retries, and to runs whose PipelineRun embeds its pipeline, as the API Server's
+	// do. Tekton retries a step right after it fails, it has no backoff between attempts. Requires the operator to
+	// serve its admission webhooks. Default: steps are only retried as set in the pipeline
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:validation:Optional
+	DefaultStepRetries int `json:"defaultStepRetries,omitempty"`
+	// Retry failed runs through the API Server, as the Retry action of the UI does, backing off between retries. Runs
+	// cancelled by a user or terminated for running past a timeout are not retried. Default: failed runs are not retried
+	// +kubebuilder:validation:Optional
+	RunRetryPolicy *RunRetryPolicy `json:"runRetryPolicy,omitempty"`
 
 	// If the Object store/DB is behind a TLS secured connection that is
 	// unrecognized by the host OpenShift/K8s cluster, then you can
@@ -109,6 +482,160 @@ type APIServer struct {
 	// server pod to trust this connection. CA Bundle should be provided
 	// as values within configmaps, mapped to keys.
 	CABundle *CABundle `json:"cABundle,omitempty"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: RollingUpdate with maxSurge 1 and maxUnavailable 0
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+	// How the replicas of this component are spread over the cluster, when it runs more than one. Default: spread
+	// across zones and nodes where possible
+	// +kubebuilder:validation:Optional
+	Placement *Placement `json:"placement,omitempty"`
+	// Service exposing this component, e.g. as a NodePort or LoadBalancer on clusters without Routes or an Ingress controller. Default: ClusterIP
+	// +kubebuilder:validation:Optional
+	Service *ComponentService `json:"service,omitempty"`
+	// Expose the gRPC API of the API Server outside the cluster, through an Envoy proxy authenticating requests and a
+	// Route, for clients using the KFP gRPC client. Default: the gRPC API is only reachable within the cluster
+	// +kubebuilder:validation:Optional
+	GRPC *APIServerGRPC `json:"grpc,omitempty"`
+	// Node selector, tolerations and affinity of this component's pods, e.g. to pin them to dedicated infrastructure
+	// nodes. Default: scheduled on any Linux node of the supported architectures
+	// +kubebuilder:validation:Optional
+	Scheduling *Scheduling `json:"scheduling,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!self.deploy || has(self.jwt)",message="the gRPC proxy requires jwt authentication"
+// +kubebuilder:validation:XValidation:rule="self.termination != 'Passthrough' || (has(self.tls) && has(self.host))",message="Passthrough termination requires a tls certificate and a host"
+type APIServerGRPC struct {
+	// Enable DS Pipelines Operator management of the gRPC proxy and its Route. Setting Deploy to false removes them. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	Deploy bool `json:"deploy"`
+	// Specify a custom image for the gRPC proxy. Default: the MLMD Envoy image
+	Image     string                `json:"image,omitempty"`
+	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Host of the Route, e.g. "kfp-grpc.apps.example.com". Default: generated by the router
+	// +kubebuilder:validation:Optional
+	Host string `json:"host,omitempty"`
+	// TLS termination of the Route. Reencrypt serves the router's certificate, and requires HTTP/2 to be enabled on
+	// the ingress controller, as gRPC runs over HTTP/2. Passthrough serves the proxy's certificate to the clients.
+	// Default: Reencrypt
+	// +kubebuilder:validation:Enum=Reencrypt;Passthrough
+	// +kubebuilder:default:=Reencrypt
+	Termination string `json:"termination,omitempty"`
+	// Certificate served by the proxy, e.g. issued by cert-manager for the Host with Passthrough termination. Default:
+	// a certificate issued by the OpenShift service CA, trusted by the router
+	// +kubebuilder:validation:Optional
+	TLS *APIServerGRPCTLS `json:"tls,omitempty"`
+	// Only proxy requests carrying a JWT of this issuer in their Authorization header, e.g. obtained by the clients from
+	// an OpenID Connect provider with the client credentials grant. Requests without a valid token are rejected with 401.
+	// Its audiences are required, so tokens the issuer grants to other applications aren't accepted.
+	// +kubebuilder:validation:XValidation:rule="has(self.audiences) && size(self.audiences) > 0",message="apiServer grpc jwt requires audiences, tokens of the issuer for other applications would otherwise be accepted"
+	// +kubebuilder:validation:Optional
+	JWT *EnvoyJWT `json:"jwt,omitempty"`
+}
+
+// RouteTLS configures how the Route of a component behind an oauth-proxy terminates TLS. Edge termination isn't
+// offered, as the oauth-proxy only serves https and its http port isn't authenticated.
+// +kubebuilder:validation:XValidation:rule="self.termination != 'Passthrough' || has(self.secretName)",message="Passthrough termination requires a secretName, the clients verify the oauth-proxy's certificate"
+type RouteTLS struct {
+	// TLS termination of the Route. Reencrypt serves the certificate of secretName, or the router's default
+	// certificate, and re-encrypts to the oauth-proxy. Passthrough has the oauth-proxy serve the certificate of
+	// secretName to clients. Allowed Values: "Reencrypt", "Passthrough"
+	// +kubebuilder:default:=Reencrypt
+	// +kubebuilder:validation:Enum=Reencrypt;Passthrough
+	// +kubebuilder:validation:Optional
+	Termination string `json:"termination,omitempty"`
+	// Secret of type kubernetes.io/tls holding the tls.crt and tls.key served for the Route host, e.g. issued by
+	// cert-manager, and optionally the ca.crt of their chain. Default: the router's default certificate
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+type APIServerGRPCTLS struct {
+	// Secret of type kubernetes.io/tls holding the tls.crt and tls.key served by the proxy.
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+}
+
+// +kubebuilder:validation:XValidation:rule="self.provider != 'Kong' || !has(self.auth) || self.auth.type != 'OIDC'",message="OIDC authentication is only supported with 3scale, configure a Kong OIDC plugin in kong.plugins instead"
+// +kubebuilder:validation:XValidation:rule="self.provider != 'ThreeScale' || has(self.threeScale)",message="the ThreeScale provider requires threeScale.providerAccountSecretName"
+type APIGateway struct {
+	// Gateway the DSP API is registered with. Kong registers it through an Ingress of the Kong Ingress Controller,
+	// ThreeScale as a 3scale Backend and Product of the 3scale operator. Allowed Values: "Kong", "ThreeScale"
+	// +kubebuilder:validation:Enum=Kong;ThreeScale
+	// +kubebuilder:validation:Required
+	Provider string `json:"provider"`
+	// Host the gateway serves the DSP API on, e.g. "pipelines.api.example.com".
+	// +kubebuilder:validation:Required
+	Host string `json:"host"`
+	// Namespaces the gateway's proxies run in, e.g. the namespace of the Kong proxy or of the self-managed APIcast.
+	// They are admitted to the oauth-proxy of the API Server by its NetworkPolicy.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Namespaces []string `json:"namespaces"`
+	// Authentication the gateway enforces on requests to the DSP API. The gateway reaches the API Server through its
+	// oauth-proxy, which also authorizes the OpenShift token of each request. Default: requests are authenticated with an API key
+	// +kubebuilder:validation:Optional
+	Auth *APIGatewayAuth `json:"auth,omitempty"`
+	// +kubebuilder:validation:Optional
+	Kong *KongGateway `json:"kong,omitempty"`
+	// +kubebuilder:validation:Optional
+	ThreeScale *ThreeScaleGateway `json:"threeScale,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="self.type != 'OIDC' || has(self.issuerEndpointSecretName)",message="OIDC authentication requires issuerEndpointSecretName"
+type APIGatewayAuth struct {
+	// APIKey requires an API key in the apikey header, issued to the gateway's consumers or applications. OIDC
+	// requires a bearer token of the OpenID Connect provider, e.g. obtained with the client credentials grant.
+	// Default: "APIKey" - Allowed Values: "APIKey", "OIDC"
+	// +kubebuilder:validation:Enum=APIKey;OIDC
+	// +kubebuilder:default:=APIKey
+	Type string `json:"type,omitempty"`
+	// Secret holding the issuerEndpoint of the OpenID Connect provider 3scale registers clients with, in the form
+	// "https://<client-id>:<client-secret>@<host>/auth/realms/<realm>".
+	// +kubebuilder:validation:Optional
+	IssuerEndpointSecretName string `json:"issuerEndpointSecretName,omitempty"`
+}
+
+type KongGateway struct {
+	// Ingress class of the Kong Ingress Controller. Default: kong
+	// +kubebuilder:default:=kong
+	// +kubebuilder:validation:Optional
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// Names of KongPlugins of the DSPA namespace applied to the DSP API in addition to the authentication plugin,
+	// e.g. rate limiting.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	Plugins []string `json:"plugins,omitempty"`
+}
+
+type ThreeScaleGateway struct {
+	// Secret holding the adminURL and token of the 3scale tenant the DSP API is registered with.
+	// +kubebuilder:validation:Required
+	ProviderAccountSecretName string `json:"providerAccountSecretName"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.usernameSecret) == has(self.passwordSecret)",message="usernameSecret and passwordSecret must be set together"
+type EgressProxy struct {
+	// URL of the proxy, e.g. "http://proxy.example.com:3128". Requests to https URLs are tunneled through it with
+	// CONNECT. Restricted like the run status webhook URLs, hosts on private networks must be allowed in the operator
+	// config.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://[^/@]+/?$`
+	URL string `json:"url"`
+	// Secret keys of the username and password the proxy authenticates requests with, sent with basic authentication.
+	// Default: the proxy doesn't require authentication
+	// +kubebuilder:validation:Optional
+	UsernameSecret *SecretKeyValue `json:"usernameSecret,omitempty"`
+	// +kubebuilder:validation:Optional
+	PasswordSecret *SecretKeyValue `json:"passwordSecret,omitempty"`
+	// PEM CA bundle trusted in addition to the system roots, for an https proxy URL and for the upstream certificates
+	// of TLS-inspecting proxies. Default: the system roots
+	// +kubebuilder:validation:Optional
+	CABundle *CABundle `json:"cABundle,omitempty"`
 }
 
 type CABundle struct {
@@ -135,8 +662,31 @@ type PersistenceAgent struct {
 	// Number of worker for Persistence Agent sync job. Default: 2
 	// +kubebuilder:default:=2
 	NumWorkers int `json:"numWorkers,omitempty"`
+	// Number of seconds a finished pipeline run's Workflow is kept before the Persistence Agent garbage collects it, 0
+	// deletes it as soon as its final state is reported. Default: 86400
+	// +kubebuilder:default:=86400
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterWorkflowFinish *int64 `json:"ttlSecondsAfterWorkflowFinish,omitempty"`
+	// Maximum number of run status reports per second sent to the API Server, and thereby written to the Database. Default: 5
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	ClientQPS int `json:"clientQPS,omitempty"`
+	// Maximum burst of run status reports sent to the API Server above ClientQPS. Default: 10
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	ClientBurst int `json:"clientBurst,omitempty"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: RollingUpdate
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+	// Node selector, tolerations and affinity of this component's pods, e.g. to pin them to dedicated infrastructure
+	// nodes. Default: scheduled on any Linux node of the supported architectures
+	// +kubebuilder:validation:Optional
+	Scheduling *Scheduling `json:"scheduling,omitempty"`
 }
 
 type ScheduledWorkflow struct {
@@ -151,6 +701,16 @@ type ScheduledWorkflow struct {
 	CronScheduleTimezone string `json:"cronScheduleTimezone,omitempty"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: RollingUpdate
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+	// Node selector, tolerations and affinity of this component's pods, e.g. to pin them to dedicated infrastructure
+	// nodes. Default: scheduled on any Linux node of the supported architectures
+	// +kubebuilder:validation:Optional
+	Scheduling *Scheduling `json:"scheduling,omitempty"`
 }
 
 type MlPipelineUI struct {
@@ -164,8 +724,21 @@ type MlPipelineUI struct {
 	// Specify a custom image for KFP UI pod.
 	// +kubebuilder:validation:Required
 	Image string `json:"image"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: RollingUpdate
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+	// TLS termination and certificate of the UI Route. Default: Reencrypt, serving the router's certificate
+	// +kubebuilder:validation:Optional
+	RouteTLS *RouteTLS `json:"routeTLS,omitempty"`
+	// Service exposing this component, e.g. as a NodePort or LoadBalancer on clusters without Routes or an Ingress controller. Default: ClusterIP
+	// +kubebuilder:validation:Optional
+	Service *ComponentService `json:"service,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!(has(self.mariaDB) && has(self.externalDB))",message="mariaDB and externalDB are mutually exclusive"
 type Database struct {
 	*MariaDB    `json:"mariaDB,omitempty"`
 	*ExternalDB `json:"externalDB,omitempty"`
@@ -196,17 +769,47 @@ type MariaDB struct {
 	PVCSize resource.Quantity `json:"pvcSize,omitempty"`
 	// Specify custom Pod resource requirements for this component.
 	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: Recreate, as its ReadWriteOnce PVC can only be mounted by one pod at a time
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+	// Service exposing this component, e.g. as a NodePort or LoadBalancer on clusters without Routes or an Ingress controller. Default: ClusterIP
+	// +kubebuilder:validation:Optional
+	Service *ComponentService `json:"service,omitempty"`
+	// Node selector, tolerations and affinity of this component's pods, e.g. to pin them to dedicated infrastructure
+	// nodes. Default: scheduled on any Linux node of the supported architectures
+	// +kubebuilder:validation:Optional
+	Scheduling *Scheduling `json:"scheduling,omitempty"`
+	// Prometheus metrics of this MariaDB, e.g. its connections, slow queries and buffer pool usage
+	// +kubebuilder:validation:Optional
+	Metrics *MariaDBMetrics `json:"metrics,omitempty"`
+}
+
+type MariaDBMetrics struct {
+	// Deploy a mysqld-exporter sidecar in the MariaDB pod, and a ServiceMonitor scraping it. Default: false
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled"`
+	// Specify a custom image for the mysqld-exporter sidecar.
+	Image string `json:"image,omitempty"`
+	// Specify custom Pod resource requirements for the mysqld-exporter sidecar.
+	Resources *ResourceRequirements `json:"resources,omitempty"`
 }
 
 type ExternalDB struct {
 	// +kubebuilder:validation:Required
-	Host           string          `json:"host"`
+	Host string `json:"host"`
+	// +kubebuilder:validation:MaxLength=5
+	// +kubebuilder:validation:XValidation:rule="self == '' || (self.matches('^[0-9]+$') && int(self) >= 1 && int(self) <= 65535)",message="port must be between 1 and 65535"
 	Port           string          `json:"port"`
 	Username       string          `json:"username"`
 	DBName         string          `json:"pipelineDBName"`
 	PasswordSecret *SecretKeyValue `json:"passwordSecret"`
 }
 
+// +kubebuilder:validation:XValidation:rule="has(self.minio) || has(self.externalStorage)",message="either minio or externalStorage must be specified"
+// +kubebuilder:validation:XValidation:rule="!(has(self.minio) && has(self.externalStorage))",message="minio and externalStorage are mutually exclusive"
 type ObjectStorage struct {
 	// Enable DS Pipelines Operator management of Minio. Setting Deploy to false disables operator reconciliation.
 	*Minio           `json:"minio,omitempty"`
@@ -219,6 +822,26 @@ type ObjectStorage struct {
 	// +kubebuilder:default:=false
 	// +kubebuilder:validation:Optional
 	EnableExternalRoute bool `json:"enableExternalRoute"`
+	// Delete the artifacts and step logs of runs from the bucket once they're older than maxAge, and the oldest ones
+	// while the artifacts exceed maxSize, so long-running DSPAs don't exhaust their object storage.
+	// Default: artifacts are kept until deleted from the bucket
+	// +kubebuilder:validation:Optional
+	ArtifactRetention *ArtifactRetention `json:"artifactRetention,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.maxAge) || has(self.maxSize)",message="artifactRetention requires maxAge, maxSize or both"
+type ArtifactRetention struct {
+	// Delete artifacts this long after they were uploaded, e.g. "720h".
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1h')",message="maxAge must be at least 1h"
+	// +kubebuilder:validation:Optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// Delete the oldest artifacts while all of them together are larger than this, e.g. "500Gi".
+	// +kubebuilder:validation:Optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Cron schedule of the cleanups, in the timezone of the cluster's controller manager. Default: daily at 03:00
+	// +kubebuilder:default:="0 3 * * *"
+	// +kubebuilder:validation:Optional
+	Schedule string `json:"schedule"`
 }
 
 type Minio struct {
@@ -239,6 +862,19 @@ type Minio struct {
 	// Specify a custom image for Minio pod.
 	// +kubebuilder:validation:Required
 	Image string `json:"image"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: Recreate, as its ReadWriteOnce PVC can only be mounted by one pod at a time
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+	// Service exposing this component, e.g. as a NodePort or LoadBalancer on clusters without Routes or an Ingress controller. Default: ClusterIP
+	// +kubebuilder:validation:Optional
+	Service *ComponentService `json:"service,omitempty"`
+	// Node selector, tolerations and affinity of this component's pods, e.g. to pin them to dedicated infrastructure
+	// nodes. Default: scheduled on any Linux node of the supported architectures
+	// +kubebuilder:validation:Optional
+	Scheduling *Scheduling `json:"scheduling,omitempty"`
 }
 
 type MLMD struct {
@@ -249,12 +885,75 @@ type MLMD struct {
 	*Envoy  `json:"envoy,omitempty"`
 	*GRPC   `json:"grpc,omitempty"`
 	*Writer `json:"writer,omitempty"`
+	// Node selector, tolerations and affinity of the pods of the Envoy, gRPC and Writer components. Default: scheduled
+	// on any Linux node of the supported architectures
+	// +kubebuilder:validation:Optional
+	Scheduling *Scheduling `json:"scheduling,omitempty"`
 }
 
 type Envoy struct {
 	Resources *ResourceRequirements `json:"resources,omitempty"`
 	// +kubebuilder:validation:Required
 	Image string `json:"image"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: RollingUpdate
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+	// Service exposing this component, e.g. as a NodePort or LoadBalancer on clusters without Routes or an Ingress controller. Default: ClusterIP
+	// +kubebuilder:validation:Optional
+	Service *ComponentService `json:"service,omitempty"`
+	// Log every request proxied to the MLMD gRPC server to the standard output of the proxy, to audit metadata
+	// traffic. Default: requests aren't logged
+	// +kubebuilder:validation:Optional
+	AccessLog *EnvoyAccessLog `json:"accessLog,omitempty"`
+	// +kubebuilder:validation:Optional
+	Timeouts *EnvoyTimeouts `json:"timeouts,omitempty"`
+	// Maximum number of connections the proxy opens to the MLMD gRPC servers. Default: 1024 per gRPC replica
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxConnections int32 `json:"maxConnections,omitempty"`
+	// Only proxy requests carrying a JWT of this issuer in their Authorization header, requests without a valid
+	// token are rejected with 401. Default: requests aren't authenticated
+	// +kubebuilder:validation:Optional
+	JWT *EnvoyJWT `json:"jwt,omitempty"`
+}
+
+type EnvoyAccessLog struct {
+	// Format of the access log entries, in Envoy's command operator syntax, e.g.
+	// "[%START_TIME%] %REQ(:PATH)% %RESPONSE_CODE% %DURATION%\n". Default: Envoy's default format
+	// +kubebuilder:validation:Optional
+	Format string `json:"format,omitempty"`
+}
+
+type EnvoyTimeouts struct {
+	// Time to establish a connection to the MLMD gRPC server, e.g. "10s". Default: 30s
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="connect timeout must be greater than 0s"
+	// +kubebuilder:validation:Optional
+	Connect *metav1.Duration `json:"connect,omitempty"`
+	// Time for the MLMD gRPC server to answer a request that doesn't set a grpc-timeout, "0s" disables the timeout.
+	// Default: 15s
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('0s')",message="request timeout must not be negative"
+	// +kubebuilder:validation:Optional
+	Request *metav1.Duration `json:"request,omitempty"`
+	// Time after which client connections without active requests are closed, "0s" disables the timeout. Default: 1h
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('0s')",message="idle timeout must not be negative"
+	// +kubebuilder:validation:Optional
+	Idle *metav1.Duration `json:"idle,omitempty"`
+}
+
+type EnvoyJWT struct {
+	// Issuer of the accepted tokens, matched against their iss claim.
+	// +kubebuilder:validation:Required
+	Issuer string `json:"issuer"`
+	// URL of the JSON Web Key Set verifying the token signatures, e.g. "https://keycloak.example.com/realms/ml/protocol/openid-connect/certs".
+	// +kubebuilder:validation:XValidation:rule="self.matches('^https?://[^/:]+(:[0-9]+)?(/.*)?$')",message="jwksUri must be an http or https URL"
+	// +kubebuilder:validation:Required
+	JWKSURI string `json:"jwksUri"`
+	// Accepted values of the aud claim. Default: the audience isn't verified
+	// +kubebuilder:validation:Optional
+	Audiences []string `json:"audiences,omitempty"`
 }
 
 type GRPC struct {
@@ -262,13 +961,373 @@ type GRPC struct {
 	// +kubebuilder:validation:Required
 	Image string `json:"image"`
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=5
+	// +kubebuilder:validation:XValidation:rule="self == '' || (self.matches('^[0-9]+$') && int(self) >= 1 && int(self) <= 65535)",message="port must be between 1 and 65535"
 	Port string `json:"port"`
+	// Also expose the gRPC server through a headless Service, ds-pipeline-metadata-grpc-headless-<name>, resolving to
+	// the address of every replica. gRPC keeps long lived connections to a single backend of a ClusterIP Service, with
+	// the headless Service clients balance requests over the replicas, as the MLMD Envoy proxy then does. Default: false
+	// +kubebuilder:validation:Optional
+	Headless bool `json:"headless,omitempty"`
+	// Number of gRPC server replicas. With more than one replica the headless Service is always deployed, so the MLMD
+	// Envoy proxy balances requests over every replica, and the database must accept writes from all of them. Default: 1
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: RollingUpdate
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+	// How the replicas of this component are spread over the cluster, when it runs more than one. Default: spread
+	// across zones and nodes where possible
+	// +kubebuilder:validation:Optional
+	Placement *Placement `json:"placement,omitempty"`
+	// Service exposing this component, e.g. as a NodePort or LoadBalancer on clusters without Routes or an Ingress controller. Default: ClusterIP
+	// +kubebuilder:validation:Optional
+	Service *ComponentService `json:"service,omitempty"`
 }
 
 type Writer struct {
 	Resources *ResourceRequirements `json:"resources,omitempty"`
 	// +kubebuilder:validation:Required
 	Image string `json:"image"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: RollingUpdate
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+}
+
+type ImagePrepuller struct {
+	// Enable DS Pipelines Operator management of the Image Prepuller. Setting Deploy to false disables operator reconciliation. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	Deploy bool `json:"deploy"`
+	// Specify a custom image for the Image Prepuller's idle container.
+	Image string `json:"image,omitempty"`
+	// Images to prepull on every node, such as the training images used by frequently run pipelines. The artifact,
+	// cache and move-results step images of the DSP API Server are always included. Each image must provide /bin/sh.
+	Images    []string              `json:"images,omitempty"`
+	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+}
+
+type CacheServer struct {
+	// Enable DS Pipelines Operator management of the Cache Server. Setting Deploy to false disables operator reconciliation, and removes its webhook so steps are no longer cached. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	Deploy bool `json:"deploy"`
+	// Specify a custom image for the Cache Server.
+	Image     string                `json:"image,omitempty"`
+	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// How long cached step results are reused for steps that don't set a max cache staleness, as an ISO 8601 duration,
+	// e.g. "P30D". Default: cached results never go stale
+	// +kubebuilder:validation:Pattern=`^P(\d+Y)?(\d+M)?(\d+W)?(\d+D)?(T(\d+H)?(\d+M)?(\d+S)?)?$`
+	// +kubebuilder:validation:Optional
+	DefaultCacheStaleness string `json:"defaultCacheStaleness,omitempty"`
+	// Upper bound of the cache staleness of every step, also capping the staleness steps set themselves, as an ISO 8601
+	// duration, e.g. "P90D". Default: unbounded
+	// +kubebuilder:validation:Pattern=`^P(\d+Y)?(\d+M)?(\d+W)?(\d+D)?(T(\d+H)?(\d+M)?(\d+S)?)?$`
+	// +kubebuilder:validation:Optional
+	MaximumCacheStaleness string `json:"maximumCacheStaleness,omitempty"`
+	// Name of the database storing the cache entries, on the server of the DSPA's database and accessed with its
+	// credentials. The Cache Server creates it if it doesn't exist, which requires the privilege to. Default: the
+	// DSPA's database
+	// +kubebuilder:validation:Optional
+	DBName string `json:"dbName,omitempty"`
+	// Certificate serving the Cache Server's admission webhook, e.g. issued by cert-manager. Default: a certificate
+	// issued by the OpenShift service CA
+	// +kubebuilder:validation:Optional
+	TLS *CacheServerTLS `json:"tls,omitempty"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+	// How this component's Deployment replaces its pods on updates. Default: RollingUpdate
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+}
+
+type ConsoleLinks struct {
+	// Enable DS Pipelines Operator management of the OpenShift ConsoleLinks. Links to Routes that aren't enabled are
+	// not created. Setting Deploy to false removes them. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	Deploy bool `json:"deploy"`
+}
+
+type Headroom struct {
+	// Enable DS Pipelines Operator management of the placeholder pods. Setting Deploy to false removes them, releasing the capacity they hold. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	Deploy bool `json:"deploy"`
+	// Specify a custom image for the placeholder pods' idle container.
+	Image string `json:"image,omitempty"`
+	// Number of placeholder pods, each holding the capacity of one pipeline step. Default: 1
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	Replicas int32 `json:"replicas"`
+	// Capacity held by each placeholder pod, sized for a typical pipeline step. Default: 1 CPU and 2Gi of memory
+	Resources *ResourceRequirements `json:"resources,omitempty"`
+	// Priority of the placeholder pods. It must be lower than the priority of the pipeline step pods, 0 unless they set
+	// a PriorityClass, for steps to preempt them, and not lower than the expendable pods priority cutoff of the cluster
+	// autoscaler, -10 by default, for pending placeholder pods to still trigger scale ups. Must be between -10 and -1.
+	// Default: -1
+	// +kubebuilder:default:=-1
+	// +kubebuilder:validation:Minimum=-10
+	// +kubebuilder:validation:Maximum=-1
+	// +kubebuilder:validation:Optional
+	Priority int32 `json:"priority"`
+	// Node labels of the node pool pipeline steps run on, for the capacity to be held there. Added to the node
+	// selector of the DSPA's components.
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Confinement profiles of this component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SecurityProfiles *SecurityProfiles `json:"securityProfiles,omitempty"`
+}
+
+type CacheServerTLS struct {
+	// Secret of type kubernetes.io/tls holding the tls.crt and tls.key of the webhook, valid for the
+	// ds-pipeline-cache-server-<dspa-name>.<namespace>.svc DNS name.
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+	// ConfigMap key holding the PEM bundle of the CA that issued the certificate, trusted by the API server when calling the webhook.
+	// +kubebuilder:validation:Required
+	CABundle *CABundle `json:"caBundle"`
+}
+
+type RunStatusWebhook struct {
+	// Name identifies the webhook when tracking deliveries, and must be unique within the DSPA.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// https URL that run status events are POSTed to as JSON. Cluster-internal, loopback, link-local and private
+	// network targets are refused.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https://`
+	URL string `json:"url"`
+	// Secret key holding the key used to sign each payload with HMAC-SHA256. The signature is sent as "sha256=<hex>" in the X-DSP-Signature-256 header.
+	// +kubebuilder:validation:Optional
+	SigningSecret *SecretKeyValue `json:"signingSecret,omitempty"`
+	// Number of delivery attempts before an event is dropped. Failed deliveries are retried on the next delivery pass. Default: 3
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+}
+
+type CommitStatusReporter struct {
+	// Name identifies the reporter when tracking reported statuses, and must be unique within the DSPA.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Git hosting provider the commit statuses are set on. Allowed Values: "github", "gitlab"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=github;gitlab
+	Provider string `json:"provider"`
+	// Base URL of the provider's API. Default: "https://api.github.com" for github, "https://gitlab.com/api/v4" for gitlab
+	// Restricted like the run status webhook URLs, hosts on private networks must be allowed in the operator config.
+	// +kubebuilder:validation:Optional
+	APIURL string `json:"apiUrl,omitempty"`
+	// Repository the commit statuses are set on, "owner/repo" for GitHub or the project path for GitLab. The token is
+	// only ever sent for this repository, add a reporter per repository the CI runs pipelines for.
+	// +kubebuilder:validation:Required
+	Repository string `json:"repository"`
+	// Name of the pipeline parameter holding the commit SHA a run was triggered for. Runs without it are not reported. Default: "commit_sha"
+	// +kubebuilder:default:=commit_sha
+	// +kubebuilder:validation:Optional
+	CommitParameter string `json:"commitParameter,omitempty"`
+	// Name shown for the commit status. Default: "data-science-pipelines"
+	// +kubebuilder:default:=data-science-pipelines
+	// +kubebuilder:validation:Optional
+	Context string `json:"context,omitempty"`
+	// Secret key holding the API token used to set commit statuses.
+	// +kubebuilder:validation:Required
+	TokenSecret *SecretKeyValue `json:"tokenSecret"`
+	// Number of attempts to report each state of a run before it is dropped. Failed reports are retried on the next
+	// delivery pass. Default: 3
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+}
+
+type StepExitHandler struct {
+	// Upload the logs of the main container of finished steps to the object store, as main-log.tgz next to the step's
+	// artifacts. Steps that succeed are skipped when the API Server already archives their logs (apiServer.archiveLogs). Default: true
+	// +kubebuilder:default:=true
+	// +kubebuilder:validation:Optional
+	CaptureLogs bool `json:"captureLogs"`
+	// Count finished steps in the data_science_pipelines_application_steps_finished_total metric, by status and reason. Default: true
+	// +kubebuilder:default:=true
+	// +kubebuilder:validation:Optional
+	EmitMetrics bool `json:"emitMetrics"`
+	// Names of the runStatusWebhooks notified when a step fails, with the same signing and delivery attempts as run status events.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	NotifyWebhooks []string `json:"notifyWebhooks,omitempty"`
+	// Upload a diagnostics bundle of every failed run to the object store, as diagnostics/<PipelineRun>/diagnostics.tgz:
+	// the PipelineRun and TaskRuns, and the logs of all the containers of its steps, so failures can be investigated
+	// once the run and its pods are pruned. Default: false
+	// +kubebuilder:default:=false
+	// +kubebuilder:validation:Optional
+	CaptureRunDiagnostics bool `json:"captureRunDiagnostics"`
+}
+
+// SecurityProfiles configures the seccomp and AppArmor confinement of a component's containers.
+type SecurityProfiles struct {
+	// Seccomp profile applied to the component's pods. Default: the seccomp profile of the operator config, if any
+	// +kubebuilder:validation:Optional
+	SeccompProfile *SeccompProfile `json:"seccompProfile,omitempty"`
+	// AppArmor profile applied to every container of the component's pods, e.g. "runtime/default" or
+	// "localhost/<profile>". Only set this on clusters whose nodes have AppArmor enabled.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(runtime/default|unconfined|localhost/.+)$`
+	AppArmorProfile string `json:"appArmorProfile,omitempty"`
+}
+
+// LifecycleHooks declares the Jobs run around component upgrades.
+type LifecycleHooks struct {
+	// Jobs run once an upgrade is found, and approved with Manual upgradeApproval. Components are only upgraded once
+	// all of them succeeded, and are held back while one of them failed.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	PreUpgrade []LifecycleHook `json:"preUpgrade,omitempty"`
+	// Jobs run once the upgraded components are ready. Their failures are reported in the UpgradePending condition.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	PostUpgrade []LifecycleHook `json:"postUpgrade,omitempty"`
+}
+
+// LifecycleHook is a Job running a single container, without retries.
+type LifecycleHook struct {
+	// Name of the hook, part of the name of its Jobs.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Image of the hook container.
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+	// Command of the hook container. Default: the entrypoint of the image
+	// +kubebuilder:validation:Optional
+	Command []string `json:"command,omitempty"`
+	// Seconds the hook may run before it's failed. Default: 600
+	// +kubebuilder:default:=600
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	ActiveDeadlineSeconds int64 `json:"activeDeadlineSeconds,omitempty"`
+}
+
+// UpdateStrategy configures how a component's Deployment replaces its pods on updates.
+// +kubebuilder:validation:XValidation:rule="self.type == 'RollingUpdate' || (!has(self.maxSurge) && !has(self.maxUnavailable))",message="maxSurge and maxUnavailable can only be set with the RollingUpdate type"
+type UpdateStrategy struct {
+	// Recreate stops the running pods before starting the new ones, e.g. for components mounting a ReadWriteOnce PVC.
+	// RollingUpdate replaces pods progressively, keeping the component available. Allowed Values: "Recreate", "RollingUpdate"
+	// +kubebuilder:validation:Enum=Recreate;RollingUpdate
+	// +kubebuilder:validation:Required
+	Type string `json:"type"`
+	// Pods created above the desired replicas during a rolling update, e.g. 1 or "25%". Default: 25%
+	// +kubebuilder:validation:Optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// Pods that can be unavailable during a rolling update, e.g. 0 or "25%". Default: 25%
+	// +kubebuilder:validation:Optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// Placement configures how the replicas of a component are spread, with a topology spread constraint for every
+// topology key and a preferred pod anti-affinity on nodes, so the component survives the outage of a node or zone.
+type Placement struct {
+	// Node labels whose values the replicas are spread over, from the widest failure domain. Default:
+	// ["topology.kubernetes.io/zone", "kubernetes.io/hostname"]
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems=1
+	TopologyKeys []string `json:"topologyKeys,omitempty"`
+	// Leave replicas pending rather than schedule them in a domain already running more replicas than the others, so
+	// the spread is guaranteed at the cost of pending pods when a domain runs out of capacity. Default: false
+	// +kubebuilder:validation:Optional
+	RequireSpread bool `json:"requireSpread,omitempty"`
+	// Don't constrain the placement of the replicas, e.g. when the cluster enforces its own placement policies.
+	// Default: false
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Scheduling constrains the nodes a component's pods are scheduled on. The node selector and affinity are added to the
+// ones the operator sets, which keep the pods on Linux nodes of the architectures the component's images are published
+// for.
+type Scheduling struct {
+	// Node labels the pods must be scheduled on, added to the node selector of the DSPA's components.
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations of the pods, e.g. for the taints of dedicated nodes.
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity of the pods. Required node affinity terms only match nodes of the supported architectures, pod
+	// anti-affinity terms are added to the ones spreading the replicas of the component. Its schema is left out of the
+	// CRD, which would otherwise outgrow the size limit of the API server with one copy per component: it's validated
+	// when the pods are created.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+}
+
+// ComponentService configures the Service of a component. Its ports are matched by name, and only change the ports the
+// Service exposes, the component's containers keep listening on their default ports.
+// +kubebuilder:validation:XValidation:rule="self.type == 'ClusterIP' ? !has(self.ports) || self.ports.all(p, !has(p.nodePort)) : true",message="nodePort can only be set on NodePort and LoadBalancer Services"
+type ComponentService struct {
+	// Default: "ClusterIP" - Allowed Values: "ClusterIP", "NodePort", "LoadBalancer"
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +kubebuilder:default:=ClusterIP
+	Type string `json:"type,omitempty"`
+	// Annotations added to the Service, e.g. to configure the load balancer of the cluster.
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Overrides of the Service ports. The API Server exposes the oauth, http and grpc ports, the UI http, MariaDB
+	// mysql, Minio http, MLMD Envoy md-envoy and MLMD gRPC grpc-api.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	Ports []ServicePort `json:"ports,omitempty"`
+}
+
+type ServicePort struct {
+	// Name of the Service port to override.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Port exposed by the Service. Default: the component's default port
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+	// Port exposed on every node for NodePort and LoadBalancer Services, within the node port range of the cluster.
+	// Default: allocated by the cluster
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	NodePort int32 `json:"nodePort,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="self.type == 'Localhost' ? has(self.localhostProfile) : !has(self.localhostProfile)",message="localhostProfile must be set if and only if type is Localhost"
+type SeccompProfile struct {
+	// Default: "RuntimeDefault" - Allowed Values: "RuntimeDefault", "Localhost", "Unconfined"
+	// +kubebuilder:validation:Enum=RuntimeDefault;Localhost;Unconfined
+	// +kubebuilder:default:=RuntimeDefault
+	Type string `json:"type"`
+	// Path of the profile on the node, relative to the kubelet's seccomp profile directory. Required when Type is Localhost.
+	// +kubebuilder:validation:Optional
+	LocalhostProfile string `json:"localhostProfile,omitempty"`
 }
 
 // ResourceRequirements structures compute resource requirements.
@@ -293,7 +1352,13 @@ type ExternalStorage struct {
 	// +kubebuilder:validation:Optional
 	Secure *bool `json:"secure"`
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=5
+	// +kubebuilder:validation:XValidation:rule="self == '' || (self.matches('^[0-9]+$') && int(self) >= 1 && int(self) <= 65535)",message="port must be between 1 and 65535"
 	Port string `json:"port"`
+	// Region of the bucket, e.g. "eu-west-1". Required in namespaces whose artifacts the operator config restricts to
+	// some regions, where it must be one of them, and compared to the region the object store reports for the bucket.
+	// +kubebuilder:validation:Optional
+	Region string `json:"region,omitempty"`
 }
 
 type S3CredentialSecret struct {
@@ -312,11 +1377,117 @@ type SecretKeyValue struct {
 
 type DSPAStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The names of the Services and Routes of the deployed components. Names derived from long DSPA names are
+	// truncated and suffixed with a hash to be valid, so they can't always be guessed from the DSPA name.
+	// +optional
+	DerivedNames *DerivedNames `json:"derivedNames,omitempty"`
+	// The feature gates enabled for this DSPA, whether by default or by spec.featureGates.
+	// +optional
+	ActiveFeatureGates []string `json:"activeFeatureGates,omitempty"`
+	// How long the most recently started pod of every component took to start, e.g. to find slow volume provisioning
+	// or image pulls. Only ready pods whose containers never restarted are measured.
+	// +optional
+	// +listType=map
+	// +listMapKey=component
+	ComponentStartups []ComponentStartup `json:"componentStartups,omitempty"`
+	// Endpoints, credentials Secrets and versions of the DSPA, for tools provisioning DSPAs as part of larger stacks,
+	// e.g. Crossplane compositions or Terraform. Their fields are only ever added to within a schemaVersion, never
+	// renamed, removed or changed in meaning.
+	// +optional
+	Outputs *StatusOutputs `json:"outputs,omitempty"`
+	// Delivery progress of every run status webhook.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	RunStatusWebhooks []RunStatusWebhookStatus `json:"runStatusWebhooks,omitempty"`
+}
+
+// RunStatusWebhookStatus is the delivery progress of a run status webhook.
+type RunStatusWebhookStatus struct {
+	Name string `json:"name"`
+	// When the webhook was added to the DSPA. Every run finishing since is delivered, however long delivery takes, and
+	// runs that finished before are not, so adding a webhook doesn't replay the run history of the namespace.
+	Since metav1.Time `json:"since"`
+}
+
+type StatusOutputs struct {
+	// Version of the schema of the outputs. Currently: v1
+	SchemaVersion string `json:"schemaVersion"`
+	// Generation of the DSPA spec the outputs were computed from.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// +optional
+	Endpoints *OutputEndpoints `json:"endpoints,omitempty"`
+	// +optional
+	CredentialsSecretRefs *CredentialsSecretRefs `json:"credentialsSecretRefs,omitempty"`
+	// +optional
+	Version *OutputVersion `json:"version,omitempty"`
+}
+
+// OutputEndpoints are the URLs of the components of the DSPA. Components the DSPA doesn't deploy or expose, and
+// Routes without a host yet, are left out.
+type OutputEndpoints struct {
+	// In-cluster URL of the API Server REST API.
+	APIServer string `json:"apiServer,omitempty"`
+	// URL of the Route of the API Server REST API.
+	APIServerRoute string `json:"apiServerRoute,omitempty"`
+	// URL of the Route of the API Server gRPC API.
+	APIServerGRPCRoute string `json:"apiServerGRPCRoute,omitempty"`
+	// In-cluster URL of the UI.
+	UI string `json:"ui,omitempty"`
+	// URL of the Route of the UI.
+	UIRoute string `json:"uiRoute,omitempty"`
+	// In-cluster address of the MLMD gRPC server, host:port.
+	MLMDGRPC string `json:"mlmdGRPC,omitempty"`
+}
+
+// CredentialsSecretRefs are the Secrets holding the credentials the components of the DSPA connect with, whether from
+// the spec or generated by the operator.
+type CredentialsSecretRefs struct {
+	// The Secret key holding the database password.
+	Database *SecretKeyValue `json:"database,omitempty"`
+	// The Secret keys holding the object storage access key and secret key.
+	ObjectStorage *S3CredentialSecret `json:"objectStorage,omitempty"`
+}
+
+type OutputVersion struct {
+	// Version of the operator that last reconciled the DSPA.
+	Operator string `json:"operator"`
+	// Source revision of the operator build.
+	SourceRevision string `json:"sourceRevision,omitempty"`
+	// Image of the API Server, if deployed.
+	APIServerImage string `json:"apiServerImage,omitempty"`
+}
+
+type ComponentStartup struct {
+	// Name of the component's Deployment.
+	Component string `json:"component"`
+	// Creation time of the pod.
+	StartedAt metav1.Time `json:"startedAt"`
+	// Time from the creation of the pod until it was scheduled, including the provisioning of its volumes.
+	TimeToScheduled metav1.Duration `json:"timeToScheduled"`
+	// Time from the creation of the pod until it became ready, including image pulls and container startup.
+	TimeToReady metav1.Duration `json:"timeToReady"`
+}
+
+type DerivedNames struct {
+	APIServerService        string `json:"apiServerService,omitempty"`
+	APIServerRoute          string `json:"apiServerRoute,omitempty"`
+	APIServerGRPCRoute      string `json:"apiServerGRPCRoute,omitempty"`
+	MlPipelineUIService     string `json:"mlpipelineUIService,omitempty"`
+	MlPipelineUIRoute       string `json:"mlpipelineUIRoute,omitempty"`
+	MariaDBService          string `json:"mariaDBService,omitempty"`
+	MinioService            string `json:"minioService,omitempty"`
+	MinioRoute              string `json:"minioRoute,omitempty"`
+	MLMDGRPCService         string `json:"mlmdGRPCService,omitempty"`
+	MLMDGRPCHeadlessService string `json:"mlmdGRPCHeadlessService,omitempty"`
+	MLMDEnvoyService        string `json:"mlmdEnvoyService,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:shortName=dspa
+// +kubebuilder:validation:XValidation:rule="self.metadata.name.matches('^[a-z0-9]([-a-z0-9]*[a-z0-9])?$')",message="DSPA name must consist of lower case alphanumeric characters or '-', and start and end with an alphanumeric character, as it is part of the Service names of its components"
+// +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 63",message="DSPA name must be no more than 63 characters, as it is used as the value of the dspa label"
 
 type DataSciencePipelinesApplication struct {
 	metav1.TypeMeta   `json:",inline"`
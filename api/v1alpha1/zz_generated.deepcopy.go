@@ -22,10 +22,62 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIGateway) DeepCopyInto(out *APIGateway) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(APIGatewayAuth)
+		**out = **in
+	}
+	if in.Kong != nil {
+		in, out := &in.Kong, &out.Kong
+		*out = new(KongGateway)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ThreeScale != nil {
+		in, out := &in.ThreeScale, &out.ThreeScale
+		*out = new(ThreeScaleGateway)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIGateway.
+func (in *APIGateway) DeepCopy() *APIGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(APIGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIGatewayAuth) DeepCopyInto(out *APIGatewayAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIGatewayAuth.
+func (in *APIGatewayAuth) DeepCopy() *APIGatewayAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(APIGatewayAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServer) DeepCopyInto(out *APIServer) {
 	*out = *in
@@ -34,16 +86,66 @@ func (in *APIServer) DeepCopyInto(out *APIServer) {
 		*out = new(ArtifactScriptConfigMap)
 		**out = **in
 	}
+	if in.RouteTLS != nil {
+		in, out := &in.RouteTLS, &out.RouteTLS
+		*out = new(RouteTLS)
+		**out = **in
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = new(ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DefaultRunTimeout != nil {
+		in, out := &in.DefaultRunTimeout, &out.DefaultRunTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DefaultStepTimeout != nil {
+		in, out := &in.DefaultStepTimeout, &out.DefaultStepTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RunRetryPolicy != nil {
+		in, out := &in.RunRetryPolicy, &out.RunRetryPolicy
+		*out = new(RunRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CABundle != nil {
 		in, out := &in.CABundle, &out.CABundle
 		*out = new(CABundle)
 		**out = **in
 	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(Placement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ComponentService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(APIServerGRPC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(Scheduling)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServer.
@@ -56,6 +158,76 @@ func (in *APIServer) DeepCopy() *APIServer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerGRPC) DeepCopyInto(out *APIServerGRPC) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(APIServerGRPCTLS)
+		**out = **in
+	}
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(EnvoyJWT)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerGRPC.
+func (in *APIServerGRPC) DeepCopy() *APIServerGRPC {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerGRPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerGRPCTLS) DeepCopyInto(out *APIServerGRPCTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerGRPCTLS.
+func (in *APIServerGRPCTLS) DeepCopy() *APIServerGRPCTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerGRPCTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactRetention) DeepCopyInto(out *ArtifactRetention) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactRetention.
+func (in *ArtifactRetention) DeepCopy() *ArtifactRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArtifactScriptConfigMap) DeepCopyInto(out *ArtifactScriptConfigMap) {
 	*out = *in
@@ -71,6 +243,71 @@ func (in *ArtifactScriptConfigMap) DeepCopy() *ArtifactScriptConfigMap {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Backup) DeepCopyInto(out *Backup) {
+	*out = *in
+	if in.Destination != nil {
+		in, out := &in.Destination, &out.Destination
+		*out = new(BackupDestination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScratchSizeLimit != nil {
+		in, out := &in.ScratchSizeLimit, &out.ScratchSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
+func (in *Backup) DeepCopy() *Backup {
+	if in == nil {
+		return nil
+	}
+	out := new(Backup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupDestination) DeepCopyInto(out *BackupDestination) {
+	*out = *in
+	if in.ExternalStorage != nil {
+		in, out := &in.ExternalStorage, &out.ExternalStorage
+		*out = new(ExternalStorage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDestination.
+func (in *BackupDestination) DeepCopy() *BackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackoutWindow) DeepCopyInto(out *BlackoutWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackoutWindow.
+func (in *BlackoutWindow) DeepCopy() *BlackoutWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackoutWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CABundle) DeepCopyInto(out *CABundle) {
 	*out = *in
@@ -87,481 +324,1698 @@ func (in *CABundle) DeepCopy() *CABundle {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DSPASpec) DeepCopyInto(out *DSPASpec) {
+func (in *CacheServer) DeepCopyInto(out *CacheServer) {
 	*out = *in
-	if in.APIServer != nil {
-		in, out := &in.APIServer, &out.APIServer
-		*out = new(APIServer)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.PersistenceAgent != nil {
-		in, out := &in.PersistenceAgent, &out.PersistenceAgent
-		*out = new(PersistenceAgent)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(CacheServerTLS)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ScheduledWorkflow != nil {
-		in, out := &in.ScheduledWorkflow, &out.ScheduledWorkflow
-		*out = new(ScheduledWorkflow)
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Database != nil {
-		in, out := &in.Database, &out.Database
-		*out = new(Database)
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.MlPipelineUI != nil {
-		in, out := &in.MlPipelineUI, &out.MlPipelineUI
-		*out = new(MlPipelineUI)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheServer.
+func (in *CacheServer) DeepCopy() *CacheServer {
+	if in == nil {
+		return nil
 	}
-	if in.ObjectStorage != nil {
-		in, out := &in.ObjectStorage, &out.ObjectStorage
-		*out = new(ObjectStorage)
-		(*in).DeepCopyInto(*out)
+	out := new(CacheServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheServerTLS) DeepCopyInto(out *CacheServerTLS) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(CABundle)
+		**out = **in
 	}
-	if in.MLMD != nil {
-		in, out := &in.MLMD, &out.MLMD
-		*out = new(MLMD)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheServerTLS.
+func (in *CacheServerTLS) DeepCopy() *CacheServerTLS {
+	if in == nil {
+		return nil
 	}
+	out := new(CacheServerTLS)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DSPASpec.
-func (in *DSPASpec) DeepCopy() *DSPASpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitStatusReporter) DeepCopyInto(out *CommitStatusReporter) {
+	*out = *in
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(SecretKeyValue)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitStatusReporter.
+func (in *CommitStatusReporter) DeepCopy() *CommitStatusReporter {
 	if in == nil {
 		return nil
 	}
-	out := new(DSPASpec)
+	out := new(CommitStatusReporter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DSPAStatus) DeepCopyInto(out *DSPAStatus) {
+func (in *ComponentService) DeepCopyInto(out *ComponentService) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]ServicePort, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentService.
+func (in *ComponentService) DeepCopy() *ComponentService {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStartup) DeepCopyInto(out *ComponentStartup) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	out.TimeToScheduled = in.TimeToScheduled
+	out.TimeToReady = in.TimeToReady
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStartup.
+func (in *ComponentStartup) DeepCopy() *ComponentStartup {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStartup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleLinks) DeepCopyInto(out *ConsoleLinks) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleLinks.
+func (in *ConsoleLinks) DeepCopy() *ConsoleLinks {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleLinks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostAttribution) DeepCopyInto(out *CostAttribution) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DSPAStatus.
-func (in *DSPAStatus) DeepCopy() *DSPAStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostAttribution.
+func (in *CostAttribution) DeepCopy() *CostAttribution {
+	if in == nil {
+		return nil
+	}
+	out := new(CostAttribution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsSecretRefs) DeepCopyInto(out *CredentialsSecretRefs) {
+	*out = *in
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(SecretKeyValue)
+		**out = **in
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = new(S3CredentialSecret)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsSecretRefs.
+func (in *CredentialsSecretRefs) DeepCopy() *CredentialsSecretRefs {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsSecretRefs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DSPASpec) DeepCopyInto(out *DSPASpec) {
+	*out = *in
+	if in.APIServer != nil {
+		in, out := &in.APIServer, &out.APIServer
+		*out = new(APIServer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistenceAgent != nil {
+		in, out := &in.PersistenceAgent, &out.PersistenceAgent
+		*out = new(PersistenceAgent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduledWorkflow != nil {
+		in, out := &in.ScheduledWorkflow, &out.ScheduledWorkflow
+		*out = new(ScheduledWorkflow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(Database)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MlPipelineUI != nil {
+		in, out := &in.MlPipelineUI, &out.MlPipelineUI
+		*out = new(MlPipelineUI)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = new(ObjectStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MLMD != nil {
+		in, out := &in.MLMD, &out.MLMD
+		*out = new(MLMD)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePrepuller != nil {
+		in, out := &in.ImagePrepuller, &out.ImagePrepuller
+		*out = new(ImagePrepuller)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CacheServer != nil {
+		in, out := &in.CacheServer, &out.CacheServer
+		*out = new(CacheServer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headroom != nil {
+		in, out := &in.Headroom, &out.Headroom
+		*out = new(Headroom)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RunStatusWebhooks != nil {
+		in, out := &in.RunStatusWebhooks, &out.RunStatusWebhooks
+		*out = make([]RunStatusWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CommitStatusReporters != nil {
+		in, out := &in.CommitStatusReporters, &out.CommitStatusReporters
+		*out = make([]CommitStatusReporter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EgressProxy != nil {
+		in, out := &in.EgressProxy, &out.EgressProxy
+		*out = new(EgressProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StepExitHandler != nil {
+		in, out := &in.StepExitHandler, &out.StepExitHandler
+		*out = new(StepExitHandler)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIGateway != nil {
+		in, out := &in.APIGateway, &out.APIGateway
+		*out = new(APIGateway)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsoleLinks != nil {
+		in, out := &in.ConsoleLinks, &out.ConsoleLinks
+		*out = new(ConsoleLinks)
+		**out = **in
+	}
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = new(LifecycleHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReconcileIntervals != nil {
+		in, out := &in.ReconcileIntervals, &out.ReconcileIntervals
+		*out = new(ReconcileIntervals)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPFamilies != nil {
+		in, out := &in.IPFamilies, &out.IPFamilies
+		*out = new(IPFamilies)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalDNS != nil {
+		in, out := &in.ExternalDNS, &out.ExternalDNS
+		*out = new(ExternalDNS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ManifestPatches != nil {
+		in, out := &in.ManifestPatches, &out.ManifestPatches
+		*out = new(ManifestPatches)
+		**out = **in
+	}
+	if in.Guardrails != nil {
+		in, out := &in.Guardrails, &out.Guardrails
+		*out = new(Guardrails)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchedulePolicy != nil {
+		in, out := &in.SchedulePolicy, &out.SchedulePolicy
+		*out = new(SchedulePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CostAttribution != nil {
+		in, out := &in.CostAttribution, &out.CostAttribution
+		*out = new(CostAttribution)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(Backup)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DSPASpec.
+func (in *DSPASpec) DeepCopy() *DSPASpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DSPASpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DSPAStatus) DeepCopyInto(out *DSPAStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DerivedNames != nil {
+		in, out := &in.DerivedNames, &out.DerivedNames
+		*out = new(DerivedNames)
+		**out = **in
+	}
+	if in.ActiveFeatureGates != nil {
+		in, out := &in.ActiveFeatureGates, &out.ActiveFeatureGates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ComponentStartups != nil {
+		in, out := &in.ComponentStartups, &out.ComponentStartups
+		*out = make([]ComponentStartup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = new(StatusOutputs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RunStatusWebhooks != nil {
+		in, out := &in.RunStatusWebhooks, &out.RunStatusWebhooks
+		*out = make([]RunStatusWebhookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DSPAStatus.
+func (in *DSPAStatus) DeepCopy() *DSPAStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DSPAStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSciencePipelinesApplication) DeepCopyInto(out *DataSciencePipelinesApplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSciencePipelinesApplication.
+func (in *DataSciencePipelinesApplication) DeepCopy() *DataSciencePipelinesApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSciencePipelinesApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataSciencePipelinesApplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSciencePipelinesApplicationList) DeepCopyInto(out *DataSciencePipelinesApplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataSciencePipelinesApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSciencePipelinesApplicationList.
+func (in *DataSciencePipelinesApplicationList) DeepCopy() *DataSciencePipelinesApplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSciencePipelinesApplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataSciencePipelinesApplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Database) DeepCopyInto(out *Database) {
+	*out = *in
+	if in.MariaDB != nil {
+		in, out := &in.MariaDB, &out.MariaDB
+		*out = new(MariaDB)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalDB != nil {
+		in, out := &in.ExternalDB, &out.ExternalDB
+		*out = new(ExternalDB)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Database.
+func (in *Database) DeepCopy() *Database {
+	if in == nil {
+		return nil
+	}
+	out := new(Database)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DerivedNames) DeepCopyInto(out *DerivedNames) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DerivedNames.
+func (in *DerivedNames) DeepCopy() *DerivedNames {
+	if in == nil {
+		return nil
+	}
+	out := new(DerivedNames)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressProxy) DeepCopyInto(out *EgressProxy) {
+	*out = *in
+	if in.UsernameSecret != nil {
+		in, out := &in.UsernameSecret, &out.UsernameSecret
+		*out = new(SecretKeyValue)
+		**out = **in
+	}
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretKeyValue)
+		**out = **in
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(CABundle)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressProxy.
+func (in *EgressProxy) DeepCopy() *EgressProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Envoy) DeepCopyInto(out *Envoy) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ComponentService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccessLog != nil {
+		in, out := &in.AccessLog, &out.AccessLog
+		*out = new(EnvoyAccessLog)
+		**out = **in
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(EnvoyTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(EnvoyJWT)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Envoy.
+func (in *Envoy) DeepCopy() *Envoy {
+	if in == nil {
+		return nil
+	}
+	out := new(Envoy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyAccessLog) DeepCopyInto(out *EnvoyAccessLog) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyAccessLog.
+func (in *EnvoyAccessLog) DeepCopy() *EnvoyAccessLog {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyAccessLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyJWT) DeepCopyInto(out *EnvoyJWT) {
+	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyJWT.
+func (in *EnvoyJWT) DeepCopy() *EnvoyJWT {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyJWT)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyTimeouts) DeepCopyInto(out *EnvoyTimeouts) {
+	*out = *in
+	if in.Connect != nil {
+		in, out := &in.Connect, &out.Connect
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Idle != nil {
+		in, out := &in.Idle, &out.Idle
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyTimeouts.
+func (in *EnvoyTimeouts) DeepCopy() *EnvoyTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDB) DeepCopyInto(out *ExternalDB) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretKeyValue)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDB.
+func (in *ExternalDB) DeepCopy() *ExternalDB {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDB)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNS) DeepCopyInto(out *ExternalDNS) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNS.
+func (in *ExternalDNS) DeepCopy() *ExternalDNS {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalStorage) DeepCopyInto(out *ExternalStorage) {
+	*out = *in
+	if in.S3CredentialSecret != nil {
+		in, out := &in.S3CredentialSecret, &out.S3CredentialSecret
+		*out = new(S3CredentialSecret)
+		**out = **in
+	}
+	if in.Secure != nil {
+		in, out := &in.Secure, &out.Secure
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStorage.
+func (in *ExternalStorage) DeepCopy() *ExternalStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPC) DeepCopyInto(out *GRPC) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(Placement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ComponentService)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPC.
+func (in *GRPC) DeepCopy() *GRPC {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollection) DeepCopyInto(out *GarbageCollection) {
+	*out = *in
+	if in.TTLAfterSucceeded != nil {
+		in, out := &in.TTLAfterSucceeded, &out.TTLAfterSucceeded
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TTLAfterFailed != nil {
+		in, out := &in.TTLAfterFailed, &out.TTLAfterFailed
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollection.
+func (in *GarbageCollection) DeepCopy() *GarbageCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Guardrails) DeepCopyInto(out *Guardrails) {
+	*out = *in
+	if in.MaxPipelineResources != nil {
+		in, out := &in.MaxPipelineResources, &out.MaxPipelineResources
+		*out = new(PipelineResources)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Guardrails.
+func (in *Guardrails) DeepCopy() *Guardrails {
+	if in == nil {
+		return nil
+	}
+	out := new(Guardrails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Headroom) DeepCopyInto(out *Headroom) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Headroom.
+func (in *Headroom) DeepCopy() *Headroom {
+	if in == nil {
+		return nil
+	}
+	out := new(Headroom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPFamilies) DeepCopyInto(out *IPFamilies) {
+	*out = *in
+	if in.Families != nil {
+		in, out := &in.Families, &out.Families
+		*out = make([]IPFamily, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPFamilies.
+func (in *IPFamilies) DeepCopy() *IPFamilies {
+	if in == nil {
+		return nil
+	}
+	out := new(IPFamilies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePrepuller) DeepCopyInto(out *ImagePrepuller) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrepuller.
+func (in *ImagePrepuller) DeepCopy() *ImagePrepuller {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePrepuller)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KongGateway) DeepCopyInto(out *KongGateway) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KongGateway.
+func (in *KongGateway) DeepCopy() *KongGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(KongGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHook) DeepCopyInto(out *LifecycleHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHook.
+func (in *LifecycleHook) DeepCopy() *LifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHooks) DeepCopyInto(out *LifecycleHooks) {
+	*out = *in
+	if in.PreUpgrade != nil {
+		in, out := &in.PreUpgrade, &out.PreUpgrade
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostUpgrade != nil {
+		in, out := &in.PostUpgrade, &out.PostUpgrade
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHooks.
+func (in *LifecycleHooks) DeepCopy() *LifecycleHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MLMD) DeepCopyInto(out *MLMD) {
+	*out = *in
+	if in.Envoy != nil {
+		in, out := &in.Envoy, &out.Envoy
+		*out = new(Envoy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(GRPC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Writer != nil {
+		in, out := &in.Writer, &out.Writer
+		*out = new(Writer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(Scheduling)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MLMD.
+func (in *MLMD) DeepCopy() *MLMD {
+	if in == nil {
+		return nil
+	}
+	out := new(MLMD)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestPatches) DeepCopyInto(out *ManifestPatches) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestPatches.
+func (in *ManifestPatches) DeepCopy() *ManifestPatches {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestPatches)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MariaDB) DeepCopyInto(out *MariaDB) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretKeyValue)
+		**out = **in
+	}
+	out.PVCSize = in.PVCSize.DeepCopy()
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ComponentService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(Scheduling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MariaDBMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MariaDB.
+func (in *MariaDB) DeepCopy() *MariaDB {
+	if in == nil {
+		return nil
+	}
+	out := new(MariaDB)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MariaDBMetrics) DeepCopyInto(out *MariaDBMetrics) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MariaDBMetrics.
+func (in *MariaDBMetrics) DeepCopy() *MariaDBMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(MariaDBMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Minio) DeepCopyInto(out *Minio) {
+	*out = *in
+	if in.S3CredentialSecret != nil {
+		in, out := &in.S3CredentialSecret, &out.S3CredentialSecret
+		*out = new(S3CredentialSecret)
+		**out = **in
+	}
+	out.PVCSize = in.PVCSize.DeepCopy()
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ComponentService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(Scheduling)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Minio.
+func (in *Minio) DeepCopy() *Minio {
+	if in == nil {
+		return nil
+	}
+	out := new(Minio)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MlPipelineUI) DeepCopyInto(out *MlPipelineUI) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RouteTLS != nil {
+		in, out := &in.RouteTLS, &out.RouteTLS
+		*out = new(RouteTLS)
+		**out = **in
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ComponentService)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MlPipelineUI.
+func (in *MlPipelineUI) DeepCopy() *MlPipelineUI {
+	if in == nil {
+		return nil
+	}
+	out := new(MlPipelineUI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStorage) DeepCopyInto(out *ObjectStorage) {
+	*out = *in
+	if in.Minio != nil {
+		in, out := &in.Minio, &out.Minio
+		*out = new(Minio)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalStorage != nil {
+		in, out := &in.ExternalStorage, &out.ExternalStorage
+		*out = new(ExternalStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArtifactRetention != nil {
+		in, out := &in.ArtifactRetention, &out.ArtifactRetention
+		*out = new(ArtifactRetention)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorage.
+func (in *ObjectStorage) DeepCopy() *ObjectStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputEndpoints) DeepCopyInto(out *OutputEndpoints) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputEndpoints.
+func (in *OutputEndpoints) DeepCopy() *OutputEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputVersion) DeepCopyInto(out *OutputVersion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputVersion.
+func (in *OutputVersion) DeepCopy() *OutputVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistenceAgent) DeepCopyInto(out *PersistenceAgent) {
+	*out = *in
+	if in.TTLSecondsAfterWorkflowFinish != nil {
+		in, out := &in.TTLSecondsAfterWorkflowFinish, &out.TTLSecondsAfterWorkflowFinish
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(Scheduling)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceAgent.
+func (in *PersistenceAgent) DeepCopy() *PersistenceAgent {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistenceAgent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineResources) DeepCopyInto(out *PipelineResources) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineResources.
+func (in *PipelineResources) DeepCopy() *PipelineResources {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Placement) DeepCopyInto(out *Placement) {
+	*out = *in
+	if in.TopologyKeys != nil {
+		in, out := &in.TopologyKeys, &out.TopologyKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Placement.
+func (in *Placement) DeepCopy() *Placement {
+	if in == nil {
+		return nil
+	}
+	out := new(Placement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileIntervals) DeepCopyInto(out *ReconcileIntervals) {
+	*out = *in
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HealthCheckPeriod != nil {
+		in, out := &in.HealthCheckPeriod, &out.HealthCheckPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileIntervals.
+func (in *ReconcileIntervals) DeepCopy() *ReconcileIntervals {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileIntervals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRequirements) DeepCopyInto(out *ResourceRequirements) {
+	*out = *in
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(Resources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = new(Resources)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRequirements.
+func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	if in == nil {
 		return nil
 	}
-	out := new(DSPAStatus)
+	out := new(ResourceRequirements)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataSciencePipelinesApplication) DeepCopyInto(out *DataSciencePipelinesApplication) {
+func (in *Resources) DeepCopyInto(out *Resources) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.CPU = in.CPU.DeepCopy()
+	out.Memory = in.Memory.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSciencePipelinesApplication.
-func (in *DataSciencePipelinesApplication) DeepCopy() *DataSciencePipelinesApplication {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resources.
+func (in *Resources) DeepCopy() *Resources {
 	if in == nil {
 		return nil
 	}
-	out := new(DataSciencePipelinesApplication)
+	out := new(Resources)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataSciencePipelinesApplication) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataSciencePipelinesApplicationList) DeepCopyInto(out *DataSciencePipelinesApplicationList) {
+func (in *RouteTLS) DeepCopyInto(out *RouteTLS) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]DataSciencePipelinesApplication, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSciencePipelinesApplicationList.
-func (in *DataSciencePipelinesApplicationList) DeepCopy() *DataSciencePipelinesApplicationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteTLS.
+func (in *RouteTLS) DeepCopy() *RouteTLS {
 	if in == nil {
 		return nil
 	}
-	out := new(DataSciencePipelinesApplicationList)
+	out := new(RouteTLS)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataSciencePipelinesApplicationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Database) DeepCopyInto(out *Database) {
+func (in *RunRetryPolicy) DeepCopyInto(out *RunRetryPolicy) {
 	*out = *in
-	if in.MariaDB != nil {
-		in, out := &in.MariaDB, &out.MariaDB
-		*out = new(MariaDB)
-		(*in).DeepCopyInto(*out)
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
 	}
-	if in.ExternalDB != nil {
-		in, out := &in.ExternalDB, &out.ExternalDB
-		*out = new(ExternalDB)
-		(*in).DeepCopyInto(*out)
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(v1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Database.
-func (in *Database) DeepCopy() *Database {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunRetryPolicy.
+func (in *RunRetryPolicy) DeepCopy() *RunRetryPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(Database)
+	out := new(RunRetryPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Envoy) DeepCopyInto(out *Envoy) {
+func (in *RunStatusWebhook) DeepCopyInto(out *RunStatusWebhook) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.SigningSecret != nil {
+		in, out := &in.SigningSecret, &out.SigningSecret
+		*out = new(SecretKeyValue)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Envoy.
-func (in *Envoy) DeepCopy() *Envoy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunStatusWebhook.
+func (in *RunStatusWebhook) DeepCopy() *RunStatusWebhook {
 	if in == nil {
 		return nil
 	}
-	out := new(Envoy)
+	out := new(RunStatusWebhook)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalDB) DeepCopyInto(out *ExternalDB) {
+func (in *RunStatusWebhookStatus) DeepCopyInto(out *RunStatusWebhookStatus) {
 	*out = *in
-	if in.PasswordSecret != nil {
-		in, out := &in.PasswordSecret, &out.PasswordSecret
-		*out = new(SecretKeyValue)
-		**out = **in
-	}
+	in.Since.DeepCopyInto(&out.Since)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDB.
-func (in *ExternalDB) DeepCopy() *ExternalDB {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunStatusWebhookStatus.
+func (in *RunStatusWebhookStatus) DeepCopy() *RunStatusWebhookStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalDB)
+	out := new(RunStatusWebhookStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalStorage) DeepCopyInto(out *ExternalStorage) {
+func (in *S3CredentialSecret) DeepCopyInto(out *S3CredentialSecret) {
 	*out = *in
-	if in.S3CredentialSecret != nil {
-		in, out := &in.S3CredentialSecret, &out.S3CredentialSecret
-		*out = new(S3CredentialSecret)
-		**out = **in
-	}
-	if in.Secure != nil {
-		in, out := &in.Secure, &out.Secure
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalStorage.
-func (in *ExternalStorage) DeepCopy() *ExternalStorage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3CredentialSecret.
+func (in *S3CredentialSecret) DeepCopy() *S3CredentialSecret {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalStorage)
+	out := new(S3CredentialSecret)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GRPC) DeepCopyInto(out *GRPC) {
+func (in *SchedulePolicy) DeepCopyInto(out *SchedulePolicy) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.BlackoutWindows != nil {
+		in, out := &in.BlackoutWindows, &out.BlackoutWindows
+		*out = make([]BlackoutWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPC.
-func (in *GRPC) DeepCopy() *GRPC {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulePolicy.
+func (in *SchedulePolicy) DeepCopy() *SchedulePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(GRPC)
+	out := new(SchedulePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MLMD) DeepCopyInto(out *MLMD) {
+func (in *ScheduledWorkflow) DeepCopyInto(out *ScheduledWorkflow) {
 	*out = *in
-	if in.Envoy != nil {
-		in, out := &in.Envoy, &out.Envoy
-		*out = new(Envoy)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.GRPC != nil {
-		in, out := &in.GRPC, &out.GRPC
-		*out = new(GRPC)
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Writer != nil {
-		in, out := &in.Writer, &out.Writer
-		*out = new(Writer)
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(Scheduling)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MLMD.
-func (in *MLMD) DeepCopy() *MLMD {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledWorkflow.
+func (in *ScheduledWorkflow) DeepCopy() *ScheduledWorkflow {
 	if in == nil {
 		return nil
 	}
-	out := new(MLMD)
+	out := new(ScheduledWorkflow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MariaDB) DeepCopyInto(out *MariaDB) {
+func (in *Scheduling) DeepCopyInto(out *Scheduling) {
 	*out = *in
-	if in.PasswordSecret != nil {
-		in, out := &in.PasswordSecret, &out.PasswordSecret
-		*out = new(SecretKeyValue)
-		**out = **in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	out.PVCSize = in.PVCSize.DeepCopy()
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ResourceRequirements)
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MariaDB.
-func (in *MariaDB) DeepCopy() *MariaDB {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scheduling.
+func (in *Scheduling) DeepCopy() *Scheduling {
 	if in == nil {
 		return nil
 	}
-	out := new(MariaDB)
+	out := new(Scheduling)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Minio) DeepCopyInto(out *Minio) {
+func (in *SeccompProfile) DeepCopyInto(out *SeccompProfile) {
 	*out = *in
-	if in.S3CredentialSecret != nil {
-		in, out := &in.S3CredentialSecret, &out.S3CredentialSecret
-		*out = new(S3CredentialSecret)
-		**out = **in
-	}
-	out.PVCSize = in.PVCSize.DeepCopy()
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Minio.
-func (in *Minio) DeepCopy() *Minio {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeccompProfile.
+func (in *SeccompProfile) DeepCopy() *SeccompProfile {
 	if in == nil {
 		return nil
 	}
-	out := new(Minio)
+	out := new(SeccompProfile)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MlPipelineUI) DeepCopyInto(out *MlPipelineUI) {
+func (in *SecretKeyValue) DeepCopyInto(out *SecretKeyValue) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MlPipelineUI.
-func (in *MlPipelineUI) DeepCopy() *MlPipelineUI {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyValue.
+func (in *SecretKeyValue) DeepCopy() *SecretKeyValue {
 	if in == nil {
 		return nil
 	}
-	out := new(MlPipelineUI)
+	out := new(SecretKeyValue)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectStorage) DeepCopyInto(out *ObjectStorage) {
+func (in *SecurityProfiles) DeepCopyInto(out *SecurityProfiles) {
 	*out = *in
-	if in.Minio != nil {
-		in, out := &in.Minio, &out.Minio
-		*out = new(Minio)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ExternalStorage != nil {
-		in, out := &in.ExternalStorage, &out.ExternalStorage
-		*out = new(ExternalStorage)
-		(*in).DeepCopyInto(*out)
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(SeccompProfile)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStorage.
-func (in *ObjectStorage) DeepCopy() *ObjectStorage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityProfiles.
+func (in *SecurityProfiles) DeepCopy() *SecurityProfiles {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectStorage)
+	out := new(SecurityProfiles)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistenceAgent) DeepCopyInto(out *PersistenceAgent) {
+func (in *ServicePort) DeepCopyInto(out *ServicePort) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceAgent.
-func (in *PersistenceAgent) DeepCopy() *PersistenceAgent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServicePort.
+func (in *ServicePort) DeepCopy() *ServicePort {
 	if in == nil {
 		return nil
 	}
-	out := new(PersistenceAgent)
+	out := new(ServicePort)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceRequirements) DeepCopyInto(out *ResourceRequirements) {
+func (in *StatusOutputs) DeepCopyInto(out *StatusOutputs) {
 	*out = *in
-	if in.Limits != nil {
-		in, out := &in.Limits, &out.Limits
-		*out = new(Resources)
-		(*in).DeepCopyInto(*out)
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = new(OutputEndpoints)
+		**out = **in
 	}
-	if in.Requests != nil {
-		in, out := &in.Requests, &out.Requests
-		*out = new(Resources)
+	if in.CredentialsSecretRefs != nil {
+		in, out := &in.CredentialsSecretRefs, &out.CredentialsSecretRefs
+		*out = new(CredentialsSecretRefs)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(OutputVersion)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRequirements.
-func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusOutputs.
+func (in *StatusOutputs) DeepCopy() *StatusOutputs {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceRequirements)
+	out := new(StatusOutputs)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Resources) DeepCopyInto(out *Resources) {
+func (in *StepExitHandler) DeepCopyInto(out *StepExitHandler) {
 	*out = *in
-	out.CPU = in.CPU.DeepCopy()
-	out.Memory = in.Memory.DeepCopy()
+	if in.NotifyWebhooks != nil {
+		in, out := &in.NotifyWebhooks, &out.NotifyWebhooks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resources.
-func (in *Resources) DeepCopy() *Resources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepExitHandler.
+func (in *StepExitHandler) DeepCopy() *StepExitHandler {
 	if in == nil {
 		return nil
 	}
-	out := new(Resources)
+	out := new(StepExitHandler)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3CredentialSecret) DeepCopyInto(out *S3CredentialSecret) {
+func (in *ThreeScaleGateway) DeepCopyInto(out *ThreeScaleGateway) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3CredentialSecret.
-func (in *S3CredentialSecret) DeepCopy() *S3CredentialSecret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreeScaleGateway.
+func (in *ThreeScaleGateway) DeepCopy() *ThreeScaleGateway {
 	if in == nil {
 		return nil
 	}
-	out := new(S3CredentialSecret)
+	out := new(ThreeScaleGateway)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledWorkflow) DeepCopyInto(out *ScheduledWorkflow) {
+func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledWorkflow.
-func (in *ScheduledWorkflow) DeepCopy() *ScheduledWorkflow {
-	if in == nil {
-		return nil
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
-	out := new(ScheduledWorkflow)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretKeyValue) DeepCopyInto(out *SecretKeyValue) {
-	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyValue.
-func (in *SecretKeyValue) DeepCopy() *SecretKeyValue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStrategy.
+func (in *UpdateStrategy) DeepCopy() *UpdateStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretKeyValue)
+	out := new(UpdateStrategy)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -574,6 +2028,16 @@ func (in *Writer) DeepCopyInto(out *Writer) {
 		*out = new(ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SecurityProfiles != nil {
+		in, out := &in.SecurityProfiles, &out.SecurityProfiles
+		*out = new(SecurityProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Writer.
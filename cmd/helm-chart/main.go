@@ -0,0 +1,235 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// helm-chart generates a Helm chart of the operator from its kustomize base, for clusters installing the operator
+// without OLM. The parameters of the base, config/base/params.env, are the values of the chart, and the operator is
+// installed in the namespace of the release.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	chartName = "data-science-pipelines-operator"
+	// paramPlaceholder wraps the name of the parameters in the manifests kustomize builds for the chart
+	paramPlaceholder = "__HELM_PARAM_%s__"
+	// namespacePlaceholder is the namespace of the manifests kustomize builds for the chart
+	namespacePlaceholder = "helm-release-namespace"
+)
+
+var (
+	// templateDelimiters are the delimiters of Helm templates found in the manifests, e.g. in PrometheusRules
+	templateDelimiters = regexp.MustCompile(`{{|}}`)
+	// wholeScalarParam is a parameter placeholder making up a whole scalar, e.g. the value of an env var
+	wholeScalarParam = regexp.MustCompile(`(?m)((?:^|:|-)[ \t]+)__HELM_PARAM_(\w+)__[ \t]*$`)
+	embeddedParam    = regexp.MustCompile(`__HELM_PARAM_(\w+)__`)
+	// optionalAPIs are installed only on clusters serving them
+	optionalAPIs = map[string]bool{"monitoring.coreos.com/v1": true}
+)
+
+// param is a parameter of the kustomize base, in the order of params.env
+type param struct {
+	name, value string
+}
+
+func main() {
+	kustomize := flag.String("kustomize", "kustomize", "The kustomize binary building the manifests.")
+	base := flag.String("base", "config/base", "The kustomize base of the operator.")
+	output := flag.String("output", "dist/chart", "The directory to write the chart to.")
+	version := flag.String("version", "0.0.1", "The version of the chart and of the operator.")
+	flag.Parse()
+
+	if err := run(*kustomize, *base, *output, *version); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to generate the Helm chart: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(kustomize, base, output, version string) error {
+	params, err := readParams(filepath.Join(base, "params.env"))
+	if err != nil {
+		return err
+	}
+	manifests, err := build(kustomize, base, params)
+	if err != nil {
+		return err
+	}
+	files, err := newChart(manifests, params, version)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(output); err != nil {
+		return err
+	}
+	for name, content := range files {
+		path := filepath.Join(output, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readParams(path string) ([]param, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var params []param
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid parameter [%s] in %s", line, path)
+		}
+		params = append(params, param{name: name, value: value})
+	}
+	return params, scanner.Err()
+}
+
+// build builds the kustomize base through an overlay replacing its parameters and namespace with placeholders
+func build(kustomize, base string, params []param) ([]byte, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return nil, err
+	}
+	overlay, err := os.MkdirTemp("", "helm-chart-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(overlay)
+
+	var kustomization strings.Builder
+	fmt.Fprintf(&kustomization, "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\n")
+	fmt.Fprintf(&kustomization, "namespace: %s\nresources:\n  - %s\n", namespacePlaceholder, absBase)
+	fmt.Fprintf(&kustomization, "configMapGenerator:\n  - name: dspo-parameters\n    behavior: replace\n    literals:\n")
+	for _, p := range params {
+		fmt.Fprintf(&kustomization, "      - %s="+paramPlaceholder+"\n", p.name, p.name)
+	}
+	err = os.WriteFile(filepath.Join(overlay, "kustomization.yaml"), []byte(kustomization.String()), 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(kustomize, "build", overlay)
+	cmd.Stderr = &stderr
+	manifests, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("kustomize build failed: %w: %s", err, stderr.String())
+	}
+	return manifests, nil
+}
+
+// newChart converts the manifests kustomize built into the files of the chart. CustomResourceDefinitions are
+// installed from the crds directory, the other manifests are templates of the parameters and release namespace.
+func newChart(manifests []byte, params []param, version string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	used := map[string]bool{}
+	for _, doc := range strings.Split(string(manifests), "\n---\n") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		meta := struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		}{}
+		if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+			return nil, err
+		}
+		if meta.Kind == "" || meta.Metadata.Name == "" {
+			return nil, fmt.Errorf("manifest without kind or name:\n%s", doc)
+		}
+		fileName := fmt.Sprintf("%s_%s.yaml", strings.ToLower(meta.Kind), meta.Metadata.Name)
+		content := strings.TrimPrefix(doc, "---\n")
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+
+		if meta.Kind == "CustomResourceDefinition" {
+			if embeddedParam.MatchString(content) || strings.Contains(content, namespacePlaceholder) {
+				return nil, fmt.Errorf("CustomResourceDefinition [%s] can't be parameterized", meta.Metadata.Name)
+			}
+			files["crds/"+fileName] = []byte(content)
+			continue
+		}
+
+		for _, match := range embeddedParam.FindAllStringSubmatch(content, -1) {
+			used[match[1]] = true
+		}
+		content = templatize(content)
+		if optionalAPIs[meta.APIVersion] {
+			content = fmt.Sprintf("{{- if .Capabilities.APIVersions.Has %q }}\n%s{{- end }}\n", meta.APIVersion, content)
+		}
+		files["templates/"+fileName] = []byte(content)
+	}
+
+	var values strings.Builder
+	values.WriteString("# Generated by cmd/helm-chart from config/base/params.env, do not edit.\n")
+	values.WriteString("# The parameters of the operator, as for its kustomize base.\n")
+	values.WriteString("parameters:\n")
+	for _, p := range params {
+		if !used[p.name] {
+			return nil, fmt.Errorf("parameter [%s] isn't used by any manifest", p.name)
+		}
+		fmt.Fprintf(&values, "  %s: %s\n", p.name, strconv.Quote(p.value))
+	}
+	files["values.yaml"] = []byte(values.String())
+	files["Chart.yaml"] = []byte(fmt.Sprintf(`apiVersion: v2
+name: %s
+description: Data Science Pipelines Operator, generated by cmd/helm-chart from config/base.
+type: application
+version: %s
+appVersion: %s
+`, chartName, version, strconv.Quote(version)))
+	return files, nil
+}
+
+// templatize escapes the template delimiters already in the manifest, and replaces its placeholders with the values
+// of the chart and the release namespace. Parameters making up a whole scalar are quoted, so that values such as
+// "false" or "10" stay strings, e.g. for env vars.
+func templatize(content string) string {
+	content = templateDelimiters.ReplaceAllStringFunc(content, func(delimiter string) string {
+		return fmt.Sprintf("{{%q}}", delimiter)
+	})
+	content = wholeScalarParam.ReplaceAllString(content, "${1}{{ .Values.parameters.${2} | quote }}")
+	content = embeddedParam.ReplaceAllString(content, "{{ .Values.parameters.${1} }}")
+	return strings.ReplaceAll(content, namespacePlaceholder, "{{ .Release.Namespace }}")
+}
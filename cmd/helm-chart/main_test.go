@@ -0,0 +1,142 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+// testManifests is a sample of the kustomize build of the overlay over config/base
+const testManifests = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: datasciencepipelinesapplications.datasciencepipelinesapplications.opendatahub.io
+spec:
+  group: datasciencepipelinesapplications.opendatahub.io
+---
+apiVersion: v1
+data:
+  DETAILED_METRICS: __HELM_PARAM_DETAILED_METRICS__
+  IMAGES_DSPO: __HELM_PARAM_IMAGES_DSPO__
+kind: ConfigMap
+metadata:
+  name: data-science-pipelines-operator-dspo-parameters-5c7d4f
+  namespace: helm-release-namespace
+---
+apiVersion: v1
+data:
+  config.yaml: |
+    Images:
+      ApiServer: __HELM_PARAM_IMAGES_DSPO__
+kind: ConfigMap
+metadata:
+  name: data-science-pipelines-operator-dspo-config-8g2b7m
+  namespace: helm-release-namespace
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: data-science-pipelines-operator-controller-manager
+  namespace: helm-release-namespace
+spec:
+  template:
+    spec:
+      containers:
+      - args:
+        - --detailed-metrics=$(DETAILED_METRICS)
+        - --image=__HELM_PARAM_IMAGES_DSPO__
+        env:
+        - name: DETAILED_METRICS
+          value: __HELM_PARAM_DETAILED_METRICS__
+        image: __HELM_PARAM_IMAGES_DSPO__
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: data-science-pipelines-operator-rules
+  namespace: helm-release-namespace
+spec:
+  groups:
+  - rules:
+    - annotations:
+        summary: Run status updates are lagging for DSPA {{ $labels.dspa_name }}
+`
+
+func TestNewChart(t *testing.T) {
+	params := []param{{name: "DETAILED_METRICS", value: "false"}, {name: "IMAGES_DSPO", value: "quay.io/dspo:1.0"}}
+	files, err := newChart([]byte(testManifests), params, "1.2.0")
+	assert.Nil(t, err)
+	assert.Len(t, files, 7)
+	assert.NotContains(t, string(files["crds/customresourcedefinition_datasciencepipelinesapplications.datasciencepipelinesapplications.opendatahub.io.yaml"]), "{{")
+	assert.Contains(t, string(files["Chart.yaml"]), "version: 1.2.0\n")
+
+	// Ensure the default values are those of params.env
+	values := map[string]map[string]string{}
+	assert.Nil(t, yaml.Unmarshal(files["values.yaml"], &values))
+	assert.Equal(t, map[string]string{"DETAILED_METRICS": "false", "IMAGES_DSPO": "quay.io/dspo:1.0"}, values["parameters"])
+
+	// Ensure the templates render the values and release namespace, and keep the template delimiters of the manifests
+	render := func(name string, apis ...string) map[string]interface{} {
+		tmpl, err := template.New(name).Funcs(template.FuncMap{"quote": strconv.Quote}).Parse(string(files[name]))
+		assert.Nil(t, err, name)
+		var out strings.Builder
+		assert.Nil(t, tmpl.Execute(&out, map[string]interface{}{
+			"Values":       map[string]interface{}{"parameters": values["parameters"]},
+			"Release":      map[string]interface{}{"Namespace": "dspo"},
+			"Capabilities": map[string]interface{}{"APIVersions": testAPIVersions(apis)},
+		}), name)
+		manifest := map[string]interface{}{}
+		assert.Nil(t, yaml.Unmarshal([]byte(out.String()), &manifest), name)
+		return manifest
+	}
+
+	deployment := render("templates/deployment_data-science-pipelines-operator-controller-manager.yaml")
+	assert.Equal(t, "dspo", deployment["metadata"].(map[string]interface{})["namespace"])
+	container := deployment["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "quay.io/dspo:1.0", container["image"])
+	assert.Equal(t, []interface{}{"--detailed-metrics=$(DETAILED_METRICS)", "--image=quay.io/dspo:1.0"}, container["args"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "DETAILED_METRICS", "value": "false"}}, container["env"])
+
+	config := render("templates/configmap_data-science-pipelines-operator-dspo-config-8g2b7m.yaml")
+	assert.Equal(t, "Images:\n  ApiServer: \"quay.io/dspo:1.0\"\n", config["data"].(map[string]interface{})["config.yaml"])
+
+	rules := render("templates/prometheusrule_data-science-pipelines-operator-rules.yaml", "monitoring.coreos.com/v1")
+	assert.Contains(t, rules["spec"].(map[string]interface{})["groups"].([]interface{})[0].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})["annotations"].(map[string]interface{})["summary"], "DSPA {{ $labels.dspa_name }}")
+	assert.Empty(t, render("templates/prometheusrule_data-science-pipelines-operator-rules.yaml"))
+
+	// Ensure parameters no manifest uses are rejected, for the chart to follow config/base
+	_, err = newChart([]byte(testManifests), append(params, param{name: "UNUSED", value: "x"}), "1.2.0")
+	assert.NotNil(t, err)
+}
+
+type testAPIVersions []string
+
+func (a testAPIVersions) Has(apiVersion string) bool {
+	for _, api := range a {
+		if api == apiVersion {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// immutableFields are the fields of the kinds DSPO deploys that can't be changed on an existing resource, so a
+// resource setting them differently can't be adopted
+var immutableFields = map[string][][]string{
+	"Deployment":            {{"spec", "selector"}},
+	"PersistentVolumeClaim": {{"spec", "accessModes"}, {"spec", "storageClassName"}},
+}
+
+// adoptingClient guards the updates of manifestival to resources the DSPA doesn't own. Manifestival updates any live
+// resource with the name of a manifest, so without it resources created by hand, e.g. by a manual KFP install, would
+// be silently overwritten and taken over.
+type adoptingClient struct {
+	client.Client
+	log   logr.Logger
+	adopt bool
+	live  map[string]*unstructured.Unstructured
+}
+
+func (c *adoptingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	if u, ok := obj.(*unstructured.Unstructured); ok && err == nil {
+		c.live[liveKey(u)] = u.DeepCopy()
+	}
+	return err
+}
+
+func (c *adoptingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+	live, found := c.live[liveKey(u)]
+	// Manifests applied with an owner are controlled by their DSPA
+	owner := metav1.GetControllerOf(u)
+	if !found || owner == nil || ownedBy(live, owner) {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+
+	if !c.adopt {
+		return fmt.Errorf("%s [%s] already exists and isn't managed by DSPA [%s], delete it, or set "+
+			"spec.adoptExistingResources for the DSPA to take it over", u.GetKind(), u.GetName(), owner.Name)
+	}
+	if conflict := adoptionConflict(live, u); conflict != "" {
+		return fmt.Errorf("%s [%s] already exists and can't be adopted by DSPA [%s]: %s", u.GetKind(), u.GetName(), owner.Name, conflict)
+	}
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.log.Info(fmt.Sprintf("Adopted existing %s [%s]", u.GetKind(), u.GetName()))
+	return nil
+}
+
+func ownedBy(u *unstructured.Unstructured, owner *metav1.OwnerReference) bool {
+	for _, ref := range u.GetOwnerReferences() {
+		if ref.UID == owner.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// adoptionConflict describes why the live resource can't be taken over by the manifest, or returns "" if it can.
+func adoptionConflict(live, manifest *unstructured.Unstructured) string {
+	for _, ref := range live.GetOwnerReferences() {
+		if ref.Controller != nil && *ref.Controller {
+			return fmt.Sprintf("it is controlled by %s [%s]", ref.Kind, ref.Name)
+		}
+	}
+	for _, field := range immutableFields[manifest.GetKind()] {
+		want, found, err := unstructured.NestedFieldNoCopy(manifest.Object, field...)
+		if err != nil || !found {
+			continue
+		}
+		have, _, _ := unstructured.NestedFieldNoCopy(live.Object, field...)
+		if !equality.Semantic.DeepEqual(want, have) {
+			return fmt.Sprintf("its immutable field %s differs from the DSPA's, %v instead of %v", strings.Join(field, "."), have, want)
+		}
+	}
+	return ""
+}
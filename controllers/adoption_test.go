@@ -0,0 +1,87 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestAdoptExistingResources(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		TypeMeta:   metav1.TypeMeta{APIVersion: dspav1alpha1.GroupVersion.String(), Kind: "DataSciencePipelinesApplication"},
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace", UID: "testdspa-uid"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// A Deployment left by a manual install, whose immutable selector differs from the DSPA's
+	existing := func(selector map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-testdspa", Namespace: "testnamespace"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: selector},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: selector},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "ml-pipeline-api-server", Image: "someimage"}}},
+				},
+			},
+		}
+	}
+	deployment := existing(map[string]string{"app": "ml-pipeline"})
+	assert.Nil(t, reconciler.Create(ctx, deployment))
+
+	// Ensure resources the DSPA doesn't own aren't overwritten unless adoption is enabled
+	err := reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.ErrorContains(t, err, "Deployment [ds-pipeline-testdspa] already exists and isn't managed by DSPA [testdspa]")
+
+	// Ensure incompatible resources aren't adopted
+	dspa.Spec.AdoptExistingResources = true
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	err = reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.ErrorContains(t, err, "can't be adopted by DSPA [testdspa]: its immutable field spec.selector differs from the DSPA's")
+
+	// Ensure compatible resources are taken over, along with the labels of the DSPA
+	assert.Nil(t, reconciler.Delete(ctx, deployment))
+	assert.Nil(t, reconciler.Create(ctx, existing(map[string]string{
+		"app": "ds-pipeline-testdspa", "component": "data-science-pipelines", "dspa": "testdspa",
+	})))
+	assert.Nil(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	adopted := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-testdspa", Namespace: "testnamespace"}, adopted))
+	assert.Equal(t, types.UID("testdspa-uid"), adopted.OwnerReferences[0].UID)
+	assert.Equal(t, "testdspa", adopted.Labels["dspa"])
+
+	// Ensure resources controlled by another object aren't adopted
+	adopted.OwnerReferences[0].UID = "otherdspa-uid"
+	adopted.OwnerReferences[0].Name = "otherdspa"
+	assert.Nil(t, reconciler.Update(ctx, adopted))
+	err = reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.ErrorContains(t, err, "it is controlled by DataSciencePipelinesApplication [otherdspa]")
+}
@@ -0,0 +1,151 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// apiGatewayTemplates are the resources registering the DSP API with each gateway provider
+var apiGatewayTemplates = map[string][]string{
+	"Kong": {
+		"api-gateway/kong-auth-plugin.yaml.tmpl",
+		"api-gateway/kong-ingress.yaml.tmpl",
+	},
+	"ThreeScale": {
+		"api-gateway/threescale-backend.yaml.tmpl",
+		"api-gateway/threescale-product.yaml.tmpl",
+	},
+}
+
+// apiGatewayKinds are the kinds of the resources of apiGatewayTemplates, deleted when the DSP API is no longer
+// registered with the provider
+var apiGatewayKinds = map[string][]schema.GroupVersionKind{
+	"Kong": {
+		{Group: "configuration.konghq.com", Version: "v1", Kind: "KongPlugin"},
+		{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+	},
+	"ThreeScale": {
+		{Group: "capabilities.3scale.net", Version: "v1beta1", Kind: "Backend"},
+		{Group: "capabilities.3scale.net", Version: "v1beta1", Kind: "Product"},
+	},
+}
+
+const apiGatewayDefaultResourceNamePrefix = "ds-pipeline-gateway-"
+
+// UsingAPIGateway returns true if the DSPA registers its API Server with an API gateway.
+func (p *DSPAParams) UsingAPIGateway(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	return dsp.Spec.APIGateway != nil && dsp.Spec.APIServer != nil && dsp.Spec.APIServer.Deploy
+}
+
+// SetupAPIGateway populates the defaults of the API gateway registration.
+func (p *DSPAParams) SetupAPIGateway() error {
+	p.APIGatewayDefaultResourceName = config.DerivedName(apiGatewayDefaultResourceNamePrefix, p.Name)
+	p.APIGatewayAuthPluginName = config.DerivedName("ds-pipeline-gateway-auth-", p.Name)
+	if p.APIGateway == nil {
+		return nil
+	}
+	if p.APIGateway.Auth == nil {
+		p.APIGateway.Auth = &dspav1alpha1.APIGatewayAuth{}
+	}
+	setStringDefault("APIKey", &p.APIGateway.Auth.Type)
+
+	switch p.APIGateway.Provider {
+	case "Kong":
+		if p.APIGateway.Auth.Type != "APIKey" {
+			return fmt.Errorf("apiGateway %s authentication is only supported with 3scale, configure a Kong OIDC plugin in kong.plugins instead", p.APIGateway.Auth.Type)
+		}
+		if p.APIGateway.Kong == nil {
+			p.APIGateway.Kong = &dspav1alpha1.KongGateway{}
+		}
+		setStringDefault("kong", &p.APIGateway.Kong.IngressClassName)
+		p.APIGatewayKongPlugins = strings.Join(append([]string{p.APIGatewayAuthPluginName}, p.APIGateway.Kong.Plugins...), ",")
+	case "ThreeScale":
+		if p.APIGateway.ThreeScale == nil {
+			return fmt.Errorf("apiGateway provider ThreeScale requires threeScale.providerAccountSecretName")
+		}
+		if p.APIGateway.Auth.Type == "OIDC" && p.APIGateway.Auth.IssuerEndpointSecretName == "" {
+			return fmt.Errorf("apiGateway OIDC authentication requires issuerEndpointSecretName")
+		}
+		// System names are unique within a 3scale tenant, which serves the DSPAs of every namespace
+		p.APIGatewaySystemName = fmt.Sprintf("dspa-%s-%s", p.Namespace, p.Name)
+	default:
+		return fmt.Errorf("unknown apiGateway provider [%s]", p.APIGateway.Provider)
+	}
+	return nil
+}
+
+func (r *DSPAReconciler) ReconcileAPIGateway(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	provider := ""
+	if params.UsingAPIGateway(dsp) {
+		provider = params.APIGateway.Provider
+	}
+	// Deregister the DSP API from the gateways it's no longer registered with
+	for otherProvider := range apiGatewayTemplates {
+		if otherProvider == provider {
+			continue
+		}
+		if err := r.deleteAPIGatewayResources(ctx, dsp, params, otherProvider); err != nil {
+			return err
+		}
+	}
+	if provider == "" {
+		log.Info("Skipping Application of API Gateway Resources")
+		return nil
+	}
+
+	log.Info(fmt.Sprintf("Applying %s API Gateway Resources", provider))
+	for _, template := range apiGatewayTemplates[provider] {
+		err := r.Apply(dsp, params, template)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteAPIGatewayResources deletes the resources registering the DSP API with the provider. Providers whose CRDs
+// aren't installed on the cluster have nothing to delete.
+func (r *DSPAReconciler) deleteAPIGatewayResources(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams, provider string) error {
+
+	for _, gvk := range apiGatewayKinds[provider] {
+		name := params.APIGatewayDefaultResourceName
+		if gvk.Kind == "KongPlugin" {
+			name = params.APIGatewayAuthPluginName
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		err := r.DeleteResourceIfItExists(ctx, obj, types.NamespacedName{Name: name, Namespace: dsp.Namespace})
+		if err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func getAPIGatewayResource(ctx context.Context, t *testing.T, reconciler *DSPAReconciler, gvk schema.GroupVersionKind, name string) (*unstructured.Unstructured, bool) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	created, err := reconciler.IsResourceCreated(ctx, obj, name, "testnamespace")
+	assert.Nil(t, err)
+	return obj, created
+}
+
+func TestDeployAPIGateway(t *testing.T) {
+	expectedName := "ds-pipeline-gateway-testdspa"
+	ingressGVK := schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+	pluginGVK := schema.GroupVersionKind{Group: "configuration.konghq.com", Version: "v1", Kind: "KongPlugin"}
+	productGVK := schema.GroupVersionKind{Group: "capabilities.3scale.net", Version: "v1beta1", Kind: "Product"}
+	backendGVK := schema.GroupVersionKind{Group: "capabilities.3scale.net", Version: "v1beta1", Kind: "Backend"}
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{Deploy: true},
+			APIGateway: &dspav1alpha1.APIGateway{
+				Provider:   "Kong",
+				Host:       "pipelines.api.example.com",
+				Namespaces: []string{"kong"},
+				Kong:       &dspav1alpha1.KongGateway{Plugins: []string{"rate-limit"}},
+			},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileAPIGateway(ctx, dspa, params))
+
+	// Ensure Kong routes the host to the API Server's oauth port, with API key authentication and the extra plugins
+	ingress, created := getAPIGatewayResource(ctx, t, reconciler, ingressGVK, expectedName)
+	assert.True(t, created)
+	assert.Equal(t, "ds-pipeline-gateway-auth-testdspa,rate-limit", ingress.GetAnnotations()["konghq.com/plugins"])
+	className, _, _ := unstructured.NestedString(ingress.Object, "spec", "ingressClassName")
+	assert.Equal(t, "kong", className)
+	rules, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "rules")
+	assert.Equal(t, "pipelines.api.example.com", rules[0].(map[string]interface{})["host"])
+	paths, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "http", "paths")
+	port, _, _ := unstructured.NestedString(paths[0].(map[string]interface{}), "backend", "service", "port", "name")
+	assert.Equal(t, "oauth", port)
+	assert.Nil(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	service := &corev1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-testdspa", Namespace: dspa.Namespace}, service))
+	assert.Equal(t, "https", service.Annotations["konghq.com/protocol"])
+	plugin, created := getAPIGatewayResource(ctx, t, reconciler, pluginGVK, "ds-pipeline-gateway-auth-testdspa")
+	assert.True(t, created)
+	assert.Equal(t, "key-auth", plugin.Object["plugin"])
+
+	// Ensure the API Server admits the gateway namespaces to its oauth port
+	assert.Nil(t, reconciler.ReconcileCommon(dspa, params))
+	policy := &networkingv1.NetworkPolicy{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipelines-testdspa", Namespace: dspa.Namespace}, policy))
+	gatewayRule := policy.Spec.Ingress[1]
+	assert.Equal(t, "kong", gatewayRule.From[0].NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"])
+	assert.Equal(t, int32(8443), gatewayRule.Ports[0].Port.IntVal)
+
+	// Ensure switching to 3scale registers the API Server there with OIDC, and deregisters it from Kong
+	dspa.Spec.APIGateway.Provider = "ThreeScale"
+	dspa.Spec.APIGateway.ThreeScale = &dspav1alpha1.ThreeScaleGateway{ProviderAccountSecretName: "threescale-tenant"}
+	dspa.Spec.APIGateway.Auth = &dspav1alpha1.APIGatewayAuth{Type: "OIDC", IssuerEndpointSecretName: "keycloak-issuer"}
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileAPIGateway(ctx, dspa, params))
+	_, created = getAPIGatewayResource(ctx, t, reconciler, ingressGVK, expectedName)
+	assert.False(t, created)
+	_, created = getAPIGatewayResource(ctx, t, reconciler, pluginGVK, "ds-pipeline-gateway-auth-testdspa")
+	assert.False(t, created)
+	backend, created := getAPIGatewayResource(ctx, t, reconciler, backendGVK, expectedName)
+	assert.True(t, created)
+	privateBaseURL, _, _ := unstructured.NestedString(backend.Object, "spec", "privateBaseURL")
+	assert.Equal(t, "https://ds-pipeline-testdspa.testnamespace.svc.cluster.local:8443", privateBaseURL)
+	product, created := getAPIGatewayResource(ctx, t, reconciler, productGVK, expectedName)
+	assert.True(t, created)
+	systemName, _, _ := unstructured.NestedString(product.Object, "spec", "systemName")
+	assert.Equal(t, "dspa-testnamespace-testdspa", systemName)
+	issuer, _, _ := unstructured.NestedString(product.Object, "spec", "deployment", "apicastSelfManaged", "authentication", "oidc", "issuerEndpointRef", "name")
+	assert.Equal(t, "keycloak-issuer", issuer)
+	publicBaseURL, _, _ := unstructured.NestedString(product.Object, "spec", "deployment", "apicastSelfManaged", "productionPublicBaseURL")
+	assert.Equal(t, "https://pipelines.api.example.com", publicBaseURL)
+
+	// Ensure the API Server is deregistered once the gateway is removed
+	dspa.Spec.APIGateway = nil
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileAPIGateway(ctx, dspa, params))
+	_, created = getAPIGatewayResource(ctx, t, reconciler, productGVK, expectedName)
+	assert.False(t, created)
+	_, created = getAPIGatewayResource(ctx, t, reconciler, backendGVK, expectedName)
+	assert.False(t, created)
+}
@@ -0,0 +1,112 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	clientmetrics "k8s.io/client-go/tools/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// APIStats counts the Kubernetes API requests of a reconcile of the DSPA, it's carried by the context of the reconcile
+// with detailed metrics
+type APIStats struct {
+	name, namespace string
+	requests        int64
+}
+
+type apiStatsKey struct{}
+
+func withAPIStats(ctx context.Context, stats *APIStats) context.Context {
+	return context.WithValue(ctx, apiStatsKey{}, stats)
+}
+
+func apiStatsFrom(ctx context.Context) *APIStats {
+	stats, _ := ctx.Value(apiStatsKey{}).(*APIStats)
+	return stats
+}
+
+// instrumentAPIClient hooks the API metrics into the client-go metrics of the rest clients, whose requests carry the
+// context they're issued with. Controller-runtime already registered the client-go metrics, so the hooks are set
+// directly, wrapping those registered.
+func instrumentAPIClient() {
+	clientmetrics.RequestResult = &apiRequestsResult{next: clientmetrics.RequestResult}
+	clientmetrics.RateLimiterLatency = &apiThrottleLatency{next: clientmetrics.RateLimiterLatency}
+}
+
+// apiRequestsResult counts the results of the API requests issued while reconciling a DSPA
+type apiRequestsResult struct {
+	next clientmetrics.ResultMetric
+}
+
+func (r *apiRequestsResult) Increment(ctx context.Context, code, method, host string) {
+	r.next.Increment(ctx, code, method, host)
+	if stats := apiStatsFrom(ctx); stats != nil {
+		atomic.AddInt64(&stats.requests, 1)
+		APIRequestsMetric.WithLabelValues(stats.name, stats.namespace, method, code).Inc()
+	}
+}
+
+// apiThrottleLatency sums the time the API requests issued while reconciling a DSPA waited on the rate limiter
+type apiThrottleLatency struct {
+	next clientmetrics.LatencyMetric
+}
+
+func (l *apiThrottleLatency) Observe(ctx context.Context, verb string, u url.URL, latency time.Duration) {
+	l.next.Observe(ctx, verb, u, latency)
+	if stats := apiStatsFrom(ctx); stats != nil {
+		APIThrottleMetric.WithLabelValues(stats.name, stats.namespace).Add(latency.Seconds())
+	}
+}
+
+// apiStatsClient issues its requests with the API stats of a reconcile, for manifestival which issues them without
+// the context of the reconcile
+type apiStatsClient struct {
+	client.Client
+	stats *APIStats
+}
+
+func (c *apiStatsClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return c.Client.Get(withAPIStats(ctx, c.stats), key, obj, opts...)
+}
+
+func (c *apiStatsClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.Client.Create(withAPIStats(ctx, c.stats), obj, opts...)
+}
+
+func (c *apiStatsClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.Client.Update(withAPIStats(ctx, c.stats), obj, opts...)
+}
+
+func (c *apiStatsClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.Client.Delete(withAPIStats(ctx, c.stats), obj, opts...)
+}
+
+// observeApply times the apply of the manifests of the template with detailed metrics
+func observeApply(params *DSPAParams, template string, manifest mf.Manifest) error {
+	if params.APIStats == nil {
+		return manifest.Apply()
+	}
+	start := time.Now()
+	err := manifest.Apply()
+	ApplyDurationMetric.WithLabelValues(params.APIStats.name, params.APIStats.namespace, template).Observe(time.Since(start).Seconds())
+	return err
+}
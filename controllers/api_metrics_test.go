@@ -0,0 +1,100 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	clientmetrics "k8s.io/client-go/tools/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// apiStatsRecordingClient records the API stats of the context of its requests
+type apiStatsRecordingClient struct {
+	client.Client
+	stats []*APIStats
+}
+
+func (c *apiStatsRecordingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.stats = append(c.stats, apiStatsFrom(ctx))
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *apiStatsRecordingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.stats = append(c.stats, apiStatsFrom(ctx))
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+type noopResultMetric struct{}
+
+func (noopResultMetric) Increment(context.Context, string, string, string) {}
+
+type noopLatencyMetric struct{}
+
+func (noopLatencyMetric) Observe(context.Context, string, url.URL, time.Duration) {}
+
+func TestAPIMetrics(t *testing.T) {
+	stats := &APIStats{name: "apimetrics", namespace: "testnamespace"}
+	ctx := withAPIStats(context.Background(), stats)
+
+	// Ensure the requests and throttling of a reconcile are counted for its DSPA only
+	result := &apiRequestsResult{next: noopResultMetric{}}
+	result.Increment(ctx, "200", "GET", "api")
+	result.Increment(ctx, "409", "PUT", "api")
+	result.Increment(context.Background(), "200", "GET", "api")
+	assert.Equal(t, int64(2), stats.requests)
+	assert.Equal(t, float64(1), testutil.ToFloat64(APIRequestsMetric.WithLabelValues("apimetrics", "testnamespace", "PUT", "409")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(APIRequestsMetric.WithLabelValues("apimetrics", "testnamespace", "GET", "200")))
+
+	latency := &apiThrottleLatency{next: noopLatencyMetric{}}
+	latency.Observe(ctx, "GET", url.URL{}, 1500*time.Millisecond)
+	latency.Observe(context.Background(), "GET", url.URL{}, time.Second)
+	assert.Equal(t, 1.5, testutil.ToFloat64(APIThrottleMetric.WithLabelValues("apimetrics", "testnamespace")))
+
+	// Ensure the manifests are applied with the API stats of the reconcile, and their apply is timed
+	_, params, reconciler := CreateNewTestObjects()
+	recorder := &apiStatsRecordingClient{Client: reconciler.Client}
+	reconciler.Client = recorder
+	reconciler.TemplatesPath = t.TempDir() + "/"
+	template := "configmap.yaml.tmpl"
+	content := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test-{{.Name}}\n  namespace: {{.Namespace}}\n"
+	assert.Nil(t, os.WriteFile(filepath.Join(reconciler.TemplatesPath, template), []byte(content), 0644))
+	params.Name, params.Namespace, params.APIStats = "apimetrics", "testnamespace", stats
+	assert.Nil(t, reconciler.ApplyWithoutOwner(params, template))
+	assert.NotEmpty(t, recorder.stats)
+	for _, recorded := range recorder.stats {
+		assert.Same(t, stats, recorded)
+	}
+	assert.Equal(t, 1, testutil.CollectAndCount(ApplyDurationMetric, "data_science_pipelines_application_apply_duration_seconds"))
+
+	// Ensure the client-go metrics registered by controller-runtime are still updated once instrumented
+	requestResult, rateLimiterLatency := clientmetrics.RequestResult, clientmetrics.RateLimiterLatency
+	t.Cleanup(func() {
+		clientmetrics.RequestResult, clientmetrics.RateLimiterLatency = requestResult, rateLimiterLatency
+	})
+	instrumentAPIClient()
+	assert.Same(t, requestResult, clientmetrics.RequestResult.(*apiRequestsResult).next)
+	assert.Equal(t, rateLimiterLatency, clientmetrics.RateLimiterLatency.(*apiThrottleLatency).next)
+}
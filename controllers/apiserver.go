@@ -17,15 +17,23 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 
 	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
 	v1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 const apiServerDefaultResourceNamePrefix = "ds-pipeline-"
 
+// apiServerConfigChecksumAnnotation is set on the API Server pod template, so config changes trigger a rolling update
+const apiServerConfigChecksumAnnotation = "datasciencepipelinesapplications.opendatahub.io/config-checksum"
+
 var apiServerTemplates = []string{
 	"apiserver/artifact_script.yaml.tmpl",
 	"apiserver/role_ds-pipeline.yaml.tmpl",
@@ -61,6 +69,12 @@ func (r *DSPAReconciler) ReconcileAPIServer(ctx context.Context, dsp *dspav1alph
 
 	log.Info("Applying APIServer Resources")
 
+	checksum, err := r.apiServerConfigChecksum(ctx, params)
+	if err != nil {
+		return err
+	}
+	params.APIServerConfigChecksum = checksum
+
 	for _, template := range apiServerTemplates {
 		err := r.Apply(dsp, params, template)
 		if err != nil {
@@ -68,20 +82,30 @@ func (r *DSPAReconciler) ReconcileAPIServer(ctx context.Context, dsp *dspav1alph
 		}
 	}
 
-	if dsp.Spec.APIServer.EnableRoute {
+	if params.APIServer.EnableRoute {
 		err := r.Apply(dsp, params, serverRoute)
 		if err != nil {
 			return err
 		}
 	} else {
 		route := &v1.Route{}
-		namespacedNamed := types.NamespacedName{Name: "ds-pipeline-" + dsp.Name, Namespace: dsp.Namespace}
+		namespacedNamed := types.NamespacedName{Name: config.DerivedRouteName(apiServerDefaultResourceNamePrefix, dsp.Name, dsp.Namespace), Namespace: dsp.Namespace}
 		err := r.DeleteResourceIfItExists(ctx, route, namespacedNamed)
 		if err != nil {
 			return err
 		}
 	}
 
+	err = r.ReconcileAPIServerGRPC(ctx, dsp, params)
+	if err != nil {
+		return err
+	}
+
+	err = r.ReconcileAPIGateway(ctx, dsp, params)
+	if err != nil {
+		return err
+	}
+
 	for cmName, template := range samplePipelineTemplates {
 		if dsp.Spec.APIServer.EnableSamplePipeline {
 			err := r.Apply(dsp, params, template)
@@ -90,7 +114,7 @@ func (r *DSPAReconciler) ReconcileAPIServer(ctx context.Context, dsp *dspav1alph
 			}
 		} else {
 			cm := &corev1.ConfigMap{}
-			namespacedNamed := types.NamespacedName{Name: cmName + "-" + dsp.Name, Namespace: dsp.Namespace}
+			namespacedNamed := types.NamespacedName{Name: config.DerivedName(cmName+"-", dsp.Name), Namespace: dsp.Namespace}
 			err := r.DeleteResourceIfItExists(ctx, cm, namespacedNamed)
 			if err != nil {
 				return err
@@ -101,3 +125,42 @@ func (r *DSPAReconciler) ReconcileAPIServer(ctx context.Context, dsp *dspav1alph
 	log.Info("Finished applying APIServer Resources")
 	return nil
 }
+
+// apiServerConfigChecksum hashes the ConfigMaps and CA bundle the API Server reads on startup, including the artifact
+// script ConfigMap provided by the user in place of the generated one. The API Server has no way to reload them in
+// place, so changing the checksum rolls out new pods, surging before old ones are removed.
+func (r *DSPAReconciler) apiServerConfigChecksum(ctx context.Context, params *DSPAParams) (string, error) {
+	templates := []string{"apiserver/artifact_script.yaml.tmpl"}
+	if params.APIServer.EnableSamplePipeline {
+		templates = append(templates, samplePipelineTemplates["sample-config"], samplePipelineTemplates["sample-pipeline"])
+	}
+
+	hash := sha256.New()
+	for _, template := range templates {
+		tmplManifest, err := config.Manifest(r.Client, r.templateFile(template), params)
+		if err != nil {
+			return "", err
+		}
+		for _, u := range tmplManifest.Resources() {
+			data, err := json.Marshal(u.Object["data"])
+			if err != nil {
+				return "", err
+			}
+			hash.Write(data)
+		}
+	}
+
+	// A user-provided artifact script that doesn't exist yet is hashed as empty: the ConfigMap is watched, so its
+	// creation triggers a reconcile rolling out the API Server once more
+	artifactScript := params.APIServer.ArtifactScriptConfigMap
+	if artifactScript.Name != config.DerivedName(config.ArtifactScriptConfigMapNamePrefix, params.Name) {
+		cm := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Name: artifactScript.Name, Namespace: params.Namespace}, cm)
+		if err != nil && !apierrs.IsNotFound(err) {
+			return "", err
+		}
+		hash.Write([]byte(cm.Data[artifactScript.Key]))
+	}
+	hash.Write(params.APICustomPemCerts)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,137 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var apiServerGRPCTemplates = []string{
+	"apiserver/grpc-envoy.configmap.yaml.tmpl",
+	"apiserver/grpc-envoy.service.yaml.tmpl",
+	"apiserver/grpc-envoy.deployment.yaml.tmpl",
+	"apiserver/grpc-route.yaml.tmpl",
+}
+
+const apiServerGRPCDefaultResourceNamePrefix = "ds-pipeline-grpc-"
+
+// APIServerGRPCProxy holds the settings of the Envoy proxy exposing the API Server's gRPC API, rendered in its
+// ConfigMap.
+type APIServerGRPCProxy struct {
+	Deploy              bool
+	DefaultResourceName string
+	ConfigMapName       string
+	TLSSecretName       string
+	JWKSHost            string
+	JWKSPort            string
+	JWKSTLS             bool
+	JWKSCABundle        string
+}
+
+// UsingAPIServerGRPC returns true if the DSPA exposes the gRPC API of its API Server outside the cluster.
+func (p *DSPAParams) UsingAPIServerGRPC(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	return dsp.Spec.APIServer != nil && dsp.Spec.APIServer.Deploy && dsp.Spec.APIServer.GRPC != nil && dsp.Spec.APIServer.GRPC.Deploy
+}
+
+// SetupAPIServerGRPC populates the defaults of the gRPC proxy. The proxy always terminates TLS, as the router only
+// speaks HTTP/2 to backends over TLS.
+func (p *DSPAParams) SetupAPIServerGRPC() error {
+	p.APIServerGRPCProxy = APIServerGRPCProxy{
+		DefaultResourceName: config.DerivedName(apiServerGRPCDefaultResourceNamePrefix, p.Name),
+		ConfigMapName:       config.DerivedName("ds-pipeline-grpc-config-", p.Name),
+		TLSSecretName:       config.DerivedName("ds-pipeline-grpc-tls-", p.Name),
+	}
+	if p.APIServer == nil || p.APIServer.GRPC == nil || !p.APIServer.GRPC.Deploy {
+		return nil
+	}
+	grpc := p.APIServer.GRPC
+	if grpc.JWT == nil {
+		return fmt.Errorf("apiServer grpc requires jwt authentication, the API Server doesn't authenticate gRPC requests itself")
+	}
+	// The API Server doesn't authorize gRPC requests either, any token the issuer grants would reach it
+	if len(grpc.JWT.Audiences) == 0 {
+		return fmt.Errorf("apiServer grpc jwt requires audiences, tokens of the issuer for other applications would otherwise be accepted")
+	}
+	if grpc.Termination == "" {
+		grpc.Termination = "Reencrypt"
+	}
+	if grpc.Termination == "Passthrough" && (grpc.TLS == nil || grpc.Host == "") {
+		return fmt.Errorf("apiServer grpc Passthrough termination requires a tls certificate and a host, the clients verify the proxy's certificate")
+	}
+	if err := p.setImageDefault(config.MlmdEnvoyImagePath, &grpc.Image); err != nil {
+		return err
+	}
+	setResourcesDefault(config.MlmdEnvoyResourceRequirements, &grpc.Resources)
+	if grpc.TLS != nil {
+		p.APIServerGRPCProxy.TLSSecretName = grpc.TLS.SecretName
+	}
+
+	host, port, tls, err := parseJWKSURI(grpc.JWT.JWKSURI)
+	if err != nil {
+		return fmt.Errorf("apiServer grpc %w", err)
+	}
+	p.APIServerGRPCProxy.JWKSHost = host
+	p.APIServerGRPCProxy.JWKSPort = port
+	p.APIServerGRPCProxy.JWKSTLS = tls
+	p.APIServerGRPCProxy.JWKSCABundle = config.MlmdEnvoyCABundlePath
+	p.APIServerGRPCProxy.Deploy = p.APIServer.Deploy
+	return nil
+}
+
+// ReconcileAPIServerGRPC applies the gRPC proxy and its Route, or removes them once the gRPC API is no longer exposed.
+func (r *DSPAReconciler) ReconcileAPIServerGRPC(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	if !params.UsingAPIServerGRPC(dsp) {
+		proxy := params.APIServerGRPCProxy
+		resources := []struct {
+			obj  client.Object
+			name string
+		}{
+			{&routev1.Route{}, config.DerivedRouteName(apiServerGRPCDefaultResourceNamePrefix, dsp.Name, dsp.Namespace)},
+			{&appsv1.Deployment{}, proxy.DefaultResourceName},
+			{&corev1.Service{}, proxy.DefaultResourceName},
+			{&corev1.ConfigMap{}, proxy.ConfigMapName},
+		}
+		for _, resource := range resources {
+			err := r.DeleteResourceIfItExists(ctx, resource.obj, types.NamespacedName{Name: resource.name, Namespace: dsp.Namespace})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	log.Info("Applying APIServer gRPC Resources")
+	for _, template := range apiServerGRPCTemplates {
+		err := r.Apply(dsp, params, template)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
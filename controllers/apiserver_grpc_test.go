@@ -0,0 +1,133 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDeployAPIServerGRPC(t *testing.T) {
+	expectedName := "ds-pipeline-grpc-testdspa"
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy: true,
+				GRPC: &dspav1alpha1.APIServerGRPC{
+					Deploy: true,
+					JWT: &dspav1alpha1.EnvoyJWT{
+						Issuer:    "https://keycloak.example.com/realms/ml",
+						JWKSURI:   "https://keycloak.example.com/realms/ml/protocol/openid-connect/certs",
+						Audiences: []string{"kfp"},
+					},
+				},
+			},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileAPIServerGRPC(ctx, dspa, params))
+
+	// Ensure the proxy authenticates requests and forwards them to the gRPC port of the API Server
+	configMap := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-grpc-config-testdspa", Namespace: dspa.Namespace}, configMap))
+	envoyConfig := configMap.Data["envoy.yaml"]
+	assert.Contains(t, envoyConfig, `issuer: "https://keycloak.example.com/realms/ml"`)
+	assert.Contains(t, envoyConfig, `- "kfp"`)
+	assert.Contains(t, envoyConfig, `address: "keycloak.example.com", port_value: 443`)
+	assert.Contains(t, envoyConfig, `address: "ds-pipeline-testdspa", port_value: 8887`)
+	assert.Contains(t, envoyConfig, `alpn_protocols: ["h2"]`)
+
+	// Ensure the proxy serves a service CA certificate, which the router trusts when reencrypting
+	service := &v1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedName, Namespace: dspa.Namespace}, service))
+	assert.Equal(t, "ds-pipeline-grpc-tls-testdspa", service.Annotations["service.beta.openshift.io/serving-cert-secret-name"])
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedName, Namespace: dspa.Namespace}, deployment))
+	assert.Equal(t, "ds-pipeline-grpc-tls-testdspa", deployment.Spec.Template.Spec.Volumes[1].Secret.SecretName)
+	route := &routev1.Route{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedName, Namespace: dspa.Namespace}, route))
+	assert.Equal(t, routev1.TLSTerminationType("Reencrypt"), route.Spec.TLS.Termination)
+	assert.Equal(t, expectedName, GetDerivedNames(dspa, params).APIServerGRPCRoute)
+
+	// Ensure the API Server admits the proxy to its gRPC port only
+	assert.Nil(t, reconciler.ReconcileCommon(dspa, params))
+	policy := &networkingv1.NetworkPolicy{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipelines-testdspa", Namespace: dspa.Namespace}, policy))
+	proxyRule := policy.Spec.Ingress[len(policy.Spec.Ingress)-1]
+	assert.Equal(t, expectedName, proxyRule.From[0].PodSelector.MatchLabels["app"])
+	assert.Len(t, proxyRule.Ports, 1)
+	assert.Equal(t, int32(8887), proxyRule.Ports[0].Port.IntVal)
+
+	// Ensure Passthrough serves the provided certificate for the Route host
+	dspa.Spec.APIServer.GRPC.Termination = "Passthrough"
+	dspa.Spec.APIServer.GRPC.Host = "kfp-grpc.apps.example.com"
+	dspa.Spec.APIServer.GRPC.TLS = &dspav1alpha1.APIServerGRPCTLS{SecretName: "kfp-grpc-cert"}
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileAPIServerGRPC(ctx, dspa, params))
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedName, Namespace: dspa.Namespace}, route))
+	assert.Equal(t, routev1.TLSTerminationType("Passthrough"), route.Spec.TLS.Termination)
+	assert.Equal(t, "kfp-grpc.apps.example.com", route.Spec.Host)
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedName, Namespace: dspa.Namespace}, deployment))
+	assert.Equal(t, "kfp-grpc-cert", deployment.Spec.Template.Spec.Volumes[1].Secret.SecretName)
+
+	// Ensure the gRPC API is no longer exposed once disabled
+	dspa.Spec.APIServer.GRPC.Deploy = false
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileAPIServerGRPC(ctx, dspa, params))
+	created, err := reconciler.IsResourceCreated(ctx, &routev1.Route{}, expectedName, dspa.Namespace)
+	assert.False(t, created)
+	assert.Nil(t, err)
+	created, err = reconciler.IsResourceCreated(ctx, &appsv1.Deployment{}, expectedName, dspa.Namespace)
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
+
+func TestAPIServerGRPCRequiresAuthentication(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy: true,
+				GRPC:   &dspav1alpha1.APIServerGRPC{Deploy: true},
+			},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.ErrorContains(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log), "requires jwt authentication")
+
+	// Ensure tokens the issuer grants to other applications aren't accepted
+	dspa.Spec.APIServer.GRPC.JWT = &dspav1alpha1.EnvoyJWT{
+		Issuer:  "https://keycloak.example.com/realms/ml",
+		JWKSURI: "https://keycloak.example.com/realms/ml/protocol/openid-connect/certs",
+	}
+	assert.ErrorContains(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log), "requires audiences")
+}
@@ -22,8 +22,10 @@ import (
 	"testing"
 
 	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestDeployAPIServer(t *testing.T) {
@@ -119,3 +121,195 @@ func TestDontDeployAPIServer(t *testing.T) {
 	assert.False(t, created)
 	assert.Nil(t, err)
 }
+
+func TestDeployAPIServerWithArchitecture(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedAPIServerName := apiServerDefaultResourceNamePrefix + testDSPAName
+	t.Cleanup(viper.Reset)
+
+	// Construct DSPASpec with deployed APIServer pinned to arm64
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy: true,
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+					Image:  "mariadb-arm64",
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+			Architecture: "arm64",
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	// Ensure params extraction fails while no arm64 API Server image is configured
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.ErrorContains(t, err, config.ArchitectureImagePath("arm64", config.OAuthProxyImagePath))
+
+	// Configure arm64 images
+	for _, imagePath := range []string{config.OAuthProxyImagePath, config.APIServerImagePath, config.APIServerArtifactImagePath,
+		config.APIServerCacheImagePath, config.APIServerMoveResultsImagePath} {
+		viper.Set(config.ArchitectureImagePath("arm64", imagePath), "arm64-image")
+	}
+	params = &DSPAParams{}
+	err = params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+	assert.Equal(t, "arm64-image", params.APIServer.Image)
+	assert.Equal(t, []string{"arm64"}, params.Architectures)
+
+	// Run test reconciliation
+	err = reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.Nil(t, err)
+
+	// Assert APIServer Deployment is pinned to arm64 nodes
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, expectedAPIServerName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	terms := deployment.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	assert.Equal(t, []string{"arm64"}, terms[0].MatchExpressions[0].Values)
+}
+
+func TestDeployAPIServerWithDrain(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedAPIServerName := apiServerDefaultResourceNamePrefix + testDSPAName
+
+	// Construct DSPASpec with a drain period exceeding the termination grace period
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy:                        true,
+				TerminationGracePeriodSeconds: 30,
+				PreStopDrainSeconds:           30,
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	// Ensure params extraction fails
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.ErrorContains(t, err, "preStopDrainSeconds")
+
+	// Shorten the drain period
+	dspa.Spec.APIServer.PreStopDrainSeconds = 10
+	params = &DSPAParams{}
+	err = params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Run test reconciliation
+	err = reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.Nil(t, err)
+
+	// Assert APIServer Deployment drains before termination
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, expectedAPIServerName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30), *deployment.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		assert.Equal(t, []string{"/bin/sh", "-c", "sleep 10"}, container.Lifecycle.PreStop.Exec.Command)
+	}
+}
+
+func TestAPIServerConfigChecksum(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedAPIServerName := apiServerDefaultResourceNamePrefix + testDSPAName
+
+	// Construct DSPASpec with deployed APIServer
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy: true,
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Run test reconciliation
+	err = reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.Nil(t, err)
+
+	// Assert APIServer Deployment pod template carries the config checksum
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, expectedAPIServerName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	checksum := deployment.Spec.Template.Annotations[apiServerConfigChecksumAnnotation]
+	assert.Equal(t, params.APIServerConfigChecksum, checksum)
+	assert.Equal(t, int32(0), deployment.Spec.Strategy.RollingUpdate.MaxUnavailable.IntVal)
+
+	// Assert the checksum only changes with the API Server's config
+	unchanged, err := reconciler.apiServerConfigChecksum(ctx, params)
+	assert.Nil(t, err)
+	assert.Equal(t, checksum, unchanged)
+	params.APIServer.EnableSamplePipeline = true
+	changed, err := reconciler.apiServerConfigChecksum(ctx, params)
+	assert.Nil(t, err)
+	assert.NotEqual(t, checksum, changed)
+
+	// Assert the checksum changes with the content of a user-provided artifact script
+	params.APIServer.ArtifactScriptConfigMap = &dspav1alpha1.ArtifactScriptConfigMap{Name: "artifact-script", Key: "artifact_script"}
+	missing, err := reconciler.apiServerConfigChecksum(ctx, params)
+	assert.Nil(t, err)
+	artifactScript := &corev1.ConfigMap{Data: map[string]string{"artifact_script": "#!/usr/bin/env sh"}}
+	artifactScript.Name = "artifact-script"
+	artifactScript.Namespace = testNamespace
+	assert.Nil(t, reconciler.Create(ctx, artifactScript))
+	provided, err := reconciler.apiServerConfigChecksum(ctx, params)
+	assert.Nil(t, err)
+	assert.NotEqual(t, missing, provided)
+	artifactScript.Data["artifact_script"] = "#!/usr/bin/env bash"
+	assert.Nil(t, reconciler.Update(ctx, artifactScript))
+	updated, err := reconciler.apiServerConfigChecksum(ctx, params)
+	assert.Nil(t, err)
+	assert.NotEqual(t, provided, updated)
+}
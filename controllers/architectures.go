@@ -0,0 +1,70 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	mf "github.com/manifestival/manifestival"
+	dspa "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SetupArchitecture determines the node architectures DSPA components are scheduled on. Scheduling is only
+// restricted when opted into: if the DSPA pins an architecture, only that architecture is used, and otherwise the
+// architectures listed in the operator config, if any.
+func (p *DSPAParams) SetupArchitecture(dsp *dspa.DataSciencePipelinesApplication) {
+	p.Architecture = dsp.Spec.Architecture
+	if p.Architecture != "" {
+		p.Architectures = []string{p.Architecture}
+	} else {
+		p.Architectures = config.GetStringSliceConfigWithDefault(config.ArchitecturesConfigName, nil)
+	}
+}
+
+// injectArchitectures requires the pods of component Deployments and DaemonSets to run on nodes of the
+// architectures the DSPA is restricted to. It runs before injectScheduling, which merges the affinity of a
+// component's Scheduling into the one added here.
+func injectArchitectures(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if len(params.Architectures) == 0 || (u.GetKind() != "Deployment" && u.GetKind() != "DaemonSet") {
+			return nil
+		}
+		affinity := &corev1.Affinity{}
+		rendered, found, err := unstructured.NestedMap(u.Object, "spec", "template", "spec", "affinity")
+		if err != nil {
+			return err
+		}
+		if found {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rendered, affinity); err != nil {
+				return err
+			}
+		}
+		mergeAffinity(affinity, &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: params.Architectures},
+				}}},
+			},
+		}})
+		merged, err := runtime.DefaultUnstructuredConverter.ToUnstructured(affinity)
+		if err != nil {
+			return err
+		}
+		return unstructured.SetNestedMap(u.Object, merged, "spec", "template", "spec", "affinity")
+	}
+}
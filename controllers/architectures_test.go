@@ -0,0 +1,66 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeployWithArchitectures(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedPersistenceAgentName := persistenceAgentDefaultResourceNamePrefix + testDSPAName
+	t.Cleanup(func() { viper.Set(config.ArchitecturesConfigName, nil) })
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: testDSPAName, Namespace: testNamespace},
+		Spec: dspav1alpha1.DSPASpec{
+			PersistenceAgent: &dspav1alpha1.PersistenceAgent{Deploy: true},
+			Database:         &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage:    &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	deployPersistenceAgent := func() *appsv1.Deployment {
+		ctx, params, reconciler := CreateNewTestObjects()
+		assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+		assert.Nil(t, reconciler.ReconcilePersistenceAgent(dspa, params))
+		deployment := &appsv1.Deployment{}
+		created, err := reconciler.IsResourceCreated(ctx, deployment, expectedPersistenceAgentName, testNamespace)
+		assert.True(t, created)
+		assert.Nil(t, err)
+		return deployment
+	}
+
+	// Assert components are scheduled on any architecture by default
+	assert.Nil(t, deployPersistenceAgent().Spec.Template.Spec.Affinity)
+
+	// Assert the architectures of the operator config are required once configured
+	viper.Set(config.ArchitecturesConfigName, []string{"amd64", "arm64"})
+	affinity := deployPersistenceAgent().Spec.Template.Spec.Affinity
+	assert.Equal(t, []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+		{Key: "kubernetes.io/arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"amd64", "arm64"}},
+	}}}, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
@@ -0,0 +1,108 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	artifactRetentionPrefix   = "ds-pipeline-artifact-retention-"
+	artifactRetentionTemplate = "artifact-retention/cronjob.yaml.tmpl"
+)
+
+// ArtifactRetention is the CronJob deleting the expired artifacts and step logs of a DSPA, under artifacts/ of its
+// bucket
+type ArtifactRetention struct {
+	Schedule string
+	// Age past which artifacts are deleted, in seconds, 0 if they don't expire
+	MaxAgeSeconds int64
+	// Total size past which the oldest artifacts are deleted, in bytes, 0 if unbounded
+	MaxSizeBytes int64
+	// Image providing the aws CLI, the artifact image of the API Server
+	Image    string
+	CABundle *dspav1alpha1.CABundle
+}
+
+// SetupArtifactRetention validates the artifact retention policy of the DSPA, once the connection to its object
+// storage is set up.
+func (p *DSPAParams) SetupArtifactRetention(dsp *dspav1alpha1.DataSciencePipelinesApplication) error {
+	p.ArtifactRetention = nil
+	if dsp.Spec.ObjectStorage == nil || dsp.Spec.ObjectStorage.ArtifactRetention == nil {
+		return nil
+	}
+	policy := dsp.Spec.ObjectStorage.ArtifactRetention
+	if policy.MaxAge == nil && policy.MaxSize == nil {
+		return fmt.Errorf("spec.objectStorage.artifactRetention requires maxAge, maxSize or both")
+	}
+
+	retention := &ArtifactRetention{Schedule: policy.Schedule}
+	if retention.Schedule == "" {
+		retention.Schedule = config.DefaultArtifactRetentionSchedule
+	}
+	if policy.MaxAge != nil {
+		if policy.MaxAge.Duration < time.Hour {
+			return fmt.Errorf("spec.objectStorage.artifactRetention.maxAge must be at least 1h, got [%s]", policy.MaxAge.Duration)
+		}
+		retention.MaxAgeSeconds = int64(policy.MaxAge.Duration.Seconds())
+	}
+	if policy.MaxSize != nil {
+		if policy.MaxSize.Sign() <= 0 {
+			return fmt.Errorf("spec.objectStorage.artifactRetention.maxSize must be greater than 0, got [%s]", policy.MaxSize.String())
+		}
+		retention.MaxSizeBytes = policy.MaxSize.Value()
+	}
+
+	if p.APIServer != nil {
+		retention.Image = p.APIServer.ArtifactImage
+		retention.CABundle = p.APIServer.CABundle
+	}
+	if err := p.setImageDefault(config.APIServerArtifactImagePath, &retention.Image); err != nil {
+		return err
+	}
+	p.ArtifactRetention = retention
+	return nil
+}
+
+// ReconcileArtifactRetention applies the CronJob enforcing the artifact retention policy of the DSPA, and deletes it
+// once no policy is set. Each run deletes the objects under artifacts/ of the bucket older than maxAge, then the oldest
+// remaining ones while their total size exceeds maxSize. Pipelines uploaded to the API Server, stored next to them in
+// the bucket, are kept.
+func (r *DSPAReconciler) ReconcileArtifactRetention(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	if params.ArtifactRetention == nil {
+		log.Info("Skipping Application of Artifact Retention Resources")
+		nn := types.NamespacedName{Name: config.DerivedName(artifactRetentionPrefix, dsp.Name), Namespace: dsp.Namespace}
+		return r.DeleteResourceIfItExists(ctx, &batchv1.CronJob{}, nn)
+	}
+
+	log.Info("Applying Artifact Retention Resources")
+	err := r.Apply(dsp, params, artifactRetentionTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Artifact Retention Resources")
+	return nil
+}
@@ -0,0 +1,86 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeployArtifactRetention(t *testing.T) {
+	expectedCronJobName := "ds-pipeline-artifact-retention-testdspa"
+	maxSize := resource.MustParse("500Gi")
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{Deploy: true},
+			Database:  &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{Deploy: true, Image: "someimage"},
+				ArtifactRetention: &dspav1alpha1.ArtifactRetention{
+					MaxAge:  &metav1.Duration{Duration: 30 * 24 * time.Hour},
+					MaxSize: &maxSize,
+				},
+			},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// Ensure the CronJob deletes the artifacts older than maxAge, then the oldest ones past maxSize
+	assert.Nil(t, reconciler.ReconcileArtifactRetention(ctx, dspa, params))
+	cronJob := &batchv1.CronJob{}
+	created, err := reconciler.IsResourceCreated(ctx, cronJob, expectedCronJobName, "testnamespace")
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, "0 3 * * *", cronJob.Spec.Schedule)
+	container := cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+	script := container.Command[2]
+	assert.Contains(t, script, "--bucket mlpipeline --prefix artifacts/")
+	assert.Contains(t, script, "$(date +%s) - 2592000")
+	assert.Contains(t, script, "-v max=536870912000")
+	assert.Equal(t, "ds-pipeline-s3-testdspa", container.Env[0].ValueFrom.SecretKeyRef.Name)
+
+	// Ensure only the configured limits are enforced
+	dspa.Spec.ObjectStorage.ArtifactRetention.MaxSize = nil
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileArtifactRetention(ctx, dspa, params))
+	created, err = reconciler.IsResourceCreated(ctx, cronJob, expectedCronJobName, "testnamespace")
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.NotContains(t, cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command[2], "-v max=")
+
+	// Ensure policies without limits are reported
+	dspa.Spec.ObjectStorage.ArtifactRetention.MaxAge = nil
+	err = params.SetupArtifactRetention(dspa)
+	assert.ErrorContains(t, err, "requires maxAge, maxSize or both")
+
+	// Ensure the CronJob is removed once the policy is
+	dspa.Spec.ObjectStorage.ArtifactRetention = nil
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileArtifactRetention(ctx, dspa, params))
+	created, err = reconciler.IsResourceCreated(ctx, &batchv1.CronJob{}, expectedCronJobName, "testnamespace")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
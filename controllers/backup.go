@@ -0,0 +1,278 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations requesting and tracking the restore of a backup of a DSPA
+const (
+	backupAnnotationPrefix = "backup.datasciencepipelinesapplications.opendatahub.io/"
+	// Set on the DSPA to the id of the backup to restore
+	restoreFromAnnotation = backupAnnotationPrefix + "restore-from"
+	// Set on the DSPA to the outcome of the last restore request
+	restoreStatusAnnotation = backupAnnotationPrefix + "restore-status"
+	// Set on the restore Job to the id of the backup it restores
+	backupIDAnnotation = backupAnnotationPrefix + "backup-id"
+)
+
+const (
+	backupCronJobPrefix   = "ds-pipeline-backup-"
+	restoreJobPrefix      = "ds-pipeline-restore-"
+	backupCronJobTemplate = "backup/cronjob.yaml.tmpl"
+	restoreJobTemplate    = "backup/restore.job.yaml.tmpl"
+)
+
+// backupIDPattern matches the ids of the backups, the UTC time they were taken at
+var backupIDPattern = regexp.MustCompile(`^[0-9]{8}T[0-9]{6}Z$`)
+
+// Backups is the backup CronJob and restore Job of a DSPA, copying its MariaDB dump and Minio bucket to the
+// destination
+type Backups struct {
+	Schedule            string
+	Suspend             bool
+	DatabaseClientImage string
+	StorageClientImage  string
+	MariaDB             bool
+	Minio               bool
+	Destination         ObjectStorageConnection
+	// Size limit of the emptyDir the Jobs stage the backup in
+	ScratchSizeLimit string
+	// Path of the backups of the DSPA in the destination bucket
+	Path string
+	// Id of the backup the restore Job restores
+	RestoreFrom string
+	// Whether a restore was requested, scaling down the components writing to the database and object store
+	Restoring bool
+}
+
+// RestoringBackup returns true while the DSPA restores a backup, with the components writing to the database or object
+// store being restored scaled down: the API Server, Persistence Agent, Scheduled Workflow controller, MLMD gRPC server
+// and writer, and the Cache Server.
+func (p *DSPAParams) RestoringBackup() bool {
+	return p.Backups != nil && p.Backups.Restoring
+}
+
+// SetupBackups validates the backup configuration of the DSPA, once the connections to its database and object
+// storage are set up.
+func (p *DSPAParams) SetupBackups(dsp *dspav1alpha1.DataSciencePipelinesApplication) error {
+	p.Backups = nil
+	backup := dsp.Spec.Backup
+	if backup == nil {
+		return nil
+	}
+	if backup.Destination == nil || backup.Destination.ExternalStorage == nil || backup.Destination.S3CredentialSecret == nil {
+		return fmt.Errorf("spec.backup.destination requires a host, bucket, scheme and s3CredentialsSecret")
+	}
+	backups := &Backups{
+		Schedule:            backup.Schedule,
+		Suspend:             backup.Suspend,
+		DatabaseClientImage: backup.DatabaseClientImage,
+		StorageClientImage:  backup.StorageClientImage,
+		MariaDB:             p.UsingMariaDB(dsp),
+		Minio:               p.UsingMinio(dsp),
+		Path:                path.Join(backup.Destination.Prefix, dsp.Namespace, dsp.Name),
+	}
+	if backupID, requested := dsp.GetAnnotations()[restoreFromAnnotation]; requested {
+		backups.Restoring = backupIDPattern.MatchString(backupID)
+	}
+	if !backups.MariaDB && !backups.Minio {
+		return fmt.Errorf("spec.backup backs up the MariaDB and Minio deployed by the DSPA, which deploys neither, " +
+			"back up its external database and object storage with their own tooling instead")
+	}
+	if backups.Schedule == "" {
+		backups.Schedule = config.DefaultBackupSchedule
+	}
+	if backup.ScratchSizeLimit != nil {
+		backups.ScratchSizeLimit = backup.ScratchSizeLimit.String()
+	} else {
+		// The dump and the bucket can't outgrow the volumes they're copied from
+		scratch := resource.Quantity{}
+		if backups.MariaDB {
+			scratch.Add(p.MariaDB.PVCSize)
+		}
+		if backups.Minio {
+			scratch.Add(p.Minio.PVCSize)
+		}
+		if !scratch.IsZero() {
+			backups.ScratchSizeLimit = scratch.String()
+		}
+	}
+
+	destination := backup.Destination.ExternalStorage
+	backups.Destination = ObjectStorageConnection{
+		Bucket:            destination.Bucket,
+		CredentialsSecret: destination.S3CredentialSecret,
+		Host:              destination.Host,
+		Port:              destination.Port,
+		Scheme:            destination.Scheme,
+		Secure:            destination.Secure,
+		Endpoint:          fmt.Sprintf("%s://%s", destination.Scheme, hostPort(destination.Host, destination.Port)),
+	}
+	if backups.Destination.Secure == nil {
+		backups.Destination.Secure = util.BoolPointer(destination.Scheme == "https")
+	}
+	if backups.Minio && backups.Destination.Host == p.ObjectStorageConnection.Host {
+		return fmt.Errorf("spec.backup.destination must not be the Minio of the DSPA, whose backups would be lost along with it")
+	}
+
+	if backups.MariaDB && backups.DatabaseClientImage == "" {
+		backups.DatabaseClientImage = p.MariaDB.Image
+	}
+	if err := p.setImageDefault(config.APIServerArtifactImagePath, &backups.StorageClientImage); err != nil {
+		return err
+	}
+	p.Backups = backups
+	return nil
+}
+
+// ReconcileBackups applies the CronJob backing up the DSPA as configured in spec.backup, and deletes it once backups
+// are no longer configured. Each run dumps the MariaDB database and syncs the Minio bucket of the DSPA into an
+// emptyDir bounded by the ScratchSizeLimit, then copies them to <path>/<backup-id>/ of the destination bucket.
+func (r *DSPAReconciler) ReconcileBackups(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	if params.Backups == nil {
+		log.Info("Skipping Application of Backup Resources")
+		nn := types.NamespacedName{Name: config.DerivedName(backupCronJobPrefix, dsp.Name), Namespace: dsp.Namespace}
+		return r.DeleteResourceIfItExists(ctx, &batchv1.CronJob{}, nn)
+	}
+
+	log.Info("Applying Backup Resources")
+	err := r.Apply(dsp, params, backupCronJobTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Backup Resources")
+	return nil
+}
+
+// ReconcileRestore restores the backup requested through the restore-from annotation of the DSPA, with a Job
+// copying the backup from the destination of spec.backup back into its Minio bucket and MariaDB database. The Job only
+// starts once the components writing to them scaled down, see RestoringBackup, and they scale back up once the
+// request ends. The Job is kept once finished, for its logs, until the next restore is requested. Restores are
+// one-time: the request annotation is removed once the Job finished, and the outcome recorded in the restore-status
+// annotation.
+func (r *DSPAReconciler) ReconcileRestore(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	backupID, requested := dsp.GetAnnotations()[restoreFromAnnotation]
+	if !requested {
+		return nil
+	}
+	if params.Backups == nil {
+		return r.endRestoreRequest(ctx, dsp, "denied, the DSPA has no spec.backup to restore from")
+	}
+	if !backupIDPattern.MatchString(backupID) {
+		return r.endRestoreRequest(ctx, dsp, fmt.Sprintf("denied, [%s] is not a backup id, the UTC time of a backup, "+
+			"e.g. 20240102T020000Z", backupID))
+	}
+
+	jobName := config.DerivedName(restoreJobPrefix, dsp.Name)
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: dsp.Namespace}, job)
+	if apierrs.IsNotFound(err) {
+		scaledDown, err := r.restoreWritersScaledDown(ctx, dsp, params)
+		if err != nil || !scaledDown {
+			return err
+		}
+		params.Backups.RestoreFrom = backupID
+		if err := r.Apply(dsp, params, restoreJobTemplate); err != nil {
+			return err
+		}
+		log.Info("Restoring backup", "backupID", backupID)
+		return r.setRestoreStatus(ctx, dsp, fmt.Sprintf("restoring backup [%s]", backupID))
+	} else if err != nil {
+		return err
+	}
+
+	switch {
+	case job.GetAnnotations()[backupIDAnnotation] != backupID:
+		// The Job of an earlier restore, the reconcile triggered by its deletion starts the requested one
+		log.Info("Deleting the Job of an earlier restore", "job", jobName)
+		return client.IgnoreNotFound(r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+	case job.Status.Succeeded > 0:
+		completedAt := time.Now()
+		if job.Status.CompletionTime != nil {
+			completedAt = job.Status.CompletionTime.Time
+		}
+		log.Info("Restored backup", "backupID", backupID)
+		return r.endRestoreRequest(ctx, dsp, fmt.Sprintf("restored backup [%s] at %s", backupID, completedAt.UTC().Format(time.RFC3339)))
+	case jobFailed(job):
+		log.Info("Failed to restore backup", "backupID", backupID)
+		return r.endRestoreRequest(ctx, dsp, fmt.Sprintf("failed to restore backup [%s], see the logs of Job [%s]", backupID, jobName))
+	}
+	return nil
+}
+
+// restoreWritersScaledDown returns true once the components of the DSPA writing to its database or object store, see
+// RestoringBackup, have no pods left. Their Deployments are watched, so the reconcile scaling down their last pod starts the
+// restore.
+func (r *DSPAReconciler) restoreWritersScaledDown(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) (bool, error) {
+	for _, name := range []string{
+		params.APIServerDefaultResourceName,
+		params.PersistentAgentDefaultResourceName,
+		params.ScheduledWorkflowDefaultResourceName,
+		config.DerivedName("ds-pipeline-metadata-grpc-", dsp.Name),
+		config.DerivedName("ds-pipeline-metadata-writer-", dsp.Name),
+		params.CacheServerDefaultResourceName,
+	} {
+		deployment := &appsv1.Deployment{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: dsp.Namespace}, deployment)
+		if apierrs.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, err
+		}
+		if (deployment.Spec.Replicas != nil && *deployment.Spec.Replicas > 0) || deployment.Status.Replicas > 0 {
+			r.Log.V(1).Info(fmt.Sprintf("Waiting for Deployment [%s] to scale down before restoring", name),
+				"namespace", dsp.Namespace, "dspa_name", dsp.Name)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *DSPAReconciler) setRestoreStatus(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, status string) error {
+	patch := client.MergeFrom(dsp.DeepCopy())
+	dsp.Annotations[restoreStatusAnnotation] = status
+	return r.Patch(ctx, dsp, patch)
+}
+
+// endRestoreRequest removes the request annotation of the DSPA, recording the outcome of the request
+func (r *DSPAReconciler) endRestoreRequest(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, status string) error {
+	patch := client.MergeFrom(dsp.DeepCopy())
+	delete(dsp.Annotations, restoreFromAnnotation)
+	dsp.Annotations[restoreStatusAnnotation] = status
+	return r.Patch(ctx, dsp, patch)
+}
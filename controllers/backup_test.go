@@ -0,0 +1,199 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newBackupTestDSPA() *dspav1alpha1.DataSciencePipelinesApplication {
+	return &dspav1alpha1.DataSciencePipelinesApplication{
+		TypeMeta:   metav1.TypeMeta{APIVersion: dspav1alpha1.GroupVersion.String(), Kind: "DataSciencePipelinesApplication"},
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true, PVCSize: resource.MustParse("10Gi")}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: true, Image: "someimage", PVCSize: resource.MustParse("20Gi")}},
+			Backup: &dspav1alpha1.Backup{
+				Schedule: "0 3 * * *",
+				Destination: &dspav1alpha1.BackupDestination{
+					ExternalStorage: &dspav1alpha1.ExternalStorage{
+						Host:   "s3.example.com",
+						Bucket: "backups",
+						Scheme: "https",
+						S3CredentialSecret: &dspav1alpha1.S3CredentialSecret{
+							SecretName: "backup-credentials", AccessKey: "accesskey", SecretKey: "secretkey",
+						},
+					},
+					Prefix: "dspa-backups",
+				},
+			},
+		},
+	}
+}
+
+func TestDeployBackups(t *testing.T) {
+	expectedCronJobName := "ds-pipeline-backup-testdspa"
+	dspa := newBackupTestDSPA()
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// Ensure the CronJob dumps MariaDB and copies Minio before uploading both to the destination
+	assert.Nil(t, reconciler.ReconcileBackups(ctx, dspa, params))
+	cronJob := &batchv1.CronJob{}
+	created, err := reconciler.IsResourceCreated(ctx, cronJob, expectedCronJobName, "testnamespace")
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, "0 3 * * *", cronJob.Spec.Schedule)
+	assert.Equal(t, batchv1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	assert.Len(t, podSpec.InitContainers, 2)
+	assert.Equal(t, "dump-mariadb", podSpec.InitContainers[0].Name)
+	assert.Equal(t, "copy-minio", podSpec.InitContainers[1].Name)
+	assert.Contains(t, podSpec.Containers[0].Command[2], "s3://backups/dspa-backups/testnamespace/testdspa/$BACKUP_ID/")
+	assert.Equal(t, "backup-credentials", podSpec.Containers[0].Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "30Gi", podSpec.Volumes[0].EmptyDir.SizeLimit.String())
+
+	// Ensure only the components the DSPA deploys are backed up
+	dspa.Spec.ObjectStorage.Minio.Deploy = false
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileBackups(ctx, dspa, params))
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedCronJobName, Namespace: "testnamespace"}, cronJob))
+	assert.Len(t, cronJob.Spec.JobTemplate.Spec.Template.Spec.InitContainers, 1)
+
+	// Ensure DSPAs deploying neither MariaDB nor Minio are reported
+	external := dspa.DeepCopy()
+	external.Spec.Database = &dspav1alpha1.Database{ExternalDB: &dspav1alpha1.ExternalDB{
+		Host: "mysql.example.com", Port: "3306", Username: "user", DBName: "db",
+		PasswordSecret: &dspav1alpha1.SecretKeyValue{Name: "db-credentials", Key: "password"},
+	}}
+	err = params.SetupBackups(external)
+	assert.ErrorContains(t, err, "backs up the MariaDB and Minio deployed by the DSPA, which deploys neither")
+
+	// Ensure the CronJob is removed once backups are
+	dspa.Spec.Backup = nil
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileBackups(ctx, dspa, params))
+	created, err = reconciler.IsResourceCreated(ctx, &batchv1.CronJob{}, expectedCronJobName, "testnamespace")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
+
+func TestRestoreBackup(t *testing.T) {
+	expectedJobName := "ds-pipeline-restore-testdspa"
+	dspa := newBackupTestDSPA()
+	dspa.Annotations = map[string]string{restoreFromAnnotation: "latest"}
+	dspa.Spec.MLMD = &dspav1alpha1.MLMD{Deploy: true}
+	dspa.Spec.CacheServer = &dspav1alpha1.CacheServer{Deploy: true}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	request := func(backupID string) {
+		dspa.Annotations[restoreFromAnnotation] = backupID
+		assert.Nil(t, reconciler.Update(ctx, dspa))
+	}
+
+	// Ensure requests for invalid backup ids are denied
+	assert.Nil(t, reconciler.ReconcileRestore(ctx, dspa, params))
+	assert.Contains(t, dspa.Annotations[restoreStatusAnnotation], "denied, [latest] is not a backup id")
+	assert.NotContains(t, dspa.Annotations, restoreFromAnnotation)
+
+	// Ensure the components writing to the database and object store are scaled down before the backup is restored
+	request("20240102T020000Z")
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.True(t, params.RestoringBackup())
+	assert.Nil(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	apiServer := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-testdspa", Namespace: "testnamespace"}, apiServer))
+	assert.Equal(t, int32(0), *apiServer.Spec.Replicas)
+	assert.Nil(t, reconciler.ReconcileMLMD(dspa, params))
+	assert.Nil(t, reconciler.ReconcileCacheServer(dspa, params))
+	writers := map[string]*appsv1.Deployment{}
+	for _, name := range []string{"ds-pipeline-metadata-grpc-testdspa", "ds-pipeline-metadata-writer-testdspa", "ds-pipeline-cache-server-testdspa"} {
+		writers[name] = &appsv1.Deployment{}
+		assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: "testnamespace"}, writers[name]))
+		assert.Equal(t, int32(0), *writers[name].Spec.Replicas)
+	}
+	apiServer.Status.Replicas = 1
+	assert.Nil(t, reconciler.Status().Update(ctx, apiServer))
+	assert.Nil(t, reconciler.ReconcileRestore(ctx, dspa, params))
+	created, err := reconciler.IsResourceCreated(ctx, &batchv1.Job{}, expectedJobName, "testnamespace")
+	assert.False(t, created)
+	assert.Nil(t, err)
+
+	// Ensure the restore also waits for the MLMD and Cache Server pods writing to the database
+	apiServer.Status.Replicas = 0
+	assert.Nil(t, reconciler.Status().Update(ctx, apiServer))
+	for _, writer := range writers {
+		writer.Status.Replicas = 1
+		assert.Nil(t, reconciler.Status().Update(ctx, writer))
+		assert.Nil(t, reconciler.ReconcileRestore(ctx, dspa, params))
+		created, err = reconciler.IsResourceCreated(ctx, &batchv1.Job{}, expectedJobName, "testnamespace")
+		assert.False(t, created)
+		assert.Nil(t, err)
+		writer.Status.Replicas = 0
+		assert.Nil(t, reconciler.Status().Update(ctx, writer))
+	}
+
+	// Ensure the requested backup is restored by a Job, reported once it succeeded
+	assert.Nil(t, reconciler.ReconcileRestore(ctx, dspa, params))
+	assert.Equal(t, "restoring backup [20240102T020000Z]", dspa.Annotations[restoreStatusAnnotation])
+	job := &batchv1.Job{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedJobName, Namespace: "testnamespace"}, job))
+	assert.Equal(t, "20240102T020000Z", job.Annotations[backupIDAnnotation])
+	assert.Equal(t, []string{"download", "restore-minio", "restore-mariadb"},
+		[]string{job.Spec.Template.Spec.InitContainers[0].Name, job.Spec.Template.Spec.InitContainers[1].Name,
+			job.Spec.Template.Spec.InitContainers[2].Name})
+	assert.Contains(t, job.Spec.Template.Spec.InitContainers[0].Command[2], "s3://backups/dspa-backups/testnamespace/testdspa/20240102T020000Z/")
+
+	assert.Nil(t, reconciler.ReconcileRestore(ctx, dspa, params))
+	assert.Equal(t, "restoring backup [20240102T020000Z]", dspa.Annotations[restoreStatusAnnotation])
+	completedAt := metav1.Date(2024, 1, 3, 10, 0, 0, 0, metav1.Now().Location())
+	job.Status.Succeeded = 1
+	job.Status.CompletionTime = &completedAt
+	assert.Nil(t, reconciler.Status().Update(ctx, job))
+	assert.Nil(t, reconciler.ReconcileRestore(ctx, dspa, params))
+	assert.Contains(t, dspa.Annotations[restoreStatusAnnotation], "restored backup [20240102T020000Z] at ")
+	assert.NotContains(t, dspa.Annotations, restoreFromAnnotation)
+
+	// Ensure the components scale back up once the restore ended
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.False(t, params.RestoringBackup())
+	assert.Nil(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-testdspa", Namespace: "testnamespace"}, apiServer))
+	// The replicas are no longer set, so the API server defaults them again
+	assert.Nil(t, apiServer.Spec.Replicas)
+
+	// Ensure the Job of an earlier restore is replaced by the one of the next request
+	request("20240103T020000Z")
+	assert.Nil(t, reconciler.ReconcileRestore(ctx, dspa, params))
+	created, err = reconciler.IsResourceCreated(ctx, &batchv1.Job{}, expectedJobName, "testnamespace")
+	assert.False(t, created)
+	assert.Nil(t, err)
+	assert.Nil(t, reconciler.ReconcileRestore(ctx, dspa, params))
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedJobName, Namespace: "testnamespace"}, job))
+	assert.Equal(t, "20240103T020000Z", job.Annotations[backupIDAnnotation])
+}
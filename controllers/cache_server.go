@@ -0,0 +1,108 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	b64 "encoding/base64"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var cacheServerTemplates = []string{
+	"cache-server/sa.yaml.tmpl",
+	"cache-server/role.yaml.tmpl",
+	"cache-server/rolebinding.yaml.tmpl",
+	"cache-server/service.yaml.tmpl",
+	"cache-server/deployment.yaml.tmpl",
+}
+
+// cacheServerWebhookTemplate is cluster scoped, so it can't be owned by the DSPA and is deleted explicitly
+const cacheServerWebhookTemplate = "cache-server/webhook.yaml.tmpl"
+
+const cacheServerDefaultResourceNamePrefix = "ds-pipeline-cache-server-"
+
+// SetupCacheServer populates the Cache Server defaults. It must run after SetupDBParams, as the cache entries are
+// stored on the DSPA's database server.
+func (p *DSPAParams) SetupCacheServer(ctx context.Context, client client.Client, log logr.Logger) error {
+	if p.CacheServer == nil {
+		return nil
+	}
+	if err := p.setImageDefault(config.CacheServerImagePath, &p.CacheServer.Image); err != nil {
+		return err
+	}
+	setResourcesDefault(config.CacheServerResourceRequirements, &p.CacheServer.Resources)
+	setStringDefault(p.DBConnection.DBName, &p.CacheServer.DBName)
+
+	// Cached steps are replaced with a container of this image, echoing the cached outputs
+	p.CacheServerStepImage = config.GetStringConfigWithDefault(config.APIServerCacheImagePath, config.DefaultImageValue)
+	if p.APIServer != nil {
+		p.CacheServerStepImage = p.APIServer.CacheImage
+	}
+
+	p.CacheServerTLSSecretName = config.DerivedName("ds-pipeline-cache-server-tls-", p.Name)
+	p.CacheServerCABundle = ""
+	if p.CacheServer.TLS != nil {
+		p.CacheServerTLSSecretName = p.CacheServer.TLS.SecretName
+		cfgKey, cfgName := p.CacheServer.TLS.CABundle.ConfigMapKey, p.CacheServer.TLS.CABundle.ConfigMapName
+		err, val := util.GetConfigMapValue(ctx, cfgKey, cfgName, p.Namespace, client, log)
+		if err != nil {
+			return fmt.Errorf("unable to read the CA bundle of the cache server webhook from key [%s] of configmap [%s]: %w", cfgKey, cfgName, err)
+		}
+		if val == "" {
+			return fmt.Errorf("no CA bundle of the cache server webhook found in key [%s] of configmap [%s]", cfgKey, cfgName)
+		}
+		p.CacheServerCABundle = b64.StdEncoding.EncodeToString([]byte(val))
+	}
+	return nil
+}
+
+func (r *DSPAReconciler) ReconcileCacheServer(dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	if !params.UsingCacheServer(dsp) {
+		log.Info("Skipping Application of Cache Server Resources")
+		// Stop mutating step pods once caching is disabled
+		return r.DeleteResource(params, cacheServerWebhookTemplate)
+	}
+
+	log.Info("Applying Cache Server Resources")
+
+	for _, template := range cacheServerTemplates {
+		err := r.Apply(dsp, params, template)
+		if err != nil {
+			return err
+		}
+	}
+	err := r.ApplyWithoutOwner(params, cacheServerWebhookTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Cache Server Resources")
+	return nil
+}
+
+func (r *DSPAReconciler) CleanUpCacheServer(params *DSPAParams) error {
+	return r.DeleteResource(params, cacheServerWebhookTemplate)
+}
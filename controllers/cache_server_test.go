@@ -0,0 +1,116 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newCacheServerTestDSPA(cacheServer *dspav1alpha1.CacheServer) *dspav1alpha1.DataSciencePipelinesApplication {
+	return &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true, CacheImage: "step-image"},
+			CacheServer:   cacheServer,
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+}
+
+func TestDeployCacheServer(t *testing.T) {
+	expectedCacheServerName := "ds-pipeline-cache-server-testdspa"
+	expectedWebhookName := "ds-pipeline-cache-webhook-testnamespace.testdspa"
+	dspa := newCacheServerTestDSPA(&dspav1alpha1.CacheServer{Deploy: true, DefaultCacheStaleness: "P30D"})
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileCacheServer(dspa, params))
+
+	// Ensure the Cache Server stores its entries in the DSPA's database, and caches with the configured staleness
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedCacheServerName, Namespace: dspa.Namespace}, deployment))
+	env := deployment.Spec.Template.Spec.Containers[0].Env
+	assert.Contains(t, env, v1.EnvVar{Name: "DBCONFIG_DB_NAME", Value: "mlpipeline"})
+	assert.Contains(t, env, v1.EnvVar{Name: "DBCONFIG_HOST_NAME", Value: "mariadb-testdspa." + dspa.Namespace + ".svc.cluster.local"})
+	assert.Contains(t, env, v1.EnvVar{Name: "CACHE_IMAGE", Value: "step-image"})
+	assert.Contains(t, env, v1.EnvVar{Name: "DEFAULT_CACHE_STALENESS", Value: "P30D"})
+	assert.NotContains(t, env, v1.EnvVar{Name: "MAXIMUM_CACHE_STALENESS"})
+
+	// Ensure the webhook certificate is issued by the OpenShift service CA by default
+	service := &v1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedCacheServerName, Namespace: dspa.Namespace}, service))
+	assert.Equal(t, "ds-pipeline-cache-server-tls-testdspa", service.Annotations["service.beta.openshift.io/serving-cert-secret-name"])
+	webhook := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedWebhookName}, webhook))
+	assert.Equal(t, "true", webhook.Annotations["service.beta.openshift.io/inject-cabundle"])
+	assert.Equal(t, expectedCacheServerName, webhook.Webhooks[0].ClientConfig.Service.Name)
+	assert.Equal(t, dspa.Namespace, webhook.Webhooks[0].NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"])
+	assert.Equal(t, admissionregistrationv1.Ignore, *webhook.Webhooks[0].FailurePolicy)
+
+	// Ensure the webhook is removed once the Cache Server is disabled, so step pods are no longer mutated
+	dspa.Spec.CacheServer.Deploy = false
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileCacheServer(dspa, params))
+	created, err := reconciler.IsResourceCreated(ctx, &admissionregistrationv1.MutatingWebhookConfiguration{}, expectedWebhookName, "")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
+
+func TestDeployCacheServerCustomTLS(t *testing.T) {
+	dspa := newCacheServerTestDSPA(&dspav1alpha1.CacheServer{
+		Deploy: true,
+		DBName: "cachedb",
+		TLS: &dspav1alpha1.CacheServerTLS{
+			SecretName: "cache-webhook-cert",
+			CABundle:   &dspav1alpha1.CABundle{ConfigMapName: "cache-webhook-ca", ConfigMapKey: "ca.crt"},
+		},
+	})
+	ctx, params, reconciler := CreateNewTestObjects()
+
+	// Ensure a missing CA bundle is reported rather than deploying a webhook the API server can't call
+	assert.ErrorContains(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log), "CA bundle of the cache server webhook")
+
+	caBundle := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cache-webhook-ca", Namespace: dspa.Namespace},
+		Data:       map[string]string{"ca.crt": "-----BEGIN CERTIFICATE-----"},
+	}
+	assert.Nil(t, reconciler.Create(ctx, caBundle))
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileCacheServer(dspa, params))
+
+	// Ensure the provided certificate serves the webhook, and its CA is trusted
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-cache-server-testdspa", Namespace: dspa.Namespace}, deployment))
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{Name: "DBCONFIG_DB_NAME", Value: "cachedb"})
+	assert.Equal(t, "cache-webhook-cert", deployment.Spec.Template.Spec.Volumes[0].Secret.SecretName)
+	service := &v1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-cache-server-testdspa", Namespace: dspa.Namespace}, service))
+	assert.NotContains(t, service.Annotations, "service.beta.openshift.io/serving-cert-secret-name")
+	webhook := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-cache-webhook-testnamespace.testdspa"}, webhook))
+	assert.NotContains(t, webhook.Annotations, "service.beta.openshift.io/inject-cabundle")
+	assert.Equal(t, []byte("-----BEGIN CERTIFICATE-----"), webhook.Webhooks[0].ClientConfig.CABundle)
+}
@@ -0,0 +1,227 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// commitStatusAnnotationPrefix is suffixed with the reporter name to track the commit status reported for each
+// PipelineRun: the last state reported, or "<state>/<attempts>" while reporting a state fails, and "<state>/dropped"
+// once its attempts are exhausted
+const commitStatusAnnotationPrefix = "commit-status.datasciencepipelinesapplications.opendatahub.io/"
+
+// Commit states as tracked on the PipelineRun, mapped to each provider's own states when reported
+const (
+	commitStatusPending = "pending"
+	commitStatusSuccess = "success"
+	commitStatusFailure = "failure"
+)
+
+// pipelineRunParameter returns the value of the named parameter the PipelineRun was started with.
+func pipelineRunParameter(pipelineRun unstructured.Unstructured, name string) string {
+	params, _, _ := unstructured.NestedSlice(pipelineRun.Object, "spec", "params")
+	for _, p := range params {
+		param, ok := p.(map[string]interface{})
+		if !ok || param["name"] != name {
+			continue
+		}
+		value, _ := param["value"].(string)
+		return value
+	}
+	return ""
+}
+
+// commitStatusRequest builds the provider API request setting the commit status of sha in repository.
+func commitStatusRequest(ctx context.Context, reporter dspav1alpha1.CommitStatusReporter, token, repository, sha, state, description string) (*http.Request, error) {
+	commitContext := reporter.Context
+	if commitContext == "" {
+		commitContext = config.DefaultCommitStatusContext
+	}
+
+	switch reporter.Provider {
+	case "github":
+		apiURL := reporter.APIURL
+		if apiURL == "" {
+			apiURL = config.DefaultGitHubAPIURL
+		}
+		body, err := json.Marshal(map[string]string{
+			"state":       state,
+			"context":     commitContext,
+			"description": description,
+		})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			fmt.Sprintf("%s/repos/%s/statuses/%s", strings.TrimSuffix(apiURL, "/"), repository, sha), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req, nil
+	case "gitlab":
+		apiURL := reporter.APIURL
+		if apiURL == "" {
+			apiURL = config.DefaultGitLabAPIURL
+		}
+		gitlabStates := map[string]string{
+			commitStatusPending: "running",
+			commitStatusSuccess: "success",
+			commitStatusFailure: "failed",
+		}
+		query := url.Values{}
+		query.Set("state", gitlabStates[state])
+		query.Set("name", commitContext)
+		query.Set("description", description)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			fmt.Sprintf("%s/projects/%s/statuses/%s?%s", strings.TrimSuffix(apiURL, "/"), url.PathEscape(repository), sha, query.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("PRIVATE-TOKEN", token)
+		return req, nil
+	}
+	return nil, fmt.Errorf("unsupported commit status provider [%s]", reporter.Provider)
+}
+
+// ReportCommitStatuses sets commit statuses for the PipelineRuns started with a commit parameter, pending while
+// the run is executing and success or failure once it finishes. The reported state is tracked per reporter in an
+// annotation on the PipelineRun, so each state is only reported once, and failed reports are retried on the next call
+// until the reporter's MaxAttempts is reached. It is called by the NotificationDispatcher, outside of Reconcile.
+func (r *DSPAReconciler) ReportCommitStatuses(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, now time.Time) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	pipelineRuns, err := r.listPipelineRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return err
+	}
+
+	tokens := make(map[string]string)
+	for _, reporter := range dsp.Spec.CommitStatusReporters {
+		token, err := r.getSecretKeyValue(ctx, dsp.Namespace, reporter.TokenSecret)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("Unable to retrieve token for commit status reporter [%s]", reporter.Name))
+			return err
+		}
+		tokens[reporter.Name] = strings.TrimSpace(string(token))
+	}
+	httpClient, err := r.webhookHTTPClient(ctx, dsp)
+	if err != nil {
+		return err
+	}
+	defer httpClient.CloseIdleConnections()
+
+	for _, pipelineRun := range pipelineRuns {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		state, description := commitStatusPending, "Pipeline run is executing"
+		if finishedAt, finished := pipelineRunCompletionTime(pipelineRun); finished {
+			if now.Sub(finishedAt) > config.RunNotificationLookback {
+				continue
+			}
+			event, terminal := newRunStatusEvent(dsp, pipelineRun)
+			if !terminal {
+				continue
+			}
+			state, description = commitStatusFailure, fmt.Sprintf("Pipeline run failed: %s", event.Reason)
+			if event.Status == "Succeeded" {
+				state, description = commitStatusSuccess, "Pipeline run succeeded"
+			}
+		}
+
+		patch := client.MergeFrom(pipelineRun.DeepCopy())
+		annotations := pipelineRun.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		changed := false
+		for _, reporter := range dsp.Spec.CommitStatusReporters {
+			commitParameter := reporter.CommitParameter
+			if commitParameter == "" {
+				commitParameter = config.DefaultCommitStatusParameter
+			}
+			sha := pipelineRunParameter(pipelineRun, commitParameter)
+			annotation := commitStatusAnnotationPrefix + reporter.Name
+			reported := annotations[annotation]
+			if sha == "" || reported == state || reported == state+"/"+runStatusWebhookDropped {
+				continue
+			}
+			attempts := 0
+			if strings.HasPrefix(reported, state+"/") {
+				attempts, _ = strconv.Atoi(strings.TrimPrefix(reported, state+"/"))
+			}
+			attempts++
+
+			req, err := commitStatusRequest(ctx, reporter, tokens[reporter.Name], reporter.Repository, sha, state, description)
+			if err != nil {
+				return err
+			}
+			err = sendCommitStatus(httpClient, req)
+			switch {
+			case err == nil:
+				annotations[annotation] = state
+			case attempts >= reporter.MaxAttempts:
+				log.Info(fmt.Sprintf("Dropping commit status [%s] of [%s] for reporter [%s] after %d attempts: %s", state, pipelineRun.GetName(), reporter.Name, attempts, err))
+				annotations[annotation] = state + "/" + runStatusWebhookDropped
+			default:
+				log.V(1).Info(fmt.Sprintf("Failed to report commit status of [%s] with reporter [%s], will retry: %s", pipelineRun.GetName(), reporter.Name, err))
+				annotations[annotation] = state + "/" + strconv.Itoa(attempts)
+			}
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		pipelineRun.SetAnnotations(annotations)
+		if err := r.Patch(ctx, &pipelineRun, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendCommitStatus(httpClient *http.Client, req *http.Request) error {
+	// The API URL is set by the editors of the DSPA, so it's restricted like the run status webhooks
+	if err := validateWebhookURL(req.URL.String()); err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("provider responded with status [%d]", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestReportCommitStatuses(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+
+	// Record the commit statuses set through the GitHub API
+	var paths, states []string
+	github := newTestWebhookServer(t, func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "Bearer gh-token", req.Header.Get("Authorization"))
+		status := map[string]string{}
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&status))
+		paths = append(paths, req.URL.Path)
+		states = append(states, status["state"])
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			CommitStatusReporters: []dspav1alpha1.CommitStatusReporter{
+				{
+					Name:        "github",
+					Provider:    "github",
+					APIURL:      github.URL,
+					Repository:  "org/default-repo",
+					TokenSecret: &dspav1alpha1.SecretKeyValue{Name: "github-token", Key: "token"},
+					MaxAttempts: 3,
+				},
+			},
+		},
+	}
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	ctx, _, reconciler := CreateNewTestObjects()
+	secret := &v1.Secret{Data: map[string][]byte{"token": []byte("gh-token\n")}}
+	secret.Name = "github-token"
+	secret.Namespace = testNamespace
+	assert.Nil(t, reconciler.Create(ctx, secret))
+
+	// A run triggered from CI, naming another repository the token must not be sent to, and a run not triggered from CI
+	ciRun := newTestPipelineRun("ci-run", testNamespace, nil, false)
+	_ = unstructured.SetNestedSlice(ciRun.Object, []interface{}{
+		map[string]interface{}{"name": "commit_sha", "value": "abc123"},
+		map[string]interface{}{"name": "repo", "value": "org/model-repo"},
+	}, "spec", "params")
+	manualRun := newTestPipelineRun("manual-run", testNamespace, nil, false)
+	assert.Nil(t, reconciler.Create(ctx, ciRun))
+	assert.Nil(t, reconciler.Create(ctx, manualRun))
+
+	// Ensure pending is only reported once while the run executes
+	now := time.Now().Truncate(time.Second)
+	for i := 0; i < 2; i++ {
+		err := reconciler.ReportCommitStatuses(ctx, dspa, now)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, []string{"/repos/org/default-repo/statuses/abc123"}, paths)
+	assert.Equal(t, []string{"pending"}, states)
+
+	// Finish the run, and ensure the final state is reported
+	finished := now.Add(-time.Minute)
+	pipelineRun, err := reconciler.listPipelineRuns(ctx, testNamespace)
+	assert.Nil(t, err)
+	for _, run := range pipelineRun {
+		if run.GetName() != "ci-run" {
+			continue
+		}
+		_ = unstructured.SetNestedField(run.Object, finished.Format(time.RFC3339), "status", "completionTime")
+		_ = unstructured.SetNestedSlice(run.Object, []interface{}{
+			map[string]interface{}{"type": "Succeeded", "status": "False", "reason": "Failed"},
+		}, "status", "conditions")
+		assert.Nil(t, reconciler.Update(ctx, &run))
+	}
+	err = reconciler.ReportCommitStatuses(ctx, dspa, now)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pending", "failure"}, states)
+}
+
+func TestCommitStatusRequestGitLab(t *testing.T) {
+	reporter := dspav1alpha1.CommitStatusReporter{
+		Name:       "gitlab",
+		Provider:   "gitlab",
+		Repository: "group/project",
+	}
+	req, err := commitStatusRequest(context.Background(), reporter, "gl-token", reporter.Repository, "abc123", commitStatusPending, "Pipeline run is executing")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://gitlab.com/api/v4/projects/group%2Fproject/statuses/abc123?description=Pipeline+run+is+executing&name=data-science-pipelines&state=running", req.URL.String())
+	assert.Equal(t, "gl-token", req.Header.Get("PRIVATE-TOKEN"))
+}
+
+func TestReportCommitStatusesMaxAttempts(t *testing.T) {
+	testNamespace := "testnamespace"
+
+	// A provider failing every report
+	attempts := 0
+	github := newTestWebhookServer(t, func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			CommitStatusReporters: []dspav1alpha1.CommitStatusReporter{
+				{
+					Name:        "github",
+					Provider:    "github",
+					APIURL:      github.URL,
+					Repository:  "org/repo",
+					TokenSecret: &dspav1alpha1.SecretKeyValue{Name: "github-token", Key: "token"},
+					MaxAttempts: 2,
+				},
+			},
+		},
+	}
+	dspa.Name = "testdspa"
+	dspa.Namespace = testNamespace
+
+	ctx, _, reconciler := CreateNewTestObjects()
+	secret := &v1.Secret{Data: map[string][]byte{"token": []byte("gh-token")}}
+	secret.Name = "github-token"
+	secret.Namespace = testNamespace
+	assert.Nil(t, reconciler.Create(ctx, secret))
+	ciRun := newTestPipelineRun("ci-run", testNamespace, nil, false)
+	_ = unstructured.SetNestedSlice(ciRun.Object, []interface{}{
+		map[string]interface{}{"name": "commit_sha", "value": "abc123"},
+	}, "spec", "params")
+	assert.Nil(t, reconciler.Create(ctx, ciRun))
+
+	// Ensure the failed report is retried on the next call, then dropped once MaxAttempts is reached
+	now := time.Now().Truncate(time.Second)
+	for i := 0; i < 4; i++ {
+		assert.Nil(t, reconciler.ReportCommitStatuses(ctx, dspa, now))
+	}
+	assert.Equal(t, 2, attempts)
+	pipelineRuns, err := reconciler.listPipelineRuns(ctx, testNamespace)
+	assert.Nil(t, err)
+	assert.Equal(t, "pending/dropped", pipelineRuns[0].GetAnnotations()[commitStatusAnnotationPrefix+"github"])
+}
+
+func TestReportCommitStatusesRestrictedAPIURL(t *testing.T) {
+	testNamespace := "testnamespace"
+
+	// A provider on loopback, standing in for a self-hosted one on a private network
+	attempts := 0
+	provider := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defaultClient := runStatusWebhookClient
+	t.Cleanup(func() {
+		provider.Close()
+		runStatusWebhookClient = defaultClient
+		viper.Set(config.WebhookAllowedHostsConfigName, nil)
+	})
+	runStatusWebhookClient = provider.Client()
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			CommitStatusReporters: []dspav1alpha1.CommitStatusReporter{
+				{
+					Name:        "github",
+					Provider:    "github",
+					APIURL:      provider.URL,
+					Repository:  "org/repo",
+					TokenSecret: &dspav1alpha1.SecretKeyValue{Name: "github-token", Key: "token"},
+					MaxAttempts: 1,
+				},
+			},
+		},
+	}
+	dspa.Name = "testdspa"
+	dspa.Namespace = testNamespace
+
+	ctx, _, reconciler := CreateNewTestObjects()
+	secret := &v1.Secret{Data: map[string][]byte{"token": []byte("gh-token")}}
+	secret.Name = "github-token"
+	secret.Namespace = testNamespace
+	assert.Nil(t, reconciler.Create(ctx, secret))
+	ciRun := newTestPipelineRun("ci-run", testNamespace, nil, false)
+	_ = unstructured.SetNestedSlice(ciRun.Object, []interface{}{
+		map[string]interface{}{"name": "commit_sha", "value": "abc123"},
+	}, "spec", "params")
+	assert.Nil(t, reconciler.Create(ctx, ciRun))
+
+	// Ensure the token isn't sent to a restricted address
+	now := time.Now().Truncate(time.Second)
+	assert.Nil(t, reconciler.ReportCommitStatuses(ctx, dspa, now))
+	assert.Equal(t, 0, attempts)
+	pipelineRuns, err := reconciler.listPipelineRuns(ctx, testNamespace)
+	assert.Nil(t, err)
+	assert.Equal(t, "pending/dropped", pipelineRuns[0].GetAnnotations()[commitStatusAnnotationPrefix+"github"])
+
+	// Ensure hosts allowed in the operator config are reported to
+	viper.Set(config.WebhookAllowedHostsConfigName, []string{"127.0.0.1"})
+	pipelineRuns[0].SetAnnotations(nil)
+	assert.Nil(t, reconciler.Update(ctx, &pipelineRuns[0]))
+	assert.Nil(t, reconciler.ReportCommitStatuses(ctx, dspa, now))
+	assert.Equal(t, 1, attempts)
+}
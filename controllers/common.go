@@ -30,6 +30,7 @@ func (r *DSPAReconciler) ReconcileCommon(dsp *dspav1alpha1.DataSciencePipelinesA
 	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
 
 	log.Info("Applying Common Resources")
+	params.OperatorNamespace = r.OperatorNamespace
 	for _, template := range commonTemplates {
 		err := r.Apply(dsp, params, template)
 		if err != nil {
@@ -71,14 +71,26 @@ func TestDeployCommonPolicies(t *testing.T) {
 	assert.Nil(t, err)
 
 	// Run test reconciliation
+	reconciler.OperatorNamespace = "operator-namespace"
 	err = reconciler.ReconcileCommon(dspa, params)
 	assert.Nil(t, err)
 
-	// Assert Common NetworkPolicies now exist
+	// Assert Common NetworkPolicies now exist, admitting the operator to the API Server
 	np = &networkingv1.NetworkPolicy{}
 	created, err = reconciler.IsResourceCreated(ctx, np, expectedNetworkPolicyName, testNamespace)
 	assert.True(t, created)
 	assert.Nil(t, err)
+	var operatorAdmitted bool
+	for _, peer := range np.Spec.Ingress[1].From {
+		if peer.NamespaceSelector != nil && peer.NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"] == "operator-namespace" {
+			// Only the operator pods of its namespace are admitted
+			if assert.NotNil(t, peer.PodSelector) {
+				assert.Equal(t, map[string]string{"app.kubernetes.io/name": "data-science-pipelines-operator"}, peer.PodSelector.MatchLabels)
+			}
+			operatorAdmitted = true
+		}
+	}
+	assert.True(t, operatorAdmitted)
 
 	np = &networkingv1.NetworkPolicy{}
 	created, err = reconciler.IsResourceCreated(ctx, np, expectedEnvoyNetworkPolicyName, testNamespace)
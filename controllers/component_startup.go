@@ -0,0 +1,96 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// podConditionTime returns when the condition of the pod last transitioned to True, if it's True.
+func podConditionTime(pod corev1.Pod, conditionType corev1.PodConditionType) (metav1.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime, true
+		}
+	}
+	return metav1.Time{}, false
+}
+
+// podStartup returns the startup of a ready pod, unless one of its containers restarted, as its Ready condition then
+// measures the restart rather than the startup.
+func podStartup(pod corev1.Pod) (dspav1alpha1.ComponentStartup, bool) {
+	for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		if status.RestartCount > 0 {
+			return dspav1alpha1.ComponentStartup{}, false
+		}
+	}
+	scheduledAt, scheduled := podConditionTime(pod, corev1.PodScheduled)
+	readyAt, ready := podConditionTime(pod, corev1.PodReady)
+	if !scheduled || !ready {
+		return dspav1alpha1.ComponentStartup{}, false
+	}
+	return dspav1alpha1.ComponentStartup{
+		Component:       pod.Labels["app"],
+		StartedAt:       pod.CreationTimestamp,
+		TimeToScheduled: metav1.Duration{Duration: scheduledAt.Sub(pod.CreationTimestamp.Time)},
+		TimeToReady:     metav1.Duration{Duration: readyAt.Sub(pod.CreationTimestamp.Time)},
+	}, true
+}
+
+// ComponentStartups returns the startup of the most recently started ready pod of every component of the DSPA, i.e.
+// of its last rollout or restart.
+func (r *DSPAReconciler) ComponentStartups(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) ([]dspav1alpha1.ComponentStartup, error) {
+	pods := &corev1.PodList{}
+	err := r.List(ctx, pods, client.InNamespace(dsp.Namespace), client.MatchingLabels{
+		"component": "data-science-pipelines",
+		"dspa":      dsp.Name,
+	}, client.HasLabels{"app"})
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]dspav1alpha1.ComponentStartup)
+	for _, pod := range pods.Items {
+		startup, ok := podStartup(pod)
+		if !ok {
+			continue
+		}
+		if previous, ok := latest[startup.Component]; !ok || startup.StartedAt.After(previous.StartedAt.Time) {
+			latest[startup.Component] = startup
+		}
+	}
+	var startups []dspav1alpha1.ComponentStartup
+	for _, startup := range latest {
+		startups = append(startups, startup)
+	}
+	sort.Slice(startups, func(i, j int) bool { return startups[i].Component < startups[j].Component })
+	return startups, nil
+}
+
+// PublishComponentStartupMetrics publishes the startups of the DSPA's components, so slow storage classes or image
+// pulls can be compared across DSPAs.
+func PublishComponentStartupMetrics(dsp *dspav1alpha1.DataSciencePipelinesApplication, startups []dspav1alpha1.ComponentStartup) {
+	for _, startup := range startups {
+		ComponentStartupMetric.WithLabelValues(dsp.Name, dsp.Namespace, startup.Component, "scheduled").Set(startup.TimeToScheduled.Seconds())
+		ComponentStartupMetric.WithLabelValues(dsp.Name, dsp.Namespace, startup.Component, "ready").Set(startup.TimeToReady.Seconds())
+	}
+}
@@ -0,0 +1,88 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestComponentPod(name, app string, created time.Time, scheduledAfter, readyAfter time.Duration, restarts int32) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "testnamespace",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{"app": app, "component": "data-science-pipelines", "dspa": "testdspa"},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(scheduledAfter))},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "main", RestartCount: restarts}},
+		},
+	}
+	if readyAfter > 0 {
+		pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+			Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(readyAfter)),
+		})
+	}
+	return pod
+}
+
+func TestComponentStartups(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+	now := time.Now().Truncate(time.Second)
+
+	for _, pod := range []*corev1.Pod{
+		// MariaDB waited for its volume, then started
+		newTestComponentPod("mariadb-old", "mariadb-testdspa", now.Add(-time.Hour), 10*time.Second, time.Minute, 0),
+		newTestComponentPod("mariadb-new", "mariadb-testdspa", now.Add(-10*time.Minute), 90*time.Second, 2*time.Minute, 0),
+		// The new API Server pod is still starting, and the older one restarted
+		newTestComponentPod("apiserver-old", "ds-pipeline-testdspa", now.Add(-time.Hour), time.Second, 20*time.Second, 0),
+		newTestComponentPod("apiserver-restarted", "ds-pipeline-testdspa", now.Add(-30*time.Minute), time.Second, time.Hour, 1),
+		newTestComponentPod("apiserver-new", "ds-pipeline-testdspa", now.Add(-time.Minute), time.Second, 0, 0),
+	} {
+		assert.Nil(t, reconciler.Create(ctx, pod))
+	}
+
+	startups, err := reconciler.ComponentStartups(ctx, dspa)
+	assert.Nil(t, err)
+
+	// Ensure the most recently started ready pod of each component is measured
+	assert.Len(t, startups, 2)
+	assert.Equal(t, "ds-pipeline-testdspa", startups[0].Component)
+	assert.Equal(t, 20*time.Second, startups[0].TimeToReady.Duration)
+	assert.Equal(t, "mariadb-testdspa", startups[1].Component)
+	assert.True(t, startups[1].StartedAt.Equal(&metav1.Time{Time: now.Add(-10 * time.Minute)}))
+	assert.Equal(t, 90*time.Second, startups[1].TimeToScheduled.Duration)
+	assert.Equal(t, 2*time.Minute, startups[1].TimeToReady.Duration)
+
+	PublishComponentStartupMetrics(dspa, startups)
+	assert.Equal(t, float64(120), testutil.ToFloat64(ComponentStartupMetric.WithLabelValues("testdspa", "testnamespace", "mariadb-testdspa", "ready")))
+	assert.Equal(t, float64(90), testutil.ToFloat64(ComponentStartupMetric.WithLabelValues("testdspa", "testnamespace", "mariadb-testdspa", "scheduled")))
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
 	"github.com/spf13/viper"
 	"k8s.io/apimachinery/pkg/api/resource"
-	"time"
 )
 
 const (
@@ -42,6 +45,9 @@ const (
 	MariaDBUser        = "mlpipeline"
 	MariaDBNamePVCSize = "10Gi"
 
+	// Account the mysqld-exporter sidecar of MariaDB scrapes it with, only allowed to connect from the MariaDB pod
+	MariaDBExporterUser = "mysqld_exporter"
+
 	MinioHostPrefix    = "minio"
 	MinioPort          = "9000"
 	MinioScheme        = "http"
@@ -54,35 +60,104 @@ const (
 	GeneratedObjectStorageAccessKeyLength = 16
 	GeneratedObjectStorageSecretKeyLength = 24
 
-	MlmdGrpcPort = "8080"
+	MlmdGrpcPort                 = "8080"
+	MlmdGRPCDefaultReplicas      = 1
+	MlmdGRPCReplicaDBConnections = 50
+	MlmdEnvoyReplicaMaxRequests  = 1024
+	MlmdEnvoyConnectTimeout      = "30s"
+	MlmdEnvoyCABundlePath        = "/etc/ssl/certs/ca-certificates.crt"
+	MariaDBDefaultMaxConnections = 151
+
+	APIServerHTTPPort = "8888"
+
+	APIServerDefaultTerminationGracePeriodSeconds = 60
+	APIServerDefaultPreStopDrainSeconds           = 15
+
+	DefaultUpgradeHookActiveDeadlineSeconds int64 = 600
+
+	DefaultBackupSchedule = "0 2 * * *"
+
+	DefaultArtifactRetentionSchedule = "0 3 * * *"
+
+	PersistenceAgentDefaultNumWorkers                    = 2
+	PersistenceAgentDefaultTTLSecondsAfterWorkflowFinish = 86400
+	PersistenceAgentDefaultClientQPS                     = 5
+	PersistenceAgentDefaultClientBurst                   = 10
+
+	DefaultArchitecture = "amd64"
+
+	DefaultGitHubAPIURL          = "https://api.github.com"
+	DefaultGitLabAPIURL          = "https://gitlab.com/api/v4"
+	DefaultCommitStatusParameter = "commit_sha"
+	DefaultCommitStatusContext   = "data-science-pipelines"
+
+	UpgradeApprovalManual = "Manual"
+
+	NodeSelectorOSLabel = "kubernetes.io/os"
+	DefaultNodeOS       = "linux"
+
+	ZoneTopologyKey     = "topology.kubernetes.io/zone"
+	HostnameTopologyKey = "kubernetes.io/hostname"
 )
 
 // DSPO Config File Paths
 const (
-	APIServerImagePath                  = "Images.ApiServer"
-	APIServerArtifactImagePath          = "Images.Artifact"
-	PersistenceAgentImagePath           = "Images.PersistentAgent"
-	ScheduledWorkflowImagePath          = "Images.ScheduledWorkflow"
-	APIServerCacheImagePath             = "Images.Cache"
-	APIServerMoveResultsImagePath       = "Images.MoveResultsImage"
-	MariaDBImagePath                    = "Images.MariaDB"
-	OAuthProxyImagePath                 = "Images.OAuthProxy"
-	MlmdEnvoyImagePath                  = "Images.MlmdEnvoy"
-	MlmdGRPCImagePath                   = "Images.MlmdGRPC"
-	MlmdWriterImagePath                 = "Images.MlmdWriter"
-	ObjStoreConnectionTimeoutConfigName = "DSPO.HealthCheck.ObjectStore.ConnectionTimeout"
-	DBConnectionTimeoutConfigName       = "DSPO.HealthCheck.Database.ConnectionTimeout"
-	RequeueTimeConfigName               = "DSPO.RequeueTime"
+	APIServerImagePath                   = "Images.ApiServer"
+	APIServerArtifactImagePath           = "Images.Artifact"
+	PersistenceAgentImagePath            = "Images.PersistentAgent"
+	ScheduledWorkflowImagePath           = "Images.ScheduledWorkflow"
+	APIServerCacheImagePath              = "Images.Cache"
+	APIServerMoveResultsImagePath        = "Images.MoveResultsImage"
+	MariaDBImagePath                     = "Images.MariaDB"
+	OAuthProxyImagePath                  = "Images.OAuthProxy"
+	MlmdEnvoyImagePath                   = "Images.MlmdEnvoy"
+	MlmdGRPCImagePath                    = "Images.MlmdGRPC"
+	MlmdWriterImagePath                  = "Images.MlmdWriter"
+	ImagePrepullerImagePath              = "Images.ImagePrepuller"
+	CacheServerImagePath                 = "Images.CacheServer"
+	HeadroomImagePath                    = "Images.Headroom"
+	MariaDBExporterImagePath             = "Images.MariaDBExporter"
+	ObjStoreConnectionTimeoutConfigName  = "DSPO.HealthCheck.ObjectStore.ConnectionTimeout"
+	DBConnectionTimeoutConfigName        = "DSPO.HealthCheck.Database.ConnectionTimeout"
+	RequeueTimeConfigName                = "DSPO.RequeueTime"
+	ArchitecturesConfigName              = "DSPO.Architectures"
+	DefaultSeccompProfileConfigName      = "DSPO.DefaultSeccompProfile"
+	ArchitectureImagesConfigPrefix       = "ImagesByArchitecture"
+	NodeSelectorConfigName               = "DSPO.NodeSelector"
+	RunReportMonitorIntervalConfigName   = "DSPO.RunReportMonitor.Interval"
+	RunMaintenanceIntervalConfigName     = "DSPO.RunMaintenance.Interval"
+	StepLogsTailLinesConfigName          = "DSPO.StepExitHandler.LogTailLines"
+	StepLogsLimitBytesConfigName         = "DSPO.StepExitHandler.LogLimitBytes"
+	TelemetryEnabledConfigName           = "DSPO.Telemetry.Enabled"
+	TelemetryEndpointConfigName          = "DSPO.Telemetry.Endpoint"
+	TelemetryIntervalConfigName          = "DSPO.Telemetry.Interval"
+	RouteHostnameTemplateConfigName      = "DSPO.RouteHostnameTemplate"
+	TemplatesPathConfigName              = "DSPO.TemplatesPath"
+	TemplateOverlaysPathConfigName       = "DSPO.TemplateOverlaysPath"
+	TemplateValuesConfigName             = "DSPO.TemplateValues"
+	WebhookServiceConfigName             = "DSPO.WebhookService"
+	DataResidencyRegionsConfigName       = "DSPO.DataResidency.AllowedRegions"
+	DataResidencyClusterConfigName       = "DSPO.DataResidency.ClusterRegion"
+	KnownGoodObservationWindowConfigName = "DSPO.Rollback.ObservationWindow"
+	WebhookAllowedHostsConfigName        = "DSPO.Webhooks.AllowedHosts"
 )
 
 // DSPA Status Condition Types
+// There is no WorkflowControllerReady, as the Tekton controller running the PipelineRuns isn't deployed per DSPA.
 const (
 	DatabaseAvailable      = "DatabaseAvailable"
 	ObjectStoreAvailable   = "ObjectStoreAvailable"
 	APIServerReady         = "APIServerReady"
 	PersistenceAgentReady  = "PersistenceAgentReady"
 	ScheduledWorkflowReady = "ScheduledWorkflowReady"
+	CacheServerReady       = "CacheServerReady"
+	DatabaseReady          = "DatabaseReady"
+	ObjectStoreReady       = "ObjectStoreReady"
+	MLMDReady              = "MLMDReady"
 	CrReady                = "Ready"
+	UpgradePending         = "UpgradePending"
+	ManifestsRendered      = "ManifestsRendered"
+	DataResidencyCompliant = "DataResidencyCompliant"
 )
 
 // DSPA Ready Status Condition Reasons
@@ -95,6 +170,40 @@ const (
 	FailingToDeploy             = "FailingToDeploy"
 	Deploying                   = "Deploying"
 	ComponentDeploymentNotFound = "ComponentDeploymentNotFound"
+	AwaitingApproval            = "AwaitingApproval"
+	AwaitingUpgradeHooks        = "AwaitingUpgradeHooks"
+	UpgradeHookFailed           = "UpgradeHookFailed"
+	UpToDate                    = "UpToDate"
+	NameCollision               = "NameCollision"
+	RenderFailed                = "RenderFailed"
+	RegionAllowed               = "RegionAllowed"
+)
+
+// DSPA Status Condition Failure Reasons
+// Stable, machine-readable classes of failures found by the
+// health checks and in component pods, so automation can
+// branch on the Reason rather than parsing the Message
+const (
+	DBAuthFailed             = "DBAuthFailed"
+	DBNotFound               = "DBNotFound"
+	DBConnectionFailed       = "DBConnectionFailed"
+	DBConcurrencyUnsupported = "DBConcurrencyUnsupported"
+	ObjStoreAuthFailed       = "ObjStoreAuthFailed"
+	BucketNotFound           = "BucketNotFound"
+	ObjStoreConnectionFailed = "ObjStoreConnectionFailed"
+	TLSHandshakeError        = "TLSHandshakeError"
+	ImagePullBackOff         = "ImagePullBackOff"
+	CrashLoopBackOff         = "CrashLoopBackOff"
+	RegionNotAllowed         = "RegionNotAllowed"
+	RegionMismatch           = "RegionMismatch"
+	RegionUnknown            = "RegionUnknown"
+)
+
+// Reasons of the Events recorded on DSPAs, besides the failure reasons of their health checks
+const (
+	CreatedEventReason     = "Created"
+	ApplyFailedEventReason = "ApplyFailed"
+	SetupFailedEventReason = "SetupFailed"
 )
 
 // Any required Configmap paths can be added here,
@@ -121,6 +230,55 @@ const DefaultMaxConcurrentReconciles = 10
 
 const DefaultRequeueTime = 2 * time.Minute
 
+// RunNotificationLookback limits commit status reports, step notifications and diagnostics to runs and steps that
+// finished within this window
+const RunNotificationLookback = time.Hour
+
+const DefaultNotificationWorkers = 2
+
+// NotificationPassTimeout bounds a delivery pass of the notifications of a DSPA, the next pass resumes where it stopped
+const NotificationPassTimeout = 5 * time.Minute
+
+// Defaults of the backoff between retries of failed runs
+const (
+	DefaultRunRetryBackoff    = time.Minute
+	DefaultRunRetryMaxBackoff = time.Hour
+)
+
+// QueueWaitWindow limits the average queue wait time metric to runs whose first step started within this window
+const QueueWaitWindow = time.Hour
+
+// Bounds of the per DSPA reconcile intervals
+const (
+	MinResyncPeriod       = 30 * time.Second
+	MinHealthCheckPeriod  = 10 * time.Second
+	MaxReconcileIntervals = 24 * time.Hour
+)
+
+// DefaultKnownGoodObservationWindow is how long new component images must stay Ready before they are recorded as the
+// known-good images rollbacks return to
+const DefaultKnownGoodObservationWindow = time.Hour
+
+// DefaultRunReportMonitorInterval is how often finished runs are checked for unreported final states, 0 disables the check
+const DefaultRunReportMonitorInterval = time.Minute
+
+// DefaultStepLogsTailLines and DefaultStepLogsLimitBytes bound the logs of a step container the step exit handler reads,
+// keeping their end, where steps report why they failed
+const (
+	DefaultStepLogsTailLines  = 10000
+	DefaultStepLogsLimitBytes = 10 * 1024 * 1024
+)
+
+// DefaultRunMaintenanceInterval is how often the runs of DSPAs are checked for notifications, timeouts, retries,
+// finished steps and garbage to collect, 0 disables the checks
+const DefaultRunMaintenanceInterval = time.Minute
+
+// DefaultWebhookService is the Service of the operator deployment serving its admission webhooks
+const DefaultWebhookService = "data-science-pipelines-operator-service"
+
+// DefaultTelemetryInterval is how often the opt-in usage telemetry is reported
+const DefaultTelemetryInterval = 24 * time.Hour
+
 func GetConfigRequiredFields() []string {
 	return requiredFields
 }
@@ -136,6 +294,10 @@ var (
 	MlmdEnvoyResourceRequirements         = createResourceRequirement(resource.MustParse("100m"), resource.MustParse("256Mi"), resource.MustParse("100m"), resource.MustParse("256Mi"))
 	MlmdGRPCResourceRequirements          = createResourceRequirement(resource.MustParse("100m"), resource.MustParse("256Mi"), resource.MustParse("100m"), resource.MustParse("256Mi"))
 	MlmdWriterResourceRequirements        = createResourceRequirement(resource.MustParse("100m"), resource.MustParse("256Mi"), resource.MustParse("100m"), resource.MustParse("256Mi"))
+	ImagePrepullerResourceRequirements    = createResourceRequirement(resource.MustParse("10m"), resource.MustParse("16Mi"), resource.MustParse("50m"), resource.MustParse("64Mi"))
+	CacheServerResourceRequirements       = createResourceRequirement(resource.MustParse("100m"), resource.MustParse("256Mi"), resource.MustParse("250m"), resource.MustParse("512Mi"))
+	MariaDBExporterResourceRequirements   = createResourceRequirement(resource.MustParse("50m"), resource.MustParse("64Mi"), resource.MustParse("200m"), resource.MustParse("128Mi"))
+	HeadroomResourceRequirements          = createResourceRequirement(resource.MustParse("1"), resource.MustParse("2Gi"), resource.MustParse("1"), resource.MustParse("2Gi"))
 )
 
 func createResourceRequirement(RequestsCPU resource.Quantity, RequestsMemory resource.Quantity, LimitsCPU resource.Quantity, LimitsMemory resource.Quantity) dspav1alpha1.ResourceRequirements {
@@ -158,6 +320,40 @@ func GetStringConfigWithDefault(configName, value string) string {
 	return viper.GetString(configName)
 }
 
+func GetStringSliceConfigWithDefault(configName string, value []string) []string {
+	if !viper.IsSet(configName) {
+		return value
+	}
+	return viper.GetStringSlice(configName)
+}
+
+func GetStringMapConfigWithDefault(configName string, value map[string]string) map[string]string {
+	if !viper.IsSet(configName) {
+		return value
+	}
+	return viper.GetStringMapString(configName)
+}
+
+// ArchitectureImagePath returns the config path of the per-architecture override
+// for the image found at imagePath, e.g. for arm64: Images.ApiServer -> ImagesByArchitecture.arm64.ApiServer
+func ArchitectureImagePath(arch, imagePath string) string {
+	return fmt.Sprintf("%s.%s.%s", ArchitectureImagesConfigPrefix, arch, strings.TrimPrefix(imagePath, "Images."))
+}
+
+func GetBoolConfigWithDefault(configName string, value bool) bool {
+	if !viper.IsSet(configName) {
+		return value
+	}
+	return viper.GetBool(configName)
+}
+
+func GetInt64ConfigWithDefault(configName string, value int64) int64 {
+	if !viper.IsSet(configName) {
+		return value
+	}
+	return viper.GetInt64(configName)
+}
+
 func GetDurationConfigWithDefault(configName string, value time.Duration) time.Duration {
 	if !viper.IsSet(configName) {
 		return value
@@ -0,0 +1,53 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// MaxDerivedNameLength is the length of a DNS label, the limit of Service names and label values
+const MaxDerivedNameLength = 63
+
+// DerivedNameHashLength is the length of the hash suffixing truncated names
+const DerivedNameHashLength = 8
+
+// DerivedName returns the name of a resource the DSPA named name manages, e.g. ds-pipeline-ui-sample. Names longer
+// than a DNS label are truncated and suffixed with a hash of the full name, so they stay valid and unique.
+func DerivedName(prefix, name string) string {
+	return truncateName(prefix+name, MaxDerivedNameLength)
+}
+
+// DerivedRouteName returns the name of a Route the DSPA named name manages. OpenShift generates the Route host as
+// <route name>-<namespace>.<ingress domain>, so the route name is shortened further for that first label to fit.
+func DerivedRouteName(prefix, name, namespace string) string {
+	return truncateName(prefix+name, MaxDerivedNameLength-len(namespace)-1)
+}
+
+func truncateName(name string, length int) string {
+	if len(name) <= length {
+		return name
+	}
+	hash := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(hash[:])[:DerivedNameHashLength]
+	if length <= len(suffix) {
+		return suffix
+	}
+	return strings.TrimRight(name[:length-len(suffix)-1], "-") + "-" + suffix
+}
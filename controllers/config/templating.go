@@ -18,11 +18,15 @@ package config
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"strings"
 	"text/template"
 
 	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // PathPrefix is the file system path which template paths will be prefixed with.
@@ -33,8 +37,9 @@ var PathPrefix string
 func PathTemplateSource(path string, context interface{}) mf.Source {
 	f, err := os.Open(prefixedPath(path))
 	if err != nil {
-		panic(err)
+		return errorSource{err}
 	}
+	defer f.Close()
 	return templateSource(f, context)
 }
 
@@ -45,20 +50,81 @@ func prefixedPath(p string) string {
 	return p
 }
 
-// A templating manifest source
+// templateFuncs derive the names of the resources of a DSPA, as the Go code looking them up does, and let template
+// overlays default and derive values without changes to the params
+var templateFuncs = template.FuncMap{
+	"derivedName":      DerivedName,
+	"derivedRouteName": DerivedRouteName,
+	"default":          defaultValue,
+	"required":         requiredValue,
+	"templateValue":    templateValue,
+}
+
+// defaultValue returns the value, or the default if the value is empty, e.g. {{ .APIServer.Image | default "image" }}
+func defaultValue(defaultValue, value interface{}) interface{} {
+	if isEmpty(value) {
+		return defaultValue
+	}
+	return value
+}
+
+// requiredValue fails the render of the template with the message if the value is empty
+func requiredValue(message string, value interface{}) (interface{}, error) {
+	if isEmpty(value) {
+		return nil, fmt.Errorf("%s", message)
+	}
+	return value, nil
+}
+
+// templateValue returns the value of the key in the DSPO.TemplateValues operator config, or an empty string if unset.
+// Keys are case-insensitive, as the config file keys are.
+func templateValue(key string) string {
+	for k, value := range GetStringMapConfigWithDefault(TemplateValuesConfigName, nil) {
+		if strings.EqualFold(k, key) {
+			return value
+		}
+	}
+	return ""
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+// A templating manifest source. Failing to render the template, e.g. for a field missing from the context, is
+// returned by the source rather than panicking, so that the reconcile of the DSPA can fail static.
 func templateSource(r io.Reader, context interface{}) mf.Source {
 	b, err := io.ReadAll(r)
 	if err != nil {
-		panic(err)
+		return errorSource{err}
 	}
-	t, err := template.New("manifestTemplateDSP").Parse(string(b))
+	t, err := template.New("manifestTemplateDSP").Funcs(templateFuncs).Option("missingkey=error").Parse(string(b))
 	if err != nil {
-		panic(err)
+		return errorSource{err}
 	}
 	var b2 bytes.Buffer
 	err = t.Execute(&b2, context)
 	if err != nil {
-		panic(err)
+		return errorSource{err}
 	}
 	return mf.Reader(&b2)
 }
+
+// errorSource is the source of a template that failed to render
+type errorSource struct {
+	err error
+}
+
+func (s errorSource) Parse() ([]unstructured.Unstructured, error) {
+	return nil, s.err
+}
@@ -0,0 +1,123 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	routev1 "github.com/openshift/api/route/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The ConsoleLinks are cluster scoped, so they can't be owned by the DSPA and are deleted explicitly
+const (
+	consoleLinkAPIServerTemplate = "console/consolelink-apiserver.yaml.tmpl"
+	consoleLinkUITemplate        = "console/consolelink-ui.yaml.tmpl"
+)
+
+var consoleLinkGVK = schema.GroupVersionKind{Group: "console.openshift.io", Version: "v1", Kind: "ConsoleLink"}
+
+// UsingConsoleLinks returns true if the DSPA links its Routes from the OpenShift web console.
+func (p *DSPAParams) UsingConsoleLinks(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	if dsp.Spec.ConsoleLinks != nil {
+		return dsp.Spec.ConsoleLinks.Deploy
+	}
+	return false
+}
+
+func consoleLinkName(prefix string, params *DSPAParams) string {
+	return fmt.Sprintf("%s%s.%s", prefix, params.Namespace, params.Name)
+}
+
+// ReconcileConsoleLinks links the API Server and UI Routes from the OpenShift web console. A link is only created once
+// the router assigned a host to its Route, the Route update triggers the reconcile creating it.
+func (r *DSPAReconciler) ReconcileConsoleLinks(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	links := []struct {
+		template string
+		name     string
+		route    string
+		enabled  bool
+		host     *string
+	}{
+		{
+			template: consoleLinkAPIServerTemplate,
+			name:     consoleLinkName("ds-pipeline-", params),
+			route:    config.DerivedRouteName(apiServerDefaultResourceNamePrefix, dsp.Name, dsp.Namespace),
+			enabled:  dsp.Spec.APIServer != nil && dsp.Spec.APIServer.Deploy && dsp.Spec.APIServer.EnableRoute,
+			host:     &params.ConsoleLinkAPIServerHost,
+		},
+		{
+			template: consoleLinkUITemplate,
+			name:     consoleLinkName("ds-pipeline-ui-", params),
+			route:    config.DerivedRouteName("ds-pipeline-ui-", dsp.Name, dsp.Namespace),
+			enabled:  dsp.Spec.MlPipelineUI != nil && dsp.Spec.MlPipelineUI.Deploy,
+			host:     &params.ConsoleLinkUIHost,
+		},
+	}
+	for _, link := range links {
+		if params.UsingConsoleLinks(dsp) && link.enabled {
+			route := &routev1.Route{}
+			err := r.Get(ctx, types.NamespacedName{Name: link.route, Namespace: dsp.Namespace}, route)
+			if err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+			if err == nil && route.Spec.Host != "" {
+				log.Info(fmt.Sprintf("Applying ConsoleLink [%s]", link.name))
+				*link.host = route.Spec.Host
+				if err := r.ApplyWithoutOwner(params, link.template); err != nil {
+					return err
+				}
+				continue
+			}
+			log.Info(fmt.Sprintf("Skipping ConsoleLink [%s], Route [%s] has no host yet", link.name, link.route))
+		}
+		if err := r.deleteConsoleLink(ctx, link.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteConsoleLink deletes the ConsoleLink, if the cluster runs the OpenShift web console.
+func (r *DSPAReconciler) deleteConsoleLink(ctx context.Context, name string) error {
+	link := &unstructured.Unstructured{}
+	link.SetGroupVersionKind(consoleLinkGVK)
+	err := r.DeleteResourceIfItExists(ctx, link, types.NamespacedName{Name: name})
+	if err != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *DSPAReconciler) CleanUpConsoleLinks(ctx context.Context, params *DSPAParams) error {
+	for _, prefix := range []string{"ds-pipeline-", "ds-pipeline-ui-"} {
+		if err := r.deleteConsoleLink(ctx, consoleLinkName(prefix, params)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDeployConsoleLinks(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true, EnableRoute: true},
+			MlPipelineUI:  &dspav1alpha1.MlPipelineUI{Deploy: true, Image: "someimage"},
+			ConsoleLinks:  &dspav1alpha1.ConsoleLinks{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// Ensure links aren't created until the router assigns a host to their Route
+	assert.Nil(t, reconciler.ReconcileConsoleLinks(ctx, dspa, params))
+	link := &unstructured.Unstructured{}
+	link.SetGroupVersionKind(consoleLinkGVK)
+	created, err := reconciler.IsResourceCreated(ctx, link, "ds-pipeline-testnamespace.testdspa", "")
+	assert.False(t, created)
+	assert.Nil(t, err)
+
+	assert.Nil(t, reconciler.Create(ctx, &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-testdspa", Namespace: dspa.Namespace},
+		Spec:       routev1.RouteSpec{Host: "ds-pipeline-testdspa-testnamespace.apps.example.com"},
+	}))
+	assert.Nil(t, reconciler.Create(ctx, &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-ui-testdspa", Namespace: dspa.Namespace},
+		Spec:       routev1.RouteSpec{Host: "ds-pipeline-ui-testdspa-testnamespace.apps.example.com"},
+	}))
+	assert.Nil(t, reconciler.ReconcileConsoleLinks(ctx, dspa, params))
+
+	// Ensure the links point to the Routes, in the dashboard of the DSPA's project only
+	for name, href := range map[string]string{
+		"ds-pipeline-testnamespace.testdspa":    "https://ds-pipeline-testdspa-testnamespace.apps.example.com",
+		"ds-pipeline-ui-testnamespace.testdspa": "https://ds-pipeline-ui-testdspa-testnamespace.apps.example.com",
+	} {
+		assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: name}, link))
+		actualHref, _, _ := unstructured.NestedString(link.Object, "spec", "href")
+		assert.Equal(t, href, actualHref)
+		namespaces, _, _ := unstructured.NestedStringSlice(link.Object, "spec", "namespaceDashboard", "namespaces")
+		assert.Equal(t, []string{"testnamespace"}, namespaces)
+	}
+
+	// Ensure the link to the API Server is removed with its Route
+	dspa.Spec.APIServer.EnableRoute = false
+	assert.Nil(t, reconciler.ReconcileConsoleLinks(ctx, dspa, params))
+	created, err = reconciler.IsResourceCreated(ctx, link, "ds-pipeline-testnamespace.testdspa", "")
+	assert.False(t, created)
+	assert.Nil(t, err)
+	created, err = reconciler.IsResourceCreated(ctx, link, "ds-pipeline-ui-testnamespace.testdspa", "")
+	assert.True(t, created)
+	assert.Nil(t, err)
+
+	// Ensure the links are removed with the DSPA, as they can't be owned by it
+	assert.Nil(t, reconciler.CleanUpConsoleLinks(ctx, &DSPAParams{Name: dspa.Name, Namespace: dspa.Namespace}))
+	created, err = reconciler.IsResourceCreated(ctx, link, "ds-pipeline-ui-testnamespace.testdspa", "")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,183 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// costAttributionWebhookTemplate is cluster scoped, so it can't be owned by the DSPA and is deleted explicitly
+const costAttributionWebhookTemplate = "cost-attribution/webhook.yaml.tmpl"
+
+// CostAttributionWebhookPath is the path of the operator's webhook server the cost attribution webhooks call
+const CostAttributionWebhookPath = "/label-pipeline-runs"
+
+const (
+	runLabelReference      = "run.label."
+	runAnnotationReference = "run.annotation."
+)
+
+var (
+	costAttributionReference = regexp.MustCompile(`\$\(([^)]*)\)`)
+	invalidLabelValueChars   = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// UsingCostAttribution returns true if the DSPA labels the pods of its pipeline runs.
+func (p *DSPAParams) UsingCostAttribution(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	return dsp.Spec.CostAttribution != nil && len(dsp.Spec.CostAttribution.Labels) > 0
+}
+
+// SetupCostAttribution validates the labels of the cost attribution of the DSPA, which are otherwise only applied by
+// the webhook, where invalid labels would fail the creation of every run.
+func (p *DSPAParams) SetupCostAttribution(dsp *dspav1alpha1.DataSciencePipelinesApplication) error {
+	if !p.UsingCostAttribution(dsp) {
+		return nil
+	}
+	for key, value := range dsp.Spec.CostAttribution.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("costAttribution label [%s] is not a valid label key: %s", key, strings.Join(errs, ", "))
+		}
+		for _, match := range costAttributionReference.FindAllStringSubmatch(value, -1) {
+			switch reference := match[1]; {
+			case reference == "namespace", reference == "dspa", reference == "submitter":
+			case strings.HasPrefix(reference, runLabelReference) && len(reference) > len(runLabelReference):
+			case strings.HasPrefix(reference, runAnnotationReference) && len(reference) > len(runAnnotationReference):
+			default:
+				return fmt.Errorf("costAttribution label [%s] refers to [%s], which is not one of $(namespace), $(dspa), "+
+					"$(submitter), $(run.label.<key>) or $(run.annotation.<key>)", key, match[0])
+			}
+		}
+	}
+	return nil
+}
+
+func (r *DSPAReconciler) ReconcileCostAttribution(dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	params.CostAttributionWebhookPath = CostAttributionWebhookPath
+	if !params.UsingCostAttribution(dsp) {
+		log.Info("Skipping Application of Cost Attribution Resources")
+		return r.DeleteResource(params, costAttributionWebhookTemplate)
+	}
+	if r.WebhookService == nil {
+		return fmt.Errorf("spec.costAttribution requires the operator to serve its admission webhooks, it serves them " +
+			"once a serving certificate is mounted in its webhook certificate directory")
+	}
+
+	log.Info("Applying Cost Attribution Resources")
+	params.WebhookService = *r.WebhookService
+	err := r.ApplyWithoutOwner(params, costAttributionWebhookTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Cost Attribution Resources")
+	return nil
+}
+
+func (r *DSPAReconciler) CleanUpCostAttribution(params *DSPAParams) error {
+	params.CostAttributionWebhookPath = CostAttributionWebhookPath
+	return r.DeleteResource(params, costAttributionWebhookTemplate)
+}
+
+// costAttributionLabelValue replaces the references to the run in the value of a cost attribution label, and makes
+// the result a valid label value.
+func costAttributionLabelValue(value string, dspa *dspav1alpha1.DataSciencePipelinesApplication, run *unstructured.Unstructured, submitter string) string {
+	value = costAttributionReference.ReplaceAllStringFunc(value, func(match string) string {
+		switch reference := match[2 : len(match)-1]; {
+		case reference == "namespace":
+			return dspa.Namespace
+		case reference == "dspa":
+			return dspa.Name
+		case reference == "submitter":
+			return submitter
+		case strings.HasPrefix(reference, runLabelReference):
+			return run.GetLabels()[strings.TrimPrefix(reference, runLabelReference)]
+		case strings.HasPrefix(reference, runAnnotationReference):
+			return run.GetAnnotations()[strings.TrimPrefix(reference, runAnnotationReference)]
+		}
+		return ""
+	})
+	value = invalidLabelValueChars.ReplaceAllString(value, "_")
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	// Label values must start and end with an alphanumeric character
+	return strings.TrimFunc(value, func(c rune) bool {
+		return c == '_' || c == '-' || c == '.'
+	})
+}
+
+// CostAttributionWebhook sets the cost attribution labels of a DSPA on the PipelineRuns it runs, the PipelineRuns
+// with its pipeline runner ServiceAccount, so Tekton propagates them to their TaskRuns and pods.
+type CostAttributionWebhook struct {
+	Client client.Client
+	Log    logr.Logger
+}
+
+func (w *CostAttributionWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	run := &unstructured.Unstructured{}
+	if err := run.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	dspas := &dspav1alpha1.DataSciencePipelinesApplicationList{}
+	if err := w.Client.List(ctx, dspas, client.InNamespace(req.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	serviceAccount, _, _ := unstructured.NestedString(run.Object, "spec", "serviceAccountName")
+	for i := range dspas.Items {
+		dspa := &dspas.Items[i]
+		if dspa.Spec.CostAttribution == nil || serviceAccount != config.DerivedName("pipeline-runner-", dspa.Name) {
+			continue
+		}
+
+		labels := run.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		// Values refer to the labels of the run as submitted, not to the labels set here
+		submitted := run.DeepCopy()
+		for key, value := range dspa.Spec.CostAttribution.Labels {
+			// Labels set by the submitter of the run are kept, invalid keys are reported by the reconcile of the DSPA
+			if _, found := labels[key]; found || len(validation.IsQualifiedName(key)) > 0 {
+				continue
+			}
+			if value := costAttributionLabelValue(value, dspa, submitted, req.UserInfo.Username); value != "" {
+				labels[key] = value
+			}
+		}
+		run.SetLabels(labels)
+		labeled, err := run.MarshalJSON()
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		return admission.PatchResponseFromRaw(req.Object.Raw, labeled)
+	}
+	return admission.Allowed("")
+}
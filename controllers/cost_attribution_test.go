@@ -0,0 +1,129 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newCostAttributionTestDSPA(labels map[string]string) *dspav1alpha1.DataSciencePipelinesApplication {
+	return &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:       &dspav1alpha1.APIServer{Deploy: true},
+			Database:        &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage:   &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+			CostAttribution: &dspav1alpha1.CostAttribution{Labels: labels},
+		},
+	}
+}
+
+func TestDeployCostAttribution(t *testing.T) {
+	expectedWebhookName := "ds-pipeline-cost-attribution-testnamespace.testdspa"
+	dspa := newCostAttributionTestDSPA(map[string]string{"cost-center": "ml-team"})
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// Ensure the runs aren't labeled if the operator doesn't serve the webhook
+	assert.ErrorContains(t, reconciler.ReconcileCostAttribution(dspa, params), "requires the operator to serve its admission webhooks")
+
+	reconciler.WebhookService = &types.NamespacedName{Name: "dspo-service", Namespace: "dspo"}
+	assert.Nil(t, reconciler.ReconcileCostAttribution(dspa, params))
+	webhook := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedWebhookName}, webhook))
+	assert.Equal(t, CostAttributionWebhookPath, *webhook.Webhooks[0].ClientConfig.Service.Path)
+	assert.Equal(t, []string{"pipelineruns"}, webhook.Webhooks[0].Rules[0].Resources)
+	assert.Equal(t, admissionregistrationv1.Ignore, *webhook.Webhooks[0].FailurePolicy)
+
+	// Ensure invalid labels are reported by the reconcile, rather than failing the runs
+	dspa.Spec.CostAttribution.Labels = map[string]string{"cost center": "ml-team"}
+	assert.ErrorContains(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log), "is not a valid label key")
+	dspa.Spec.CostAttribution.Labels = map[string]string{"user": "$(run.owner)"}
+	assert.ErrorContains(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log), "refers to [$(run.owner)]")
+
+	// Ensure the webhook is removed once the cost attribution is
+	dspa.Spec.CostAttribution = nil
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileCostAttribution(dspa, params))
+	created, err := reconciler.IsResourceCreated(ctx, &admissionregistrationv1.MutatingWebhookConfiguration{}, expectedWebhookName, "")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
+
+func TestCostAttributionWebhook(t *testing.T) {
+	dspa := newCostAttributionTestDSPA(map[string]string{
+		"cost-center":                  "ml-team",
+		"kubecost.io/project":          "$(namespace)-$(dspa)",
+		"opendatahub.io/submitted-by":  "$(submitter)",
+		"opendatahub.io/run-id":        "$(run.label.pipeline/runid)",
+		"opendatahub.io/experiment":    "$(run.annotation.example.com/experiment)",
+		"opendatahub.io/set-by-runner": "overridden",
+	})
+	ctx, _, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	webhook := &CostAttributionWebhook{Client: reconciler.Client, Log: reconciler.Log}
+	admit := func(run *unstructured.Unstructured) admission.Response {
+		raw, err := run.MarshalJSON()
+		assert.Nil(t, err)
+		return webhook.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: run.GetNamespace(),
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+			UserInfo:  authenticationv1.UserInfo{Username: "system:serviceaccount:testnamespace:ds-pipeline-testdspa"},
+		}})
+	}
+
+	// Ensure the runs of the DSPA are labeled, with valid label values, keeping the labels they were submitted with
+	run := newTestPipelineRun("somerun", "testnamespace", nil, false)
+	run.SetLabels(map[string]string{runIDLabel: "0b2e6c1a", "opendatahub.io/set-by-runner": "kept"})
+	run.SetAnnotations(map[string]string{"example.com/experiment": "Nightly training"})
+	assert.Nil(t, unstructured.SetNestedField(run.Object, "pipeline-runner-testdspa", "spec", "serviceAccountName"))
+	response := admit(run)
+	assert.True(t, response.Allowed)
+	patches := map[string]interface{}{}
+	for _, patch := range response.Patches {
+		patches[patch.Path] = patch.Value
+	}
+	assert.Equal(t, map[string]interface{}{
+		"/metadata/labels/cost-center":                  "ml-team",
+		"/metadata/labels/kubecost.io~1project":         "testnamespace-testdspa",
+		"/metadata/labels/opendatahub.io~1submitted-by": "system_serviceaccount_testnamespace_ds-pipeline-testdspa",
+		"/metadata/labels/opendatahub.io~1run-id":       "0b2e6c1a",
+		"/metadata/labels/opendatahub.io~1experiment":   "Nightly_training",
+	}, patches)
+
+	// Ensure runs of other DSPAs and namespaces are left as they are
+	assert.Nil(t, unstructured.SetNestedField(run.Object, "pipeline-runner-otherdspa", "spec", "serviceAccountName"))
+	response = admit(run)
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Patches)
+	response = admit(newTestPipelineRun("somerun", "othernamespace", nil, false))
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Patches)
+}
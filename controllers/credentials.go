@@ -0,0 +1,190 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	b64 "encoding/base64"
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const redactedCredential = "[REDACTED]"
+
+// minCredentialLength is the length below which credentials aren't matched, as short ones such as "1" would match
+// unrelated text far more often than they could leak
+const minCredentialLength = 4
+
+// plaintextCredentials returns the decoded passwords of the DSPA's database and object store, which must only
+// ever be stored in Secrets. Passwords equal to settings the DSPA publishes anyway, such as its database name, are
+// left out, as they'd match those settings everywhere.
+func (p *DSPAParams) plaintextCredentials() []string {
+	public := map[string]bool{
+		p.DBConnection.Username:          true,
+		p.DBConnection.DBName:            true,
+		p.ObjectStorageConnection.Bucket: true,
+	}
+	var credentials []string
+	for _, encoded := range []string{p.DBConnection.Password, p.ObjectStorageConnection.SecretAccessKey} {
+		decoded, err := b64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(decoded) < minCredentialLength || public[string(decoded)] {
+			continue
+		}
+		credentials = append(credentials, string(decoded))
+	}
+	return credentials
+}
+
+// isTokenChar returns true for the characters a credential match may not be adjacent to, so credentials are only
+// matched as whole tokens, e.g. "root" in "password=root" but not in "rootless".
+func isTokenChar(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '_' || b == '-'
+}
+
+// redactCredentials replaces the DSPA's credentials in a message, e.g. an error reported by a database driver.
+func (p *DSPAParams) redactCredentials(message string) string {
+	for _, credential := range p.plaintextCredentials() {
+		var redacted strings.Builder
+		for {
+			i := strings.Index(message, credential)
+			if i < 0 {
+				break
+			}
+			end := i + len(credential)
+			if (i > 0 && isTokenChar(message[i-1])) || (end < len(message) && isTokenChar(message[end])) {
+				redacted.WriteString(message[:end])
+			} else {
+				redacted.WriteString(message[:i] + redactedCredential)
+			}
+			message = message[end:]
+		}
+		message = redacted.String() + message
+	}
+	return message
+}
+
+// containsCredential returns true if any of the values contains one of the DSPA's credentials.
+func (p *DSPAParams) containsCredential(values map[string]string) bool {
+	for _, value := range values {
+		if p.redactCredentials(value) != value {
+			return true
+		}
+	}
+	return false
+}
+
+// podSpecFields returns the path of the pod spec of the workload kinds whose containers are checked for credentials.
+func podSpecFields(kind string) ([]string, bool) {
+	switch kind {
+	case "Deployment", "DaemonSet", "Job":
+		return []string{"spec", "template", "spec"}, true
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}, true
+	}
+	return nil, false
+}
+
+// rejectPlaintextCredentials fails rendering of ConfigMaps and workloads that would hold the DSPA's credentials in
+// plaintext, i.e. in ConfigMap data, env literals or container arguments, as they must be referenced from their
+// Secret instead.
+func rejectPlaintextCredentials(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		switch u.GetKind() {
+		case "ConfigMap":
+			data, _, err := unstructured.NestedStringMap(u.Object, "data")
+			if err != nil {
+				return err
+			}
+			if params.containsCredential(data) {
+				return fmt.Errorf("refusing to apply ConfigMap [%s], it would contain plaintext credentials", u.GetName())
+			}
+		default:
+			podSpec, ok := podSpecFields(u.GetKind())
+			if !ok {
+				return nil
+			}
+			for _, field := range []string{"initContainers", "containers"} {
+				containers, _, err := unstructured.NestedSlice(u.Object, append(podSpec, field)...)
+				if err != nil {
+					return err
+				}
+				for _, c := range containers {
+					container, ok := c.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					for _, argsField := range []string{"command", "args"} {
+						args, _, _ := unstructured.NestedStringSlice(container, argsField)
+						for _, arg := range args {
+							if params.redactCredentials(arg) != arg {
+								return fmt.Errorf("refusing to apply %s [%s], the %s of container [%s] would contain "+
+									"plaintext credentials, read them from an env var referencing their Secret instead",
+									u.GetKind(), u.GetName(), argsField, container["name"])
+							}
+						}
+					}
+					env, _, _ := unstructured.NestedSlice(container, "env")
+					for _, e := range env {
+						envVar, ok := e.(map[string]interface{})
+						if !ok {
+							continue
+						}
+						if value, ok := envVar["value"].(string); ok && params.redactCredentials(value) != value {
+							return fmt.Errorf("refusing to apply %s [%s], env var [%s] of container [%s] would contain "+
+								"plaintext credentials, reference them with a secretKeyRef instead",
+								u.GetKind(), u.GetName(), envVar["name"], container["name"])
+						}
+					}
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// ScrubPlaintextCredentials redacts the DSPA's credentials from the ConfigMaps it owns, e.g. left by an earlier
+// operator version or added by hand, as keys DSPO doesn't render are kept when it applies them.
+func (r *DSPAReconciler) ScrubPlaintextCredentials(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.List(ctx, configMaps, client.InNamespace(dsp.Namespace)); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if !metav1.IsControlledBy(configMap, dsp) || !params.containsCredential(configMap.Data) {
+			continue
+		}
+		for key, value := range configMap.Data {
+			configMap.Data[key] = params.redactCredentials(value)
+		}
+		log.Info(fmt.Sprintf("Redacting plaintext credentials from ConfigMap [%s]", configMap.Name))
+		if err := r.Update(ctx, configMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
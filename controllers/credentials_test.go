@@ -0,0 +1,140 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	b64 "encoding/base64"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestCredentialParams() *DSPAParams {
+	params := &DSPAParams{}
+	params.DBConnection.Password = b64.StdEncoding.EncodeToString([]byte("dbsecret"))
+	params.ObjectStorageConnection.SecretAccessKey = b64.StdEncoding.EncodeToString([]byte("s3secret"))
+	return params
+}
+
+func TestRejectPlaintextCredentials(t *testing.T) {
+	params := newTestCredentialParams()
+	transform := rejectPlaintextCredentials(params)
+
+	newDeployment := func(env map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":     "Deployment",
+			"metadata": map[string]interface{}{"name": "ds-pipeline-testdspa"},
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "ds-pipeline-api-server", "env": []interface{}{env}}},
+			}}},
+		}}
+	}
+	newCronJob := func(args ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":     "CronJob",
+			"metadata": map[string]interface{}{"name": "ds-pipeline-backup-testdspa"},
+			"spec": map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{
+				"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "backup", "args": args}},
+				}},
+			}}},
+		}}
+	}
+	newConfigMap := func(value string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":     "ConfigMap",
+			"metadata": map[string]interface{}{"name": "ds-pipeline-ui-configmap-testdspa"},
+			"data":     map[string]interface{}{"config.json": value},
+		}}
+	}
+
+	// Ensure credentials referenced from their Secret, and other values, are accepted
+	assert.Nil(t, transform(newDeployment(map[string]interface{}{
+		"name":      "DBCONFIG_PASSWORD",
+		"valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "ds-pipeline-db-testdspa", "key": "password"}},
+	})))
+	assert.Nil(t, transform(newDeployment(map[string]interface{}{"name": "DBCONFIG_USER", "value": "mlpipeline"})))
+	assert.Nil(t, transform(newConfigMap(`{"s3": {"endpoint": "minio"}}`)))
+	assert.Nil(t, transform(newCronJob("mysqldump", "--password=$(DBCONFIG_PASSWORD)")))
+
+	// Ensure values merely containing a credential are accepted
+	assert.Nil(t, transform(newDeployment(map[string]interface{}{"name": "DBCONFIG_HOST", "value": "dbsecretive.example.com"})))
+
+	// Ensure credentials in env literals or ConfigMap data are rejected
+	assert.NotNil(t, transform(newDeployment(map[string]interface{}{"name": "DBCONFIG_PASSWORD", "value": "dbsecret"})))
+	assert.NotNil(t, transform(newConfigMap(`{"s3": {"secretKey": "s3secret"}}`)))
+	assert.NotNil(t, transform(newCronJob("mysqldump", "--password=dbsecret")))
+}
+
+func TestRedactCredentials(t *testing.T) {
+	params := newTestCredentialParams()
+	assert.Equal(t, "Access denied with password [REDACTED]", params.redactCredentials("Access denied with password dbsecret"))
+	assert.Equal(t, "Could not connect to database", params.redactCredentials("Could not connect to database"))
+	assert.Equal(t, "Could not connect", (&DSPAParams{}).redactCredentials("Could not connect"))
+
+	// Ensure credentials are only redacted as whole tokens
+	assert.Equal(t, "user=mlpipeline password=[REDACTED]", params.redactCredentials("user=mlpipeline password=dbsecret"))
+	assert.Equal(t, "Unknown host dbsecretive", params.redactCredentials("Unknown host dbsecretive"))
+
+	// Ensure short credentials, and ones equal to the DSPA's public settings, aren't redacted
+	params.DBConnection.Password = b64.StdEncoding.EncodeToString([]byte("1"))
+	params.DBConnection.DBName = "mlpipeline"
+	params.ObjectStorageConnection.SecretAccessKey = b64.StdEncoding.EncodeToString([]byte("mlpipeline"))
+	assert.Equal(t, "Unknown database mlpipeline on port 1", params.redactCredentials("Unknown database mlpipeline on port 1"))
+}
+
+func TestScrubPlaintextCredentials(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace", UID: "testuid"},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+	params := newTestCredentialParams()
+
+	owner := []metav1.OwnerReference{{
+		APIVersion: "datasciencepipelinesapplications.opendatahub.io/v1alpha1",
+		Kind:       "DataSciencePipelinesApplication",
+		Name:       "testdspa",
+		UID:        "testuid",
+		Controller: util.BoolPointer(true),
+	}}
+	owned := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "testnamespace", OwnerReferences: owner},
+		Data:       map[string]string{"config": "password=dbsecret", "endpoint": "minio"},
+	}
+	unowned := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "unowned", Namespace: "testnamespace"},
+		Data:       map[string]string{"config": "password=dbsecret"},
+	}
+	assert.Nil(t, reconciler.Create(ctx, owned))
+	assert.Nil(t, reconciler.Create(ctx, unowned))
+
+	assert.Nil(t, reconciler.ScrubPlaintextCredentials(ctx, dspa, params))
+
+	// Ensure credentials are only redacted from the ConfigMaps the DSPA owns
+	got := &corev1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "owned", Namespace: "testnamespace"}, got))
+	assert.Equal(t, map[string]string{"config": "password=[REDACTED]", "endpoint": "minio"}, got.Data)
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "unowned", Namespace: "testnamespace"}, got))
+	assert.Equal(t, "password=dbsecret", got.Data["config"])
+}
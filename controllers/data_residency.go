@@ -0,0 +1,176 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+)
+
+// allNamespaces is the DataResidency.AllowedRegions entry of the namespaces without one of their own
+const allNamespaces = "*"
+
+// DataResidency is the regions the operator config allows the artifacts of the namespace of a DSPA to be stored in
+type DataResidency struct {
+	AllowedRegions []string
+	// Region the artifacts of the DSPA are declared in, the cluster region for the Minio it deploys
+	Region string
+	// Whether the artifacts are stored in external object storage, whose bucket location is checked
+	External bool
+}
+
+// allowedRegions returns the regions the operator config allows the artifacts of the namespace to be stored in, from
+// its DataResidency.AllowedRegions entry or the "*" one, and false if it restricts neither.
+func allowedRegions(namespace string) ([]string, bool) {
+	namespaceRegions := config.GetStringMapConfigWithDefault(config.DataResidencyRegionsConfigName, nil)
+	regions, restricted := namespaceRegions[namespace]
+	if !restricted {
+		regions, restricted = namespaceRegions[allNamespaces]
+	}
+	if !restricted {
+		return nil, false
+	}
+	allowed := []string{}
+	for _, region := range strings.Split(regions, ",") {
+		if region = strings.TrimSpace(region); region != "" {
+			allowed = append(allowed, region)
+		}
+	}
+	return allowed, true
+}
+
+func regionAllowed(region string, allowed []string) bool {
+	for _, allowedRegion := range allowed {
+		if region == allowedRegion {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupDataResidency validates the regions the DSPA stores its artifacts and backups in, when the operator config
+// restricts the regions of its namespace. It only reads the spec, so the validation webhook rejects the same DSPAs.
+func (p *DSPAParams) SetupDataResidency(dsp *dspav1alpha1.DataSciencePipelinesApplication) error {
+	p.DataResidency = nil
+	allowed, restricted := allowedRegions(dsp.Namespace)
+	if !restricted {
+		return nil
+	}
+	residency := &DataResidency{AllowedRegions: allowed}
+	checkRegion := func(field, region string) error {
+		if region == "" {
+			return fmt.Errorf("%s is required, the artifacts of namespace [%s] must stay in regions [%s]",
+				field, dsp.Namespace, strings.Join(allowed, ", "))
+		}
+		if !regionAllowed(region, allowed) {
+			return fmt.Errorf("%s [%s] is not allowed, the artifacts of namespace [%s] must stay in regions [%s]",
+				field, region, dsp.Namespace, strings.Join(allowed, ", "))
+		}
+		return nil
+	}
+
+	if dsp.Spec.ObjectStorage != nil && dsp.Spec.ObjectStorage.ExternalStorage != nil {
+		residency.External = true
+		residency.Region = dsp.Spec.ObjectStorage.ExternalStorage.Region
+		if err := checkRegion("spec.objectStorage.externalStorage.region", residency.Region); err != nil {
+			return err
+		}
+	} else if residency.Region = config.GetStringConfigWithDefault(config.DataResidencyClusterConfigName, ""); residency.Region != "" {
+		// Without a cluster region, the artifacts of the Minio deployed by the DSPA are only known to stay in the cluster
+		if err := checkRegion("the cluster region of spec.objectStorage.minio", residency.Region); err != nil {
+			return err
+		}
+	}
+	if backup := dsp.Spec.Backup; backup != nil && backup.Destination != nil && backup.Destination.ExternalStorage != nil {
+		if err := checkRegion("spec.backup.destination.region", backup.Destination.Region); err != nil {
+			return err
+		}
+	}
+	p.DataResidency = residency
+	return nil
+}
+
+// DataResidencyViolated returns true once the object store reported the bucket of the DSPA outside of the regions
+// allowed in its namespace, or of the region it's declared in. The API Server and Scheduled Workflow controller are then
+// scaled down, so no new run writes artifacts to the bucket until it's fixed.
+func (p *DSPAParams) DataResidencyViolated() bool {
+	diagnosis := p.DataResidencyDiagnosis
+	return p.DataResidency != nil && diagnosis != nil &&
+		(diagnosis.Reason == config.RegionNotAllowed || diagnosis.Reason == config.RegionMismatch)
+}
+
+// checkDataResidency verifies the bucket of the DSPA is in the region it's declared in, as reported by the object
+// store, for the DataResidencyCompliant condition.
+func (r *DSPAReconciler) checkDataResidency(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) *Diagnosis {
+	residency := params.DataResidency
+	if residency == nil {
+		return nil
+	}
+	bucket := params.ObjectStorageConnection.Bucket
+	if !residency.External {
+		if residency.Region == "" {
+			return &Diagnosis{Reason: config.RegionAllowed, Message: "Artifacts are stored in the Minio of the DSPA, in the cluster."}
+		}
+		return &Diagnosis{Reason: config.RegionAllowed, Message: fmt.Sprintf(
+			"Artifacts are stored in the Minio of the DSPA, in the allowed cluster region [%s].", residency.Region)}
+	}
+	if params.ObjectStorageHealthCheckDisabled(dsp) {
+		return &Diagnosis{Reason: config.RegionAllowed, Message: fmt.Sprintf(
+			"Bucket [%s] is declared in the allowed region [%s], not verified as the Object Storage health check is disabled.", bucket, residency.Region)}
+	}
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	unknown := func(err error) *Diagnosis {
+		return &Diagnosis{Reason: config.RegionUnknown, Message: fmt.Sprintf("Could not read the region of bucket [%s]: %s", bucket, err)}
+	}
+	endpoint, err := joinHostPort(params.ObjectStorageConnection.Host, params.ObjectStorageConnection.Port)
+	if err != nil {
+		return unknown(err)
+	}
+	accesskey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.AccessKeyID)
+	if err != nil {
+		return unknown(fmt.Errorf("could not decode Object Storage Access Key ID"))
+	}
+	secretkey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.SecretAccessKey)
+	if err != nil {
+		return unknown(fmt.Errorf("could not decode Object Storage Secret Access Key"))
+	}
+	objStoreConnectionTimeout := config.GetDurationConfigWithDefault(config.ObjStoreConnectionTimeoutConfigName, config.DefaultObjStoreConnectionTimeout)
+	location, err := QueryBucketLocation(ctx, log, endpoint, bucket, accesskey, secretkey,
+		*params.ObjectStorageConnection.Secure, params.APICustomPemCerts, objStoreConnectionTimeout)
+	if err != nil {
+		log.Info(fmt.Sprintf("Could not read the region of bucket [%s], Error: %s", bucket, err))
+		return unknown(err)
+	}
+
+	switch {
+	case !regionAllowed(location, residency.AllowedRegions):
+		return &Diagnosis{Reason: config.RegionNotAllowed, Message: fmt.Sprintf(
+			"Bucket [%s] is in region [%s], the artifacts of namespace [%s] must stay in regions [%s]. The API Server "+
+				"and Scheduled Workflow controller are scaled down until it's fixed.",
+			bucket, location, dsp.Namespace, strings.Join(residency.AllowedRegions, ", "))}
+	case location != residency.Region:
+		return &Diagnosis{Reason: config.RegionMismatch, Message: fmt.Sprintf(
+			"Bucket [%s] is in region [%s], not in its declared region [%s]. The API Server and Scheduled Workflow "+
+				"controller are scaled down until it's fixed.", bucket, location, residency.Region)}
+	}
+	return &Diagnosis{Reason: config.RegionAllowed, Message: fmt.Sprintf("Bucket [%s] is in the allowed region [%s].", bucket, location)}
+}
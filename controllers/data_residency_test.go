@@ -0,0 +1,133 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetupDataResidency(t *testing.T) {
+	viper.Set(config.DataResidencyRegionsConfigName, map[string]string{
+		"testnamespace": "eu-west-1, eu-central-1",
+		"*":             "us-east-1",
+	})
+	t.Cleanup(func() {
+		viper.Set(config.DataResidencyRegionsConfigName, nil)
+		viper.Set(config.DataResidencyClusterConfigName, nil)
+	})
+	dspa := newValidationTestDSPA()
+	params := &DSPAParams{}
+
+	// Ensure external storage must declare an allowed region
+	assert.ErrorContains(t, params.SetupDataResidency(dspa), "spec.objectStorage.externalStorage.region is required")
+	dspa.Spec.ObjectStorage.ExternalStorage.Region = "us-east-1"
+	assert.ErrorContains(t, params.SetupDataResidency(dspa), "[us-east-1] is not allowed, the artifacts of namespace [testnamespace] must stay in regions [eu-west-1, eu-central-1]")
+	dspa.Spec.ObjectStorage.ExternalStorage.Region = "eu-central-1"
+	assert.Nil(t, params.SetupDataResidency(dspa))
+	assert.Equal(t, &DataResidency{AllowedRegions: []string{"eu-west-1", "eu-central-1"}, Region: "eu-central-1", External: true}, params.DataResidency)
+
+	// Ensure namespaces without their own entry fall back to the "*" one
+	dspa.Namespace = "othernamespace"
+	assert.ErrorContains(t, params.SetupDataResidency(dspa), "must stay in regions [us-east-1]")
+
+	// Ensure backups are held to the same regions
+	dspa.Namespace = "testnamespace"
+	dspa.Spec.Backup = &dspav1alpha1.Backup{Destination: &dspav1alpha1.BackupDestination{
+		ExternalStorage: &dspav1alpha1.ExternalStorage{Host: "s3.example.com", Bucket: "backups", Region: "us-east-1"},
+	}}
+	assert.ErrorContains(t, params.SetupDataResidency(dspa), "spec.backup.destination.region [us-east-1] is not allowed")
+	dspa.Spec.Backup = nil
+
+	// Ensure a deployed Minio is checked against the cluster region, when one is configured
+	dspa.Spec.ObjectStorage = &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: true, Image: "someimage"}}
+	assert.Nil(t, params.SetupDataResidency(dspa))
+	viper.Set(config.DataResidencyClusterConfigName, "us-east-1")
+	assert.ErrorContains(t, params.SetupDataResidency(dspa), "the cluster region of spec.objectStorage.minio [us-east-1] is not allowed")
+
+	// Ensure namespaces without restrictions are left alone
+	viper.Set(config.DataResidencyRegionsConfigName, map[string]string{"testnamespace": "eu-west-1"})
+	dspa.Namespace = "othernamespace"
+	assert.Nil(t, params.SetupDataResidency(dspa))
+	assert.Nil(t, params.DataResidency)
+}
+
+func TestDataResidencyCompliantCondition(t *testing.T) {
+	viper.Set(config.DataResidencyRegionsConfigName, map[string]string{"testnamespace": "eu-west-1,eu-central-1"})
+	defaultQueryBucketLocation := QueryBucketLocation
+	t.Cleanup(func() {
+		viper.Set(config.DataResidencyRegionsConfigName, nil)
+		QueryBucketLocation = defaultQueryBucketLocation
+	})
+	location := ""
+	QueryBucketLocation = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, timeout time.Duration) (string, error) {
+		return location, nil
+	}
+
+	dspa := newValidationTestDSPA()
+	dspa.Spec.ObjectStorage.ExternalStorage.Region = "eu-west-1"
+	ctx, _, reconciler := CreateNewTestObjects()
+	secure := true
+	params := &DSPAParams{
+		ObjectStorageConnection: ObjectStorageConnection{
+			Host:            "s3.example.com",
+			Bucket:          "mlpipeline",
+			Secure:          &secure,
+			AccessKeyID:     base64.StdEncoding.EncodeToString([]byte("fooaccesskey")),
+			SecretAccessKey: base64.StdEncoding.EncodeToString([]byte("foosecretkey")),
+		},
+	}
+	assert.Nil(t, params.SetupDataResidency(dspa))
+
+	// Ensure the bucket location reported by the object store is checked against the allowed and declared regions
+	// Ensure the components creating runs are stopped while the bucket is misplaced
+	location = "us-east-1"
+	params.DataResidencyDiagnosis = reconciler.checkDataResidency(ctx, dspa, params)
+	assert.Equal(t, config.RegionNotAllowed, params.DataResidencyDiagnosis.Reason)
+	assert.True(t, params.DataResidencyViolated())
+	location = "eu-central-1"
+	params.DataResidencyDiagnosis = reconciler.checkDataResidency(ctx, dspa, params)
+	assert.Equal(t, config.RegionMismatch, params.DataResidencyDiagnosis.Reason)
+	assert.True(t, params.DataResidencyViolated())
+	location = "eu-west-1"
+	params.DataResidencyDiagnosis = reconciler.checkDataResidency(ctx, dspa, params)
+	assert.Equal(t, config.RegionAllowed, params.DataResidencyDiagnosis.Reason)
+	assert.False(t, params.DataResidencyViolated())
+
+	conditions, err := reconciler.GenerateStatus(ctx, dspa, params, true, true)
+	assert.Nil(t, err)
+	dataResidencyCompliant := util.GetConditionByType(config.DataResidencyCompliant, conditions)
+	assert.Equal(t, metav1.ConditionTrue, dataResidencyCompliant.Status)
+	assert.Equal(t, "Bucket [mlpipeline] is in the allowed region [eu-west-1].", dataResidencyCompliant.Message)
+
+	// Ensure DSPAs in unrestricted namespaces don't report the condition
+	params.DataResidency = nil
+	conditions, err = reconciler.GenerateStatus(ctx, dspa, params, true, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "", util.GetConditionByType(config.DataResidencyCompliant, conditions).Type)
+}
@@ -28,30 +28,76 @@ import (
 )
 
 const dbSecret = "mariadb/secret.yaml.tmpl"
+const mariadbPVCTemplate = "mariadb/pvc.yaml.tmpl"
 
 var mariadbTemplates = []string{
 	"mariadb/deployment.yaml.tmpl",
-	"mariadb/pvc.yaml.tmpl",
+	mariadbPVCTemplate,
 	"mariadb/service.yaml.tmpl",
 	"mariadb/mariadb-sa.yaml.tmpl",
 }
 
 // extract to var for mocking in testing
-var ConnectAndQueryDatabase = func(host, port, username, password, dbname string, dbConnectionTimeout time.Duration) bool {
+var ConnectAndQueryDatabase = func(host, port, username, password, dbname string, dbConnectionTimeout time.Duration) error {
 	// Create a context with a timeout of 1 second
 	ctx, cancel := context.WithTimeout(context.Background(), dbConnectionTimeout)
 	defer cancel()
 
-	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", username, password, host, port, dbname)
+	connectionString := fmt.Sprintf("%s:%s@tcp(%s)/%s", username, password, hostPort(host, port), dbname)
 	db, err := sql.Open("mysql", connectionString)
 	if err != nil {
-		return false
+		return err
 	}
 	defer db.Close()
 
 	testStatement := "SELECT 1;"
 	_, err = db.QueryContext(ctx, testStatement)
-	return err == nil
+	return err
+}
+
+// extract to var for mocking in testing
+var QueryDatabaseWriteCapacity = func(host, port, username, password, dbname string, dbConnectionTimeout time.Duration) (bool, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbConnectionTimeout)
+	defer cancel()
+
+	connectionString := fmt.Sprintf("%s:%s@tcp(%s)/%s", username, password, hostPort(host, port), dbname)
+	db, err := sql.Open("mysql", connectionString)
+	if err != nil {
+		return false, 0, err
+	}
+	defer db.Close()
+
+	var readOnly bool
+	var maxConnections int
+	err = db.QueryRowContext(ctx, "SELECT @@GLOBAL.read_only, @@GLOBAL.max_connections;").Scan(&readOnly, &maxConnections)
+	return readOnly, maxConnections, err
+}
+
+// checkConcurrentWriters verifies the database accepts writes from every MLMD gRPC server replica, a read only
+// replica or a server limiting connections below what the replicas open fails the health check.
+func checkConcurrentWriters(params *DSPAParams, password string, dbConnectionTimeout time.Duration) *Diagnosis {
+	required := params.MlmdDBConnections()
+	if required == 0 {
+		return nil
+	}
+	readOnly, maxConnections, err := QueryDatabaseWriteCapacity(params.DBConnection.Host,
+		params.DBConnection.Port,
+		params.DBConnection.Username,
+		password,
+		params.DBConnection.DBName,
+		dbConnectionTimeout)
+	if err != nil {
+		return diagnoseDatabaseError(err)
+	}
+	if readOnly {
+		return &Diagnosis{Reason: config.DBConcurrencyUnsupported, Message: fmt.Sprintf("Database is read only, "+
+			"the %d MLMD gRPC replicas require a writable primary", params.MLMD.GRPC.Replicas)}
+	}
+	if maxConnections < int(required) {
+		return &Diagnosis{Reason: config.DBConcurrencyUnsupported, Message: fmt.Sprintf("Database allows %d connections, "+
+			"the %d MLMD gRPC replicas require at least %d", maxConnections, params.MLMD.GRPC.Replicas, required)}
+	}
+	return nil
 }
 
 func (r *DSPAReconciler) isDatabaseAccessible(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
@@ -69,6 +115,7 @@ func (r *DSPAReconciler) isDatabaseAccessible(ctx context.Context, dsp *dspav1al
 	usingMariaDB := !databaseSpecified || dsp.Spec.Database.MariaDB != nil
 	if !usingMariaDB && !usingExternalDB {
 		log.Info("Could not connect to Database: Unsupported Type")
+		params.DatabaseDiagnosis = &Diagnosis{Reason: config.DBConnectionFailed, Message: "Could not connect to database: unsupported type"}
 		return false
 	}
 
@@ -77,18 +124,24 @@ func (r *DSPAReconciler) isDatabaseAccessible(ctx context.Context, dsp *dspav1al
 
 	log.V(1).Info(fmt.Sprintf("Database Heath Check connection timeout: %s", dbConnectionTimeout))
 
-	dbHealthCheckPassed := ConnectAndQueryDatabase(params.DBConnection.Host,
+	err := ConnectAndQueryDatabase(params.DBConnection.Host,
 		params.DBConnection.Port,
 		params.DBConnection.Username,
 		string(decodePass),
 		params.DBConnection.DBName,
 		dbConnectionTimeout)
-	if dbHealthCheckPassed {
-		log.Info("Database Health Check Successful")
-	} else {
-		log.Info("Unable to connect to Database")
+	if err != nil {
+		params.DatabaseDiagnosis = diagnoseDatabaseError(err)
+		log.Info(fmt.Sprintf("Unable to connect to Database, Reason: [%s]", params.DatabaseDiagnosis.Reason))
+		return false
 	}
-	return dbHealthCheckPassed
+	if diagnosis := checkConcurrentWriters(params, string(decodePass), dbConnectionTimeout); diagnosis != nil {
+		params.DatabaseDiagnosis = diagnosis
+		log.Info(fmt.Sprintf("Database does not support the MLMD gRPC replicas, Reason: [%s]", diagnosis.Reason))
+		return false
+	}
+	log.Info("Database Health Check Successful")
+	return true
 }
 
 func (r *DSPAReconciler) ReconcileDatabase(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
@@ -120,8 +173,15 @@ func (r *DSPAReconciler) ReconcileDatabase(ctx context.Context, dsp *dspav1alpha
 				return err
 			}
 		}
+		if err := r.reconcileMariaDBMetrics(ctx, dsp, params); err != nil {
+			return err
+		}
 		log.Info("Applying mariaDB resources.")
 		for _, template := range mariadbTemplates {
+			// Dev mode stores the database in a tmpfs emptyDir instead
+			if params.DevMode && template == mariadbPVCTemplate {
+				continue
+			}
 			err := r.Apply(dsp, params, template)
 			if err != nil {
 				return err
@@ -18,11 +18,14 @@ limitations under the License.
 package controllers
 
 import (
+	"encoding/base64"
 	"testing"
 
 	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestDeployDatabase(t *testing.T) {
@@ -124,3 +127,85 @@ func TestDontDeployDatabase(t *testing.T) {
 	assert.False(t, created)
 	assert.Nil(t, err)
 }
+
+func TestDeployDatabaseMetrics(t *testing.T) {
+	testNamespace := "testnamespace"
+	expectedDatabaseName := "mariadb-testdspa"
+	expectedExporterName := "ds-pipeline-mariadb-exporter-testdspa"
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy:  true,
+					Metrics: &dspav1alpha1.MariaDBMetrics{Enabled: true, Image: "exporterimage"},
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"},
+			},
+		},
+	}
+	dspa.Name = "testdspa"
+	dspa.Namespace = testNamespace
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileDatabase(ctx, dspa, params))
+
+	// Assert the exporter runs next to MariaDB, with an account created by the MariaDB start scripts
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, expectedDatabaseName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	containers := deployment.Spec.Template.Spec.Containers
+	assert.Len(t, containers, 2)
+	assert.Equal(t, "exporterimage", containers[1].Image)
+	assert.Contains(t, containers[1].Args, "--mysqld.username=mysqld_exporter")
+	assert.Equal(t, expectedExporterName, containers[1].Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "/opt/app-root/src/mysql-init", containers[0].VolumeMounts[1].MountPath)
+	grants := &corev1.ConfigMap{}
+	created, err = reconciler.IsResourceCreated(ctx, grants, expectedExporterName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Contains(t, grants.Data["exporter-grants.sh"], "GRANT PROCESS, REPLICATION CLIENT, SELECT ON *.*")
+
+	// Assert the sidecar is scraped through the MariaDB Service
+	service := &corev1.Service{}
+	created, err = reconciler.IsResourceCreated(ctx, service, expectedDatabaseName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, "metrics", service.Spec.Ports[1].Name)
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(serviceMonitorGVK)
+	created, err = reconciler.IsResourceCreated(ctx, monitor, expectedDatabaseName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+
+	// Assert the password of the account is kept across reconciles
+	secret := &corev1.Secret{}
+	created, err = reconciler.IsResourceCreated(ctx, secret, expectedExporterName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(secret.Data["password"]), params.MariaDBExporter.Password)
+
+	// Assert the exporter resources are removed once metrics are disabled
+	dspa.Spec.Database.MariaDB.Metrics.Enabled = false
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileDatabase(ctx, dspa, params))
+	created, err = reconciler.IsResourceCreated(ctx, deployment, expectedDatabaseName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Len(t, deployment.Spec.Template.Spec.Containers, 1)
+	created, err = reconciler.IsResourceCreated(ctx, &corev1.Secret{}, expectedExporterName, testNamespace)
+	assert.False(t, created)
+	assert.Nil(t, err)
+	created, err = reconciler.IsResourceCreated(ctx, &corev1.ConfigMap{}, expectedExporterName, testNamespace)
+	assert.False(t, created)
+	assert.Nil(t, err)
+	monitor = &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(serviceMonitorGVK)
+	created, err = reconciler.IsResourceCreated(ctx, monitor, expectedDatabaseName, testNamespace)
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
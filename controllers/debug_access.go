@@ -0,0 +1,234 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations requesting and tracking the debug access to the database, object storage and MLMD of a DSPA
+const (
+	debugAccessAnnotationPrefix = "debug.datasciencepipelinesapplications.opendatahub.io/"
+	// Set on the DSPA to the name of the user requesting debug access
+	debugAccessForAnnotation = debugAccessAnnotationPrefix + "access-for"
+	// Optionally set on the DSPA to the minutes the debug access lasts
+	debugAccessMinutesAnnotation = debugAccessAnnotationPrefix + "access-minutes"
+	// Set on the DSPA to when the debug access granted expires
+	debugAccessExpiresAtAnnotation = debugAccessAnnotationPrefix + "access-expires-at"
+	// Set on the DSPA to the outcome of the last debug access request
+	debugAccessStatusAnnotation = debugAccessAnnotationPrefix + "access-status"
+)
+
+const (
+	debugProxyPrefix               = "ds-pipeline-debug-proxy-"
+	debugProxyConfigPrefix         = "ds-pipeline-debug-proxy-config-"
+	debugAccessRolePrefix          = "ds-pipeline-debug-access-"
+	defaultDebugAccessMinutes      = 30
+	maxDebugAccessMinutes          = 240
+	debugProxyConfigMapTemplate    = "debug-access/proxy.configmap.yaml.tmpl"
+	debugProxyPodTemplate          = "debug-access/proxy.pod.yaml.tmpl"
+	debugAccessRoleTemplate        = "debug-access/role.yaml.tmpl"
+	debugAccessRoleBindingTemplate = "debug-access/rolebinding.yaml.tmpl"
+)
+
+var debugAccessTemplates = []string{
+	debugProxyConfigMapTemplate,
+	debugAccessRoleTemplate,
+	debugAccessRoleBindingTemplate,
+	debugProxyPodTemplate,
+}
+
+// DebugAccess is the debug proxy of a DSPA, forwarding the ports of its targets to their Services for a user
+type DebugAccess struct {
+	User            string
+	DeadlineSeconds int64
+	Image           string
+	Targets         []DebugAccessTarget
+}
+
+// DebugAccessTarget is a component the debug proxy forwards a local port to
+type DebugAccessTarget struct {
+	Name      string
+	Host      string
+	Port      string
+	LocalPort int
+}
+
+// debugAccessAllowed returns true if the user may manage the DSPA, and so may be granted debug access to it
+var debugAccessAllowed = func(ctx context.Context, c client.Client, user string, dsp *dspav1alpha1.DataSciencePipelinesApplication) (bool, error) {
+	access := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User: user,
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: dsp.Namespace,
+			Verb:      "update",
+			Group:     dspav1alpha1.GroupVersion.Group,
+			Resource:  "datasciencepipelinesapplications",
+			Name:      dsp.Name,
+		},
+	}}
+	if err := c.Create(ctx, access); err != nil {
+		return false, err
+	}
+	return access.Status.Allowed, nil
+}
+
+// debugAccessTargets lists the components deployed in the namespace of the DSPA that the debug proxy forwards to,
+// each on its usual port
+func (p *DSPAParams) debugAccessTargets(dsp *dspav1alpha1.DataSciencePipelinesApplication) []DebugAccessTarget {
+	var targets []DebugAccessTarget
+	if !p.UsingExternalDB(dsp) && p.MariaDB != nil && p.MariaDB.Deploy {
+		targets = append(targets, DebugAccessTarget{Name: "mariadb", Host: p.DBConnection.Host, Port: p.DBConnection.Port, LocalPort: 3306})
+	}
+	if !p.UsingExternalStorage(dsp) && p.Minio != nil && p.Minio.Deploy {
+		targets = append(targets, DebugAccessTarget{Name: "minio", Host: p.ObjectStorageConnection.Host, Port: p.ObjectStorageConnection.Port, LocalPort: 9000})
+	}
+	if p.UsingMLMD(dsp) {
+		host := fmt.Sprintf("%s.%s.svc.cluster.local", config.DerivedName("ds-pipeline-metadata-grpc-", p.Name), p.Namespace)
+		targets = append(targets, DebugAccessTarget{Name: "mlmd", Host: host, Port: p.MLMD.GRPC.Port, LocalPort: 8080})
+	}
+	return targets
+}
+
+// ReconcileDebugAccess grants the debug access requested through the access-for annotation of the DSPA, and revokes
+// it once expired or when the annotation is removed. A user who may update the DSPA is granted a port-forward to a
+// proxy pod forwarding to its MariaDB, Minio and MLMD, for access-minutes. The proxy only listens on the loopback of
+// its pod, so only the port-forwards of the user, recorded in the Kubernetes audit logs, reach it, and it logs the
+// connections it forwards. Expiry is enforced by the activeDeadlineSeconds of the pod, whose failure triggers the
+// reconcile deleting the proxy and its RBAC. Access is one-time: the request annotations are removed once it's
+// revoked.
+func (r *DSPAReconciler) ReconcileDebugAccess(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams, now time.Time) error {
+
+	annotations := dsp.GetAnnotations()
+	user := annotations[debugAccessForAnnotation]
+	expires, granted := annotations[debugAccessExpiresAtAnnotation]
+	if !granted {
+		if user == "" {
+			return nil
+		}
+		return r.grantDebugAccess(ctx, dsp, params, user, now)
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, expires)
+	if err != nil {
+		return r.revokeDebugAccess(ctx, dsp, fmt.Sprintf("revoked on invalid expiry [%s]", expires))
+	}
+	if user == "" {
+		return r.revokeDebugAccess(ctx, dsp, fmt.Sprintf("revoked at %s", now.UTC().Format(time.RFC3339)))
+	}
+	pod := &corev1.Pod{}
+	err = r.Get(ctx, types.NamespacedName{Name: config.DerivedName(debugProxyPrefix, dsp.Name), Namespace: dsp.Namespace}, pod)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	podDone := err == nil && (pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded)
+	if podDone || !now.Before(expiresAt) {
+		return r.revokeDebugAccess(ctx, dsp, fmt.Sprintf("expired at %s", now.UTC().Format(time.RFC3339)))
+	}
+	return nil
+}
+
+func (r *DSPAReconciler) grantDebugAccess(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams, user string, now time.Time) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	minutes := defaultDebugAccessMinutes
+	if value, ok := dsp.GetAnnotations()[debugAccessMinutesAnnotation]; ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxDebugAccessMinutes {
+			return r.endDebugAccessRequest(ctx, dsp, fmt.Sprintf("denied, %s must be a number of minutes between 1 and %d",
+				debugAccessMinutesAnnotation, maxDebugAccessMinutes))
+		}
+		minutes = parsed
+	}
+	if strings.ContainsAny(user, "\"\n") {
+		return r.endDebugAccessRequest(ctx, dsp, "denied, invalid user name")
+	}
+	targets := params.debugAccessTargets(dsp)
+	if len(targets) == 0 {
+		return r.endDebugAccessRequest(ctx, dsp, "denied, the DSPA deploys neither MariaDB, Minio nor MLMD")
+	}
+	allowed, err := debugAccessAllowed(ctx, r.Client, user, dsp)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		log.Info("Denied debug access", "user", user)
+		return r.endDebugAccessRequest(ctx, dsp, fmt.Sprintf("denied, user [%s] may not update the DSPA", user))
+	}
+
+	params.DebugAccess = &DebugAccess{User: user, DeadlineSeconds: int64(minutes) * 60, Targets: targets}
+	if err := params.setImageDefault(config.MlmdEnvoyImagePath, &params.DebugAccess.Image); err != nil {
+		return err
+	}
+	for _, template := range debugAccessTemplates {
+		if err := r.Apply(dsp, params, template); err != nil {
+			return err
+		}
+	}
+
+	expiresAt := now.Add(time.Duration(minutes) * time.Minute).UTC().Format(time.RFC3339)
+	log.Info("Granted debug access", "user", user, "expiresAt", expiresAt)
+	patch := client.MergeFrom(dsp.DeepCopy())
+	dsp.Annotations[debugAccessExpiresAtAnnotation] = expiresAt
+	dsp.Annotations[debugAccessStatusAnnotation] = fmt.Sprintf("granted to [%s] until %s", user, expiresAt)
+	return r.Patch(ctx, dsp, patch)
+}
+
+func (r *DSPAReconciler) revokeDebugAccess(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, status string) error {
+	nn := func(prefix string) types.NamespacedName {
+		return types.NamespacedName{Name: config.DerivedName(prefix, dsp.Name), Namespace: dsp.Namespace}
+	}
+	for _, resource := range []struct {
+		obj    client.Object
+		prefix string
+	}{
+		{&corev1.Pod{}, debugProxyPrefix},
+		{&rbacv1.RoleBinding{}, debugAccessRolePrefix},
+		{&rbacv1.Role{}, debugAccessRolePrefix},
+		{&corev1.ConfigMap{}, debugProxyConfigPrefix},
+	} {
+		if err := r.DeleteResourceIfItExists(ctx, resource.obj, nn(resource.prefix)); err != nil {
+			return err
+		}
+	}
+	r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name).
+		Info("Revoked debug access", "user", dsp.GetAnnotations()[debugAccessForAnnotation], "status", status)
+	return r.endDebugAccessRequest(ctx, dsp, status)
+}
+
+// endDebugAccessRequest removes the request annotations of the DSPA, recording the outcome of the request
+func (r *DSPAReconciler) endDebugAccessRequest(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, status string) error {
+	patch := client.MergeFrom(dsp.DeepCopy())
+	delete(dsp.Annotations, debugAccessForAnnotation)
+	delete(dsp.Annotations, debugAccessMinutesAnnotation)
+	delete(dsp.Annotations, debugAccessExpiresAtAnnotation)
+	dsp.Annotations[debugAccessStatusAnnotation] = status
+	return r.Patch(ctx, dsp, patch)
+}
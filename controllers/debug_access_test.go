@@ -0,0 +1,125 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileDebugAccess(t *testing.T) {
+	defaultDebugAccessAllowed := debugAccessAllowed
+	debugAccessAllowed = func(ctx context.Context, c client.Client, user string, dsp *dspav1alpha1.DataSciencePipelinesApplication) (bool, error) {
+		return user == "alice", nil
+	}
+	t.Cleanup(func() { debugAccessAllowed = defaultDebugAccessAllowed })
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testdspa",
+			Namespace: "testnamespace",
+			Annotations: map[string]string{
+				debugAccessForAnnotation:     "alice",
+				debugAccessMinutesAnnotation: "10",
+			},
+		},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: true, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	nn := func(prefix string) types.NamespacedName {
+		return types.NamespacedName{Name: prefix + "testdspa", Namespace: "testnamespace"}
+	}
+	request := func(user, minutes string) {
+		dspa.Annotations[debugAccessForAnnotation] = user
+		dspa.Annotations[debugAccessMinutesAnnotation] = minutes
+		assert.Nil(t, reconciler.Update(ctx, dspa))
+	}
+
+	// Ensure the requested access is granted through a proxy pod expiring with the access
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	assert.Nil(t, reconciler.ReconcileDebugAccess(ctx, dspa, params, now))
+	assert.Equal(t, "2023-10-01T12:10:00Z", dspa.Annotations[debugAccessExpiresAtAnnotation])
+	assert.Equal(t, "granted to [alice] until 2023-10-01T12:10:00Z", dspa.Annotations[debugAccessStatusAnnotation])
+
+	pod := &corev1.Pod{}
+	assert.Nil(t, reconciler.Get(ctx, nn(debugProxyPrefix), pod))
+	assert.Equal(t, int64(600), *pod.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, "testdspa", pod.Labels["dspa"])
+	assert.Len(t, pod.Spec.Containers[0].Ports, 2)
+	proxyConfig := &corev1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, nn(debugProxyConfigPrefix), proxyConfig))
+	assert.Contains(t, proxyConfig.Data["envoy.yaml"], "address: mariadb-testdspa.testnamespace.svc.cluster.local")
+	assert.Contains(t, proxyConfig.Data["envoy.yaml"], "address: minio-testdspa.testnamespace.svc.cluster.local")
+	role := &rbacv1.Role{}
+	assert.Nil(t, reconciler.Get(ctx, nn(debugAccessRolePrefix), role))
+	assert.Equal(t, []string{"ds-pipeline-debug-proxy-testdspa"}, role.Rules[1].ResourceNames)
+	binding := &rbacv1.RoleBinding{}
+	assert.Nil(t, reconciler.Get(ctx, nn(debugAccessRolePrefix), binding))
+	assert.Equal(t, []rbacv1.Subject{{APIGroup: "rbac.authorization.k8s.io", Kind: "User", Name: "alice"}}, binding.Subjects)
+
+	// Ensure the access is kept until it expires, and revoked once the proxy pod exceeded its deadline
+	assert.Nil(t, reconciler.ReconcileDebugAccess(ctx, dspa, params, now.Add(time.Minute)))
+	assert.Nil(t, reconciler.Get(ctx, nn(debugProxyPrefix), &corev1.Pod{}))
+	pod.Status.Phase = corev1.PodFailed
+	assert.Nil(t, reconciler.Status().Update(ctx, pod))
+	assert.Nil(t, reconciler.ReconcileDebugAccess(ctx, dspa, params, now.Add(time.Minute)))
+	assert.Equal(t, "expired at 2023-10-01T12:01:00Z", dspa.Annotations[debugAccessStatusAnnotation])
+	assert.NotContains(t, dspa.Annotations, debugAccessForAnnotation)
+	assert.NotContains(t, dspa.Annotations, debugAccessExpiresAtAnnotation)
+	assert.True(t, apierrs.IsNotFound(reconciler.Get(ctx, nn(debugProxyPrefix), &corev1.Pod{})))
+	assert.True(t, apierrs.IsNotFound(reconciler.Get(ctx, nn(debugAccessRolePrefix), &rbacv1.RoleBinding{})))
+	assert.True(t, apierrs.IsNotFound(reconciler.Get(ctx, nn(debugProxyConfigPrefix), &corev1.ConfigMap{})))
+
+	// Ensure the access is revoked once expired, or when its request is removed
+	request("alice", "10")
+	assert.Nil(t, reconciler.ReconcileDebugAccess(ctx, dspa, params, now))
+	assert.Nil(t, reconciler.ReconcileDebugAccess(ctx, dspa, params, now.Add(10*time.Minute)))
+	assert.Equal(t, "expired at 2023-10-01T12:10:00Z", dspa.Annotations[debugAccessStatusAnnotation])
+	request("alice", "10")
+	assert.Nil(t, reconciler.ReconcileDebugAccess(ctx, dspa, params, now))
+	delete(dspa.Annotations, debugAccessForAnnotation)
+	assert.Nil(t, reconciler.Update(ctx, dspa))
+	assert.Nil(t, reconciler.ReconcileDebugAccess(ctx, dspa, params, now))
+	assert.Equal(t, "revoked at 2023-10-01T12:00:00Z", dspa.Annotations[debugAccessStatusAnnotation])
+	assert.True(t, apierrs.IsNotFound(reconciler.Get(ctx, nn(debugProxyPrefix), &corev1.Pod{})))
+
+	// Ensure users who may not update the DSPA and invalid requests are denied
+	for user, minutes := range map[string]string{"bob": "10", "alice": "1000"} {
+		request(user, minutes)
+		assert.Nil(t, reconciler.ReconcileDebugAccess(ctx, dspa, params, now))
+		assert.Contains(t, dspa.Annotations[debugAccessStatusAnnotation], "denied")
+		assert.NotContains(t, dspa.Annotations, debugAccessForAnnotation)
+		assert.True(t, apierrs.IsNotFound(reconciler.Get(ctx, nn(debugProxyPrefix), &corev1.Pod{})))
+	}
+}
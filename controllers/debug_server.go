@@ -0,0 +1,151 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// debugAuthorizer returns true if the bearer token may GET the path
+type debugAuthorizer func(ctx context.Context, token, path string) (bool, error)
+
+// kubernetesDebugAuthorizer authenticates bearer tokens with a TokenReview, and authorizes GETs of the non-resource URL
+// with a SubjectAccessReview, so access to the debug endpoints is granted through RBAC like
+// config/rbac/debug_reader_role.yaml.
+func kubernetesDebugAuthorizer(c client.Client) debugAuthorizer {
+	return func(ctx context.Context, token, path string) (bool, error) {
+		review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+		if err := c.Create(ctx, review); err != nil {
+			return false, err
+		}
+		if !review.Status.Authenticated {
+			return false, nil
+		}
+
+		extra := map[string]authorizationv1.ExtraValue{}
+		for key, value := range review.Status.User.Extra {
+			extra[key] = authorizationv1.ExtraValue(value)
+		}
+		access := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:                  review.Status.User.Username,
+			UID:                   review.Status.User.UID,
+			Groups:                review.Status.User.Groups,
+			Extra:                 extra,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: path, Verb: "get"},
+		}}
+		if err := c.Create(ctx, access); err != nil {
+			return false, err
+		}
+		return access.Status.Allowed, nil
+	}
+}
+
+func withDebugAuthorization(log logr.Logger, authorize debugAuthorizer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if token == "" || token == req.Header.Get("Authorization") {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		allowed, err := authorize(req.Context(), token, req.URL.Path)
+		if err != nil {
+			log.Error(err, "Could not authorize debug endpoint request")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func newDebugHandler(log logr.Logger, authorize debugAuthorizer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return withDebugAuthorization(log, authorize, mux)
+}
+
+// DebugServer serves the pprof endpoints over TLS to callers authorized to GET /debug/pprof/* through RBAC, with the
+// serving certificate of the admission webhooks, so the bearer tokens of the callers are never sent in the clear
+type DebugServer struct {
+	Addr string
+	// CertDir is the directory of the tls.crt and tls.key to serve, reloaded when the service CA rotates them
+	CertDir string
+	Client  client.Client
+	Log     logr.Logger
+}
+
+// Start implements manager.Runnable, serving until ctx is done
+func (s *DebugServer) Start(ctx context.Context) error {
+	watcher, err := certwatcher.New(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			s.Log.Error(err, "Could not watch the serving certificate of the debug endpoints")
+		}
+	}()
+
+	server := &http.Server{
+		Addr:              s.Addr,
+		Handler:           newDebugHandler(s.Log, kubernetesDebugAuthorizer(s.Client)),
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: watcher.GetCertificate},
+	}
+	errs := make(chan error, 1)
+	go func() {
+		s.Log.Info(fmt.Sprintf("Serving debug endpoints on [%s]", s.Addr))
+		errs <- server.ListenAndServeTLS("", "")
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so every operator replica can be profiled
+func (s *DebugServer) NeedLeaderElection() bool {
+	return false
+}
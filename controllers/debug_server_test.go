@@ -0,0 +1,117 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugHandlerAuthorization(t *testing.T) {
+	var authorizedPath string
+	handler := newDebugHandler(logr.Discard(), func(ctx context.Context, token, path string) (bool, error) {
+		authorizedPath = path
+		switch token {
+		case "profiler":
+			return true, nil
+		case "broken":
+			return false, errors.New("TokenReview failed")
+		}
+		return false, nil
+	})
+
+	tests := map[string]struct {
+		authorization string
+		status        int
+	}{
+		"no token":           {"", http.StatusUnauthorized},
+		"basic auth":         {"Basic cHJvZmlsZXI6", http.StatusUnauthorized},
+		"unauthorized token": {"Bearer developer", http.StatusForbidden},
+		"review error":       {"Bearer broken", http.StatusInternalServerError},
+		"authorized token":   {"Bearer profiler", http.StatusOK},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			assert.Equal(t, test.status, recorder.Code)
+		})
+	}
+	assert.Equal(t, "/debug/pprof/", authorizedPath)
+}
+
+func TestDebugServerTLS(t *testing.T) {
+	// Write the certificate of an httptest TLS server as the serving certificate
+	certServer := httptest.NewTLSServer(http.NotFoundHandler())
+	certServer.Close()
+	certificate := certServer.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(certificate.PrivateKey)
+	assert.Nil(t, err)
+	certDir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(certDir, "tls.crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]}), 0600))
+	assert.Nil(t, os.WriteFile(filepath.Join(certDir, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0600))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	addr := listener.Addr().String()
+	assert.Nil(t, listener.Close())
+	_, _, reconciler := CreateNewTestObjects()
+	server := &DebugServer{Addr: addr, CertDir: certDir, Client: reconciler.Client, Log: logr.Discard()}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- server.Start(ctx) }()
+
+	// Assert the endpoints are only served over TLS
+	var resp *http.Response
+	assert.Eventually(t, func() bool {
+		resp, err = certServer.Client().Get("https://" + addr + "/debug/pprof/")
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, err = http.Get("http://" + addr + "/debug/pprof/")
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	cancel()
+	assert.Nil(t, <-done)
+}
+
+func TestInstrumentedReconciler(t *testing.T) {
+	_, _, reconciler := CreateNewTestObjects()
+	assert.Same(t, reconciler, reconciler.instrumented())
+	reconciler.DetailedMetrics = true
+	assert.IsType(t, &detailedMetricsReconciler{}, reconciler.instrumented())
+}
@@ -0,0 +1,100 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Probe settings of dev mode: pods are marked ready as soon as they answer, and restarted only once they stopped
+// answering for a while, as components start slowly on loaded development machines.
+const (
+	devModeReadinessInitialDelaySeconds = int64(1)
+	devModeReadinessPeriodSeconds       = int64(2)
+	devModeLivenessFailureThreshold     = int64(10)
+)
+
+// SetupDevMode trims the DSPA down to single replicas without the oauth-proxies, whose serving certificates are
+// issued by the OpenShift service CA. Called before the component defaults are populated.
+func (p *DSPAParams) SetupDevMode(dsp *dspav1alpha1.DataSciencePipelinesApplication) {
+	p.DevMode = dsp.Spec.DevMode
+	if !p.DevMode {
+		return
+	}
+	if p.APIServer != nil {
+		p.APIServer.EnableRoute = false
+		p.APIServer.Replicas = 1
+	}
+	if p.MLMD != nil && p.MLMD.GRPC != nil {
+		p.MLMD.GRPC.Replicas = 1
+	}
+}
+
+// injectDevMode relaxes the probes of component workloads and replaces their PersistentVolumeClaims with tmpfs
+// emptyDirs, so MariaDB and Minio start without provisioning volumes. Their data is lost when their pods restart.
+func injectDevMode(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if !params.DevMode || (u.GetKind() != "Deployment" && u.GetKind() != "DaemonSet") {
+			return nil
+		}
+		volumes, _, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "volumes")
+		if err != nil {
+			return err
+		}
+		for i, v := range volumes {
+			volume, ok := v.(map[string]interface{})
+			if !ok || volume["persistentVolumeClaim"] == nil {
+				continue
+			}
+			delete(volume, "persistentVolumeClaim")
+			volume["emptyDir"] = map[string]interface{}{"medium": "Memory"}
+			volumes[i] = volume
+		}
+		if len(volumes) > 0 {
+			if err := unstructured.SetNestedSlice(u.Object, volumes, "spec", "template", "spec", "volumes"); err != nil {
+				return err
+			}
+		}
+
+		containers, found, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+		if err != nil || !found {
+			return err
+		}
+		for i, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, found := container["readinessProbe"]; found {
+				if err := unstructured.SetNestedField(container, devModeReadinessInitialDelaySeconds, "readinessProbe", "initialDelaySeconds"); err != nil {
+					return err
+				}
+				if err := unstructured.SetNestedField(container, devModeReadinessPeriodSeconds, "readinessProbe", "periodSeconds"); err != nil {
+					return err
+				}
+			}
+			if _, found := container["livenessProbe"]; found {
+				if err := unstructured.SetNestedField(container, devModeLivenessFailureThreshold, "livenessProbe", "failureThreshold"); err != nil {
+					return err
+				}
+			}
+			containers[i] = container
+		}
+		return unstructured.SetNestedSlice(u.Object, containers, "spec", "template", "spec", "containers")
+	}
+}
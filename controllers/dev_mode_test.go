@@ -0,0 +1,88 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDeployDevMode(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			DevMode:          true,
+			APIServer:        &dspav1alpha1.APIServer{Deploy: true, EnableRoute: true},
+			PersistenceAgent: &dspav1alpha1.PersistenceAgent{Deploy: true},
+			MlPipelineUI:     &dspav1alpha1.MlPipelineUI{Deploy: true, Image: "someimage"},
+			Database:         &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage:    &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: true, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileDatabase(ctx, dspa, params))
+	assert.Nil(t, reconciler.ReconcileStorage(ctx, dspa, params))
+	assert.Nil(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	assert.Nil(t, reconciler.ReconcileUI(dspa, params))
+
+	// Ensure MariaDB and Minio store their data in tmpfs instead of PersistentVolumeClaims
+	for _, name := range []string{"mariadb-testdspa", "minio-testdspa"} {
+		created, err := reconciler.IsResourceCreated(ctx, &v1.PersistentVolumeClaim{}, name, dspa.Namespace)
+		assert.False(t, created)
+		assert.Nil(t, err)
+		deployment := &appsv1.Deployment{}
+		assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: dspa.Namespace}, deployment))
+		volume := deployment.Spec.Template.Spec.Volumes[0]
+		assert.Nil(t, volume.PersistentVolumeClaim)
+		assert.Equal(t, v1.StorageMediumMemory, volume.EmptyDir.Medium)
+	}
+
+	// Ensure probes are relaxed
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "mariadb-testdspa", Namespace: dspa.Namespace}, deployment))
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, int32(1), container.ReadinessProbe.InitialDelaySeconds)
+	assert.Equal(t, int32(2), container.ReadinessProbe.PeriodSeconds)
+	assert.Equal(t, int32(10), container.LivenessProbe.FailureThreshold)
+	assert.Equal(t, int32(30), container.LivenessProbe.InitialDelaySeconds)
+
+	// Ensure neither the Routes nor the oauth-proxies, which need the OpenShift service CA, are deployed
+	for _, name := range []string{"ds-pipeline-testdspa", "ds-pipeline-ui-testdspa"} {
+		created, err := reconciler.IsResourceCreated(ctx, &routev1.Route{}, name, dspa.Namespace)
+		assert.False(t, created)
+		assert.Nil(t, err)
+		assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: dspa.Namespace}, deployment))
+		assert.Len(t, deployment.Spec.Template.Spec.Containers, 1)
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			assert.Nil(t, volume.Secret)
+		}
+	}
+	service := &v1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-ui-testdspa", Namespace: dspa.Namespace}, service))
+	assert.Equal(t, intstr.FromInt(3000), service.Spec.Ports[0].TargetPort)
+	assert.NotContains(t, service.Annotations, "service.alpha.openshift.io/serving-cert-secret-name")
+}
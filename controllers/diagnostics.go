@@ -0,0 +1,103 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/minio/minio-go/v7"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MySQL server error numbers, see https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	mysqlAccessDenied      = 1045
+	mysqlUnknownDatabase   = 1049
+	mysqlDBAccessDenied    = 1044
+	mysqlHostNotPrivileged = 1130
+)
+
+// errInvalidCABundle is returned by the health checks when the custom CA bundle can't be used to verify TLS connections
+var errInvalidCABundle = errors.New("invalid CA bundle")
+
+// Diagnosis is the failure class (one of the config Failure Reasons) and description of a failed health check, that
+// is reported in the Reason and Message of the corresponding DSPA condition.
+type Diagnosis struct {
+	Reason  string
+	Message string
+}
+
+func isTLSError(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalidCertificate x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	var recordHeader tls.RecordHeaderError
+	return errors.Is(err, errInvalidCABundle) || errors.As(err, &unknownAuthority) ||
+		errors.As(err, &invalidCertificate) || errors.As(err, &hostname) || errors.As(err, &recordHeader)
+}
+
+func diagnoseDatabaseError(err error) *Diagnosis {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlAccessDenied, mysqlDBAccessDenied, mysqlHostNotPrivileged:
+			return &Diagnosis{Reason: config.DBAuthFailed, Message: fmt.Sprintf("Database rejected the credentials: %s", mysqlErr.Message)}
+		case mysqlUnknownDatabase:
+			return &Diagnosis{Reason: config.DBNotFound, Message: fmt.Sprintf("Database does not exist: %s", mysqlErr.Message)}
+		}
+	}
+	if isTLSError(err) {
+		return &Diagnosis{Reason: config.TLSHandshakeError, Message: fmt.Sprintf("Could not establish a TLS connection to database: %s", err)}
+	}
+	return &Diagnosis{Reason: config.DBConnectionFailed, Message: fmt.Sprintf("Could not connect to database: %s", err)}
+}
+
+func diagnoseObjectStoreError(err error, bucket string) *Diagnosis {
+	if errors.Is(err, errBucketNotFound) {
+		return &Diagnosis{Reason: config.BucketNotFound, Message: fmt.Sprintf("Object Store connectivity successfully verified, "+
+			"but bucket [%s] does not exist yet and will be created by the API Server", bucket)}
+	}
+	var response minio.ErrorResponse
+	if errors.As(err, &response) {
+		switch response.Code {
+		case "AccessDenied", "InvalidAccessKeyId", "SignatureDoesNotMatch":
+			return &Diagnosis{Reason: config.ObjStoreAuthFailed, Message: fmt.Sprintf("Object Store rejected the credentials: %s", response.Message)}
+		}
+	}
+	if isTLSError(err) {
+		return &Diagnosis{Reason: config.TLSHandshakeError, Message: fmt.Sprintf("Could not establish a TLS connection to Object Store: %s", err)}
+	}
+	return &Diagnosis{Reason: config.ObjStoreConnectionFailed, Message: fmt.Sprintf("Could not connect to Object Store: %s", err)}
+}
+
+// containerFailureReason returns the failure class of a container waiting to run, or "" if it isn't failing.
+func containerFailureReason(status corev1.ContainerStatus) string {
+	if status.State.Waiting == nil {
+		return ""
+	}
+	switch status.State.Waiting.Reason {
+	case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+		return config.ImagePullBackOff
+	case "CrashLoopBackOff":
+		return config.CrashLoopBackOff
+	}
+	return ""
+}
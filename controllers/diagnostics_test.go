@@ -0,0 +1,231 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-sql-driver/mysql"
+	"github.com/minio/minio-go/v7"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiagnoseDatabaseError(t *testing.T) {
+	tests := map[string]struct {
+		err    error
+		reason string
+	}{
+		"access denied":    {&mysql.MySQLError{Number: 1045, Message: "Access denied for user 'mlpipeline'"}, config.DBAuthFailed},
+		"unknown database": {&mysql.MySQLError{Number: 1049, Message: "Unknown database 'mlpipeline'"}, config.DBNotFound},
+		"unknown CA": {&url.Error{Op: "Get", URL: "https://mysql", Err: x509.UnknownAuthorityError{}},
+			config.TLSHandshakeError},
+		"timeout": {context.DeadlineExceeded, config.DBConnectionFailed},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.reason, diagnoseDatabaseError(test.err).Reason)
+		})
+	}
+}
+
+func TestDiagnoseObjectStoreError(t *testing.T) {
+	tests := map[string]struct {
+		err    error
+		reason string
+	}{
+		"missing bucket":    {errBucketNotFound, config.BucketNotFound},
+		"access denied":     {minio.ErrorResponse{Code: "AccessDenied"}, config.ObjStoreAuthFailed},
+		"invalid key":       {minio.ErrorResponse{Code: "InvalidAccessKeyId"}, config.ObjStoreAuthFailed},
+		"invalid CA bundle": {fmt.Errorf("%w: no certificates", errInvalidCABundle), config.TLSHandshakeError},
+		"wrong hostname": {&url.Error{Op: "Head", URL: "https://s3", Err: x509.HostnameError{Certificate: &x509.Certificate{}, Host: "s3"}},
+			config.TLSHandshakeError},
+		"connection refused": {errors.New("dial tcp: connection refused"), config.ObjStoreConnectionFailed},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.reason, diagnoseObjectStoreError(test.err, "mlpipeline").Reason)
+		})
+	}
+}
+
+func TestContainerFailureReason(t *testing.T) {
+	waiting := func(reason string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}}
+	}
+	assert.Equal(t, config.ImagePullBackOff, containerFailureReason(waiting("ErrImagePull")))
+	assert.Equal(t, config.ImagePullBackOff, containerFailureReason(waiting("ImagePullBackOff")))
+	assert.Equal(t, config.CrashLoopBackOff, containerFailureReason(waiting("CrashLoopBackOff")))
+	assert.Equal(t, "", containerFailureReason(waiting("ContainerCreating")))
+	assert.Equal(t, "", containerFailureReason(corev1.ContainerStatus{}))
+}
+
+func TestGenerateStatusWithDiagnosis(t *testing.T) {
+	defaultConnectAndQueryDatabase := ConnectAndQueryDatabase
+	defaultConnectAndQueryObjStore := ConnectAndQueryObjStore
+	t.Cleanup(func() {
+		ConnectAndQueryDatabase = defaultConnectAndQueryDatabase
+		ConnectAndQueryObjStore = defaultConnectAndQueryObjStore
+	})
+	ConnectAndQueryDatabase = func(host, port, username, password, dbname string, dbConnectionTimeout time.Duration) error {
+		return &mysql.MySQLError{Number: 1045, Message: "Access denied for user 'mlpipeline'"}
+	}
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) error {
+		return errBucketNotFound
+	}
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+	secure := false
+	params := &DSPAParams{
+		ObjectStorageConnection: ObjectStorageConnection{
+			Host:            "foo",
+			Port:            "1337",
+			Bucket:          "mlpipeline",
+			Secure:          &secure,
+			AccessKeyID:     base64.StdEncoding.EncodeToString([]byte("fooaccesskey")),
+			SecretAccessKey: base64.StdEncoding.EncodeToString([]byte("foosecretkey")),
+		},
+	}
+
+	// Assert the failure classes of the health checks are reported as condition reasons
+	dbAvailable := reconciler.isDatabaseAccessible(ctx, dspa, params)
+	objStoreAvailable := reconciler.isObjectStorageAccessible(ctx, dspa, params)
+	assert.False(t, dbAvailable)
+	assert.True(t, objStoreAvailable)
+	conditions, err := reconciler.GenerateStatus(ctx, dspa, params, dbAvailable, objStoreAvailable)
+	assert.Nil(t, err)
+
+	databaseAvailable := util.GetConditionByType(config.DatabaseAvailable, conditions)
+	assert.Equal(t, metav1.ConditionFalse, databaseAvailable.Status)
+	assert.Equal(t, config.DBAuthFailed, databaseAvailable.Reason)
+	objectStoreAvailable := util.GetConditionByType(config.ObjectStoreAvailable, conditions)
+	assert.Equal(t, metav1.ConditionTrue, objectStoreAvailable.Status)
+	assert.Equal(t, config.BucketNotFound, objectStoreAvailable.Reason)
+	assert.Equal(t, config.DBAuthFailed, util.GetConditionByType(config.CrReady, conditions).Reason)
+}
+
+func TestGenerateStatusOfDeployedComponents(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: true, Image: "someimage"}},
+			MLMD:          &dspav1alpha1.MLMD{Deploy: true},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// MariaDB and the ML Metadata envoy and gRPC server are available, the ML Metadata writer failed to progress, and
+	// Minio isn't deployed yet
+	deployment := func(name string, condition appsv1.DeploymentCondition) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testnamespace"},
+			Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}},
+			Status:     appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{condition}},
+		}
+	}
+	available := appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}
+	for _, name := range []string{"mariadb-testdspa", "ds-pipeline-metadata-envoy-testdspa", "ds-pipeline-metadata-grpc-testdspa"} {
+		assert.Nil(t, reconciler.Create(ctx, deployment(name, available)))
+	}
+	assert.Nil(t, reconciler.Create(ctx, deployment("ds-pipeline-metadata-writer-testdspa", appsv1.DeploymentCondition{
+		Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+	})))
+	previousTransition := metav1.NewTime(time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC))
+	dspa.Status.Conditions = []metav1.Condition{
+		{Type: config.DatabaseReady, Status: metav1.ConditionTrue, LastTransitionTime: previousTransition},
+	}
+
+	// Assert every deployed component reports its own condition, and fails the Ready condition of the DSPA
+	conditions, err := reconciler.GenerateStatus(ctx, dspa, params, true, true)
+	assert.Nil(t, err)
+	databaseReady := util.GetConditionByType(config.DatabaseReady, conditions)
+	assert.Equal(t, metav1.ConditionTrue, databaseReady.Status)
+	assert.Equal(t, previousTransition, databaseReady.LastTransitionTime)
+	objectStoreReady := util.GetConditionByType(config.ObjectStoreReady, conditions)
+	assert.Equal(t, metav1.ConditionFalse, objectStoreReady.Status)
+	assert.Equal(t, config.ComponentDeploymentNotFound, objectStoreReady.Reason)
+	mlmdReady := util.GetConditionByType(config.MLMDReady, conditions)
+	assert.Equal(t, metav1.ConditionFalse, mlmdReady.Status)
+	assert.Equal(t, config.FailingToDeploy, mlmdReady.Reason)
+	assert.Contains(t, mlmdReady.Message, "ds-pipeline-metadata-writer-testdspa")
+	crReady := util.GetConditionByType(config.CrReady, conditions)
+	assert.Equal(t, metav1.ConditionFalse, crReady.Status)
+	assert.Contains(t, crReady.Message, "ds-pipeline-metadata-writer-testdspa")
+
+	// Assert components the DSPA doesn't deploy report no condition
+	dspa.Spec.Database = &dspav1alpha1.Database{ExternalDB: &dspav1alpha1.ExternalDB{
+		Host: "mysql.example.com", Port: "3306", Username: "user", DBName: "db",
+		PasswordSecret: &dspav1alpha1.SecretKeyValue{Name: "db-credentials", Key: "password"},
+	}}
+	dspa.Spec.MLMD = nil
+	conditions, err = reconciler.GenerateStatus(ctx, dspa, params, true, true)
+	assert.Nil(t, err)
+	assert.Empty(t, util.GetConditionByType(config.DatabaseReady, conditions).Type)
+	assert.Empty(t, util.GetConditionByType(config.MLMDReady, conditions).Type)
+	assert.NotEmpty(t, util.GetConditionByType(config.ObjectStoreReady, conditions).Type)
+}
+
+func TestCheckConcurrentWriters(t *testing.T) {
+	defaultQueryDatabaseWriteCapacity := QueryDatabaseWriteCapacity
+	t.Cleanup(func() { QueryDatabaseWriteCapacity = defaultQueryDatabaseWriteCapacity })
+
+	tests := map[string]struct {
+		replicas       int32
+		readOnly       bool
+		maxConnections int
+		expectedReason string
+	}{
+		"single replica":          {replicas: 1, readOnly: true, expectedReason: ""},
+		"writable primary":        {replicas: 3, maxConnections: 151, expectedReason: ""},
+		"read only replica":       {replicas: 3, readOnly: true, maxConnections: 151, expectedReason: config.DBConcurrencyUnsupported},
+		"too few max connections": {replicas: 4, maxConnections: 151, expectedReason: config.DBConcurrencyUnsupported},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			QueryDatabaseWriteCapacity = func(host, port, username, password, dbname string, dbConnectionTimeout time.Duration) (bool, int, error) {
+				return test.readOnly, test.maxConnections, nil
+			}
+			params := &DSPAParams{MLMD: &dspav1alpha1.MLMD{GRPC: &dspav1alpha1.GRPC{Replicas: test.replicas}}}
+			diagnosis := checkConcurrentWriters(params, "password", time.Second)
+			if test.expectedReason == "" {
+				assert.Nil(t, diagnosis)
+			} else {
+				assert.Equal(t, test.expectedReason, diagnosis.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const catalogPath = "../config/configmaps/files/catalog"
+
+// TestDSPACatalog ensures the example DSPAs published in the catalog ConfigMap match the current API, and are accepted
+// by the operator once the Secrets and ConfigMaps they reference exist.
+func TestDSPACatalog(t *testing.T) {
+	kustomization, err := os.ReadFile(filepath.Join(catalogPath, "..", "..", "kustomization.yaml"))
+	assert.Nil(t, err)
+	examples, err := filepath.Glob(filepath.Join(catalogPath, "*.yaml"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, examples)
+
+	for _, example := range examples {
+		t.Run(filepath.Base(example), func(t *testing.T) {
+			assert.Contains(t, string(kustomization), "files/catalog/"+filepath.Base(example))
+
+			// Decode strictly, so examples using renamed or removed fields fail
+			content, err := os.ReadFile(example)
+			assert.Nil(t, err)
+			content, err = yaml.ToJSON(content)
+			assert.Nil(t, err)
+			dspa := &dspav1alpha1.DataSciencePipelinesApplication{}
+			decoder := json.NewDecoder(bytes.NewReader(content))
+			decoder.DisallowUnknownFields()
+			assert.Nil(t, decoder.Decode(dspa))
+			assert.Equal(t, dspav1alpha1.GroupVersion.String(), dspa.APIVersion)
+			assert.Equal(t, "DataSciencePipelinesApplication", dspa.Kind)
+			dspa.Namespace = "testnamespace"
+
+			// Create the referenced Secrets and ConfigMaps
+			ctx, params, reconciler := CreateNewTestObjects()
+			if dspa.Spec.Database.ExternalDB != nil {
+				secret := &v1.Secret{Data: map[string][]byte{dspa.Spec.Database.ExternalDB.PasswordSecret.Key: []byte("password")}}
+				secret.Name = dspa.Spec.Database.ExternalDB.PasswordSecret.Name
+				secret.Namespace = dspa.Namespace
+				assert.Nil(t, reconciler.Create(ctx, secret))
+			}
+			if externalStorage := dspa.Spec.ObjectStorage.ExternalStorage; externalStorage != nil {
+				secret := &v1.Secret{Data: map[string][]byte{
+					externalStorage.S3CredentialSecret.AccessKey: []byte("accesskey"),
+					externalStorage.S3CredentialSecret.SecretKey: []byte("secretkey"),
+				}}
+				secret.Name = externalStorage.S3CredentialSecret.SecretName
+				secret.Namespace = dspa.Namespace
+				assert.Nil(t, reconciler.Create(ctx, secret))
+			}
+			if dspa.Spec.APIServer != nil && dspa.Spec.APIServer.CABundle != nil {
+				cm := &v1.ConfigMap{Data: map[string]string{dspa.Spec.APIServer.CABundle.ConfigMapKey: "cert"}}
+				cm.Name = dspa.Spec.APIServer.CABundle.ConfigMapName
+				cm.Namespace = dspa.Namespace
+				assert.Nil(t, reconciler.Create(ctx, cm))
+			}
+
+			err = params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+			assert.Nil(t, err)
+		})
+	}
+}
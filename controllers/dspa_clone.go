@@ -0,0 +1,318 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations requesting and tracking the clone of a DSPA within its namespace
+const (
+	cloneAnnotationPrefix = "clone.datasciencepipelinesapplications.opendatahub.io/"
+	// Set on the source DSPA to the name of the clone
+	cloneToAnnotation = cloneAnnotationPrefix + "to"
+	// Set on the source DSPA to "true" to also copy its pipelines and their versions into the clone
+	clonePipelinesAnnotation = cloneAnnotationPrefix + "pipelines"
+	// Set on the clone to the name of the source DSPA
+	cloneFromAnnotation = cloneAnnotationPrefix + "from"
+	// Set on the clone once the pipelines of the source DSPA have been copied
+	clonePipelinesCopiedAnnotation = cloneAnnotationPrefix + "pipelines-copied"
+)
+
+const apiServerPageSize = 100
+
+var cloneHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// apiServerURL returns the in-cluster URL of the DSP API Server of a DSPA.
+var apiServerURL = func(namespace, name, port string) string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%s", config.DerivedName(config.DSPServicePrefix+"-", name), namespace, port)
+}
+
+// apiServerHTTPPort returns the port of the http API of the DSP API Server Service of a DSPA.
+func apiServerHTTPPort(dsp *dspav1alpha1.DataSciencePipelinesApplication) string {
+	if dsp.Spec.APIServer == nil {
+		return config.APIServerHTTPPort
+	}
+	return servicePort(dsp.Spec.APIServer.Service, "http", config.APIServerHTTPPort)
+}
+
+type apiServerPipeline struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type apiServerPipelineVersion struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// apiServerGet decodes the JSON response of a GET request to the DSP API Server.
+func apiServerGet(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := cloneHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to [%s] failed with status [%d]", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// apiServerUpload uploads a pipeline template to the DSP API Server.
+func apiServerUpload(ctx context.Context, endpoint, template string, out interface{}) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("uploadfile", "pipeline.yaml")
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write([]byte(template)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := cloneHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("upload to [%s] failed with status [%d]", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func listPipelines(ctx context.Context, baseURL string) ([]apiServerPipeline, error) {
+	var pipelines []apiServerPipeline
+	pageToken := ""
+	for {
+		page := struct {
+			Pipelines     []apiServerPipeline `json:"pipelines"`
+			NextPageToken string              `json:"next_page_token"`
+		}{}
+		endpoint := fmt.Sprintf("%s/apis/v1beta1/pipelines?page_size=%d&page_token=%s", baseURL, apiServerPageSize, url.QueryEscape(pageToken))
+		if err := apiServerGet(ctx, endpoint, &page); err != nil {
+			return nil, err
+		}
+		pipelines = append(pipelines, page.Pipelines...)
+		if page.NextPageToken == "" {
+			return pipelines, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// listPipelineVersions returns the versions of a pipeline, oldest first.
+func listPipelineVersions(ctx context.Context, baseURL, pipelineID string) ([]apiServerPipelineVersion, error) {
+	var versions []apiServerPipelineVersion
+	pageToken := ""
+	for {
+		page := struct {
+			Versions      []apiServerPipelineVersion `json:"versions"`
+			NextPageToken string                     `json:"next_page_token"`
+		}{}
+		endpoint := fmt.Sprintf("%s/apis/v1beta1/pipeline_versions?resource_key.type=PIPELINE&resource_key.id=%s&sort_by=created_at&page_size=%d&page_token=%s",
+			baseURL, url.QueryEscape(pipelineID), apiServerPageSize, url.QueryEscape(pageToken))
+		if err := apiServerGet(ctx, endpoint, &page); err != nil {
+			return nil, err
+		}
+		versions = append(versions, page.Versions...)
+		if page.NextPageToken == "" {
+			return versions, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func pipelineVersionTemplate(ctx context.Context, baseURL, versionID string) (string, error) {
+	template := struct {
+		Template string `json:"template"`
+	}{}
+	endpoint := fmt.Sprintf("%s/apis/v1beta1/pipeline_versions/%s/templates", baseURL, url.PathEscape(versionID))
+	if err := apiServerGet(ctx, endpoint, &template); err != nil {
+		return "", err
+	}
+	return template.Template, nil
+}
+
+// copyPipelines copies the pipelines and their versions from one DSP API Server to another. Versions are matched by
+// name: the pipelines already in the target, e.g. the sample pipeline or the ones of a copy interrupted by a failure,
+// only get the versions they are missing. Runs, experiments and jobs are not copied.
+func copyPipelines(ctx context.Context, sourceURL, targetURL string) error {
+	existing, err := listPipelines(ctx, targetURL)
+	if err != nil {
+		return err
+	}
+	existingIDs := make(map[string]string)
+	for _, pipeline := range existing {
+		existingIDs[pipeline.Name] = pipeline.ID
+	}
+
+	pipelines, err := listPipelines(ctx, sourceURL)
+	if err != nil {
+		return err
+	}
+	for _, pipeline := range pipelines {
+		versions, err := listPipelineVersions(ctx, sourceURL, pipeline.ID)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		// Uploading a pipeline creates its first version, named after the pipeline
+		copied := make(map[string]bool)
+		targetID, found := existingIDs[pipeline.Name]
+		if found {
+			targetVersions, err := listPipelineVersions(ctx, targetURL, targetID)
+			if err != nil {
+				return err
+			}
+			for _, version := range targetVersions {
+				copied[version.Name] = true
+			}
+		} else {
+			template, err := pipelineVersionTemplate(ctx, sourceURL, versions[0].ID)
+			if err != nil {
+				return err
+			}
+			created := apiServerPipeline{}
+			endpoint := fmt.Sprintf("%s/apis/v1beta1/pipelines/upload?name=%s&description=%s",
+				targetURL, url.QueryEscape(pipeline.Name), url.QueryEscape(pipeline.Description))
+			if err := apiServerUpload(ctx, endpoint, template, &created); err != nil {
+				return err
+			}
+			targetID = created.ID
+			copied[pipeline.Name] = true
+		}
+		if copied[pipeline.Name] {
+			copied[versions[0].Name] = true
+		}
+
+		for _, version := range versions {
+			if copied[version.Name] {
+				continue
+			}
+			template, err := pipelineVersionTemplate(ctx, sourceURL, version.ID)
+			if err != nil {
+				return err
+			}
+			endpoint := fmt.Sprintf("%s/apis/v1beta1/pipelines/upload_version?name=%s&description=%s&pipelineid=%s",
+				targetURL, url.QueryEscape(version.Name), url.QueryEscape(version.Description), url.QueryEscape(targetID))
+			if err := apiServerUpload(ctx, endpoint, template, &apiServerPipelineVersion{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ReconcileClone creates a copy of a DSPA named after its clone annotation, e.g. to spin up a staging copy of a
+// production pipeline stack. The clone is only created in the namespace of its source, so requesting one grants no
+// access beyond the namespace of the DSPA. The clone deploys its own MariaDB and gets newly generated credentials, so
+// none of the source's run history is shared, while Secrets and ConfigMaps that can't be generated, like external
+// object storage credentials and CA bundles, are shared. Pipelines are copied once both API Servers are ready, in which
+// case ReconcileClone returns true while the copy is still pending.
+func (r *DSPAReconciler) ReconcileClone(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) (bool, error) {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	cloneName := dsp.GetAnnotations()[cloneToAnnotation]
+	if cloneName == "" {
+		return false, nil
+	}
+	if cloneName == dsp.Name {
+		return false, fmt.Errorf("DSPA [%s] can't be cloned into itself", dsp.Name)
+	}
+
+	clone := &dspav1alpha1.DataSciencePipelinesApplication{}
+	err := r.Get(ctx, types.NamespacedName{Name: cloneName, Namespace: dsp.Namespace}, clone)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return false, err
+	} else if err != nil {
+		if dsp.Spec.Database != nil && dsp.Spec.Database.ExternalDB != nil {
+			return false, fmt.Errorf("DSPA [%s] uses an external database, which its clone would share", dsp.Name)
+		}
+		clone = &dspav1alpha1.DataSciencePipelinesApplication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        cloneName,
+				Namespace:   dsp.Namespace,
+				Annotations: map[string]string{cloneFromAnnotation: dsp.Name},
+			},
+			Spec: *dsp.Spec.DeepCopy(),
+		}
+		// Runs of the clone must not notify the source's CI/CD integrations
+		clone.Spec.RunStatusWebhooks = nil
+		clone.Spec.CommitStatusReporters = nil
+		log.Info(fmt.Sprintf("Cloning DSPA into [%s]", cloneName))
+		if err := r.Create(ctx, clone); err != nil {
+			return false, err
+		}
+	} else if clone.GetAnnotations()[cloneFromAnnotation] != dsp.Name {
+		return false, fmt.Errorf("DSPA [%s] already exists and is not a clone of [%s]", cloneName, dsp.Name)
+	}
+
+	if dsp.GetAnnotations()[clonePipelinesAnnotation] != "true" || clone.GetAnnotations()[clonePipelinesCopiedAnnotation] == "true" {
+		return false, nil
+	}
+	sourceReady := util.GetConditionByType(config.APIServerReady, dsp.Status.Conditions).Status == metav1.ConditionTrue
+	cloneReady := util.GetConditionByType(config.APIServerReady, clone.Status.Conditions).Status == metav1.ConditionTrue
+	if !sourceReady || !cloneReady {
+		return true, nil
+	}
+
+	log.Info(fmt.Sprintf("Copying pipelines into clone [%s]", cloneName))
+	err = copyPipelines(ctx, apiServerURL(dsp.Namespace, dsp.Name, apiServerHTTPPort(dsp)), apiServerURL(dsp.Namespace, cloneName, apiServerHTTPPort(clone)))
+	if err != nil {
+		return true, err
+	}
+	patch := client.MergeFrom(clone.DeepCopy())
+	annotations := clone.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[clonePipelinesCopiedAnnotation] = "true"
+	clone.SetAnnotations(annotations)
+	return false, r.Patch(ctx, clone, patch)
+}
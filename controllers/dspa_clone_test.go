@@ -0,0 +1,171 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newTestAPIServer serves the pipelines, versions and templates of a DSP API Server, and records uploads.
+func newTestAPIServer(t *testing.T, pipelines map[string][]string, uploads *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/v1beta1/pipelines":
+			page := map[string][]apiServerPipeline{"pipelines": {}}
+			for name := range pipelines {
+				page["pipelines"] = append(page["pipelines"], apiServerPipeline{ID: name, Name: name})
+			}
+			assert.Nil(t, json.NewEncoder(w).Encode(page))
+		case "/apis/v1beta1/pipeline_versions":
+			page := map[string][]apiServerPipelineVersion{"versions": {}}
+			for _, version := range pipelines[req.URL.Query().Get("resource_key.id")] {
+				page["versions"] = append(page["versions"], apiServerPipelineVersion{ID: version, Name: version})
+			}
+			assert.Nil(t, json.NewEncoder(w).Encode(page))
+		case "/apis/v1beta1/pipelines/upload", "/apis/v1beta1/pipelines/upload_version":
+			file, _, err := req.FormFile("uploadfile")
+			assert.Nil(t, err)
+			template, _ := io.ReadAll(file)
+			*uploads = append(*uploads, req.URL.Query().Get("name")+":"+string(template))
+			assert.Nil(t, json.NewEncoder(w).Encode(apiServerPipeline{ID: req.URL.Query().Get("name")}))
+		default:
+			// Pipeline version templates
+			assert.Nil(t, json.NewEncoder(w).Encode(map[string]string{"template": "template of " + req.URL.Path}))
+		}
+	}))
+}
+
+func TestReconcileClone(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	cloneName := "testdspa-staging"
+
+	// Serve a source API Server with two pipelines, and a clone API Server that only has the sample pipeline and the
+	// first version of a pipeline whose copy was interrupted
+	var sourceUploads, targetUploads []string
+	source := newTestAPIServer(t, map[string][]string{
+		"iris":   {"iris", "iris-v2"},
+		"mnist":  {"mnist", "mnist-v2"},
+		"sample": {"sample"},
+	}, &sourceUploads)
+	defer source.Close()
+	target := newTestAPIServer(t, map[string][]string{
+		"mnist":  {"mnist"},
+		"sample": {"sample"},
+	}, &targetUploads)
+	defer target.Close()
+	defaultAPIServerURL := apiServerURL
+	apiServerURL = func(namespace, name, port string) string {
+		assert.Equal(t, testNamespace, namespace)
+		if name == testDSPAName {
+			return source.URL
+		}
+		return target.URL
+	}
+	t.Cleanup(func() { apiServerURL = defaultAPIServerURL })
+
+	// Construct source DSPA with external object storage, requesting a clone with pipelines
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testDSPAName,
+			Namespace: testNamespace,
+			Annotations: map[string]string{
+				cloneToAnnotation:        cloneName,
+				clonePipelinesAnnotation: "true",
+			},
+		},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{Deploy: true},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				ExternalStorage: &dspav1alpha1.ExternalStorage{
+					Host:               "s3.amazonaws.com",
+					Bucket:             "pipelines",
+					S3CredentialSecret: &dspav1alpha1.S3CredentialSecret{SecretName: "s3-creds", AccessKey: "k", SecretKey: "s"},
+				},
+			},
+			RunStatusWebhooks: []dspav1alpha1.RunStatusWebhook{{Name: "ci", URL: "https://ci.example.com"}},
+		},
+		Status: dspav1alpha1.DSPAStatus{
+			Conditions: []metav1.Condition{{Type: config.APIServerReady, Status: metav1.ConditionTrue}},
+		},
+	}
+
+	// Assert the clone is created next to its source, but pipelines wait for its API Server
+	ctx, _, reconciler := CreateNewTestObjects()
+	pending, err := reconciler.ReconcileClone(ctx, dspa)
+	assert.Nil(t, err)
+	assert.True(t, pending)
+	clone := &dspav1alpha1.DataSciencePipelinesApplication{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: cloneName, Namespace: testNamespace}, clone))
+	assert.Equal(t, testDSPAName, clone.Annotations[cloneFromAnnotation])
+	assert.Equal(t, "s3-creds", clone.Spec.ObjectStorage.ExternalStorage.S3CredentialSecret.SecretName)
+	assert.Empty(t, clone.Spec.RunStatusWebhooks)
+	assert.Empty(t, targetUploads)
+
+	// Assert the pipelines and versions missing from the clone are copied once its API Server is ready
+	clone.Status.Conditions = []metav1.Condition{{Type: config.APIServerReady, Status: metav1.ConditionTrue}}
+	assert.Nil(t, reconciler.Status().Update(ctx, clone))
+	pending, err = reconciler.ReconcileClone(ctx, dspa)
+	assert.Nil(t, err)
+	assert.False(t, pending)
+	assert.ElementsMatch(t, []string{
+		"iris:template of /apis/v1beta1/pipeline_versions/iris/templates",
+		"iris-v2:template of /apis/v1beta1/pipeline_versions/iris-v2/templates",
+		"mnist-v2:template of /apis/v1beta1/pipeline_versions/mnist-v2/templates",
+	}, targetUploads)
+	assert.Empty(t, sourceUploads)
+
+	// Assert pipelines are only copied once
+	pending, err = reconciler.ReconcileClone(ctx, dspa)
+	assert.Nil(t, err)
+	assert.False(t, pending)
+	assert.Len(t, targetUploads, 3)
+}
+
+func TestReconcileCloneWithExternalDB(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "testdspa",
+			Namespace:   "testnamespace",
+			Annotations: map[string]string{cloneToAnnotation: "testdspa-staging"},
+		},
+		Spec: dspav1alpha1.DSPASpec{
+			Database: &dspav1alpha1.Database{
+				ExternalDB: &dspav1alpha1.ExternalDB{Host: "mysql.example.com"},
+			},
+		},
+	}
+
+	// Assert DSPAs sharing their database with the clone are rejected
+	ctx, _, reconciler := CreateNewTestObjects()
+	_, err := reconciler.ReconcileClone(ctx, dspa)
+	assert.ErrorContains(t, err, "external database")
+	created, err := reconciler.IsResourceCreated(ctx, &dspav1alpha1.DataSciencePipelinesApplication{}, "testdspa-staging", "testnamespace")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
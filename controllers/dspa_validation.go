@@ -0,0 +1,259 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DSPAValidationWebhookPath is the path of the operator's webhook server the DSPA validating webhook calls
+const DSPAValidationWebhookPath = "/validate-dspa"
+
+// credentialsSecretRef is a Secret of credentials referenced by a DSPA, with the keys the components read from it.
+type credentialsSecretRef struct {
+	field string
+	name  string
+	keys  []string
+	// generated Secrets are created by the operator if they don't exist
+	generated bool
+}
+
+// DSPAValidationWebhook rejects the DSPAs the reconcile would fail on, so the errors are reported to whoever applies
+// them rather than only in the operator logs and the DSPA status.
+type DSPAValidationWebhook struct {
+	Client client.Client
+	Log    logr.Logger
+}
+
+func (w *DSPAValidationWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{}
+	if err := json.Unmarshal(req.Object.Raw, dspa); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// DSPAs being deleted must be able to drop their finalizers, and the operator must be able to update the
+	// metadata of DSPAs that became invalid, e.g. once a Secret they reference was changed
+	if dspa.DeletionTimestamp != nil {
+		return admission.Allowed("")
+	}
+	if req.Operation == admissionv1.Update {
+		old := &dspav1alpha1.DataSciencePipelinesApplication{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if equality.Semantic.DeepEqual(old.Spec, dspa.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	problems := validateDSPASpec(dspa, time.Now())
+	secretProblems, warnings, err := w.validateCredentialsSecrets(ctx, dspa)
+	if err != nil {
+		w.Log.Error(err, "Unable to validate the credentials Secrets of the DSPA", "namespace", dspa.Namespace, "dspa_name", dspa.Name)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	problems = append(problems, secretProblems...)
+	if len(problems) > 0 {
+		return admission.Denied(strings.Join(problems, "; ")).WithWarnings(warnings...)
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// validateDSPASpec returns the problems of the spec of the DSPA that don't depend on other resources.
+func validateDSPASpec(dspa *dspav1alpha1.DataSciencePipelinesApplication, now time.Time) []string {
+	var problems []string
+
+	// Also enforced by the CRD, but only on clusters validating its CEL rules
+	if db := dspa.Spec.Database; db != nil && db.MariaDB != nil && db.ExternalDB != nil {
+		problems = append(problems, "spec.database.mariaDB and spec.database.externalDB are mutually exclusive")
+	}
+	if storage := dspa.Spec.ObjectStorage; storage != nil && storage.Minio != nil && storage.ExternalStorage != nil {
+		problems = append(problems, "spec.objectStorage.minio and spec.objectStorage.externalStorage are mutually exclusive")
+	}
+
+	// The validations of ExtractParams that don't read or create any resource
+	params := &DSPAParams{}
+	for _, setup := range []func() error{
+		func() error { return params.SetupReconcileIntervals(dspa) },
+		func() error { return params.SetupSchedulePolicy(dspa, now) },
+		func() error { return params.SetupCostAttribution(dspa) },
+		func() error { return params.SetupFeatureGates(dspa) },
+		func() error { return params.SetupDataResidency(dspa) },
+	} {
+		if err := setup(); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	for _, webhook := range dspa.Spec.RunStatusWebhooks {
+		if err := validateWebhookURL(webhook.URL); err != nil {
+			problems = append(problems, fmt.Sprintf("spec.runStatusWebhooks[%s].url: %s", webhook.Name, err))
+		}
+	}
+	if proxy := dspa.Spec.EgressProxy; proxy != nil {
+		if err := validateEgressProxyURL(proxy.URL); err != nil {
+			problems = append(problems, fmt.Sprintf("spec.egressProxy.url: %s", err))
+		}
+	}
+	for _, reporter := range dspa.Spec.CommitStatusReporters {
+		if reporter.APIURL == "" {
+			continue
+		}
+		if err := validateWebhookURL(reporter.APIURL); err != nil {
+			problems = append(problems, fmt.Sprintf("spec.commitStatusReporters[%s].apiUrl: %s", reporter.Name, err))
+		}
+	}
+
+	for field, resources := range componentResources(dspa) {
+		if resources == nil || resources.Requests == nil || resources.Limits == nil {
+			continue
+		}
+		requests, limits := resources.Requests, resources.Limits
+		if !requests.CPU.IsZero() && !limits.CPU.IsZero() && requests.CPU.Cmp(limits.CPU) > 0 {
+			problems = append(problems, fmt.Sprintf("%s.requests.cpu (%s) must be less than or equal to %s.limits.cpu (%s)",
+				field, requests.CPU.String(), field, limits.CPU.String()))
+		}
+		if !requests.Memory.IsZero() && !limits.Memory.IsZero() && requests.Memory.Cmp(limits.Memory) > 0 {
+			problems = append(problems, fmt.Sprintf("%s.requests.memory (%s) must be less than or equal to %s.limits.memory (%s)",
+				field, requests.Memory.String(), field, limits.Memory.String()))
+		}
+	}
+	// The resources are iterated from a map
+	sort.Strings(problems)
+	return problems
+}
+
+// componentResources returns the resource requirements of the components of the DSPA, by the path of their field.
+func componentResources(dspa *dspav1alpha1.DataSciencePipelinesApplication) map[string]*dspav1alpha1.ResourceRequirements {
+	spec := dspa.Spec
+	resources := make(map[string]*dspav1alpha1.ResourceRequirements)
+	if spec.APIServer != nil {
+		resources["spec.apiServer.resources"] = spec.APIServer.Resources
+		if spec.APIServer.GRPC != nil {
+			resources["spec.apiServer.grpc.resources"] = spec.APIServer.GRPC.Resources
+		}
+	}
+	if spec.PersistenceAgent != nil {
+		resources["spec.persistenceAgent.resources"] = spec.PersistenceAgent.Resources
+	}
+	if spec.ScheduledWorkflow != nil {
+		resources["spec.scheduledWorkflow.resources"] = spec.ScheduledWorkflow.Resources
+	}
+	if spec.MlPipelineUI != nil {
+		resources["spec.mlpipelineUI.resources"] = spec.MlPipelineUI.Resources
+	}
+	if spec.Database != nil && spec.Database.MariaDB != nil {
+		resources["spec.database.mariaDB.resources"] = spec.Database.MariaDB.Resources
+		if spec.Database.MariaDB.Metrics != nil {
+			resources["spec.database.mariaDB.metrics.resources"] = spec.Database.MariaDB.Metrics.Resources
+		}
+	}
+	if spec.ObjectStorage != nil && spec.ObjectStorage.Minio != nil {
+		resources["spec.objectStorage.minio.resources"] = spec.ObjectStorage.Minio.Resources
+	}
+	if spec.MLMD != nil {
+		if spec.MLMD.Envoy != nil {
+			resources["spec.mlmd.envoy.resources"] = spec.MLMD.Envoy.Resources
+		}
+		if spec.MLMD.GRPC != nil {
+			resources["spec.mlmd.grpc.resources"] = spec.MLMD.GRPC.Resources
+		}
+		if spec.MLMD.Writer != nil {
+			resources["spec.mlmd.writer.resources"] = spec.MLMD.Writer.Resources
+		}
+	}
+	if spec.ImagePrepuller != nil {
+		resources["spec.imagePrepuller.resources"] = spec.ImagePrepuller.Resources
+	}
+	if spec.CacheServer != nil {
+		resources["spec.cacheServer.resources"] = spec.CacheServer.Resources
+	}
+	if spec.Headroom != nil {
+		resources["spec.headroom.resources"] = spec.Headroom.Resources
+	}
+	return resources
+}
+
+// credentialsSecretRefs returns the credentials Secrets named in the spec of the DSPA.
+func credentialsSecretRefs(dspa *dspav1alpha1.DataSciencePipelinesApplication) []credentialsSecretRef {
+	var refs []credentialsSecretRef
+	if db := dspa.Spec.Database; db != nil {
+		if db.ExternalDB != nil && db.ExternalDB.PasswordSecret != nil {
+			secret := db.ExternalDB.PasswordSecret
+			refs = append(refs, credentialsSecretRef{field: "spec.database.externalDB.passwordSecret", name: secret.Name, keys: []string{secret.Key}})
+		}
+		if db.MariaDB != nil && db.MariaDB.PasswordSecret != nil {
+			secret := db.MariaDB.PasswordSecret
+			refs = append(refs, credentialsSecretRef{field: "spec.database.mariaDB.passwordSecret", name: secret.Name, keys: []string{secret.Key}, generated: true})
+		}
+	}
+	if storage := dspa.Spec.ObjectStorage; storage != nil {
+		if storage.ExternalStorage != nil && storage.ExternalStorage.S3CredentialSecret != nil {
+			secret := storage.ExternalStorage.S3CredentialSecret
+			refs = append(refs, credentialsSecretRef{field: "spec.objectStorage.externalStorage.s3CredentialsSecret",
+				name: secret.SecretName, keys: []string{secret.AccessKey, secret.SecretKey}})
+		}
+		if storage.Minio != nil && storage.Minio.S3CredentialSecret != nil {
+			secret := storage.Minio.S3CredentialSecret
+			refs = append(refs, credentialsSecretRef{field: "spec.objectStorage.minio.s3CredentialsSecret",
+				name: secret.SecretName, keys: []string{secret.AccessKey, secret.SecretKey}, generated: true})
+		}
+	}
+	return refs
+}
+
+// validateCredentialsSecrets returns the problems of the credentials Secrets of the DSPA, and warnings for the external
+// ones that don't exist yet, as they may be applied right after the DSPA.
+func (w *DSPAValidationWebhook) validateCredentialsSecrets(ctx context.Context, dspa *dspav1alpha1.DataSciencePipelinesApplication) ([]string, []string, error) {
+	var problems, warnings []string
+	for _, ref := range credentialsSecretRefs(dspa) {
+		secret := &corev1.Secret{}
+		err := w.Client.Get(ctx, types.NamespacedName{Name: ref.name, Namespace: dspa.Namespace}, secret)
+		if apierrs.IsNotFound(err) {
+			if !ref.generated {
+				warnings = append(warnings, fmt.Sprintf("%s refers to Secret [%s], which doesn't exist yet, the DSPA won't "+
+					"be ready until it is created", ref.field, ref.name))
+			}
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range ref.keys {
+			if key == "" {
+				problems = append(problems, fmt.Sprintf("%s doesn't name the key of Secret [%s] to read", ref.field, ref.name))
+			} else if _, found := secret.Data[key]; !found {
+				problems = append(problems, fmt.Sprintf("%s refers to key [%s] of Secret [%s], which the Secret doesn't have",
+					ref.field, key, ref.name))
+			}
+		}
+	}
+	return problems, warnings, nil
+}
@@ -0,0 +1,137 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newValidationTestDSPA() *dspav1alpha1.DataSciencePipelinesApplication {
+	return &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{Deploy: true},
+			Database: &dspav1alpha1.Database{ExternalDB: &dspav1alpha1.ExternalDB{
+				Host:           "mysql.example.com",
+				PasswordSecret: &dspav1alpha1.SecretKeyValue{Name: "db-credentials", Key: "password"},
+			}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{ExternalStorage: &dspav1alpha1.ExternalStorage{
+				Host: "s3.example.com",
+				S3CredentialSecret: &dspav1alpha1.S3CredentialSecret{
+					SecretName: "s3-credentials", AccessKey: "accesskey", SecretKey: "secretkey",
+				},
+			}},
+		},
+	}
+}
+
+func TestDSPAValidationWebhook(t *testing.T) {
+	ctx, _, reconciler := CreateNewTestObjects()
+	webhook := &DSPAValidationWebhook{Client: reconciler.Client, Log: reconciler.Log}
+	admit := func(operation admissionv1.Operation, dspa, old *dspav1alpha1.DataSciencePipelinesApplication) admission.Response {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Namespace: dspa.Namespace, Operation: operation}}
+		raw, err := json.Marshal(dspa)
+		assert.Nil(t, err)
+		req.Object = runtime.RawExtension{Raw: raw}
+		if old != nil {
+			raw, err = json.Marshal(old)
+			assert.Nil(t, err)
+			req.OldObject = runtime.RawExtension{Raw: raw}
+		}
+		return webhook.Handle(ctx, req)
+	}
+
+	// Ensure DSPAs referring to Secrets that don't exist yet are admitted, with a warning
+	dspa := newValidationTestDSPA()
+	response := admit(admissionv1.Create, dspa, nil)
+	assert.True(t, response.Allowed)
+	assert.Len(t, response.Warnings, 2)
+	assert.Contains(t, response.Warnings[0], "refers to Secret [db-credentials], which doesn't exist yet")
+
+	// Ensure Secrets missing the keys the DSPA refers to are rejected
+	assert.Nil(t, reconciler.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: "testnamespace"},
+		Data:       map[string][]byte{"password": []byte("password")},
+	}))
+	assert.Nil(t, reconciler.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: "testnamespace"},
+		Data:       map[string][]byte{"accesskey": []byte("accesskey")},
+	}))
+	response = admit(admissionv1.Create, dspa, nil)
+	assert.False(t, response.Allowed)
+	assert.Equal(t, "spec.objectStorage.externalStorage.s3CredentialsSecret refers to key [secretkey] of Secret [s3-credentials], "+
+		"which the Secret doesn't have", string(response.Result.Reason))
+	dspa.Spec.ObjectStorage.ExternalStorage.S3CredentialSecret.SecretKey = "accesskey"
+	response = admit(admissionv1.Create, dspa, nil)
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Warnings)
+
+	// Ensure invalid specs are rejected, with every problem found
+	invalid := dspa.DeepCopy()
+	invalid.Spec.Database.MariaDB = &dspav1alpha1.MariaDB{Deploy: true}
+	invalid.Spec.APIServer.Resources = &dspav1alpha1.ResourceRequirements{
+		Requests: &dspav1alpha1.Resources{CPU: resource.MustParse("2"), Memory: resource.MustParse("1Gi")},
+		Limits:   &dspav1alpha1.Resources{CPU: resource.MustParse("500m"), Memory: resource.MustParse("2Gi")},
+	}
+	invalid.Spec.FeatureGates = map[string]bool{"SomeGate": true}
+	invalid.Spec.EgressProxy = &dspav1alpha1.EgressProxy{URL: "http://proxy.testnamespace.svc:3128"}
+	invalid.Spec.CommitStatusReporters = []dspav1alpha1.CommitStatusReporter{{Name: "github", Provider: "github", APIURL: "https://10.0.0.1/api/v3"}}
+	response = admit(admissionv1.Create, invalid, nil)
+	assert.False(t, response.Allowed)
+	assert.Equal(t, "spec.apiServer.resources.requests.cpu (2) must be less than or equal to spec.apiServer.resources.limits.cpu (500m); "+
+		"spec.commitStatusReporters[github].apiUrl: url [https://10.0.0.1/api/v3] targets the restricted address [10.0.0.1]; "+
+		"spec.database.mariaDB and spec.database.externalDB are mutually exclusive; "+
+		"spec.egressProxy.url: url [http://proxy.testnamespace.svc:3128] targets the cluster-internal host [proxy.testnamespace.svc]; "+
+		"unknown feature gate [SomeGate]",
+		string(response.Result.Reason))
+
+	// Ensure artifacts stored outside the regions allowed for the namespace are rejected
+	viper.Set(config.DataResidencyRegionsConfigName, map[string]string{"testnamespace": "eu-west-1"})
+	t.Cleanup(func() { viper.Set(config.DataResidencyRegionsConfigName, nil) })
+	response = admit(admissionv1.Create, dspa, nil)
+	assert.False(t, response.Allowed)
+	assert.Contains(t, string(response.Result.Reason), "spec.objectStorage.externalStorage.region is required")
+	resident := dspa.DeepCopy()
+	resident.Spec.ObjectStorage.ExternalStorage.Region = "eu-west-1"
+	response = admit(admissionv1.Create, resident, nil)
+	assert.True(t, response.Allowed)
+
+	// Ensure updates are only validated when they change the spec, and DSPAs being deleted are never rejected
+	response = admit(admissionv1.Update, invalid, dspa)
+	assert.False(t, response.Allowed)
+	labeled := invalid.DeepCopy()
+	labeled.Labels = map[string]string{"somelabel": "somevalue"}
+	response = admit(admissionv1.Update, labeled, invalid)
+	assert.True(t, response.Allowed)
+	now := metav1.Now()
+	invalid.DeletionTimestamp = &now
+	response = admit(admissionv1.Update, invalid, dspa)
+	assert.True(t, response.Allowed)
+}
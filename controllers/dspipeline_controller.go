@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	mf "github.com/manifestival/manifestival"
@@ -29,12 +32,16 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/prometheus/client_golang/prometheus"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -52,44 +59,93 @@ type DSPAReconciler struct {
 	Log                     logr.Logger
 	TemplatesPath           string
 	MaxConcurrentReconciles int
+	// DetailedMetrics observes the ReconcileDurationMetric of every reconcile, see InitDetailedMetrics
+	DetailedMetrics bool
+	// WebhookService is the Service of the operator serving its admission webhooks, nil if it doesn't serve them
+	WebhookService *types.NamespacedName
+	// OperatorNamespace is the namespace the operator runs in, whose pods the API Server NetworkPolicy admits
+	OperatorNamespace string
+	// Clientset reads the logs of step pods, which the controller-runtime client doesn't, with the manager's config
+	Clientset kubernetes.Interface
+	// Recorder records the Events of the DSPAs, see recordEvent
+	Recorder record.EventRecorder
+	// Notifications delivers the run status webhooks and commit statuses of the DSPAs queued by Reconcile, nil if they
+	// aren't delivered
+	Notifications *NotificationDispatcher
+	// healthChecks holds the last healthCheckResult of each DSPA, by NamespacedName
+	healthChecks sync.Map
 }
 
 func (r *DSPAReconciler) Apply(owner mf.Owner, params *DSPAParams, template string, fns ...mf.Transformer) error {
-	tmplManifest, err := config.Manifest(r.Client, r.TemplatesPath+template, params)
+	tmplManifest, err := r.render(owner, params, template, fns...)
 	if err != nil {
-		return fmt.Errorf("error loading template (%s) yaml: %w", template, err)
+		return err
 	}
-	tmplManifest, err = tmplManifest.Transform(
-		mf.InjectOwner(owner),
-	)
+	err = observeApply(params, template, tmplManifest)
+	if dsp, ok := owner.(*dspav1alpha1.DataSciencePipelinesApplication); ok && err != nil {
+		r.recordEvent(dsp, params, corev1.EventTypeWarning, config.ApplyFailedEventReason, "Failed to apply %s: %s", template, err)
+	}
+	return err
+}
+
+func (r *DSPAReconciler) ApplyWithoutOwner(params *DSPAParams, template string, fns ...mf.Transformer) error {
+	tmplManifest, err := r.renderWithoutOwner(params, template, fns...)
 	if err != nil {
 		return err
 	}
+	return observeApply(params, template, tmplManifest)
+}
 
-	tmplManifest, err = tmplManifest.Transform(fns...)
+// render loads the template and transforms its manifests as Apply does, without applying them
+func (r *DSPAReconciler) render(owner mf.Owner, params *DSPAParams, template string, fns ...mf.Transformer) (mf.Manifest, error) {
+	manifestClient := r.manifestClient(params)
+	if dsp, ok := owner.(*dspav1alpha1.DataSciencePipelinesApplication); ok && r.Recorder != nil {
+		manifestClient = &eventRecordingClient{Client: manifestClient, recorder: r.Recorder, dspa: dsp}
+	}
+	tmplManifest, err := config.Manifest(manifestClient, r.templateFile(template), params)
 	if err != nil {
-		return err
+		return mf.Manifest{}, fmt.Errorf("error loading template (%s) yaml: %w", template, err)
+	}
+	tmplManifest, err = tmplManifest.Transform(
+		mf.InjectOwner(owner),
+		injectProvenance,
+		injectSecurityProfiles(params),
+		injectReadOnlyRootFilesystem(params),
+		injectServices(params),
+		injectRouteHostnames(params),
+		injectRouteCertificates(params),
+		injectUpdateStrategies(params),
+		injectArchitectures(params),
+		injectPlacements(params),
+		injectScheduling(params),
+		injectIPFamilies(params),
+		injectDevMode(params),
+		injectManifestPatches(params),
+		rejectPlaintextCredentials(params),
+	)
+	if err != nil {
+		return mf.Manifest{}, err
 	}
 
-	return tmplManifest.Apply()
+	return tmplManifest.Transform(fns...)
 }
 
-func (r *DSPAReconciler) ApplyWithoutOwner(params *DSPAParams, template string, fns ...mf.Transformer) error {
-	tmplManifest, err := config.Manifest(r.Client, r.TemplatesPath+template, params)
+// renderWithoutOwner loads the template and transforms its manifests as ApplyWithoutOwner does, without applying them
+func (r *DSPAReconciler) renderWithoutOwner(params *DSPAParams, template string, fns ...mf.Transformer) (mf.Manifest, error) {
+	tmplManifest, err := config.Manifest(r.manifestClient(params), r.templateFile(template), params)
 	if err != nil {
-		return fmt.Errorf("error loading template (%s) yaml: %w", template, err)
+		return mf.Manifest{}, fmt.Errorf("error loading template (%s) yaml: %w", template, err)
 	}
-
-	tmplManifest, err = tmplManifest.Transform(fns...)
+	tmplManifest, err = tmplManifest.Transform(injectProvenance, rejectPlaintextCredentials(params))
 	if err != nil {
-		return err
+		return mf.Manifest{}, err
 	}
 
-	return tmplManifest.Apply()
+	return tmplManifest.Transform(fns...)
 }
 
 func (r *DSPAReconciler) DeleteResource(params *DSPAParams, template string, fns ...mf.Transformer) error {
-	tmplManifest, err := config.Manifest(r.Client, r.TemplatesPath+template, params)
+	tmplManifest, err := config.Manifest(r.manifestClient(params), r.templateFile(template), params)
 	if err != nil {
 		return fmt.Errorf("error loading template (%s) yaml: %w", template, err)
 	}
@@ -129,6 +185,7 @@ func (r *DSPAReconciler) buildCondition(conditionType string, dspa *dspav1alpha1
 //+kubebuilder:rbac:groups=datasciencepipelinesapplications.opendatahub.io,resources=datasciencepipelinesapplications,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=datasciencepipelinesapplications.opendatahub.io,resources=datasciencepipelinesapplications/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=datasciencepipelinesapplications.opendatahub.io,resources=datasciencepipelinesapplications/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=*,resources=deployments;services,verbs=get;list;watch;create;update;patch;delete
@@ -136,13 +193,20 @@ func (r *DSPAReconciler) buildCondition(conditionType string, dspa *dspav1alpha1
 //+kubebuilder:rbac:groups=core,resources=persistentvolumes;persistentvolumeclaims,verbs=*
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create
+//+kubebuilder:rbac:groups=console.openshift.io,resources=consolelinks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongplugins,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=capabilities.3scale.net,resources=backends;products,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=create;delete;get
 //+kubebuilder:rbac:groups=argoproj.io,resources=workflows,verbs=*
-//+kubebuilder:rbac:groups=core,resources=pods;pods/exec;pods/log;services,verbs=*
+//+kubebuilder:rbac:groups=core,resources=pods;pods/exec;pods/log;pods/portforward;services,verbs=*
 //+kubebuilder:rbac:groups=core;apps;extensions,resources=deployments;replicasets,verbs=*
 //+kubebuilder:rbac:groups=kubeflow.org,resources=*,verbs=*
-//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=*
+//+kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=*
 //+kubebuilder:rbac:groups=machinelearning.seldon.io,resources=seldondeployments,verbs=*
 //+kubebuilder:rbac:groups=tekton.dev,resources=*,verbs=*
 //+kubebuilder:rbac:groups=custom.tekton.dev,resources=pipelineloops,verbs=*
@@ -160,7 +224,7 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 	log.V(1).Info("DataSciencePipelinesApplication Reconciler called.")
 
-	params := &DSPAParams{}
+	params := &DSPAParams{APIStats: apiStatsFrom(ctx)}
 
 	dspa := &dspav1alpha1.DataSciencePipelinesApplication{}
 	err := r.Get(ctx, req.NamespacedName, dspa)
@@ -193,7 +257,10 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		if controllerutil.ContainsFinalizer(dspa, finalizerName) {
 			params.Name = dspa.Name
 			params.Namespace = dspa.Namespace
-			if err := r.cleanUpResources(params); err != nil {
+			if err := r.cleanUpResources(ctx, params); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := r.DeleteLegacyClusterRoleBinding(ctx, dspa); err != nil {
 				return ctrl.Result{}, err
 			}
 			controllerutil.RemoveFinalizer(dspa, finalizerName)
@@ -203,6 +270,7 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		}
 
 		// Stop reconciliation as the item is being deleted
+		r.healthChecks.Delete(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
@@ -210,31 +278,84 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	err = params.ExtractParams(ctx, dspa, r.Client, r.Log)
 	if err != nil {
 		log.Info(fmt.Sprintf("Encountered error when parsing CR: [%s]", err))
+		r.recordEvent(dspa, params, corev1.EventTypeWarning, config.SetupFailedEventReason, "Could not set up the DSPA: %s", err)
+		return ctrl.Result{Requeue: true, RequeueAfter: requeueTime}, nil
+	}
+
+	// Run the last known-good images when a rollback is requested
+	err = r.ApplyKnownGoodImages(ctx, dspa, params)
+	if err != nil {
+		log.Info(fmt.Sprintf("Encountered error when rolling back images: [%s]", err))
 		return ctrl.Result{Requeue: true, RequeueAfter: requeueTime}, nil
 	}
 
-	err = r.ReconcileDatabase(ctx, dspa, params)
+	// Don't let a DSPA take over the resources of an older DSPA of the namespace
+	params.NameCollision, err = r.FindNameCollision(ctx, dspa)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	err = r.ReconcileStorage(ctx, dspa, params)
+	// Hold back changes of an operator upgrade until they are approved
+	params.PendingUpgrade, err = r.GetPendingUpgrade(ctx, dspa, params)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if params.NameCollision == "" && params.PendingUpgrade == nil {
+		// Keep the deployed resources as they are unless all of their manifests render
+		params.RenderFailure, err = r.CheckManifests(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if params.NameCollision == "" && params.PendingUpgrade == nil && params.RenderFailure == nil {
+		// Run the pre-upgrade hooks of the DSPA before its components are upgraded
+		err = r.ReconcileUpgradeHooks(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if params.NameCollision != "" {
+		log.Info(params.NameCollision)
+	} else if params.PendingUpgrade != nil {
+		log.Info(params.PendingUpgrade.Message())
+	} else if params.RenderFailure != nil {
+		log.Info(params.RenderFailure.Message)
+	} else if params.PreUpgradeHooks != nil {
+		log.Info(params.PreUpgradeHooks.Message)
+	} else {
+		err = r.DeleteLegacyWorkloads(ctx, dspa)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileDatabase(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileStorage(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	// Get Prereq Status (DB and ObjStore Ready)
-	dbAvailable := r.isDatabaseAccessible(ctx, dspa, params)
-	objStoreAvailable := r.isObjectStorageAccessible(ctx, dspa, params)
+	dbAvailable, objStoreAvailable := r.checkDependencies(ctx, dspa, params, time.Now())
 	dspaPrereqsReady := dbAvailable && objStoreAvailable
 
-	if dspaPrereqsReady {
+	if dspaPrereqsReady && params.PendingUpgrade == nil && params.RenderFailure == nil && params.PreUpgradeHooks == nil &&
+		params.NameCollision == "" {
 		// Manage Common Manifests
 		err = r.ReconcileCommon(dspa, params)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 
+		err = r.DeleteLegacyClusterRoleBinding(ctx, dspa)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
 		err = r.ReconcileAPIServer(ctx, dspa, params)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -255,10 +376,75 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 			return ctrl.Result{}, err
 		}
 
+		err = r.ReconcileConsoleLinks(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
 		err = r.ReconcileMLMD(dspa, params)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+
+		err = r.ReconcileImagePrepuller(dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileCacheServer(dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileHeadroom(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileGuardrails(dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileSchedulePolicy(dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileCostAttribution(dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileStepRetries(dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileBackups(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileArtifactRetention(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileVersionManifest(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ReconcileSecurityPosture(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.ScrubPlaintextCredentials(ctx, dspa, params)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	log.Info("Updating CR status")
@@ -275,6 +461,22 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, err
 	}
 	dspa.Status.Conditions = conditions
+	dspa.Status.DerivedNames = GetDerivedNames(dspa, params)
+	dspa.Status.ActiveFeatureGates = params.ActiveFeatureGates()
+	startups, err := r.ComponentStartups(ctx, dspa)
+	if err != nil {
+		log.Info(fmt.Sprintf("Encountered error when measuring component startups: [%s]", err))
+	} else {
+		dspa.Status.ComponentStartups = startups
+		PublishComponentStartupMetrics(dspa, startups)
+	}
+	outputs, err := r.StatusOutputs(ctx, dspa, params)
+	if err != nil {
+		log.Info(fmt.Sprintf("Encountered error when computing status outputs: [%s]", err))
+	} else {
+		dspa.Status.Outputs = outputs
+	}
+	dspa.Status.RunStatusWebhooks = runStatusWebhookStatuses(dspa, time.Now())
 
 	// Update Status
 	err = r.Status().Update(ctx, dspa)
@@ -291,7 +493,122 @@ func (r *DSPAReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		util.GetConditionByType(config.CrReady, conditions):                CrReadyMetric,
 	}
 	r.PublishMetrics(dspa, metricsMap)
-	return ctrl.Result{}, nil
+
+	// Remember the images of fully rolled out, Ready components to roll back to
+	err = r.RecordKnownGoodImages(ctx, dspa, params, conditions, time.Now())
+	if err != nil {
+		log.Info(fmt.Sprintf("Encountered error when recording known-good images: [%s]", err))
+	}
+
+	// Run the post-upgrade hooks once the upgraded components rolled out
+	err = r.StartPostUpgradeHooks(ctx, dspa, params)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Report on-demand dependency verification as done, now that the conditions are refreshed
+	err = r.AcknowledgeVerification(ctx, dspa, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Clone the DSPA into another namespace when requested, requeueing until its pipelines are copied
+	clonePending, err := r.ReconcileClone(ctx, dspa)
+	if err != nil {
+		log.Info(fmt.Sprintf("Encountered error when cloning DSPA: [%s]", err))
+		clonePending = true
+	}
+
+	// Grant the debug access requested through annotations, and revoke it once expired
+	err = r.ReconcileDebugAccess(ctx, dspa, params, time.Now())
+	if err != nil {
+		log.Info(fmt.Sprintf("Encountered error when reconciling debug access: [%s]", err))
+	}
+
+	// Restore the backup requested through annotations
+	err = r.ReconcileRestore(ctx, dspa, params)
+	if err != nil {
+		log.Info(fmt.Sprintf("Encountered error when restoring a backup: [%s]", err))
+	}
+
+	if params.APIServer != nil && params.APIServer.Deploy {
+		err = r.ReleaseQueuedRuns(ctx, dspa, params)
+		if err != nil {
+			log.Info(fmt.Sprintf("Encountered error when starting the runs queued during a blackout window: [%s]", err))
+		}
+	}
+
+	// Periodically requeue to keep the run report backlog and queue metrics current, and to maintain the runs of the
+	// DSPA: notify run status webhooks and commit status reporters, enforce run timeouts, retry failed runs, handle
+	// finished steps and collect garbage. Each requeues the DSPA at its own interval, the earliest one is kept
+	result := ctrl.Result{RequeueAfter: params.ResyncPeriod}
+	if clonePending {
+		result = requeueAfter(result, requeueTime)
+	}
+	runReportMonitorInterval := config.GetDurationConfigWithDefault(config.RunReportMonitorIntervalConfigName, config.DefaultRunReportMonitorInterval)
+	if runReportMonitorInterval > 0 && params.PersistenceAgent != nil && params.PersistenceAgent.Deploy {
+		r.PublishRunReportMetrics(ctx, dspa)
+		result = requeueAfter(result, runReportMonitorInterval)
+	}
+	if runReportMonitorInterval > 0 && params.APIServer != nil && params.APIServer.Deploy && params.FeatureEnabled(QueueMetricsFeatureGate) {
+		r.PublishQueueMetrics(ctx, dspa)
+		result = requeueAfter(result, runReportMonitorInterval)
+	} else {
+		DeleteQueueMetrics(dspa)
+	}
+	runMaintenanceInterval := config.GetDurationConfigWithDefault(config.RunMaintenanceIntervalConfigName, config.DefaultRunMaintenanceInterval)
+	if runMaintenanceInterval <= 0 {
+		return requeueForPendingChanges(result, params, time.Now()), nil
+	}
+	if len(dspa.Spec.RunStatusWebhooks) > 0 || len(dspa.Spec.CommitStatusReporters) > 0 {
+		if r.Notifications != nil {
+			r.Notifications.Enqueue(dspa)
+		}
+		result = requeueAfter(result, runMaintenanceInterval)
+	}
+	if params.UsingRunTimeouts(dspa) {
+		err = r.EnforceRunTimeouts(ctx, dspa, time.Now())
+		if err != nil {
+			log.Info(fmt.Sprintf("Encountered error when enforcing run timeouts: [%s]", err))
+		}
+		result = requeueAfter(result, runMaintenanceInterval)
+	}
+	if params.UsingRunRetries(dspa) {
+		err = r.RetryFailedRuns(ctx, dspa, time.Now())
+		if err != nil {
+			log.Info(fmt.Sprintf("Encountered error when retrying failed runs: [%s]", err))
+		}
+		result = requeueAfter(result, runMaintenanceInterval)
+	}
+	if dspa.Spec.StepExitHandler != nil {
+		err = r.HandleStepExits(ctx, dspa, params, time.Now())
+		if err != nil {
+			log.Info(fmt.Sprintf("Encountered error when handling finished steps: [%s]", err))
+		}
+		result = requeueAfter(result, runMaintenanceInterval)
+	}
+	if params.UsingGarbageCollection(dspa) {
+		err = r.CollectGarbage(ctx, dspa, time.Now())
+		if err != nil {
+			log.Info(fmt.Sprintf("Encountered error when collecting finished runs and pods: [%s]", err))
+		}
+		result = requeueAfter(result, runMaintenanceInterval)
+	}
+	return requeueForPendingChanges(result, params, time.Now()), nil
+}
+
+// requeueAfter requeues after the interval, if the result doesn't requeue earlier.
+func requeueAfter(result ctrl.Result, after time.Duration) ctrl.Result {
+	if result.RequeueAfter == 0 || after < result.RequeueAfter {
+		result.RequeueAfter = after
+	}
+	return result
+}
+
+// requeueForPendingChanges requeues the DSPA when a blackout window starts or ends, or its candidate images are due
+// to be recorded as known-good, if the result doesn't requeue it earlier.
+func requeueForPendingChanges(result ctrl.Result, params *DSPAParams, now time.Time) ctrl.Result {
+	return requeueForKnownGoodImages(requeueForSchedulePolicy(result, params, now), params, now)
 }
 
 // handleReadyCondition evaluates if condition with "name" is in condition of type "conditionType".
@@ -383,12 +700,12 @@ func (r *DSPAReconciler) handleReadyCondition(ctx context.Context, dspa *dspav1a
 		// We loop through the containers in each pod, as in some cases the Pod can be in pending state
 		// but an individual container may be failing due to runtime errors.
 		for _, c := range p.Status.ContainerStatuses {
-			if c.State.Waiting != nil && c.State.Waiting.Reason == "CrashLoopBackOff" {
-				readyCondition.Reason = config.FailingToDeploy
+			if reason := containerFailureReason(c); reason != "" {
+				readyCondition.Reason = reason
 				readyCondition.Status = metav1.ConditionFalse
 				// We concatenate messages from all failing containers.
-				readyCondition.Message = fmt.Sprintf("Component [%s] is in CrashLoopBackOff. "+
-					"Message from pod: [%s]", component, c.State.Waiting.Message)
+				readyCondition.Message = fmt.Sprintf("Component [%s] is in %s. "+
+					"Message from pod: [%s]", component, c.State.Waiting.Reason, c.State.Waiting.Message)
 				return readyCondition, nil
 			}
 		}
@@ -410,6 +727,21 @@ func (r *DSPAReconciler) handleReadyCondition(ctx context.Context, dspa *dspav1a
 
 }
 
+// handleReadyConditions reports the readiness of a component made of several Deployments, with the condition of
+// the first one that isn't ready.
+func (r *DSPAReconciler) handleReadyConditions(ctx context.Context, dspa *dspav1alpha1.DataSciencePipelinesApplication, components []string, condition string) (metav1.Condition, error) {
+	for _, component := range components {
+		readyCondition, err := r.handleReadyCondition(ctx, dspa, component, condition)
+		if err != nil || readyCondition.Status != metav1.ConditionTrue {
+			return readyCondition, err
+		}
+	}
+	readyCondition := r.buildCondition(condition, dspa, config.MinimumReplicasAvailable)
+	readyCondition.Status = metav1.ConditionTrue
+	readyCondition.Message = fmt.Sprintf("Components [%s] are minimally available.", strings.Join(components, ", "))
+	return readyCondition, nil
+}
+
 func (r *DSPAReconciler) GenerateStatus(ctx context.Context, dspa *dspav1alpha1.DataSciencePipelinesApplication,
 	params *DSPAParams, dbAvailableStatus, objStoreAvailableStatus bool) ([]metav1.Condition, error) {
 	// Create Database Availability Condition
@@ -420,6 +752,10 @@ func (r *DSPAReconciler) GenerateStatus(ctx context.Context, dspa *dspav1alpha1.
 	} else {
 		databaseAvailable.Message = "Could not connect to database"
 	}
+	if params.DatabaseDiagnosis != nil {
+		databaseAvailable.Reason = params.DatabaseDiagnosis.Reason
+		databaseAvailable.Message = params.DatabaseDiagnosis.Message
+	}
 
 	// Create Object Storage Availability Condition
 	objStoreAvailable := r.buildCondition(config.ObjectStoreAvailable, dspa, config.ObjectStoreAvailable)
@@ -429,6 +765,10 @@ func (r *DSPAReconciler) GenerateStatus(ctx context.Context, dspa *dspav1alpha1.
 	} else {
 		objStoreAvailable.Message = "Could not connect to Object Store"
 	}
+	if params.ObjectStorageDiagnosis != nil {
+		objStoreAvailable.Reason = params.ObjectStorageDiagnosis.Reason
+		objStoreAvailable.Message = params.ObjectStorageDiagnosis.Message
+	}
 
 	// Create APIServer Readiness Condition
 	apiServerReady, err := r.handleReadyCondition(ctx, dspa, params.APIServerDefaultResourceName, config.APIServerReady)
@@ -460,12 +800,58 @@ func (r *DSPAReconciler) GenerateStatus(ctx context.Context, dspa *dspav1alpha1.
 	crReady.Type = config.CrReady
 
 	componentConditions := []metav1.Condition{databaseAvailable, objStoreAvailable, apiServerReady, persistenceAgentReady, scheduledWorkflowReady}
+
+	// Create CacheServer Readiness Condition, only reported when deployed as step caching is optional
+	if params.UsingCacheServer(dspa) {
+		cacheServerReady, err := r.handleReadyCondition(ctx, dspa, params.CacheServerDefaultResourceName, config.CacheServerReady)
+		if err != nil {
+			return []metav1.Condition{}, err
+		}
+		conditions = append(conditions, cacheServerReady)
+		componentConditions = append(componentConditions, cacheServerReady)
+	}
+
+	// Create Readiness Conditions of the MariaDB, Minio and ML Metadata deployed by the DSPA, the external database and
+	// object storage are only reported by their Available Conditions
+	var deployedComponents []metav1.Condition
+	if params.UsingMariaDB(dspa) {
+		databaseReady, err := r.handleReadyCondition(ctx, dspa, config.DerivedName(config.MariaDBHostPrefix+"-", dspa.Name), config.DatabaseReady)
+		if err != nil {
+			return []metav1.Condition{}, err
+		}
+		deployedComponents = append(deployedComponents, databaseReady)
+	}
+	if params.UsingMinio(dspa) {
+		objectStoreReady, err := r.handleReadyCondition(ctx, dspa, config.DerivedName(config.MinioHostPrefix+"-", dspa.Name), config.ObjectStoreReady)
+		if err != nil {
+			return []metav1.Condition{}, err
+		}
+		deployedComponents = append(deployedComponents, objectStoreReady)
+	}
+	if params.UsingMLMD(dspa) {
+		mlmdReady, err := r.handleReadyConditions(ctx, dspa, []string{
+			config.DerivedName("ds-pipeline-metadata-envoy-", dspa.Name),
+			config.DerivedName("ds-pipeline-metadata-grpc-", dspa.Name),
+			config.DerivedName("ds-pipeline-metadata-writer-", dspa.Name),
+		}, config.MLMDReady)
+		if err != nil {
+			return []metav1.Condition{}, err
+		}
+		deployedComponents = append(deployedComponents, mlmdReady)
+	}
+	conditions = append(conditions, deployedComponents...)
+	componentConditions = append(componentConditions, deployedComponents...)
+
 	allReady := true
 	failureMessages := ""
+	failureReason := ""
 	for _, c := range componentConditions {
 		if c.Status == metav1.ConditionFalse {
 			allReady = false
 			failureMessages += fmt.Sprintf("%s \n", c.Message)
+			if failureReason == "" {
+				failureReason = c.Reason
+			}
 		}
 	}
 
@@ -473,14 +859,66 @@ func (r *DSPAReconciler) GenerateStatus(ctx context.Context, dspa *dspav1alpha1.
 		crReady.Status = metav1.ConditionTrue
 		crReady.Message = "All components are ready."
 	} else {
+		// Report the failure class of the first failing component
 		crReady.Status = metav1.ConditionFalse
+		crReady.Reason = failureReason
 		crReady.Message = failureMessages
 	}
+	if params.NameCollision != "" {
+		crReady.Status = metav1.ConditionFalse
+		crReady.Reason = config.NameCollision
+		crReady.Message = params.NameCollision
+	}
 	conditions = append(conditions, crReady)
 
-	for i, condition := range dspa.Status.Conditions {
-		if condition.Status == conditions[i].Status {
-			conditions[i].LastTransitionTime = condition.LastTransitionTime
+	// Create UpgradePending Condition
+	upgradePending := r.buildCondition(config.UpgradePending, dspa, config.UpToDate)
+	upgradePending.Message = "All components are managed by the running operator version."
+	if params.PendingUpgrade != nil {
+		upgradePending.Status = metav1.ConditionTrue
+		upgradePending.Reason = config.AwaitingApproval
+		upgradePending.Message = params.PendingUpgrade.Message()
+	} else if params.PreUpgradeHooks != nil {
+		upgradePending.Status = metav1.ConditionTrue
+		upgradePending.Reason = params.PreUpgradeHooks.Reason
+		upgradePending.Message = params.PreUpgradeHooks.Message
+	} else if params.PostUpgradeHooks != nil {
+		upgradePending.Reason = params.PostUpgradeHooks.Reason
+		upgradePending.Message = params.PostUpgradeHooks.Message
+	}
+	conditions = append(conditions, upgradePending)
+
+	// Create ManifestsRendered Condition
+	manifestsRendered := r.buildCondition(config.ManifestsRendered, dspa, config.ManifestsRendered)
+	manifestsRendered.Status = metav1.ConditionTrue
+	manifestsRendered.Message = "All manifests rendered successfully."
+	if params.RenderFailure != nil {
+		manifestsRendered.Status = metav1.ConditionFalse
+		manifestsRendered.Reason = params.RenderFailure.Reason
+		manifestsRendered.Message = params.RenderFailure.Message
+	}
+	conditions = append(conditions, manifestsRendered)
+
+	// Create DataResidencyCompliant Condition, only reported in namespaces whose artifacts are restricted to some regions
+	if params.DataResidency != nil && params.DataResidencyDiagnosis != nil {
+		dataResidencyCompliant := r.buildCondition(config.DataResidencyCompliant, dspa, params.DataResidencyDiagnosis.Reason)
+		if params.DataResidencyDiagnosis.Reason == config.RegionAllowed {
+			dataResidencyCompliant.Status = metav1.ConditionTrue
+		}
+		dataResidencyCompliant.Message = params.DataResidencyDiagnosis.Message
+		conditions = append(conditions, dataResidencyCompliant)
+	}
+
+	// Diagnoses quote the errors of database and object store clients, ensure they never expose credentials
+	for i := range conditions {
+		conditions[i].Message = params.redactCredentials(conditions[i].Message)
+	}
+
+	// Conditions are only reported for the optional components the DSPA deploys, so match them by type
+	for i := range conditions {
+		previous := meta.FindStatusCondition(dspa.Status.Conditions, conditions[i].Type)
+		if previous != nil && previous.Status == conditions[i].Status {
+			conditions[i].LastTransitionTime = previous.LastTransitionTime
 		}
 	}
 
@@ -505,9 +943,15 @@ func (r *DSPAReconciler) PublishMetrics(dspa *dspav1alpha1.DataSciencePipelinesA
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DSPAReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := setupReferenceIndexes(mgr); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dspav1alpha1.DataSciencePipelinesApplication{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.DaemonSet{}).
+		Owns(&batchv1.Job{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
@@ -516,6 +960,11 @@ func (r *DSPAReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
 		Owns(&routev1.Route{}).
+		// Watch for user provided Secrets and ConfigMaps referenced by DSPAs
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.dspasReferencing(referencedSecretsIndex))).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.dspasReferencing(referencedConfigMapsIndex))).
 		// Watch for Pods belonging to DSPA
 		Watches(&source.Kind{Type: &corev1.Pod{}},
 			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
@@ -547,10 +996,31 @@ func (r *DSPAReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
 		}).
-		Complete(r)
+		Complete(r.instrumented())
 }
 
 // Clean Up any resources not handled by garbage collection, like Cluster ResourceRequirements
-func (r *DSPAReconciler) cleanUpResources(params *DSPAParams) error {
+func (r *DSPAReconciler) cleanUpResources(ctx context.Context, params *DSPAParams) error {
+	if err := r.CleanUpCacheServer(params); err != nil {
+		return err
+	}
+	if err := r.CleanUpHeadroom(params); err != nil {
+		return err
+	}
+	if err := r.CleanUpGuardrails(params); err != nil {
+		return err
+	}
+	if err := r.CleanUpSchedulePolicy(params); err != nil {
+		return err
+	}
+	if err := r.CleanUpCostAttribution(params); err != nil {
+		return err
+	}
+	if err := r.CleanUpStepRetries(params); err != nil {
+		return err
+	}
+	if err := r.CleanUpConsoleLinks(ctx, params); err != nil {
+		return err
+	}
 	return r.CleanUpCommon(params)
 }
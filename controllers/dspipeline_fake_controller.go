@@ -20,6 +20,7 @@ package controllers
 import (
 	"context"
 
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -57,6 +58,7 @@ func NewFakeController() *DSPAReconciler {
 		Log:           ctrl.Log.WithName("controllers").WithName("ds-pipelines-controller"),
 		Scheme:        FakeScheme,
 		TemplatesPath: "../config/internal/",
+		Clientset:     fakeclientset.NewSimpleClientset(),
 	}
 
 	return r
@@ -0,0 +1,150 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	fakeStorageDBHost        = "fake-db.test"
+	fakeStorageDBPort        = "3306"
+	fakeStorageDBName        = "mlpipeline"
+	fakeStorageDBUser        = "mlpipeline"
+	fakeStorageDBPassword    = "fakedbpassword"
+	fakeStorageDBSecret      = "fake-db-credentials"
+	fakeStorageAccessKey     = "fakeaccesskey"
+	fakeStorageSecretKey     = "fakesecretkey"
+	fakeStorageS3Secret      = "fake-s3-credentials"
+	fakeStorageCABundle      = "fake-s3-ca-bundle"
+	fakeStorageCABundleKey   = "ca.crt"
+	fakeStorageDefaultBucket = "mlpipeline"
+)
+
+// The live health checks, captured before tests replace them with mocks
+var (
+	liveConnectAndQueryObjStore = ConnectAndQueryObjStore
+	liveUploadToObjStore        = UploadToObjStore
+)
+
+// StorageHarness backs the storage health checks of the reconciler with the test doubles of testutil, so the storage
+// validation can be unit tested without a cluster: the object store checks run unchanged against an in-memory S3
+// server, the database checks are answered by an in-memory MySQL stand-in.
+type StorageHarness struct {
+	ObjectStore *testutil.FakeObjectStore
+	Database    *testutil.FakeDatabase
+
+	restore func()
+}
+
+// NewStorageHarness starts the test doubles, with a bucket and a database both named mlpipeline, and routes the health
+// checks to them until Close is called. The object store is served over TLS with a self-signed certificate if secure.
+func NewStorageHarness(secure bool) *StorageHarness {
+	h := &StorageHarness{
+		ObjectStore: testutil.NewFakeObjectStore(fakeStorageAccessKey, fakeStorageSecretKey, secure),
+		Database:    testutil.NewFakeDatabase(fakeStorageDBHost, fakeStorageDBPort),
+	}
+	h.ObjectStore.CreateBucket(fakeStorageDefaultBucket)
+	h.Database.AddUser(fakeStorageDBUser, fakeStorageDBPassword)
+	h.Database.CreateDatabase(fakeStorageDBName)
+
+	connectAndQueryDatabase, queryDatabaseWriteCapacity := ConnectAndQueryDatabase, QueryDatabaseWriteCapacity
+	connectAndQueryObjStore, uploadToObjStore := ConnectAndQueryObjStore, UploadToObjStore
+	h.restore = func() {
+		ConnectAndQueryDatabase, QueryDatabaseWriteCapacity = connectAndQueryDatabase, queryDatabaseWriteCapacity
+		ConnectAndQueryObjStore, UploadToObjStore = connectAndQueryObjStore, uploadToObjStore
+	}
+	ConnectAndQueryDatabase = h.Database.ConnectAndQuery
+	QueryDatabaseWriteCapacity = h.Database.QueryWriteCapacity
+	ConnectAndQueryObjStore = liveConnectAndQueryObjStore
+	UploadToObjStore = liveUploadToObjStore
+	return h
+}
+
+// Close stops the object store and restores the health checks replaced by NewStorageHarness.
+func (h *StorageHarness) Close() {
+	h.ObjectStore.Close()
+	h.restore()
+}
+
+// ExternalDB returns the spec of a DSPA using the fake database.
+func (h *StorageHarness) ExternalDB() *dspav1alpha1.ExternalDB {
+	return &dspav1alpha1.ExternalDB{
+		Host:           h.Database.Host,
+		Port:           h.Database.Port,
+		Username:       fakeStorageDBUser,
+		DBName:         fakeStorageDBName,
+		PasswordSecret: &dspav1alpha1.SecretKeyValue{Name: fakeStorageDBSecret, Key: "password"},
+	}
+}
+
+// ExternalStorage returns the spec of a DSPA using the fake object store.
+func (h *StorageHarness) ExternalStorage() *dspav1alpha1.ExternalStorage {
+	scheme := "http"
+	if h.ObjectStore.CACert() != nil {
+		scheme = "https"
+	}
+	return &dspav1alpha1.ExternalStorage{
+		Host:   h.ObjectStore.Host(),
+		Port:   h.ObjectStore.Port(),
+		Bucket: fakeStorageDefaultBucket,
+		Scheme: scheme,
+		S3CredentialSecret: &dspav1alpha1.S3CredentialSecret{
+			SecretName: fakeStorageS3Secret,
+			AccessKey:  "accesskey",
+			SecretKey:  "secretkey",
+		},
+	}
+}
+
+// CABundle returns the CA bundle trusting the object store served over TLS, nil otherwise.
+func (h *StorageHarness) CABundle() *dspav1alpha1.CABundle {
+	if h.ObjectStore.CACert() == nil {
+		return nil
+	}
+	return &dspav1alpha1.CABundle{ConfigMapName: fakeStorageCABundle, ConfigMapKey: fakeStorageCABundleKey}
+}
+
+// Objects returns the credential Secrets and CA bundle ConfigMap referenced by the specs of the harness, to be created
+// in the namespace of the DSPA before its params are extracted.
+func (h *StorageHarness) Objects(namespace string) []client.Object {
+	objects := []client.Object{
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: fakeStorageDBSecret, Namespace: namespace},
+			Data:       map[string][]byte{"password": []byte(fakeStorageDBPassword)},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: fakeStorageS3Secret, Namespace: namespace},
+			Data: map[string][]byte{
+				"accesskey": []byte(fakeStorageAccessKey),
+				"secretkey": []byte(fakeStorageSecretKey),
+			},
+		},
+	}
+	if caCert := h.ObjectStore.CACert(); caCert != nil {
+		objects = append(objects, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: fakeStorageCABundle, Namespace: namespace},
+			Data:       map[string]string{fakeStorageCABundleKey: string(caCert)},
+		})
+	}
+	return objects
+}
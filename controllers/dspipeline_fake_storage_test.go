@@ -0,0 +1,147 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/tests/dspabuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+// newHarnessedTestObjects returns the params of a DSPA using the database and object store of the harness.
+func newHarnessedTestObjects(t *testing.T, h *StorageHarness) (context.Context, *dspav1alpha1.DataSciencePipelinesApplication, *DSPAParams, *DSPAReconciler) {
+	dspa := dspabuilder.New("testdspa", "testnamespace").
+		WithSpec(func(spec *dspav1alpha1.DSPASpec) {
+			spec.Database = &dspav1alpha1.Database{ExternalDB: h.ExternalDB()}
+			spec.ObjectStorage = &dspav1alpha1.ObjectStorage{ExternalStorage: h.ExternalStorage()}
+			spec.APIServer.CABundle = h.CABundle()
+		}).
+		Build()
+	ctx, params, reconciler := CreateNewTestObjects()
+	for _, obj := range h.Objects(dspa.Namespace) {
+		assert.Nil(t, reconciler.Create(ctx, obj))
+	}
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	return ctx, dspa, params, reconciler
+}
+
+func TestStorageHarnessHealthChecks(t *testing.T) {
+	h := NewStorageHarness(false)
+	defer h.Close()
+	ctx, dspa, params, reconciler := newHarnessedTestObjects(t, h)
+
+	assert.True(t, reconciler.isDatabaseAccessible(ctx, dspa, params))
+	assert.Nil(t, params.DatabaseDiagnosis)
+	assert.Equal(t, 1, h.Database.Queries())
+	assert.True(t, reconciler.isObjectStorageAccessible(ctx, dspa, params))
+	assert.Nil(t, params.ObjectStorageDiagnosis)
+}
+
+func TestStorageHarnessDiagnoses(t *testing.T) {
+	h := NewStorageHarness(false)
+	defer h.Close()
+	ctx, dspa, params, reconciler := newHarnessedTestObjects(t, h)
+
+	// The health checks fail with the errors of the servers
+	h.Database.DropDatabase(fakeStorageDBName)
+	h.ObjectStore.SecretKey = "rotatedsecretkey"
+	assert.False(t, reconciler.isDatabaseAccessible(ctx, dspa, params))
+	assert.Equal(t, config.DBNotFound, params.DatabaseDiagnosis.Reason)
+	assert.False(t, reconciler.isObjectStorageAccessible(ctx, dspa, params))
+	assert.Equal(t, config.ObjStoreAuthFailed, params.ObjectStorageDiagnosis.Reason)
+
+	// A missing bucket is created by the API Server, so it is reported without failing the health check
+	h.ObjectStore.SecretKey = fakeStorageSecretKey
+	params.ObjectStorageConnection.Bucket = "missingbucket"
+	assert.True(t, reconciler.isObjectStorageAccessible(ctx, dspa, params))
+	assert.Equal(t, config.BucketNotFound, params.ObjectStorageDiagnosis.Reason)
+}
+
+func TestStorageHarnessTLS(t *testing.T) {
+	h := NewStorageHarness(true)
+	defer h.Close()
+	ctx, dspa, params, reconciler := newHarnessedTestObjects(t, h)
+
+	assert.True(t, *params.ObjectStorageConnection.Secure)
+	assert.True(t, reconciler.isObjectStorageAccessible(ctx, dspa, params))
+
+	// Without the CA bundle, the self-signed certificate of the object store is rejected
+	params.APICustomPemCerts = nil
+	assert.False(t, reconciler.isObjectStorageAccessible(ctx, dspa, params))
+	assert.Equal(t, config.TLSHandshakeError, params.ObjectStorageDiagnosis.Reason)
+}
+
+func TestStorageHarnessUploadAndPresign(t *testing.T) {
+	h := NewStorageHarness(false)
+	defer h.Close()
+	ctx := context.Background()
+	log := NewFakeController().Log
+
+	err := UploadToObjStore(ctx, log, h.ObjectStore.Endpoint(), fakeStorageDefaultBucket, "logs/step.log",
+		[]byte(fakeStorageAccessKey), []byte(fakeStorageSecretKey), false, nil, []byte("step logs"))
+	assert.Nil(t, err)
+	contents, found := h.ObjectStore.Object(fakeStorageDefaultBucket, "logs/step.log")
+	assert.True(t, found)
+	assert.Equal(t, "step logs", string(contents))
+
+	// Uploads of unknown size are streamed
+	err = StreamToObjStore(ctx, log, h.ObjectStore.Endpoint(), fakeStorageDefaultBucket, "logs/streamed.log",
+		[]byte(fakeStorageAccessKey), []byte(fakeStorageSecretKey), false, nil, strings.NewReader("streamed step logs"))
+	assert.Nil(t, err)
+	contents, found = h.ObjectStore.Object(fakeStorageDefaultBucket, "logs/streamed.log")
+	assert.True(t, found)
+	assert.Equal(t, "streamed step logs", string(contents))
+	large := strings.Repeat("step logs\n", objStoreStreamPartSize/5)
+	err = StreamToObjStore(ctx, log, h.ObjectStore.Endpoint(), fakeStorageDefaultBucket, "logs/large.log",
+		[]byte(fakeStorageAccessKey), []byte(fakeStorageSecretKey), false, nil, strings.NewReader(large))
+	assert.Nil(t, err)
+	contents, _ = h.ObjectStore.Object(fakeStorageDefaultBucket, "logs/large.log")
+	assert.Equal(t, large, string(contents))
+
+	minioClient, err := newObjStoreClient(log, h.ObjectStore.Endpoint(), []byte(fakeStorageAccessKey), []byte(fakeStorageSecretKey), false, nil)
+	assert.Nil(t, err)
+	presigned, err := minioClient.PresignedGetObject(ctx, fakeStorageDefaultBucket, "logs/step.log", time.Minute, nil)
+	assert.Nil(t, err)
+	resp, err := http.Get(presigned.String())
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "step logs", string(body))
+
+	// The signature covers the key
+	tampered := *presigned
+	tampered.Path = "/" + fakeStorageDefaultBucket + "/logs/other.log"
+	resp, err = http.Get(tampered.String())
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	_, err = minioClient.StatObject(ctx, fakeStorageDefaultBucket, "logs/missing.log", minio.StatObjectOptions{})
+	assert.Equal(t, "NoSuchKey", minio.ToErrorResponse(err).Code)
+}
@@ -44,6 +44,7 @@ type DSPAParams struct {
 	PiplinesCABundleMountPath            string
 	APIServerDefaultResourceName         string
 	APIServerServiceName                 string
+	APIServerConfigChecksum              string
 	APICustomPemCerts                    []byte
 	OAuthProxy                           string
 	ScheduledWorkflow                    *dspa.ScheduledWorkflow
@@ -54,6 +55,83 @@ type DSPAParams struct {
 	MariaDB                              *dspa.MariaDB
 	Minio                                *dspa.Minio
 	MLMD                                 *dspa.MLMD
+	ImagePrepuller                       *dspa.ImagePrepuller
+	ImagePrepullerDefaultResourceName    string
+	MariaDBPVCName                       string
+	MinioPVCName                         string
+	ImagePrepullerImages                 []string
+	CacheServer                          *dspa.CacheServer
+	CacheServerDefaultResourceName       string
+	CacheServerStepImage                 string
+	CacheServerTLSSecretName             string
+	CacheServerCABundle                  string
+	Headroom                             *dspa.Headroom
+	HeadroomDefaultResourceName          string
+	HeadroomPriority                     int32
+	HeadroomNodeSelector                 map[string]string
+	ConsoleLinkAPIServerHost             string
+	ConsoleLinkUIHost                    string
+	Architecture                         string
+	Architectures                        []string
+	NodeSelector                         map[string]string
+	OperatorVersion                      string
+	SourceRevision                       string
+	VersionManifest                      map[string]string
+	SecurityPosture                      map[string]string
+	KnownGoodImages                      map[string]string
+	KnownGoodCandidateImages             map[string]string
+	KnownGoodCandidatesSince             time.Time
+	KnownGoodImagesPromotedAt            time.Time
+	DatabaseDiagnosis                    *Diagnosis
+	ObjectStorageDiagnosis               *Diagnosis
+	SecurityProfiles                     map[string]*dspa.SecurityProfiles
+	DefaultSeccompProfile                string
+	Services                             map[string]*dspa.ComponentService
+	UpdateStrategies                     map[string]*dspa.UpdateStrategy
+	Placements                           map[string]*dspa.Placement
+	Scheduling                           map[string]*dspa.Scheduling
+	IPFamilies                           *dspa.IPFamilies
+	ExternalDNS                          *dspa.ExternalDNS
+	RouteEndpoints                       map[string]*RouteEndpoint
+	RouteCertificates                    map[string]*RouteCertificate
+	ListenAddress                        string
+	ListenIPv4Compat                     bool
+	MlmdEnvoyProxy                       MlmdEnvoyProxy
+	APIServerGRPCProxy                   APIServerGRPCProxy
+	APIGateway                           *dspa.APIGateway
+	APIGatewayDefaultResourceName        string
+	APIGatewayAuthPluginName             string
+	APIGatewayKongPlugins                string
+	APIGatewaySystemName                 string
+	MariaDBMaxConnections                int32
+	MariaDBExporter                      *MariaDBExporter
+	ReadOnlyRootFilesystem               bool
+	DevMode                              bool
+	AdoptExistingResources               bool
+	PendingUpgrade                       *PendingUpgrade
+	LifecycleHooksServiceAccountName     string
+	PreUpgradeHooks                      *Diagnosis
+	PostUpgradeHooks                     *Diagnosis
+	RenderFailure                        *Diagnosis
+	ManifestPatches                      []*ManifestPatch
+	NameCollision                        string
+	ResyncPeriod                         time.Duration
+	HealthCheckPeriod                    time.Duration
+	FeatureGates                         map[string]bool
+	APIStats                             *APIStats
+	DebugAccess                          *DebugAccess
+	Backups                              *Backups
+	ArtifactRetention                    *ArtifactRetention
+	DataResidency                        *DataResidency
+	DataResidencyDiagnosis               *Diagnosis
+	WebhookService                       types.NamespacedName
+	OperatorNamespace                    string
+	GuardrailsWebhookPath                string
+	SchedulesPaused                      bool
+	SchedulePolicyChangesAt              time.Time
+	SchedulePolicyWebhookPath            string
+	CostAttributionWebhookPath           string
+	StepRetriesWebhookPath               string
 	DBConnection
 	ObjectStorageConnection
 }
@@ -103,6 +181,16 @@ func (p *DSPAParams) UsingExternalStorage(dsp *dspa.DataSciencePipelinesApplicat
 	return false
 }
 
+// UsingMariaDB will return true if the DSPA deploys its own MariaDB, otherwise false.
+func (p *DSPAParams) UsingMariaDB(dsp *dspa.DataSciencePipelinesApplication) bool {
+	return !p.UsingExternalDB(dsp) && p.MariaDB != nil && p.MariaDB.Deploy
+}
+
+// UsingMinio will return true if the DSPA deploys its own Minio, otherwise false.
+func (p *DSPAParams) UsingMinio(dsp *dspa.DataSciencePipelinesApplication) bool {
+	return !p.UsingExternalStorage(dsp) && p.Minio != nil && p.Minio.Deploy
+}
+
 // ObjectStorageHealthCheckDisabled will return the value if the Object Storage has disableHealthCheck specified in the CR, otherwise false.
 func (p *DSPAParams) ObjectStorageHealthCheckDisabled(dsp *dspa.DataSciencePipelinesApplication) bool {
 	if dsp.Spec.ObjectStorage != nil {
@@ -118,6 +206,27 @@ func (p *DSPAParams) UsingMLMD(dsp *dspa.DataSciencePipelinesApplication) bool {
 	return false
 }
 
+func (p *DSPAParams) UsingCacheServer(dsp *dspa.DataSciencePipelinesApplication) bool {
+	if dsp.Spec.CacheServer != nil {
+		return dsp.Spec.CacheServer.Deploy
+	}
+	return false
+}
+
+func (p *DSPAParams) UsingHeadroom(dsp *dspa.DataSciencePipelinesApplication) bool {
+	if dsp.Spec.Headroom != nil {
+		return dsp.Spec.Headroom.Deploy
+	}
+	return false
+}
+
+func (p *DSPAParams) UsingImagePrepuller(dsp *dspa.DataSciencePipelinesApplication) bool {
+	if dsp.Spec.ImagePrepuller != nil {
+		return dsp.Spec.ImagePrepuller.Deploy
+	}
+	return false
+}
+
 func passwordGen(n int) string {
 	rand.Seed(time.Now().UnixNano())
 	var chars = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890")
@@ -203,7 +312,6 @@ func (p *DSPAParams) SetupDBParams(ctx context.Context, dsp *dspa.DataSciencePip
 		if p.MariaDB == nil {
 			p.MariaDB = &dspa.MariaDB{
 				Deploy:    true,
-				Image:     config.GetStringConfigWithDefault(config.MariaDBImagePath, config.DefaultImageValue),
 				Resources: config.MariaDBResourceRequirements.DeepCopy(),
 				Username:  config.MariaDBUser,
 				DBName:    config.MariaDBName,
@@ -213,8 +321,8 @@ func (p *DSPAParams) SetupDBParams(ctx context.Context, dsp *dspa.DataSciencePip
 
 		// If MariaDB was specified, ensure missing fields are
 		// populated with defaults.
-		if p.MariaDB.Image == "" {
-			p.MariaDB.Image = config.GetStringConfigWithDefault(config.MariaDBImagePath, config.DefaultImageValue)
+		if err := p.setImageDefault(config.MariaDBImagePath, &p.MariaDB.Image); err != nil {
+			return err
 		}
 		setStringDefault(config.MariaDBUser, &p.MariaDB.Username)
 		setStringDefault(config.MariaDBName, &p.MariaDB.DBName)
@@ -222,10 +330,10 @@ func (p *DSPAParams) SetupDBParams(ctx context.Context, dsp *dspa.DataSciencePip
 
 		p.DBConnection.Host = fmt.Sprintf(
 			"%s.%s.svc.cluster.local",
-			config.MariaDBHostPrefix+"-"+p.Name,
+			config.DerivedName(config.MariaDBHostPrefix+"-", p.Name),
 			p.Namespace,
 		)
-		p.DBConnection.Port = config.MariaDBHostPort
+		p.DBConnection.Port = servicePort(p.MariaDB.Service, "mysql", config.MariaDBHostPort)
 		p.DBConnection.Username = p.MariaDB.Username
 		p.DBConnection.DBName = p.MariaDB.DBName
 
@@ -234,7 +342,7 @@ func (p *DSPAParams) SetupDBParams(ctx context.Context, dsp *dspa.DataSciencePip
 			p.DBConnection.CredentialsSecret = p.MariaDB.PasswordSecret
 		} else {
 			p.DBConnection.CredentialsSecret = &dspa.SecretKeyValue{
-				Name: config.DefaultDBSecretNamePrefix + p.Name,
+				Name: config.DerivedName(config.DefaultDBSecretNamePrefix, p.Name),
 				Key:  config.DefaultDBSecretKey,
 			}
 		}
@@ -311,10 +419,10 @@ func (p *DSPAParams) SetupObjectParams(ctx context.Context, dsp *dspa.DataScienc
 		p.ObjectStorageConnection.Bucket = config.MinioDefaultBucket
 		p.ObjectStorageConnection.Host = fmt.Sprintf(
 			"%s.%s.svc.cluster.local",
-			config.MinioHostPrefix+"-"+p.Name,
+			config.DerivedName(config.MinioHostPrefix+"-", p.Name),
 			p.Namespace,
 		)
-		p.ObjectStorageConnection.Port = config.MinioPort
+		p.ObjectStorageConnection.Port = servicePort(p.Minio.Service, "http", config.MinioPort)
 		p.ObjectStorageConnection.Scheme = config.MinioScheme
 		p.ObjectStorageConnection.Secure = util.BoolPointer(false)
 
@@ -322,7 +430,7 @@ func (p *DSPAParams) SetupObjectParams(ctx context.Context, dsp *dspa.DataScienc
 			p.ObjectStorageConnection.CredentialsSecret = p.Minio.S3CredentialSecret
 		} else {
 			p.ObjectStorageConnection.CredentialsSecret = &dspa.S3CredentialSecret{
-				SecretName: config.DefaultObjectStorageSecretNamePrefix + p.Name,
+				SecretName: config.DerivedName(config.DefaultObjectStorageSecretNamePrefix, p.Name),
 				AccessKey:  config.DefaultObjectStorageAccessKey,
 				SecretKey:  config.DefaultObjectStorageSecretKey,
 			}
@@ -335,22 +443,12 @@ func (p *DSPAParams) SetupObjectParams(ctx context.Context, dsp *dspa.DataScienc
 		p.ObjectStorageConnection.SecretAccessKey = secretKey
 	}
 
-	endpoint := fmt.Sprintf(
+	p.ObjectStorageConnection.Endpoint = fmt.Sprintf(
 		"%s://%s",
 		p.ObjectStorageConnection.Scheme,
-		p.ObjectStorageConnection.Host,
+		hostPort(p.ObjectStorageConnection.Host, p.ObjectStorageConnection.Port),
 	)
 
-	if p.ObjectStorageConnection.Port != "" {
-		endpoint = fmt.Sprintf(
-			"%s:%s",
-			endpoint,
-			p.ObjectStorageConnection.Port,
-		)
-	}
-
-	p.ObjectStorageConnection.Endpoint = endpoint
-
 	if p.ObjectStorageConnection.AccessKeyID == "" || p.ObjectStorageConnection.SecretAccessKey == "" {
 		return fmt.Errorf(fmt.Sprintf("Object Storage Password from secret [%s] for keys [%s, %s] was not "+
 			"successfully retrieved, ensure that the secret with this key exist.",
@@ -364,34 +462,116 @@ func (p *DSPAParams) SetupObjectParams(ctx context.Context, dsp *dspa.DataScienc
 func (p *DSPAParams) SetupMLMD(ctx context.Context, dsp *dspa.DataSciencePipelinesApplication, client client.Client, log logr.Logger) error {
 	if p.MLMD != nil {
 		if p.MLMD.Envoy == nil {
-			p.MLMD.Envoy = &dspa.Envoy{
-				Image: config.GetStringConfigWithDefault(config.MlmdEnvoyImagePath, config.DefaultImageValue),
-			}
+			p.MLMD.Envoy = &dspa.Envoy{}
 		}
 		if p.MLMD.GRPC == nil {
-			p.MLMD.GRPC = &dspa.GRPC{
-				Image: config.GetStringConfigWithDefault(config.MlmdGRPCImagePath, config.DefaultImageValue),
-			}
+			p.MLMD.GRPC = &dspa.GRPC{}
 		}
 		if p.MLMD.Writer == nil {
-			p.MLMD.Writer = &dspa.Writer{
-				Image: config.GetStringConfigWithDefault(config.MlmdWriterImagePath, config.DefaultImageValue),
-			}
+			p.MLMD.Writer = &dspa.Writer{}
 		}
 
-		mlmdEnvoyImageFromConfig := config.GetStringConfigWithDefault(config.MlmdEnvoyImagePath, config.DefaultImageValue)
-		mlmdGRPCImageFromConfig := config.GetStringConfigWithDefault(config.MlmdGRPCImagePath, config.DefaultImageValue)
-		mlmdWriterImageFromConfig := config.GetStringConfigWithDefault(config.MlmdWriterImagePath, config.DefaultImageValue)
-
-		setStringDefault(mlmdEnvoyImageFromConfig, &p.MLMD.Envoy.Image)
-		setStringDefault(mlmdGRPCImageFromConfig, &p.MLMD.GRPC.Image)
-		setStringDefault(mlmdWriterImageFromConfig, &p.MLMD.Writer.Image)
+		if err := p.setImageDefault(config.MlmdEnvoyImagePath, &p.MLMD.Envoy.Image); err != nil {
+			return err
+		}
+		if err := p.setImageDefault(config.MlmdGRPCImagePath, &p.MLMD.GRPC.Image); err != nil {
+			return err
+		}
+		if err := p.setImageDefault(config.MlmdWriterImagePath, &p.MLMD.Writer.Image); err != nil {
+			return err
+		}
 
 		setResourcesDefault(config.MlmdEnvoyResourceRequirements, &p.MLMD.Envoy.Resources)
 		setResourcesDefault(config.MlmdGRPCResourceRequirements, &p.MLMD.GRPC.Resources)
 		setResourcesDefault(config.MlmdWriterResourceRequirements, &p.MLMD.Writer.Resources)
 
 		setStringDefault(config.MlmdGrpcPort, &p.MLMD.GRPC.Port)
+		if p.MLMD.GRPC.Replicas < 1 {
+			p.MLMD.GRPC.Replicas = config.MlmdGRPCDefaultReplicas
+		}
+		// A ClusterIP Service pins the long lived gRPC connection of Envoy to a single replica, and each extra replica
+		// opens its own database connections
+		if p.MLMD.GRPC.Replicas > 1 {
+			p.MLMD.GRPC.Headless = true
+			p.MariaDBMaxConnections = config.MariaDBDefaultMaxConnections + (p.MLMD.GRPC.Replicas-1)*config.MlmdGRPCReplicaDBConnections
+		}
+		if err := p.SetupMLMDEnvoy(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MlmdDBConnections returns the number of database connections the MLMD gRPC servers are expected to open, or 0 when
+// a single server is deployed.
+func (p *DSPAParams) MlmdDBConnections() int32 {
+	if p.MLMD == nil || p.MLMD.GRPC == nil || p.MLMD.GRPC.Replicas <= 1 {
+		return 0
+	}
+	return p.MLMD.GRPC.Replicas * config.MlmdGRPCReplicaDBConnections
+}
+
+// SetupNodeSelector restricts DSPA components to Linux nodes, so they are never scheduled onto
+// Windows nodes of mixed-OS clusters, and adds any extra node selectors from the operator config.
+func (p *DSPAParams) SetupNodeSelector() {
+	p.NodeSelector = map[string]string{config.NodeSelectorOSLabel: config.DefaultNodeOS}
+	for key, value := range config.GetStringMapConfigWithDefault(config.NodeSelectorConfigName, nil) {
+		p.NodeSelector[key] = value
+	}
+}
+
+// setImageDefault populates an empty image with the one configured for imagePath in the operator config.
+// When the DSPA pins an architecture, a per-architecture override is preferred, and the default image
+// is only used if it is published for that architecture.
+func (p *DSPAParams) setImageDefault(imagePath string, value *string) error {
+	if *value != "" {
+		return nil
+	}
+	if p.Architecture == "" {
+		*value = config.GetStringConfigWithDefault(imagePath, config.DefaultImageValue)
+		return nil
+	}
+	archImagePath := config.ArchitectureImagePath(p.Architecture, imagePath)
+	if image := config.GetStringConfigWithDefault(archImagePath, ""); image != "" {
+		*value = image
+		return nil
+	}
+	supported := config.GetStringSliceConfigWithDefault(config.ArchitecturesConfigName, []string{config.DefaultArchitecture})
+	for _, arch := range supported {
+		if arch == p.Architecture {
+			*value = config.GetStringConfigWithDefault(imagePath, config.DefaultImageValue)
+			return nil
+		}
+	}
+	return fmt.Errorf("no [%s] image configured for [%s], specify one in the DSPA CR Spec or set [%s] in the operator config",
+		p.Architecture, imagePath, archImagePath)
+}
+
+// SetupImagePrepuller populates the Image Prepuller defaults and collects the deduplicated list of
+// images to prepull, starting with the pipeline step images managed by DSP.
+func (p *DSPAParams) SetupImagePrepuller() error {
+	if p.ImagePrepuller == nil {
+		return nil
+	}
+	if err := p.setImageDefault(config.ImagePrepullerImagePath, &p.ImagePrepuller.Image); err != nil {
+		return err
+	}
+	setResourcesDefault(config.ImagePrepullerResourceRequirements, &p.ImagePrepuller.Resources)
+
+	var images []string
+	if p.APIServer != nil {
+		images = append(images, p.APIServer.ArtifactImage, p.APIServer.CacheImage, p.APIServer.MoveResultsImage)
+	}
+	images = append(images, p.ImagePrepuller.Images...)
+
+	seen := make(map[string]bool)
+	p.ImagePrepullerImages = nil
+	for _, image := range images {
+		if image == "" || seen[image] {
+			continue
+		}
+		seen[image] = true
+		p.ImagePrepullerImages = append(p.ImagePrepullerImages, image)
 	}
 	return nil
 }
@@ -402,6 +582,12 @@ func setStringDefault(defaultValue string, value *string) {
 	}
 }
 
+func setIntDefault(defaultValue int, value *int) {
+	if *value == 0 {
+		*value = defaultValue
+	}
+}
+
 func setResourcesDefault(defaultValue dspa.ResourceRequirements, value **dspa.ResourceRequirements) {
 	if *value == nil {
 		*value = defaultValue.DeepCopy()
@@ -413,37 +599,76 @@ func (p *DSPAParams) ExtractParams(ctx context.Context, dsp *dspa.DataSciencePip
 	p.Namespace = dsp.Namespace
 	p.Owner = dsp
 	p.APIServer = dsp.Spec.APIServer.DeepCopy()
-	p.APIServerDefaultResourceName = apiServerDefaultResourceNamePrefix + dsp.Name
-	p.APIServerServiceName = fmt.Sprintf("%s-%s", config.DSPServicePrefix, p.Name)
+	p.APIServerDefaultResourceName = config.DerivedName(apiServerDefaultResourceNamePrefix, dsp.Name)
+	p.APIServerServiceName = config.DerivedName(config.DSPServicePrefix+"-", p.Name)
 	p.ScheduledWorkflow = dsp.Spec.ScheduledWorkflow.DeepCopy()
-	p.ScheduledWorkflowDefaultResourceName = scheduledWorkflowDefaultResourceNamePrefix + dsp.Name
+	p.ScheduledWorkflowDefaultResourceName = config.DerivedName(scheduledWorkflowDefaultResourceNamePrefix, dsp.Name)
 	p.PersistenceAgent = dsp.Spec.PersistenceAgent.DeepCopy()
-	p.PersistentAgentDefaultResourceName = persistenceAgentDefaultResourceNamePrefix + dsp.Name
+	p.PersistentAgentDefaultResourceName = config.DerivedName(persistenceAgentDefaultResourceNamePrefix, dsp.Name)
 	p.MlPipelineUI = dsp.Spec.MlPipelineUI.DeepCopy()
 	p.MariaDB = dsp.Spec.Database.MariaDB.DeepCopy()
 	p.Minio = dsp.Spec.ObjectStorage.Minio.DeepCopy()
-	p.OAuthProxy = config.GetStringConfigWithDefault(config.OAuthProxyImagePath, config.DefaultImageValue)
 	p.MLMD = dsp.Spec.MLMD.DeepCopy()
+	p.ImagePrepuller = dsp.Spec.ImagePrepuller.DeepCopy()
+	p.IPFamilies = dsp.Spec.IPFamilies.DeepCopy()
+	p.ExternalDNS = dsp.Spec.ExternalDNS.DeepCopy()
+	p.ImagePrepullerDefaultResourceName = config.DerivedName(imagePrepullerDefaultResourceNamePrefix, dsp.Name)
+	p.CacheServer = dsp.Spec.CacheServer.DeepCopy()
+	p.CacheServerDefaultResourceName = config.DerivedName(cacheServerDefaultResourceNamePrefix, dsp.Name)
+	p.Headroom = dsp.Spec.Headroom.DeepCopy()
+	p.HeadroomDefaultResourceName = config.DerivedName(headroomDefaultResourceNamePrefix, dsp.Name)
+	p.APIGateway = dsp.Spec.APIGateway.DeepCopy()
 	p.APIServerPiplinesCABundleMountPath = config.APIServerPiplinesCABundleMountPath
 	p.PiplinesCABundleMountPath = config.PiplinesCABundleMountPath
+	p.OperatorVersion = config.OperatorVersion
+	p.SourceRevision = config.SourceRevision
+	p.ReadOnlyRootFilesystem = dsp.Spec.ReadOnlyRootFilesystem
+	p.AdoptExistingResources = dsp.Spec.AdoptExistingResources
+	p.SetupDevMode(dsp)
+	if err := p.SetupReconcileIntervals(dsp); err != nil {
+		return err
+	}
+	if err := p.SetupSchedulePolicy(dsp, time.Now()); err != nil {
+		return err
+	}
+	if err := p.SetupCostAttribution(dsp); err != nil {
+		return err
+	}
+	if err := p.SetupFeatureGates(dsp); err != nil {
+		return err
+	}
+	p.SetupArchitecture(dsp)
+	p.SetupNodeSelector()
+	if err := p.setImageDefault(config.OAuthProxyImagePath, &p.OAuthProxy); err != nil {
+		return err
+	}
 
 	if p.APIServer != nil {
 
-		serverImageFromConfig := config.GetStringConfigWithDefault(config.APIServerImagePath, config.DefaultImageValue)
-		artifactImageFromConfig := config.GetStringConfigWithDefault(config.APIServerArtifactImagePath, config.DefaultImageValue)
-		cacheImageFromConfig := config.GetStringConfigWithDefault(config.APIServerCacheImagePath, config.DefaultImageValue)
-		moveResultsImageFromConfig := config.GetStringConfigWithDefault(config.APIServerMoveResultsImagePath, config.DefaultImageValue)
-
-		setStringDefault(serverImageFromConfig, &p.APIServer.Image)
-		setStringDefault(artifactImageFromConfig, &p.APIServer.ArtifactImage)
-		setStringDefault(cacheImageFromConfig, &p.APIServer.CacheImage)
-		setStringDefault(moveResultsImageFromConfig, &p.APIServer.MoveResultsImage)
+		if err := p.setImageDefault(config.APIServerImagePath, &p.APIServer.Image); err != nil {
+			return err
+		}
+		if err := p.setImageDefault(config.APIServerArtifactImagePath, &p.APIServer.ArtifactImage); err != nil {
+			return err
+		}
+		if err := p.setImageDefault(config.APIServerCacheImagePath, &p.APIServer.CacheImage); err != nil {
+			return err
+		}
+		if err := p.setImageDefault(config.APIServerMoveResultsImagePath, &p.APIServer.MoveResultsImage); err != nil {
+			return err
+		}
 
 		setResourcesDefault(config.APIServerResourceRequirements, &p.APIServer.Resources)
+		setIntDefault(config.APIServerDefaultTerminationGracePeriodSeconds, &p.APIServer.TerminationGracePeriodSeconds)
+		setIntDefault(config.APIServerDefaultPreStopDrainSeconds, &p.APIServer.PreStopDrainSeconds)
+		if p.APIServer.PreStopDrainSeconds >= p.APIServer.TerminationGracePeriodSeconds {
+			return fmt.Errorf("apiServer preStopDrainSeconds (%d) must be lower than terminationGracePeriodSeconds (%d)",
+				p.APIServer.PreStopDrainSeconds, p.APIServer.TerminationGracePeriodSeconds)
+		}
 
 		if p.APIServer.ArtifactScriptConfigMap == nil {
 			p.APIServer.ArtifactScriptConfigMap = &dspa.ArtifactScriptConfigMap{
-				Name: config.ArtifactScriptConfigMapNamePrefix + dsp.Name,
+				Name: config.DerivedName(config.ArtifactScriptConfigMapNamePrefix, dsp.Name),
 				Key:  config.ArtifactScriptConfigMapKey,
 			}
 		}
@@ -462,13 +687,23 @@ func (p *DSPAParams) ExtractParams(ctx context.Context, dsp *dspa.DataSciencePip
 	}
 
 	if p.PersistenceAgent != nil {
-		persistenceAgentImageFromConfig := config.GetStringConfigWithDefault(config.PersistenceAgentImagePath, config.DefaultImageValue)
-		setStringDefault(persistenceAgentImageFromConfig, &p.PersistenceAgent.Image)
+		if err := p.setImageDefault(config.PersistenceAgentImagePath, &p.PersistenceAgent.Image); err != nil {
+			return err
+		}
 		setResourcesDefault(config.PersistenceAgentResourceRequirements, &p.PersistenceAgent.Resources)
+		setIntDefault(config.PersistenceAgentDefaultNumWorkers, &p.PersistenceAgent.NumWorkers)
+		setIntDefault(config.PersistenceAgentDefaultClientQPS, &p.PersistenceAgent.ClientQPS)
+		setIntDefault(config.PersistenceAgentDefaultClientBurst, &p.PersistenceAgent.ClientBurst)
+		if p.PersistenceAgent.TTLSecondsAfterWorkflowFinish == nil {
+			ttl := int64(config.PersistenceAgentDefaultTTLSecondsAfterWorkflowFinish)
+			p.PersistenceAgent.TTLSecondsAfterWorkflowFinish = &ttl
+		}
+		p.extendWorkflowTTL(dsp)
 	}
 	if p.ScheduledWorkflow != nil {
-		scheduledWorkflowImageFromConfig := config.GetStringConfigWithDefault(config.ScheduledWorkflowImagePath, config.DefaultImageValue)
-		setStringDefault(scheduledWorkflowImageFromConfig, &p.ScheduledWorkflow.Image)
+		if err := p.setImageDefault(config.ScheduledWorkflowImagePath, &p.ScheduledWorkflow.Image); err != nil {
+			return err
+		}
 		setResourcesDefault(config.ScheduledWorkflowResourceRequirements, &p.ScheduledWorkflow.Resources)
 	}
 	if p.MlPipelineUI != nil {
@@ -476,7 +711,7 @@ func (p *DSPAParams) ExtractParams(ctx context.Context, dsp *dspa.DataSciencePip
 			return fmt.Errorf("mlPipelineUI specified, but no image provided in the DSPA CR Spec")
 		}
 		p.MlPipelineUI.Image = dsp.Spec.MlPipelineUI.Image
-		setStringDefault(config.MLPipelineUIConfigMapPrefix+dsp.Name, &p.MlPipelineUI.ConfigMapName)
+		setStringDefault(config.DerivedName(config.MLPipelineUIConfigMapPrefix, dsp.Name), &p.MlPipelineUI.ConfigMapName)
 		setResourcesDefault(config.MlPipelineUIResourceRequirements, &p.MlPipelineUI.Resources)
 	}
 
@@ -485,15 +720,82 @@ func (p *DSPAParams) ExtractParams(ctx context.Context, dsp *dspa.DataSciencePip
 		return err
 	}
 
+	err = p.SetupImagePrepuller()
+	if err != nil {
+		return err
+	}
+
+	err = p.SetupHeadroom()
+	if err != nil {
+		return err
+	}
+
+	err = p.SetupAPIServerGRPC()
+	if err != nil {
+		return err
+	}
+
+	err = p.SetupAPIGateway()
+	if err != nil {
+		return err
+	}
+
 	err = p.SetupDBParams(ctx, dsp, client, log)
 	if err != nil {
 		return err
 	}
 
+	err = p.SetupPVCNames(ctx, dsp, client)
+	if err != nil {
+		return err
+	}
+
+	err = p.SetupMariaDBMetrics(ctx, dsp, client, log)
+	if err != nil {
+		return err
+	}
+
 	err = p.SetupObjectParams(ctx, dsp, client, log)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	err = p.SetupBackups(dsp)
+	if err != nil {
+		return err
+	}
+
+	err = p.SetupArtifactRetention(dsp)
+	if err != nil {
+		return err
+	}
+
+	err = p.SetupDataResidency(dsp)
+	if err != nil {
+		return err
+	}
+
+	err = p.SetupCacheServer(ctx, client, log)
+	if err != nil {
+		return err
+	}
+
+	err = p.SetupRouteTLS(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	p.SetupSecurityProfiles()
+	p.SetupServices()
+	p.SetupUpdateStrategies()
+	p.SetupPlacements()
+	p.SetupScheduling()
+	p.SetupIPFamilies()
+
+	err = p.SetupManifestPatches(ctx, dsp, client)
+	if err != nil {
+		return err
+	}
+
+	return p.SetupRouteHostnames()
 }
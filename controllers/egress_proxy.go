@@ -0,0 +1,72 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+)
+
+// egressHTTPClient returns the client the outbound notifications of the DSPA are sent with, through its egress proxy
+// if it has one. The caller closes the idle connections of proxied clients once done.
+func (r *DSPAReconciler) egressHTTPClient(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) (*http.Client, error) {
+	proxy := dsp.Spec.EgressProxy
+	if proxy == nil {
+		return runStatusWebhookClient, nil
+	}
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid egressProxy url: %w", err)
+	}
+	if proxy.UsernameSecret != nil && proxy.PasswordSecret != nil {
+		username, err := r.getSecretKeyValue(ctx, dsp.Namespace, proxy.UsernameSecret)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve the egress proxy username: %w", err)
+		}
+		password, err := r.getSecretKeyValue(ctx, dsp.Namespace, proxy.PasswordSecret)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve the egress proxy password: %w", err)
+		}
+		proxyURL.User = url.UserPassword(strings.TrimSpace(string(username)), strings.TrimSpace(string(password)))
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	if proxy.CABundle != nil {
+		err, pemCerts := util.GetConfigMapValue(ctx, proxy.CABundle.ConfigMapKey, proxy.CABundle.ConfigMapName, dsp.Namespace, r.Client, r.Log)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the egress proxy CA bundle from key [%s] of configmap [%s]: %w",
+				proxy.CABundle.ConfigMapKey, proxy.CABundle.ConfigMapName, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if ok := pool.AppendCertsFromPEM([]byte(pemCerts)); !ok {
+			return nil, fmt.Errorf("error parsing the egress proxy CA bundle of configmap [%s], ensure it holds PEM certificates", proxy.CABundle.ConfigMapName)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &http.Client{Timeout: runStatusWebhookClient.Timeout, Transport: transport}, nil
+}
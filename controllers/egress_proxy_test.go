@@ -0,0 +1,136 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	b64 "encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEgressHTTPClient(t *testing.T) {
+	// Record the requests an https egress proxy forwards
+	var proxied []*http.Request
+	proxy := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		proxied = append(proxied, req)
+	}))
+	defer proxy.Close()
+	proxyCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: proxy.Certificate().Raw})
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+
+	// Ensure DSPAs without an egress proxy send requests with the default client
+	httpClient, err := reconciler.egressHTTPClient(ctx, dspa)
+	assert.Nil(t, err)
+	assert.Equal(t, runStatusWebhookClient, httpClient)
+
+	dspa.Spec.EgressProxy = &dspav1alpha1.EgressProxy{
+		URL:            proxy.URL,
+		UsernameSecret: &dspav1alpha1.SecretKeyValue{Name: "proxy-credentials", Key: "username"},
+		PasswordSecret: &dspav1alpha1.SecretKeyValue{Name: "proxy-credentials", Key: "password"},
+		CABundle:       &dspav1alpha1.CABundle{ConfigMapName: "proxy-ca", ConfigMapKey: "ca.crt"},
+	}
+	// Ensure missing credentials are reported
+	_, err = reconciler.egressHTTPClient(ctx, dspa)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, reconciler.Create(ctx, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxy-credentials", Namespace: "testnamespace"},
+		Data:       map[string][]byte{"username": []byte("dspa"), "password": []byte("proxysecret\n")},
+	}))
+	caBundle := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxy-ca", Namespace: "testnamespace"},
+		Data:       map[string]string{"ca.crt": "not a certificate"},
+	}
+	assert.Nil(t, reconciler.Create(ctx, caBundle))
+
+	// Ensure an invalid CA bundle is rejected
+	_, err = reconciler.egressHTTPClient(ctx, dspa)
+	assert.NotNil(t, err)
+
+	// Ensure requests are sent through the proxy, trusting its CA and authenticating with the credentials
+	caBundle.Data["ca.crt"] = string(proxyCA)
+	assert.Nil(t, reconciler.Update(ctx, caBundle))
+	httpClient, err = reconciler.egressHTTPClient(ctx, dspa)
+	assert.Nil(t, err)
+	defer httpClient.CloseIdleConnections()
+	resp, err := httpClient.Get("http://webhooks.example.com/notify")
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Len(t, proxied, 1)
+	assert.Equal(t, "webhooks.example.com", proxied[0].URL.Host)
+	assert.Equal(t, "Basic "+b64.StdEncoding.EncodeToString([]byte("dspa:proxysecret")), proxied[0].Header.Get("Proxy-Authorization"))
+}
+
+func TestWebhookHTTPClientEgressProxy(t *testing.T) {
+	// An egress proxy on loopback, standing in for an internal host a DSPA editor points the operator at
+	var proxied []*http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		proxied = append(proxied, req)
+	}))
+	t.Cleanup(func() {
+		proxy.Close()
+		viper.Set(config.WebhookAllowedHostsConfigName, nil)
+	})
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec:       dspav1alpha1.DSPASpec{EgressProxy: &dspav1alpha1.EgressProxy{URL: proxy.URL}},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+
+	// Ensure the egress proxy of the DSPA is restricted like webhook hosts
+	httpClient, err := reconciler.webhookHTTPClient(ctx, dspa)
+	assert.Nil(t, err)
+	_, err = httpClient.Get("http://webhooks.example.com/notify")
+	assert.ErrorContains(t, err, "resolves to the restricted address [127.0.0.1]")
+	assert.Empty(t, proxied)
+
+	// Ensure proxies allowed in the operator config are connected to
+	viper.Set(config.WebhookAllowedHostsConfigName, []string{"127.0.0.1"})
+	httpClient, err = reconciler.webhookHTTPClient(ctx, dspa)
+	assert.Nil(t, err)
+	defer httpClient.CloseIdleConnections()
+	resp, err := httpClient.Get("http://webhooks.example.com/notify")
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Len(t, proxied, 1)
+
+	// Ensure trusted socks5 proxies without a port are exempt on their default port
+	socksURL, err := url.Parse("socks5://127.0.0.1")
+	assert.Nil(t, err)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(socksURL)
+	viper.Set(config.WebhookAllowedHostsConfigName, nil)
+	_, err = (&http.Client{Transport: restrictTransport(transport, true)}).Get("http://webhooks.example.com/notify")
+	assert.NotNil(t, err)
+	assert.NotContains(t, err.Error(), "restricted address")
+}
@@ -0,0 +1,68 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// recordEvent records an Event on the DSPA, listed by `kubectl describe dspa`. Reconcilers without a Recorder, e.g.
+// the one writing upgrade reports, record nothing. Messages quote errors of the database and object store clients like
+// the status conditions do, so the DSPA's credentials are redacted from them too.
+func (r *DSPAReconciler) recordEvent(dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Event(dsp, eventType, reason, params.redactCredentials(fmt.Sprintf(messageFmt, args...)))
+}
+
+// recordHealthCheckEvents records a Warning Event for each failed health check, with the failure reason of its
+// condition. Only fresh checks are recorded, their cached results were recorded when checked.
+func (r *DSPAReconciler) recordHealthCheckEvents(dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams, result healthCheckResult) {
+	if !result.dbAvailable && result.databaseDiagnosis != nil {
+		r.recordEvent(dsp, params, corev1.EventTypeWarning, result.databaseDiagnosis.Reason, "%s", result.databaseDiagnosis.Message)
+	}
+	if !result.objStoreAvailable && result.objectStorageDiagnosis != nil {
+		r.recordEvent(dsp, params, corev1.EventTypeWarning, result.objectStorageDiagnosis.Reason, "%s", result.objectStorageDiagnosis.Message)
+	}
+	if result.dataResidencyDiagnosis != nil && result.dataResidencyDiagnosis.Reason != config.RegionAllowed {
+		r.recordEvent(dsp, params, corev1.EventTypeWarning, result.dataResidencyDiagnosis.Reason, "%s", result.dataResidencyDiagnosis.Message)
+	}
+}
+
+// eventRecordingClient records a Created Event on the DSPA for every object manifestival creates for it. Only
+// creations are recorded, so that applying the manifests of every reconcile doesn't flood the DSPA with Events.
+type eventRecordingClient struct {
+	client.Client
+	recorder record.EventRecorder
+	dspa     *dspav1alpha1.DataSciencePipelinesApplication
+}
+
+func (c *eventRecordingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	if err == nil {
+		c.recorder.Eventf(c.dspa, corev1.EventTypeNormal, config.CreatedEventReason, "Created %s %s",
+			obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName())
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-sql-driver/mysql"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// recordedEvents drains the Events recorded so far
+func recordedEvents(recorder *record.FakeRecorder) []string {
+	events := []string{}
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestRecordCreatedEvents(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	recorder := record.NewFakeRecorder(100)
+	reconciler.Recorder = recorder
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// Assert every object created for the DSPA is recorded
+	assert.Nil(t, reconciler.ReconcileDatabase(ctx, dspa, params))
+	events := recordedEvents(recorder)
+	assert.Contains(t, events, "Normal Created Created Deployment mariadb-testdspa")
+	assert.Contains(t, events, "Normal Created Created Secret ds-pipeline-db-testdspa")
+
+	// Assert objects applied again are not
+	assert.Nil(t, reconciler.ReconcileDatabase(ctx, dspa, params))
+	assert.Empty(t, recordedEvents(recorder))
+}
+
+func TestRecordHealthCheckEvents(t *testing.T) {
+	defaultConnectAndQueryDatabase := ConnectAndQueryDatabase
+	defaultConnectAndQueryObjStore := ConnectAndQueryObjStore
+	t.Cleanup(func() {
+		ConnectAndQueryDatabase = defaultConnectAndQueryDatabase
+		ConnectAndQueryObjStore = defaultConnectAndQueryObjStore
+	})
+	ConnectAndQueryDatabase = func(host, port, username, password, dbname string, dbConnectionTimeout time.Duration) error {
+		return &mysql.MySQLError{Number: 1045, Message: "Access denied for user 'mlpipeline' with password 'foopassword'"}
+	}
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) error {
+		return nil
+	}
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+	}
+	_, _, reconciler := CreateNewTestObjects()
+	recorder := record.NewFakeRecorder(100)
+	reconciler.Recorder = recorder
+	secure := false
+	params := &DSPAParams{
+		HealthCheckPeriod: time.Minute,
+		DBConnection:      DBConnection{Password: base64.StdEncoding.EncodeToString([]byte("foopassword"))},
+		ObjectStorageConnection: ObjectStorageConnection{
+			Host:            "foo",
+			Port:            "1337",
+			Bucket:          "mlpipeline",
+			Secure:          &secure,
+			AccessKeyID:     base64.StdEncoding.EncodeToString([]byte("fooaccesskey")),
+			SecretAccessKey: base64.StdEncoding.EncodeToString([]byte("foosecretkey")),
+		},
+	}
+
+	// Assert failed health checks are recorded with the failure reason of their condition
+	now := time.Now()
+	dbAvailable, objStoreAvailable := reconciler.checkDependencies(context.Background(), dspa, params, now)
+	assert.False(t, dbAvailable)
+	assert.True(t, objStoreAvailable)
+	events := recordedEvents(recorder)
+	assert.Len(t, events, 1)
+	assert.Contains(t, events[0], "Warning DBAuthFailed")
+
+	// Assert the credentials quoted by the failure are redacted
+	assert.NotContains(t, events[0], "foopassword")
+
+	// Assert cached results are not recorded again
+	reconciler.checkDependencies(context.Background(), dspa, params, now.Add(time.Second))
+	assert.Empty(t, recordedEvents(recorder))
+}
@@ -0,0 +1,56 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	externalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+	externalDNSTTLAnnotation      = "external-dns.alpha.kubernetes.io/ttl"
+
+	defaultExternalDNSHostnameTemplate = "{{.Component}}-{{.Name}}-{{.Namespace}}"
+)
+
+// setupExternalDNSAnnotations sets the annotations external-dns publishes the records of every endpoint from, for
+// DSPAs with externalDNS.
+func (p *DSPAParams) setupExternalDNSAnnotations() error {
+	if p.ExternalDNS == nil {
+		return nil
+	}
+	for _, endpoint := range p.RouteEndpoints {
+		endpoint.Annotations = map[string]string{externalDNSHostnameAnnotation: endpoint.Hostname}
+		if p.ExternalDNS.TTL > 0 {
+			endpoint.Annotations[externalDNSTTLAnnotation] = strconv.Itoa(int(p.ExternalDNS.TTL))
+		}
+		for key, value := range p.ExternalDNS.Annotations {
+			annotation, err := template.New(key).Option("missingkey=error").Parse(value)
+			if err != nil {
+				return fmt.Errorf("invalid externalDNS annotation [%s]: %w", key, err)
+			}
+			var rendered strings.Builder
+			if err := annotation.Execute(&rendered, endpoint); err != nil {
+				return fmt.Errorf("invalid externalDNS annotation [%s]: %w", key, err)
+			}
+			endpoint.Annotations[key] = rendered.String()
+		}
+	}
+	return nil
+}
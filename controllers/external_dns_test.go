@@ -0,0 +1,69 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupExternalDNS(t *testing.T) {
+	params := &DSPAParams{
+		Name:      "testdspa",
+		Namespace: "testnamespace",
+		APIServer: &dspav1alpha1.APIServer{GRPC: &dspav1alpha1.APIServerGRPC{Host: "kfp-grpc.example.com"}},
+		ExternalDNS: &dspav1alpha1.ExternalDNS{
+			Domain:      "pipelines.example.com",
+			TTL:         60,
+			Annotations: map[string]string{"external-dns.alpha.kubernetes.io/set-identifier": "{{.Namespace}}-{{.Component}}"},
+		},
+	}
+	assert.Nil(t, params.SetupRouteHostnames())
+
+	// Ensure hostnames are generated in the domain, except for hosts set explicitly
+	api := params.RouteEndpoints["ds-pipeline-testdspa"]
+	assert.Equal(t, "api-testdspa-testnamespace.pipelines.example.com", api.Hostname)
+	assert.Equal(t, map[string]string{
+		externalDNSHostnameAnnotation:                     "api-testdspa-testnamespace.pipelines.example.com",
+		externalDNSTTLAnnotation:                          "60",
+		"external-dns.alpha.kubernetes.io/set-identifier": "testnamespace-api",
+	}, api.Annotations)
+	assert.Equal(t, "ui-testdspa-testnamespace.pipelines.example.com", params.RouteEndpoints["ds-pipeline-ui-testdspa"].Hostname)
+	assert.Equal(t, "kfp-grpc.example.com", params.RouteEndpoints["ds-pipeline-grpc-testdspa"].Hostname)
+
+	// Ensure the hostname template is applied
+	params.ExternalDNS.HostnameTemplate = "{{.Name}}-{{.Component}}"
+	assert.Nil(t, params.SetupRouteHostnames())
+	assert.Equal(t, "testdspa-minio.pipelines.example.com", params.RouteEndpoints["minio-testdspa"].Hostname)
+
+	// Ensure invalid templates and hostnames are rejected
+	params.ExternalDNS.HostnameTemplate = "{{.Cluster}}"
+	assert.NotNil(t, params.SetupRouteHostnames())
+	params.ExternalDNS.HostnameTemplate = "{{.Component}}_{{.Name}}"
+	assert.NotNil(t, params.SetupRouteHostnames())
+	params.ExternalDNS.HostnameTemplate = strings.Repeat("a", 60) + "-{{.Component}}"
+	assert.NotNil(t, params.SetupRouteHostnames())
+
+	// Ensure DSPAs without externalDNS keep the hostnames generated by the router
+	params.ExternalDNS = nil
+	assert.Nil(t, params.SetupRouteHostnames())
+	assert.Nil(t, params.RouteEndpoints)
+}
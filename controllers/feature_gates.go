@@ -0,0 +1,73 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+)
+
+// FeatureGate is an experimental behavior of DSPO, enabled or disabled per DSPA with spec.featureGates.
+type FeatureGate struct {
+	// Default applies to DSPAs that don't set the gate
+	Default bool
+	// Stage is Alpha for gates disabled by default, Beta for gates enabled by default
+	Stage string
+}
+
+// QueueMetricsFeatureGate publishes the queue metrics of the DSPA, listing every TaskRun of its namespace on each run
+// report interval.
+const QueueMetricsFeatureGate = "QueueMetrics"
+
+// featureGates are the gates known to DSPO. The validation of DSPASpec.FeatureGates lists the same names.
+var featureGates = map[string]FeatureGate{
+	QueueMetricsFeatureGate: {Default: true, Stage: "Beta"},
+}
+
+// SetupFeatureGates resolves the feature gates of the DSPA from their defaults and spec.featureGates. Unknown gates
+// are rejected, as the CRD validation doesn't apply to DSPAs created before the gate was removed.
+func (p *DSPAParams) SetupFeatureGates(dsp *dspav1alpha1.DataSciencePipelinesApplication) error {
+	p.FeatureGates = make(map[string]bool, len(featureGates))
+	for name, gate := range featureGates {
+		p.FeatureGates[name] = gate.Default
+	}
+	for name, enabled := range dsp.Spec.FeatureGates {
+		if _, ok := featureGates[name]; !ok {
+			return fmt.Errorf("unknown feature gate [%s]", name)
+		}
+		p.FeatureGates[name] = enabled
+	}
+	return nil
+}
+
+// FeatureEnabled returns true if the feature gate is enabled for the DSPA.
+func (p *DSPAParams) FeatureEnabled(name string) bool {
+	return p.FeatureGates[name]
+}
+
+// ActiveFeatureGates returns the sorted names of the feature gates enabled for the DSPA.
+func (p *DSPAParams) ActiveFeatureGates() []string {
+	var active []string
+	for name, enabled := range p.FeatureGates {
+		if enabled {
+			active = append(active, name)
+		}
+	}
+	sort.Strings(active)
+	return active
+}
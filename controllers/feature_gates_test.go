@@ -0,0 +1,62 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"os"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetupFeatureGates(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+
+	// Ensure gates keep their defaults when unset
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.True(t, params.FeatureEnabled(QueueMetricsFeatureGate))
+	assert.Equal(t, []string{QueueMetricsFeatureGate}, params.ActiveFeatureGates())
+
+	// Ensure gates can be disabled per DSPA
+	dspa.Spec.FeatureGates = map[string]bool{QueueMetricsFeatureGate: false}
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.False(t, params.FeatureEnabled(QueueMetricsFeatureGate))
+	assert.Empty(t, params.ActiveFeatureGates())
+
+	// Ensure unknown gates are rejected
+	dspa.Spec.FeatureGates = map[string]bool{"ArtifactProxy": true}
+	assert.ErrorContains(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log), "unknown feature gate [ArtifactProxy]")
+}
+
+func TestFeatureGatesValidation(t *testing.T) {
+	// Ensure the CRD validation accepts every known gate
+	crd, err := os.ReadFile("../config/crd/bases/datasciencepipelinesapplications.opendatahub.io_datasciencepipelinesapplications.yaml")
+	assert.Nil(t, err)
+	for name := range featureGates {
+		assert.Contains(t, string(crd), "'"+name+"'")
+	}
+}
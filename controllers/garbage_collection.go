@@ -0,0 +1,170 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Pod GC strategies, named after the equivalent Argo Workflows strategies
+const (
+	podGCOnPodCompletion      = "OnPodCompletion"
+	podGCOnPodSuccess         = "OnPodSuccess"
+	podGCOnWorkflowCompletion = "OnWorkflowCompletion"
+	podGCOnWorkflowSuccess    = "OnWorkflowSuccess"
+	taskRunLabel              = "tekton.dev/taskRun"
+)
+
+// UsingGarbageCollection returns true if the DSPA deletes finished runs or their pods itself.
+func (p *DSPAParams) UsingGarbageCollection(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	gc := dsp.Spec.GarbageCollection
+	return gc != nil && (gc.TTLAfterSucceeded != nil || gc.TTLAfterFailed != nil || gc.PodGCStrategy != "")
+}
+
+// extendWorkflowTTL raises the Persistence Agent's TTL to the longest run TTL of the DSPA, as it deletes every finished
+// run once its own TTL expires.
+func (p *DSPAParams) extendWorkflowTTL(dsp *dspav1alpha1.DataSciencePipelinesApplication) {
+	gc := dsp.Spec.GarbageCollection
+	if gc == nil {
+		return
+	}
+	for _, ttl := range []*metav1.Duration{gc.TTLAfterSucceeded, gc.TTLAfterFailed} {
+		if ttl == nil {
+			continue
+		}
+		if seconds := int64(ttl.Duration.Seconds()); seconds > *p.PersistenceAgent.TTLSecondsAfterWorkflowFinish {
+			p.PersistenceAgent.TTLSecondsAfterWorkflowFinish = &seconds
+		}
+	}
+}
+
+// finishedRun is a finished PipelineRun, with whether it succeeded.
+type finishedRun struct {
+	pipelineRun unstructured.Unstructured
+	finishedAt  time.Time
+	succeeded   bool
+}
+
+// deletePodOf returns true if the pod of a finished step is deleted with the strategy, given the run of the step.
+func deletePodOf(strategy string, taskRun unstructured.Unstructured, run *finishedRun) bool {
+	if _, finished := pipelineRunCompletionTime(taskRun); !finished {
+		return false
+	}
+	status, _, _ := succeededCondition(taskRun)
+	switch strategy {
+	case podGCOnPodCompletion:
+		return true
+	case podGCOnPodSuccess:
+		return status == "Succeeded"
+	case podGCOnWorkflowCompletion:
+		return run != nil
+	case podGCOnWorkflowSuccess:
+		return run != nil && run.succeeded
+	}
+	return false
+}
+
+// CollectGarbage deletes the finished PipelineRuns of the DSPA namespace past the TTL of their status, and the step pods
+// matching the PodGCStrategy, except those of the RetainedRunPods most recently finished runs. Runs are only deleted
+// once the Persistence Agent reported their final state, so the API Server keeps their record, and pods are only
+// deleted once the step exit handler, if enabled, handled their step, so their logs are captured first.
+func (r *DSPAReconciler) CollectGarbage(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, now time.Time) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	gc := dsp.Spec.GarbageCollection
+
+	pipelineRuns, err := r.listPipelineRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return err
+	}
+	var finished []*finishedRun
+	for _, pipelineRun := range pipelineRuns {
+		finishedAt, ok := pipelineRunCompletionTime(pipelineRun)
+		if !ok {
+			continue
+		}
+		status, _, _ := succeededCondition(pipelineRun)
+		finished = append(finished, &finishedRun{pipelineRun: pipelineRun, finishedAt: finishedAt, succeeded: status == "Succeeded"})
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].finishedAt.After(finished[j].finishedAt) })
+
+	runs := make(map[string]*finishedRun)
+	retained := make(map[string]bool)
+	for i, run := range finished {
+		name := run.pipelineRun.GetName()
+		runs[name] = run
+		if i < gc.RetainedRunPods {
+			retained[name] = true
+		}
+
+		ttl := gc.TTLAfterFailed
+		if run.succeeded {
+			ttl = gc.TTLAfterSucceeded
+		}
+		if ttl == nil || now.Sub(run.finishedAt) <= ttl.Duration || run.pipelineRun.GetLabels()[persistedFinalStateLabel] != "true" {
+			continue
+		}
+		log.Info(fmt.Sprintf("Deleting PipelineRun [%s], finished for longer than its TTL [%s]", name, ttl.Duration))
+		if err := r.Delete(ctx, &run.pipelineRun, client.PropagationPolicy("Background")); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if gc.PodGCStrategy == "" {
+		return nil
+	}
+	taskRuns, err := r.listPipelineTaskRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return err
+	}
+	taskRunsByName := make(map[string]unstructured.Unstructured, len(taskRuns))
+	for _, taskRun := range taskRuns {
+		taskRunsByName[taskRun.GetName()] = taskRun
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(dsp.Namespace), client.HasLabels{taskRunLabel, pipelineRunLabel}); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		pipelineRun := pod.Labels[pipelineRunLabel]
+		taskRun, ok := taskRunsByName[pod.Labels[taskRunLabel]]
+		if !ok || retained[pipelineRun] || !deletePodOf(gc.PodGCStrategy, taskRun, runs[pipelineRun]) {
+			continue
+		}
+		// The step exit handler only handles steps that finished within the lookback window
+		stepFinishedAt, _ := pipelineRunCompletionTime(taskRun)
+		_, handled := taskRun.GetAnnotations()[stepExitAnnotation]
+		if dsp.Spec.StepExitHandler != nil && !handled && now.Sub(stepFinishedAt) <= config.RunNotificationLookback {
+			continue
+		}
+		log.V(1).Info(fmt.Sprintf("Deleting pod [%s] of TaskRun [%s] with pod GC strategy [%s]", pod.Name, taskRun.GetName(), gc.PodGCStrategy))
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestFinishedRun(name, status string, completionTime time.Time) *unstructured.Unstructured {
+	pipelineRun := newTestPipelineRun(name, "testnamespace", &completionTime, true)
+	_ = unstructured.SetNestedSlice(pipelineRun.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": status},
+	}, "status", "conditions")
+	return pipelineRun
+}
+
+func newTestStepPod(taskRun *unstructured.Unstructured) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      taskRun.GetName() + "-pod",
+			Namespace: "testnamespace",
+			Labels: map[string]string{
+				taskRunLabel:     taskRun.GetName(),
+				pipelineRunLabel: taskRun.GetLabels()[pipelineRunLabel],
+			},
+		},
+	}
+}
+
+func TestExtendWorkflowTTL(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			GarbageCollection: &dspav1alpha1.GarbageCollection{
+				TTLAfterSucceeded: &metav1.Duration{Duration: time.Hour},
+				TTLAfterFailed:    &metav1.Duration{Duration: 168 * time.Hour},
+			},
+		},
+	}
+	ttl := int64(86400)
+	params := &DSPAParams{PersistenceAgent: &dspav1alpha1.PersistenceAgent{TTLSecondsAfterWorkflowFinish: &ttl}}
+	params.extendWorkflowTTL(dspa)
+	assert.Equal(t, int64(168*3600), *params.PersistenceAgent.TTLSecondsAfterWorkflowFinish)
+}
+
+func TestCollectGarbage(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			GarbageCollection: &dspav1alpha1.GarbageCollection{
+				TTLAfterSucceeded: &metav1.Duration{Duration: time.Hour},
+				TTLAfterFailed:    &metav1.Duration{Duration: 168 * time.Hour},
+				PodGCStrategy:     podGCOnWorkflowSuccess,
+				RetainedRunPods:   1,
+			},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.True(t, params.UsingGarbageCollection(dspa))
+	now := time.Now().Truncate(time.Second)
+
+	expired := newTestFinishedRun("expired", "True", now.Add(-2*time.Hour))
+	unreported := newTestFinishedRun("unreported", "True", now.Add(-2*time.Hour))
+	unreported.SetLabels(nil)
+	failed := newTestFinishedRun("failed", "False", now.Add(-2*time.Hour))
+	succeeded := newTestFinishedRun("succeeded", "True", now.Add(-30*time.Minute))
+	latest := newTestFinishedRun("latest", "True", now.Add(-time.Minute))
+	for _, run := range []*unstructured.Unstructured{expired, unreported, failed, succeeded, latest} {
+		assert.Nil(t, reconciler.Create(ctx, run))
+	}
+	var pods []*corev1.Pod
+	for _, run := range []string{"failed", "succeeded", "latest"} {
+		taskRun := newTestTaskRun(run+"-train", "train", "True", now.Add(-time.Hour))
+		taskRun.SetLabels(map[string]string{pipelineRunLabel: run})
+		assert.Nil(t, reconciler.Create(ctx, taskRun))
+		pod := newTestStepPod(taskRun)
+		assert.Nil(t, reconciler.Create(ctx, pod))
+		pods = append(pods, pod)
+	}
+
+	assert.Nil(t, reconciler.CollectGarbage(ctx, dspa, now))
+
+	// Ensure only reported runs past the TTL of their status are deleted
+	for name, deleted := range map[string]bool{"expired": true, "unreported": false, "failed": false, "succeeded": false, "latest": false} {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(expired.GroupVersionKind())
+		err := reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: "testnamespace"}, got)
+		assert.Equal(t, deleted, apierrs.IsNotFound(err), name)
+	}
+	// Ensure only the pods of succeeded runs are deleted, except those of the latest run
+	for _, pod := range pods {
+		err := reconciler.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, &corev1.Pod{})
+		assert.Equal(t, pod.Name == "succeeded-train-pod", apierrs.IsNotFound(err), pod.Name)
+	}
+}
+
+func TestCollectGarbageWaitsForStepExitHandler(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			GarbageCollection: &dspav1alpha1.GarbageCollection{PodGCStrategy: podGCOnPodCompletion},
+			StepExitHandler:   &dspav1alpha1.StepExitHandler{CaptureLogs: true},
+		},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+	now := time.Now().Truncate(time.Second)
+
+	unhandled := newTestTaskRun("somerun-train", "train", "False", now.Add(-time.Minute))
+	handled := newTestTaskRun("somerun-prepare", "prepare", "True", now.Add(-time.Minute))
+	handled.SetAnnotations(map[string]string{stepExitAnnotation: stepExitHandled})
+	for _, taskRun := range []*unstructured.Unstructured{unhandled, handled} {
+		assert.Nil(t, reconciler.Create(ctx, taskRun))
+		assert.Nil(t, reconciler.Create(ctx, newTestStepPod(taskRun)))
+	}
+
+	assert.Nil(t, reconciler.CollectGarbage(ctx, dspa, now))
+
+	err := reconciler.Get(ctx, types.NamespacedName{Name: "somerun-train-pod", Namespace: "testnamespace"}, &corev1.Pod{})
+	assert.Nil(t, err)
+	err = reconciler.Get(ctx, types.NamespacedName{Name: "somerun-prepare-pod", Namespace: "testnamespace"}, &corev1.Pod{})
+	assert.True(t, apierrs.IsNotFound(err))
+}
@@ -0,0 +1,177 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// guardrailsWebhookTemplate is cluster scoped, so it can't be owned by the DSPA and is deleted explicitly
+const guardrailsWebhookTemplate = "guardrails/webhook.yaml.tmpl"
+
+// GuardrailsWebhookPath is the path of the operator's webhook server the guardrails webhooks call
+const GuardrailsWebhookPath = "/validate-pipeline-pods"
+
+const defaultGPUResourceName = "nvidia.com/gpu"
+
+// UsingGuardrails returns true if the DSPA limits the resources of its pipeline runs.
+func (p *DSPAParams) UsingGuardrails(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	return dsp.Spec.Guardrails != nil && dsp.Spec.Guardrails.MaxPipelineResources != nil
+}
+
+func (r *DSPAReconciler) ReconcileGuardrails(dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	params.GuardrailsWebhookPath = GuardrailsWebhookPath
+	if !params.UsingGuardrails(dsp) {
+		log.Info("Skipping Application of Guardrails Resources")
+		// Stop admitting step pods through the operator once the guardrails are removed
+		return r.DeleteResource(params, guardrailsWebhookTemplate)
+	}
+	// The webhook rejects the step pods it can't call, fail the reconcile instead
+	if r.WebhookService == nil {
+		return fmt.Errorf("spec.guardrails requires the operator to serve its admission webhook, it serves it once a " +
+			"serving certificate is mounted in its webhook certificate directory")
+	}
+
+	log.Info("Applying Guardrails Resources")
+	params.WebhookService = *r.WebhookService
+	err := r.ApplyWithoutOwner(params, guardrailsWebhookTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Guardrails Resources")
+	return nil
+}
+
+func (r *DSPAReconciler) CleanUpGuardrails(params *DSPAParams) error {
+	params.GuardrailsWebhookPath = GuardrailsWebhookPath
+	return r.DeleteResource(params, guardrailsWebhookTemplate)
+}
+
+// GuardrailsWebhook admits the pipeline step pods of a namespace as long as the total resources requested by its
+// running step pods stay within the maxPipelineResources of every DSPA of the namespace.
+type GuardrailsWebhook struct {
+	Client client.Client
+	Log    logr.Logger
+}
+
+func (w *GuardrailsWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	if err := json.Unmarshal(req.Object.Raw, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	dspas := &dspav1alpha1.DataSciencePipelinesApplicationList{}
+	if err := w.Client.List(ctx, dspas, client.InNamespace(req.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	pods := &corev1.PodList{}
+	if err := w.Client.List(ctx, pods, client.InNamespace(req.Namespace), client.HasLabels{pipelineRunLabel}); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	for _, dspa := range dspas.Items {
+		if dspa.Spec.Guardrails == nil || dspa.Spec.Guardrails.MaxPipelineResources == nil {
+			continue
+		}
+		if exceeded := exceededPipelineResources(dspa.Spec.Guardrails.MaxPipelineResources, pods.Items, pod); exceeded != "" {
+			w.Log.Info("Rejected pipeline pod over the guardrails", "namespace", req.Namespace, "dspa_name", dspa.Name,
+				"pod", pod.Name, "reason", exceeded)
+			// Tekton keeps the TaskRun pending and retries creating the pod on errors of exceeded quotas, rather
+			// than failing the TaskRun
+			return admission.Denied(fmt.Sprintf("exceeded quota: guardrails of DSPA [%s], %s", dspa.Name, exceeded))
+		}
+	}
+	return admission.Allowed("")
+}
+
+// exceededPipelineResources describes the resource the running step pods and the pod would request more of than
+// the maximum, or returns "" if they stay within it.
+func exceededPipelineResources(max *dspav1alpha1.PipelineResources, running []corev1.Pod, pod *corev1.Pod) string {
+	total := podRequests(pod)
+	for i := range running {
+		if podFinished(&running[i]) {
+			continue
+		}
+		for name, quantity := range podRequests(&running[i]) {
+			sum := total[name]
+			sum.Add(quantity)
+			total[name] = sum
+		}
+	}
+
+	gpuResourceName := corev1.ResourceName(defaultGPUResourceName)
+	if max.GPUResourceName != "" {
+		gpuResourceName = corev1.ResourceName(max.GPUResourceName)
+	}
+	for _, limit := range []struct {
+		name corev1.ResourceName
+		max  *resource.Quantity
+	}{
+		{corev1.ResourceCPU, max.CPU},
+		{corev1.ResourceMemory, max.Memory},
+		{gpuResourceName, max.GPU},
+	} {
+		requested := total[limit.name]
+		if limit.max != nil && requested.Cmp(*limit.max) > 0 {
+			return fmt.Sprintf("the running pipeline pods would request %s of %s, over its maxPipelineResources of %s",
+				requested.String(), limit.name, limit.max.String())
+		}
+	}
+	return ""
+}
+
+// podFinished returns true if the pod no longer holds the resources it requests.
+func podFinished(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// podRequests returns the resources the scheduler reserves for the pod: the largest of the total requests of its
+// containers and the requests of each init container, which run one after the other, plus its overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			sum := requests[name]
+			sum.Add(quantity)
+			requests[name] = sum
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current := requests[name]; quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	for name, quantity := range pod.Spec.Overhead {
+		sum := requests[name]
+		sum.Add(quantity)
+		requests[name] = sum
+	}
+	return requests
+}
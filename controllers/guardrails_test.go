@@ -0,0 +1,136 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newGuardrailsTestDSPA(guardrails *dspav1alpha1.Guardrails) *dspav1alpha1.DataSciencePipelinesApplication {
+	return &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+			Guardrails:    guardrails,
+		},
+	}
+}
+
+func newGuardrailsTestPod(name, cpu string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testnamespace", Labels: map[string]string{pipelineRunLabel: "run"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "step",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				"nvidia.com/gpu":      resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}},
+		}}},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestDeployGuardrails(t *testing.T) {
+	expectedWebhookName := "ds-pipeline-guardrails-testnamespace.testdspa"
+	cpu := resource.MustParse("4")
+	dspa := newGuardrailsTestDSPA(&dspav1alpha1.Guardrails{MaxPipelineResources: &dspav1alpha1.PipelineResources{CPU: &cpu}})
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// Ensure the guardrails aren't deployed if the operator doesn't serve the webhook, which would reject every step pod
+	assert.ErrorContains(t, reconciler.ReconcileGuardrails(dspa, params), "requires the operator to serve its admission webhook")
+
+	// Ensure the step pods of the DSPA namespace are admitted by the operator
+	reconciler.WebhookService = &types.NamespacedName{Name: "dspo-service", Namespace: "dspo"}
+	assert.Nil(t, reconciler.ReconcileGuardrails(dspa, params))
+	webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedWebhookName}, webhook))
+	assert.Equal(t, "dspo-service", webhook.Webhooks[0].ClientConfig.Service.Name)
+	assert.Equal(t, "dspo", webhook.Webhooks[0].ClientConfig.Service.Namespace)
+	assert.Equal(t, GuardrailsWebhookPath, *webhook.Webhooks[0].ClientConfig.Service.Path)
+	assert.Equal(t, dspa.Namespace, webhook.Webhooks[0].NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"])
+	assert.Equal(t, pipelineRunLabel, webhook.Webhooks[0].ObjectSelector.MatchExpressions[0].Key)
+	assert.Equal(t, admissionregistrationv1.Fail, *webhook.Webhooks[0].FailurePolicy)
+
+	// Ensure the webhook is removed once the guardrails are, so step pods are no longer admitted by the operator
+	dspa.Spec.Guardrails = nil
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileGuardrails(dspa, params))
+	created, err := reconciler.IsResourceCreated(ctx, &admissionregistrationv1.ValidatingWebhookConfiguration{}, expectedWebhookName, "")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
+
+func TestGuardrailsWebhook(t *testing.T) {
+	cpu, gpu := resource.MustParse("4"), resource.MustParse("2")
+	dspa := newGuardrailsTestDSPA(&dspav1alpha1.Guardrails{MaxPipelineResources: &dspav1alpha1.PipelineResources{CPU: &cpu, GPU: &gpu}})
+	ctx, _, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	webhook := &GuardrailsWebhook{Client: reconciler.Client, Log: reconciler.Log}
+	admit := func(pod *corev1.Pod) admission.Response {
+		raw, err := json.Marshal(pod)
+		assert.Nil(t, err)
+		return webhook.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: pod.Namespace,
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+	}
+
+	// Ensure step pods are admitted while the running step pods stay within the maximum, finished ones don't count
+	assert.Nil(t, reconciler.Create(ctx, newGuardrailsTestPod("finished", "4", corev1.PodSucceeded)))
+	assert.True(t, admit(newGuardrailsTestPod("first", "2", corev1.PodPending)).Allowed)
+	assert.Nil(t, reconciler.Create(ctx, newGuardrailsTestPod("first", "2", corev1.PodRunning)))
+
+	// Ensure step pods over the maximum are rejected as exceeding a quota, for Tekton to retry them
+	response := admit(newGuardrailsTestPod("second", "2500m", corev1.PodPending))
+	assert.False(t, response.Allowed)
+	assert.Contains(t, string(response.Result.Reason), "exceeded quota: guardrails of DSPA [testdspa]")
+	assert.Contains(t, string(response.Result.Reason), "would request 4500m of cpu, over its maxPipelineResources of 4")
+
+	// Ensure every limited resource is checked, and the requests of init containers count while they run
+	assert.True(t, admit(newGuardrailsTestPod("second", "2", corev1.PodPending)).Allowed)
+	assert.Nil(t, reconciler.Create(ctx, newGuardrailsTestPod("second", "1", corev1.PodRunning)))
+	response = admit(newGuardrailsTestPod("third", "100m", corev1.PodPending))
+	assert.False(t, response.Allowed)
+	assert.Contains(t, string(response.Result.Reason), "would request 3 of nvidia.com/gpu, over its maxPipelineResources of 2")
+	pod := newGuardrailsTestPod("init", "0", corev1.PodPending)
+	pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{}
+	pod.Spec.InitContainers = []corev1.Container{{Name: "init", Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+	}}}
+	assert.False(t, admit(pod).Allowed)
+
+	// Ensure step pods of namespaces without guardrails are admitted
+	assert.True(t, admit(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unlimited", Namespace: "othernamespace"}}).Allowed)
+}
@@ -0,0 +1,101 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	appsv1 "k8s.io/api/apps/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const headroomDeploymentTemplate = "headroom/deployment.yaml.tmpl"
+
+// headroomPriorityClassTemplate is cluster scoped, so it can't be owned by the DSPA and is deleted explicitly
+const headroomPriorityClassTemplate = "headroom/priorityclass.yaml.tmpl"
+
+const headroomDefaultResourceNamePrefix = "ds-pipeline-headroom-"
+
+// SetupHeadroom populates the defaults of the placeholder pods.
+func (p *DSPAParams) SetupHeadroom() error {
+	if p.Headroom == nil {
+		return nil
+	}
+	if err := p.setImageDefault(config.HeadroomImagePath, &p.Headroom.Image); err != nil {
+		return err
+	}
+	setResourcesDefault(config.HeadroomResourceRequirements, &p.Headroom.Resources)
+	p.HeadroomPriority = p.Headroom.Priority
+
+	p.HeadroomNodeSelector = make(map[string]string)
+	for key, value := range p.NodeSelector {
+		p.HeadroomNodeSelector[key] = value
+	}
+	for key, value := range p.Headroom.NodeSelector {
+		p.HeadroomNodeSelector[key] = value
+	}
+	return nil
+}
+
+func (r *DSPAReconciler) ReconcileHeadroom(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	priorityClassName := fmt.Sprintf("ds-pipeline-headroom-%s.%s", dsp.Namespace, dsp.Name)
+
+	if !params.UsingHeadroom(dsp) {
+		log.Info("Skipping Application of Headroom Resources")
+		// Release the capacity held by the placeholder pods once disabled
+		err := r.DeleteResourceIfItExists(ctx, &appsv1.Deployment{}, types.NamespacedName{Name: params.HeadroomDefaultResourceName, Namespace: dsp.Namespace})
+		if err != nil {
+			return err
+		}
+		return r.DeleteResourceIfItExists(ctx, &schedulingv1.PriorityClass{}, types.NamespacedName{Name: priorityClassName})
+	}
+
+	log.Info("Applying Headroom Resources")
+
+	// The value of a PriorityClass is immutable, replace it when the priority changes. Running placeholder pods keep
+	// their priority until they're recreated.
+	priorityClass := &schedulingv1.PriorityClass{}
+	err := r.Get(ctx, types.NamespacedName{Name: priorityClassName}, priorityClass)
+	if err == nil && priorityClass.Value != params.HeadroomPriority {
+		log.Info(fmt.Sprintf("Replacing PriorityClass [%s], its priority changed from %d to %d", priorityClassName, priorityClass.Value, params.HeadroomPriority))
+		err = r.DeleteResource(params, headroomPriorityClassTemplate)
+		if err != nil {
+			return err
+		}
+	}
+	err = r.ApplyWithoutOwner(params, headroomPriorityClassTemplate)
+	if err != nil {
+		return err
+	}
+	err = r.Apply(dsp, params, headroomDeploymentTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Headroom Resources")
+	return nil
+}
+
+func (r *DSPAReconciler) CleanUpHeadroom(params *DSPAParams) error {
+	return r.DeleteResource(params, headroomPriorityClassTemplate)
+}
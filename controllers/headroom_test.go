@@ -0,0 +1,87 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDeployHeadroom(t *testing.T) {
+	expectedDeploymentName := "ds-pipeline-headroom-testdspa"
+	expectedPriorityClassName := "ds-pipeline-headroom-testnamespace.testdspa"
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+			Headroom: &dspav1alpha1.Headroom{
+				Deploy:       true,
+				Replicas:     3,
+				Priority:     -1,
+				NodeSelector: map[string]string{"node-pool": "pipelines"},
+			},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileHeadroom(ctx, dspa, params))
+
+	// Ensure the placeholder pods hold the default step capacity, on the steps' node pool, and are preemptible
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedDeploymentName, Namespace: dspa.Namespace}, deployment))
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	assert.NotContains(t, deployment.Labels, "dspa")
+	podSpec := deployment.Spec.Template.Spec
+	assert.Equal(t, expectedPriorityClassName, podSpec.PriorityClassName)
+	assert.Equal(t, "pipelines", podSpec.NodeSelector["node-pool"])
+	assert.Equal(t, "linux", podSpec.NodeSelector["kubernetes.io/os"])
+	assert.Equal(t, resource.MustParse("1"), podSpec.Containers[0].Resources.Requests[v1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("2Gi"), podSpec.Containers[0].Resources.Requests[v1.ResourceMemory])
+	assert.Equal(t, "true", deployment.Spec.Template.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"])
+	priorityClass := &schedulingv1.PriorityClass{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedPriorityClassName}, priorityClass))
+	assert.Equal(t, int32(-1), priorityClass.Value)
+	assert.Equal(t, v1.PreemptNever, *priorityClass.PreemptionPolicy)
+
+	// Ensure the PriorityClass is replaced when the priority changes, as its value is immutable
+	dspa.Spec.Headroom.Priority = -5
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileHeadroom(ctx, dspa, params))
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedPriorityClassName}, priorityClass))
+	assert.Equal(t, int32(-5), priorityClass.Value)
+
+	// Ensure the held capacity is released once disabled
+	dspa.Spec.Headroom.Deploy = false
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileHeadroom(ctx, dspa, params))
+	created, err := reconciler.IsResourceCreated(ctx, &appsv1.Deployment{}, expectedDeploymentName, dspa.Namespace)
+	assert.False(t, created)
+	assert.Nil(t, err)
+	created, err = reconciler.IsResourceCreated(ctx, &schedulingv1.PriorityClass{}, expectedPriorityClassName, "")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
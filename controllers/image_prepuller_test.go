@@ -0,0 +1,122 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+func TestDeployImagePrepuller(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedImagePrepullerName := imagePrepullerDefaultResourceNamePrefix + testDSPAName
+
+	// Construct DSPASpec with deployed Image Prepuller
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy:           true,
+				ArtifactImage:    "artifact-image",
+				CacheImage:       "step-image",
+				MoveResultsImage: "step-image",
+			},
+			ImagePrepuller: &dspav1alpha1.ImagePrepuller{
+				Deploy: true,
+				Images: []string{"training-image", "artifact-image"},
+			},
+			Database: &dspav1alpha1.Database{
+				DisableHealthCheck: false,
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				DisableHealthCheck: false,
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Namespace = testNamespace
+	dspa.Name = testDSPAName
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Ensure step images and configured images are prepulled once each
+	assert.Equal(t, []string{"artifact-image", "step-image", "training-image"}, params.ImagePrepullerImages)
+
+	// Ensure Image Prepuller DaemonSet doesn't yet exist
+	daemonSet := &appsv1.DaemonSet{}
+	created, err := reconciler.IsResourceCreated(ctx, daemonSet, expectedImagePrepullerName, testNamespace)
+	assert.False(t, created)
+	assert.Nil(t, err)
+
+	// Run test reconciliation
+	err = reconciler.ReconcileImagePrepuller(dspa, params)
+	assert.Nil(t, err)
+
+	// Ensure Image Prepuller DaemonSet now exists with an init container per image
+	daemonSet = &appsv1.DaemonSet{}
+	created, err = reconciler.IsResourceCreated(ctx, daemonSet, expectedImagePrepullerName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Len(t, daemonSet.Spec.Template.Spec.InitContainers, 3)
+}
+
+func TestDontDeployImagePrepuller(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedImagePrepullerName := imagePrepullerDefaultResourceNamePrefix + testDSPAName
+
+	// Construct DSPASpec with non-deployed Image Prepuller
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			ImagePrepuller: &dspav1alpha1.ImagePrepuller{
+				Deploy: false,
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Namespace = testNamespace
+	dspa.Name = testDSPAName
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+
+	// Run test reconciliation
+	err := reconciler.ReconcileImagePrepuller(dspa, params)
+	assert.Nil(t, err)
+
+	// Ensure Image Prepuller DaemonSet still doesn't exist
+	daemonSet := &appsv1.DaemonSet{}
+	created, err := reconciler.IsResourceCreated(ctx, daemonSet, expectedImagePrepullerName, testNamespace)
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
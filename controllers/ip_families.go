@@ -0,0 +1,71 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	ipv4ListenAddress = "0.0.0.0"
+	ipv6ListenAddress = "::"
+)
+
+// SetupIPFamilies sets the address components with a configurable listener bind to. Listening on IPv6 is opt-in, as
+// binding "::" fails on nodes with IPv6 disabled. With both families, IPv4 connections are accepted on the IPv6 socket.
+func (p *DSPAParams) SetupIPFamilies() {
+	p.ListenAddress = ipv4ListenAddress
+	p.ListenIPv4Compat = false
+	if p.IPFamilies == nil {
+		return
+	}
+	ipv4, ipv6 := false, false
+	for _, family := range p.IPFamilies.Families {
+		ipv4 = ipv4 || family == dspav1alpha1.IPFamily("IPv4")
+		ipv6 = ipv6 || family == dspav1alpha1.IPFamily("IPv6")
+	}
+	if ipv6 {
+		p.ListenAddress = ipv6ListenAddress
+		p.ListenIPv4Compat = ipv4 || (p.IPFamilies.Policy != "" && p.IPFamilies.Policy != "SingleStack")
+	}
+}
+
+// injectIPFamilies sets the IP family policy and families of every Service. Services keep the cluster's default
+// when they aren't configured.
+func injectIPFamilies(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Service" || params.IPFamilies == nil {
+			return nil
+		}
+		if params.IPFamilies.Policy != "" {
+			if err := unstructured.SetNestedField(u.Object, params.IPFamilies.Policy, "spec", "ipFamilyPolicy"); err != nil {
+				return err
+			}
+		}
+		if len(params.IPFamilies.Families) > 0 {
+			families := make([]interface{}, 0, len(params.IPFamilies.Families))
+			for _, family := range params.IPFamilies.Families {
+				families = append(families, string(family))
+			}
+			if err := unstructured.SetNestedSlice(u.Object, families, "spec", "ipFamilies"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newMLMDTestDSPA(families *dspav1alpha1.IPFamilies) *dspav1alpha1.DataSciencePipelinesApplication {
+	return &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{ArchiveLogs: true},
+			MLMD:          &dspav1alpha1.MLMD{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+			IPFamilies:    families,
+		},
+	}
+}
+
+func TestIPFamiliesDualStack(t *testing.T) {
+	dspa := newMLMDTestDSPA(&dspav1alpha1.IPFamilies{
+		Policy:   "PreferDualStack",
+		Families: []dspav1alpha1.IPFamily{"IPv6", "IPv4"},
+	})
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileMLMD(dspa, params))
+
+	// Assert Services are dual-stack, IPv6 first
+	service := &v1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-metadata-grpc-testdspa", Namespace: dspa.Namespace}, service))
+	assert.Equal(t, v1.IPFamilyPolicyPreferDualStack, *service.Spec.IPFamilyPolicy)
+	assert.Equal(t, []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol}, service.Spec.IPFamilies)
+
+	// Assert Envoy listens on both families
+	cm := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-metadata-envoy-config-testdspa", Namespace: dspa.Namespace}, cm))
+	assert.Contains(t, cm.Data["envoy.yaml"], `address: "::", port_value: 9090, ipv4_compat: true`)
+}
+
+func TestIPFamiliesDefault(t *testing.T) {
+	dspa := newMLMDTestDSPA(nil)
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileMLMD(dspa, params))
+
+	// Assert Services keep the cluster's default, and Envoy listens on IPv4
+	service := &v1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-metadata-grpc-testdspa", Namespace: dspa.Namespace}, service))
+	assert.Nil(t, service.Spec.IPFamilyPolicy)
+	assert.Empty(t, service.Spec.IPFamilies)
+	cm := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-metadata-envoy-config-testdspa", Namespace: dspa.Namespace}, cm))
+	assert.Contains(t, cm.Data["envoy.yaml"], `address: "0.0.0.0", port_value: 9090, ipv4_compat: false`)
+}
@@ -0,0 +1,259 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Labels of the Jobs of lifecycle hooks, with the phase of the hook and the upgrade it runs for
+const (
+	upgradeHookLabel = "datasciencepipelinesapplications.opendatahub.io/upgrade-hook"
+	upgradeLabel     = "datasciencepipelinesapplications.opendatahub.io/upgrade"
+	preUpgradeHook   = "pre-upgrade"
+	postUpgradeHook  = "post-upgrade"
+)
+
+// lifecycleHooksServiceAccountTemplate is the ServiceAccount every hook runs as, so the roles hooks are granted are
+// bound by the namespace admins rather than picked by whoever edits the DSPA
+const lifecycleHooksServiceAccountTemplate = "lifecycle-hooks/sa.yaml.tmpl"
+
+// upgradeID identifies the operator version and component images an upgrade moves the DSPA to.
+func upgradeID(images map[string]string) string {
+	var components []string
+	for component, image := range images {
+		components = append(components, component+"="+image)
+	}
+	sort.Strings(components)
+	hash := sha256.Sum256([]byte(config.OperatorVersion + "\n" + strings.Join(components, "\n")))
+	return hex.EncodeToString(hash[:])[:10]
+}
+
+// componentUpgradePending returns true if the components of the DSPA were deployed by another operator version or
+// with other images than the running operator would apply.
+func (r *DSPAReconciler) componentUpgradePending(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) (bool, error) {
+
+	manifest := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: config.DerivedName("ds-pipeline-version-manifest-", dsp.Name), Namespace: dsp.Namespace}, manifest)
+	if apierrs.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return manifest.Data["operatorVersion"] != config.OperatorVersion || len(imageChanges(manifest, params.componentImages(dsp))) > 0, nil
+}
+
+func (r *DSPAReconciler) newUpgradeHookJob(dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams,
+	hook dspav1alpha1.LifecycleHook, phase, upgrade string) (*batchv1.Job, error) {
+
+	activeDeadlineSeconds := hook.ActiveDeadlineSeconds
+	if activeDeadlineSeconds == 0 {
+		activeDeadlineSeconds = config.DefaultUpgradeHookActiveDeadlineSeconds
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.DerivedName(fmt.Sprintf("ds-pipeline-%s-%s-%s-", phase, hook.Name, upgrade), dsp.Name),
+			Namespace: dsp.Namespace,
+			Labels: map[string]string{
+				"dspa":           dsp.Name,
+				upgradeHookLabel: phase,
+				upgradeLabel:     upgrade,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			Suspend:               util.BoolPointer(phase == postUpgradeHook),
+			BackoffLimit:          new(int32),
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: params.LifecycleHooksServiceAccountName,
+					NodeSelector:       params.NodeSelector,
+					Containers: []corev1.Container{{
+						Name:    "hook",
+						Image:   hook.Image,
+						Command: hook.Command,
+					}},
+				},
+			},
+		},
+	}
+	return job, controllerutil.SetControllerReference(dsp, job, r.Scheme)
+}
+
+func jobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconcileUpgradeHooks runs the pre-upgrade hooks of a pending component upgrade, and holds the components back with
+// params.PreUpgradeHooks until all of them succeeded. The post-upgrade hooks are then created suspended, to be started
+// by StartPostUpgradeHooks, and their failures reported in params.PostUpgradeHooks. The Jobs of earlier upgrades are
+// deleted.
+func (r *DSPAReconciler) ReconcileUpgradeHooks(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	params.LifecycleHooksServiceAccountName = config.DerivedName("ds-pipeline-lifecycle-hooks-", dsp.Name)
+	hooks := dsp.Spec.LifecycleHooks
+	if hooks == nil {
+		return r.DeleteResource(params, lifecycleHooksServiceAccountTemplate)
+	}
+	if err := r.Apply(dsp, params, lifecycleHooksServiceAccountTemplate); err != nil {
+		return err
+	}
+	upgrade := upgradeID(params.componentImages(dsp))
+
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(dsp.Namespace), client.MatchingLabels{"dspa": dsp.Name}); err != nil {
+		return err
+	}
+	existing := make(map[string]*batchv1.Job)
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if _, ok := job.Labels[upgradeHookLabel]; !ok {
+			continue
+		}
+		if job.Labels[upgradeLabel] == upgrade {
+			existing[job.Name] = job
+			continue
+		}
+		log.V(1).Info(fmt.Sprintf("Deleting Job [%s] of an earlier upgrade", job.Name))
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	pending, err := r.componentUpgradePending(ctx, dsp, params)
+	if err != nil {
+		return err
+	}
+	if !pending {
+		for _, hook := range hooks.PostUpgrade {
+			job, err := r.newUpgradeHookJob(dsp, params, hook, postUpgradeHook, upgrade)
+			if err != nil {
+				return err
+			}
+			if deployed, ok := existing[job.Name]; ok && jobFailed(deployed) {
+				params.PostUpgradeHooks = &Diagnosis{Reason: config.UpgradeHookFailed,
+					Message: fmt.Sprintf("Post-upgrade hook [%s] failed, see Job [%s]", hook.Name, job.Name)}
+			}
+		}
+		return nil
+	}
+
+	var running []string
+	for _, hook := range hooks.PreUpgrade {
+		job, err := r.newUpgradeHookJob(dsp, params, hook, preUpgradeHook, upgrade)
+		if err != nil {
+			return err
+		}
+		deployed, ok := existing[job.Name]
+		switch {
+		case !ok:
+			log.Info(fmt.Sprintf("Running pre-upgrade hook [%s]", hook.Name))
+			if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+				return err
+			}
+			running = append(running, hook.Name)
+		case jobFailed(deployed):
+			params.PreUpgradeHooks = &Diagnosis{Reason: config.UpgradeHookFailed,
+				Message: fmt.Sprintf("Upgrade is held back as pre-upgrade hook [%s] failed, delete Job [%s] to retry it", hook.Name, job.Name)}
+			return nil
+		case deployed.Status.Succeeded == 0:
+			running = append(running, hook.Name)
+		}
+	}
+	if len(running) > 0 {
+		params.PreUpgradeHooks = &Diagnosis{Reason: config.AwaitingUpgradeHooks,
+			Message: fmt.Sprintf("Upgrade is held back until pre-upgrade hooks [%s] succeed", strings.Join(running, ", "))}
+		return nil
+	}
+
+	for _, hook := range hooks.PostUpgrade {
+		job, err := r.newUpgradeHookJob(dsp, params, hook, postUpgradeHook, upgrade)
+		if err != nil {
+			return err
+		}
+		if _, ok := existing[job.Name]; ok {
+			continue
+		}
+		if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartPostUpgradeHooks starts the suspended post-upgrade hooks of the last upgrade once its components rolled out.
+// The Ready condition isn't enough: it's still true while the pods of the previous version serve, right after the
+// upgrade is applied.
+func (r *DSPAReconciler) StartPostUpgradeHooks(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	if dsp.Spec.LifecycleHooks == nil || len(dsp.Spec.LifecycleHooks.PostUpgrade) == 0 {
+		return nil
+	}
+	rolledOut, err := r.componentsRolledOut(ctx, dsp)
+	if err != nil || !rolledOut {
+		return err
+	}
+
+	jobs := &batchv1.JobList{}
+	err = r.List(ctx, jobs, client.InNamespace(dsp.Namespace), client.MatchingLabels{
+		"dspa":           dsp.Name,
+		upgradeHookLabel: postUpgradeHook,
+		upgradeLabel:     upgradeID(params.componentImages(dsp)),
+	})
+	if err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if job.Spec.Suspend == nil || !*job.Spec.Suspend {
+			continue
+		}
+		log.Info(fmt.Sprintf("Running post-upgrade hook Job [%s]", job.Name))
+		patch := client.MergeFrom(job.DeepCopy())
+		job.Spec.Suspend = util.BoolPointer(false)
+		if err := r.Patch(ctx, job, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
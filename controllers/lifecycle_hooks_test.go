@@ -0,0 +1,133 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileUpgradeHooks(t *testing.T) {
+	defaultOperatorVersion := config.OperatorVersion
+	config.OperatorVersion = "v1.1.0"
+	t.Cleanup(func() { config.OperatorVersion = defaultOperatorVersion })
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace", UID: "testuid"},
+		Spec: dspav1alpha1.DSPASpec{
+			LifecycleHooks: &dspav1alpha1.LifecycleHooks{
+				PreUpgrade:  []dspav1alpha1.LifecycleHook{{Name: "backup", Image: "backup:latest", Command: []string{"backup.sh"}}},
+				PostUpgrade: []dspav1alpha1.LifecycleHook{{Name: "smoke-test", Image: "smoke-test:latest"}},
+			},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	params.Name, params.Namespace = dspa.Name, dspa.Namespace
+	params.APIServer = &dspav1alpha1.APIServer{Deploy: true, Image: "api-server:v1.1.0"}
+	listJobs := func(phase string) []batchv1.Job {
+		jobs := &batchv1.JobList{}
+		assert.Nil(t, reconciler.List(ctx, jobs, client.MatchingLabels{upgradeHookLabel: phase}))
+		return jobs.Items
+	}
+
+	// Ensure no hook runs for DSPAs that haven't been deployed yet
+	assert.Nil(t, reconciler.ReconcileUpgradeHooks(ctx, dspa, params))
+	assert.Nil(t, params.PreUpgradeHooks)
+	assert.Empty(t, listJobs(preUpgradeHook))
+
+	// Ensure an upgrade is held back until its pre-upgrade hooks succeed
+	manifest := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-version-manifest-testdspa", Namespace: "testnamespace"},
+		Data:       map[string]string{"operatorVersion": "v1.0.0", "apiServer.image": "api-server:v1.0.0"},
+	}
+	assert.Nil(t, reconciler.Create(ctx, manifest))
+	assert.Nil(t, reconciler.ReconcileUpgradeHooks(ctx, dspa, params))
+	assert.Equal(t, config.AwaitingUpgradeHooks, params.PreUpgradeHooks.Reason)
+	preJobs := listJobs(preUpgradeHook)
+	assert.Len(t, preJobs, 1)
+	assert.Equal(t, "backup:latest", preJobs[0].Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(0), *preJobs[0].Spec.BackoffLimit)
+	assert.Equal(t, int64(600), *preJobs[0].Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, "ds-pipeline-lifecycle-hooks-testdspa", preJobs[0].Spec.Template.Spec.ServiceAccountName)
+	created, err := reconciler.IsResourceCreated(ctx, &corev1.ServiceAccount{}, "ds-pipeline-lifecycle-hooks-testdspa", "testnamespace")
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Empty(t, listJobs(postUpgradeHook))
+
+	// Ensure a failed pre-upgrade hook keeps holding the upgrade back
+	preJobs[0].Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	assert.Nil(t, reconciler.Status().Update(ctx, &preJobs[0]))
+	params.PreUpgradeHooks = nil
+	assert.Nil(t, reconciler.ReconcileUpgradeHooks(ctx, dspa, params))
+	assert.Equal(t, config.UpgradeHookFailed, params.PreUpgradeHooks.Reason)
+	assert.Contains(t, params.PreUpgradeHooks.Message, preJobs[0].Name)
+
+	// Ensure the post-upgrade hooks are created suspended once the pre-upgrade hooks succeeded
+	preJobs[0].Status = batchv1.JobStatus{Succeeded: 1}
+	assert.Nil(t, reconciler.Status().Update(ctx, &preJobs[0]))
+	params.PreUpgradeHooks = nil
+	assert.Nil(t, reconciler.ReconcileUpgradeHooks(ctx, dspa, params))
+	assert.Nil(t, params.PreUpgradeHooks)
+	postJobs := listJobs(postUpgradeHook)
+	assert.Len(t, postJobs, 1)
+	assert.True(t, *postJobs[0].Spec.Suspend)
+
+	// Ensure the post-upgrade hooks start once the upgraded components rolled out
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ds-pipeline-testdspa",
+			Namespace: "testnamespace",
+			Labels:    map[string]string{"component": "data-science-pipelines", "dspa": "testdspa"},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 2},
+	}
+	assert.Nil(t, reconciler.Create(ctx, deployment))
+	assert.Nil(t, reconciler.StartPostUpgradeHooks(ctx, dspa, params))
+	assert.True(t, *listJobs(postUpgradeHook)[0].Spec.Suspend)
+	deployment.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	assert.Nil(t, reconciler.Status().Update(ctx, deployment))
+	assert.Nil(t, reconciler.StartPostUpgradeHooks(ctx, dspa, params))
+	assert.False(t, *listJobs(postUpgradeHook)[0].Spec.Suspend)
+
+	// Ensure failed post-upgrade hooks are reported once the upgrade is applied
+	manifest.Data = map[string]string{"operatorVersion": "v1.1.0", "apiServer.image": "api-server:v1.1.0"}
+	assert.Nil(t, reconciler.Update(ctx, manifest))
+	postJobs = listJobs(postUpgradeHook)
+	postJobs[0].Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	assert.Nil(t, reconciler.Status().Update(ctx, &postJobs[0]))
+	assert.Nil(t, reconciler.ReconcileUpgradeHooks(ctx, dspa, params))
+	assert.Nil(t, params.PreUpgradeHooks)
+	assert.Equal(t, config.UpgradeHookFailed, params.PostUpgradeHooks.Reason)
+
+	// Ensure the hooks of an earlier upgrade are deleted on the next one
+	params.APIServer.Image = "api-server:v1.2.0"
+	params.PostUpgradeHooks = nil
+	assert.Nil(t, reconciler.ReconcileUpgradeHooks(ctx, dspa, params))
+	preJobs = listJobs(preUpgradeHook)
+	assert.Len(t, preJobs, 1)
+	assert.Equal(t, upgradeID(params.componentImages(dspa)), preJobs[0].Labels[upgradeLabel])
+	assert.Empty(t, listJobs(postUpgradeHook))
+}
@@ -0,0 +1,220 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// kustomization is the subset of a kustomization file the manifest patches of a DSPA are read from
+type kustomization struct {
+	APIVersion string               `json:"apiVersion,omitempty"`
+	Kind       string               `json:"kind,omitempty"`
+	Patches    []kustomizationPatch `json:"patches,omitempty"`
+}
+
+type kustomizationPatch struct {
+	Target *kustomizationPatchTarget `json:"target,omitempty"`
+	Patch  string                    `json:"patch"`
+}
+
+type kustomizationPatchTarget struct {
+	Group         string `json:"group,omitempty"`
+	Version       string `json:"version,omitempty"`
+	Kind          string `json:"kind,omitempty"`
+	Name          string `json:"name,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// ManifestPatch is a patch of the manifests of a DSPA, either a JSON 6902 patch or a strategic merge patch, and the
+// manifests it applies to.
+type ManifestPatch struct {
+	group, version, kind string
+	name                 *regexp.Regexp
+	selector             labels.Selector
+	jsonPatch            jsonpatch.Patch
+	mergePatch           []byte
+}
+
+// unpatchableKinds are the kinds the manifest patches can't change: patching the RBAC of the components would let
+// whoever edits the DSPA grant its service accounts any permission the operator holds.
+var unpatchableKinds = map[string]bool{
+	"Role":               true,
+	"RoleBinding":        true,
+	"ClusterRole":        true,
+	"ClusterRoleBinding": true,
+}
+
+func (m *ManifestPatch) matches(u *unstructured.Unstructured) bool {
+	gvk := u.GroupVersionKind()
+	if gvk.Group == "rbac.authorization.k8s.io" && unpatchableKinds[gvk.Kind] {
+		return false
+	}
+	return (m.group == "" || m.group == gvk.Group) &&
+		(m.version == "" || m.version == gvk.Version) &&
+		(m.kind == "" || m.kind == gvk.Kind) &&
+		(m.name == nil || m.name.MatchString(u.GetName())) &&
+		(m.selector == nil || m.selector.Matches(labels.Set(u.GetLabels())))
+}
+
+// SetupManifestPatches reads the kustomize patches of the DSPA from its manifestPatches ConfigMap. As for kustomize,
+// a patch is a JSON 6902 patch if it's a list of operations, and a strategic merge patch otherwise, and target names
+// are regular expressions. Strategic merge patches without target apply to the manifest of their kind and name.
+func (p *DSPAParams) SetupManifestPatches(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, client client.Client) error {
+	p.ManifestPatches = nil
+	if dsp.Spec.ManifestPatches == nil {
+		return nil
+	}
+	ref := dsp.Spec.ManifestPatches
+	configMap := &corev1.ConfigMap{}
+	err := client.Get(ctx, types.NamespacedName{Name: ref.ConfigMapName, Namespace: dsp.Namespace}, configMap)
+	if err != nil {
+		return fmt.Errorf("unable to read the manifest patches from configmap [%s]: %w", ref.ConfigMapName, err)
+	}
+	key := ref.ConfigMapKey
+	if key == "" {
+		key = "kustomization.yaml"
+	}
+	content, ok := configMap.Data[key]
+	if !ok {
+		return fmt.Errorf("configmap [%s] of the manifest patches has no key [%s]", ref.ConfigMapName, key)
+	}
+	k := &kustomization{}
+	if err := yaml.UnmarshalStrict([]byte(content), k); err != nil {
+		return fmt.Errorf("invalid manifest patches in configmap [%s], only the patches of a kustomization are supported: %w", ref.ConfigMapName, err)
+	}
+
+	for i, patch := range k.Patches {
+		manifestPatch, err := newManifestPatch(patch)
+		if err != nil {
+			return fmt.Errorf("invalid manifest patch %d in configmap [%s]: %w", i, ref.ConfigMapName, err)
+		}
+		p.ManifestPatches = append(p.ManifestPatches, manifestPatch)
+	}
+	return nil
+}
+
+func newManifestPatch(patch kustomizationPatch) (*ManifestPatch, error) {
+	raw, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return nil, err
+	}
+	m := &ManifestPatch{}
+	var operations []interface{}
+	if json.Unmarshal(raw, &operations) == nil {
+		m.jsonPatch, err = jsonpatch.DecodePatch(raw)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		object := &unstructured.Unstructured{}
+		if err := json.Unmarshal(raw, &object.Object); err != nil {
+			return nil, fmt.Errorf("patch is neither a JSON 6902 patch nor a strategic merge patch: %w", err)
+		}
+		if patch.Target == nil {
+			if object.GetKind() == "" || object.GetName() == "" {
+				return nil, fmt.Errorf("strategic merge patches without target require a kind and a metadata.name")
+			}
+			gvk := object.GroupVersionKind()
+			m.group, m.version, m.kind = gvk.Group, gvk.Version, gvk.Kind
+			m.name = regexp.MustCompile("^" + regexp.QuoteMeta(object.GetName()) + "$")
+		}
+		// The patch identifies the manifests, it doesn't rename them
+		unstructured.RemoveNestedField(object.Object, "apiVersion")
+		unstructured.RemoveNestedField(object.Object, "kind")
+		unstructured.RemoveNestedField(object.Object, "metadata", "name")
+		unstructured.RemoveNestedField(object.Object, "metadata", "namespace")
+		m.mergePatch, err = json.Marshal(object.Object)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if target := patch.Target; target != nil {
+		m.group, m.version, m.kind = target.Group, target.Version, target.Kind
+		if target.Name != "" {
+			m.name, err = regexp.Compile("^(?:" + target.Name + ")$")
+			if err != nil {
+				return nil, err
+			}
+		}
+		if target.LabelSelector != "" {
+			m.selector, err = labels.Parse(target.LabelSelector)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if m.jsonPatch != nil {
+		return nil, fmt.Errorf("JSON 6902 patches require a target")
+	}
+	if unpatchableKinds[m.kind] && (m.group == "" || m.group == "rbac.authorization.k8s.io") {
+		return nil, fmt.Errorf("%s manifests can't be patched", m.kind)
+	}
+	return m, nil
+}
+
+// injectManifestPatches applies the manifest patches of the DSPA to the rendered manifests they target. Strategic
+// merge patches of kinds unknown to the operator, e.g. Routes, are applied as JSON merge patches.
+func injectManifestPatches(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		for _, patch := range params.ManifestPatches {
+			if !patch.matches(u) {
+				continue
+			}
+			original, err := u.MarshalJSON()
+			if err != nil {
+				return err
+			}
+			var patched []byte
+			if patch.jsonPatch != nil {
+				patched, err = patch.jsonPatch.Apply(original)
+			} else if typed, schemeErr := scheme.Scheme.New(u.GroupVersionKind()); schemeErr == nil {
+				patched, err = strategicpatch.StrategicMergePatch(original, patch.mergePatch, typed)
+			} else {
+				patched, err = jsonpatch.MergePatch(original, patch.mergePatch)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to patch %s [%s]: %w", u.GetKind(), u.GetName(), err)
+			}
+			gvk, name, namespace, owners := u.GroupVersionKind(), u.GetName(), u.GetNamespace(), u.GetOwnerReferences()
+			if err := u.UnmarshalJSON(patched); err != nil {
+				return err
+			}
+			// The operator tracks, updates and garbage collects the manifests by their identity and owner
+			if u.GroupVersionKind() != gvk || u.GetName() != name || u.GetNamespace() != namespace ||
+				!reflect.DeepEqual(u.GetOwnerReferences(), owners) {
+				return fmt.Errorf("unable to patch %s [%s]: patches can't change the apiVersion, kind, name, namespace or ownerReferences of a manifest", gvk.Kind, name)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testManifestPatches = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+patches:
+  - target:
+      kind: Deployment
+      name: ds-pipeline-(persistenceagent|scheduledworkflow)-testdspa
+    patch: |-
+      - op: add
+        path: /spec/template/metadata/labels/team
+        value: a
+  - patch: |-
+      apiVersion: apps/v1
+      kind: Deployment
+      metadata:
+        name: ds-pipeline-persistenceagent-testdspa
+      spec:
+        template:
+          spec:
+            containers:
+              - name: ds-pipeline-persistenceagent
+                env:
+                  - name: LOG_LEVEL
+                    value: debug
+  - target:
+      kind: Route
+    patch: |-
+      apiVersion: route.openshift.io/v1
+      kind: Route
+      metadata:
+        name: any
+        annotations:
+          haproxy.router.openshift.io/timeout: 5m
+`
+
+func TestManifestPatches(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			ManifestPatches: &dspav1alpha1.ManifestPatches{ConfigMapName: "manifest-patches"},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "manifest-patches", Namespace: "testnamespace"},
+		Data:       map[string]string{"kustomization.yaml": testManifestPatches},
+	}
+	assert.Nil(t, reconciler.Create(ctx, configMap))
+	assert.Nil(t, params.SetupManifestPatches(ctx, dspa, reconciler.Client))
+	assert.Len(t, params.ManifestPatches, 3)
+	transform := injectManifestPatches(params)
+
+	newDeployment := func(name, container string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec": map[string]interface{}{"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": name}},
+				"spec": map[string]interface{}{"containers": []interface{}{
+					map[string]interface{}{"name": "oauth-proxy", "image": "oauth-proxy"},
+					map[string]interface{}{"name": container, "image": container, "env": []interface{}{
+						map[string]interface{}{"name": "NAMESPACE", "value": "testnamespace"},
+					}},
+				}},
+			}},
+		}}
+	}
+
+	// Ensure JSON 6902 patches apply to the manifests their target matches, and strategic merge patches merge lists
+	persistenceAgent := newDeployment("ds-pipeline-persistenceagent-testdspa", "ds-pipeline-persistenceagent")
+	assert.Nil(t, transform(persistenceAgent))
+	labels, _, _ := unstructured.NestedStringMap(persistenceAgent.Object, "spec", "template", "metadata", "labels")
+	assert.Equal(t, map[string]string{"app": "ds-pipeline-persistenceagent-testdspa", "team": "a"}, labels)
+	containers, _, _ := unstructured.NestedSlice(persistenceAgent.Object, "spec", "template", "spec", "containers")
+	assert.Len(t, containers, 2)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+		map[string]interface{}{"name": "NAMESPACE", "value": "testnamespace"},
+	}, containers[1].(map[string]interface{})["env"])
+
+	scheduledWorkflow := newDeployment("ds-pipeline-scheduledworkflow-testdspa", "ds-pipeline-scheduledworkflow")
+	assert.Nil(t, transform(scheduledWorkflow))
+	labels, _, _ = unstructured.NestedStringMap(scheduledWorkflow.Object, "spec", "template", "metadata", "labels")
+	assert.Equal(t, "a", labels["team"])
+	containers, _, _ = unstructured.NestedSlice(scheduledWorkflow.Object, "spec", "template", "spec", "containers")
+	assert.Len(t, containers[1].(map[string]interface{})["env"], 1)
+
+	apiServer := newDeployment("ds-pipeline-testdspa", "ds-pipeline-api-server")
+	want := apiServer.DeepCopy()
+	assert.Nil(t, transform(apiServer))
+	assert.Equal(t, want, apiServer)
+
+	// Ensure merge patches of kinds without a strategy keep the name of the manifests
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "route.openshift.io/v1",
+		"kind":       "Route",
+		"metadata":   map[string]interface{}{"name": "ds-pipeline-testdspa"},
+	}}
+	assert.Nil(t, transform(route))
+	assert.Equal(t, "ds-pipeline-testdspa", route.GetName())
+	assert.Equal(t, map[string]string{"haproxy.router.openshift.io/timeout": "5m"}, route.GetAnnotations())
+
+	// Ensure patches can't rename, move or disown manifests, nor change RBAC manifests
+	params.ManifestPatches = nil
+	for _, patch := range []string{
+		"[{op: replace, path: /metadata/name, value: other}]",
+		"[{op: add, path: /metadata/namespace, value: other}]",
+		"[{op: replace, path: /kind, value: StatefulSet}]",
+		"[{op: add, path: /metadata/ownerReferences, value: [{apiVersion: v1, kind: ConfigMap, name: other, uid: otheruid}]}]",
+	} {
+		manifestPatch, err := newManifestPatch(kustomizationPatch{Target: &kustomizationPatchTarget{Kind: "Deployment"}, Patch: patch})
+		assert.Nil(t, err)
+		params.ManifestPatches = []*ManifestPatch{manifestPatch}
+		assert.ErrorContains(t, injectManifestPatches(params)(apiServer.DeepCopy()), "patches can't change", patch)
+	}
+	manifestPatch, err := newManifestPatch(kustomizationPatch{Target: &kustomizationPatchTarget{Name: ".*"}, Patch: "[{op: add, path: /metadata/labels, value: {team: a}}]"})
+	assert.Nil(t, err)
+	params.ManifestPatches = []*ManifestPatch{manifestPatch}
+	role := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "Role",
+		"metadata":   map[string]interface{}{"name": "ds-pipeline-testdspa"},
+	}}
+	want = role.DeepCopy()
+	assert.Nil(t, injectManifestPatches(params)(role))
+	assert.Equal(t, want, role)
+
+	// Ensure unsupported kustomizations and invalid patches are rejected
+	for _, invalid := range []string{
+		"resources: [deployment.yaml]",
+		"patches: [{patch: '[{op: add, path: /metadata/labels/team, value: a}]'}]",
+		"patches: [{target: {name: '('}, patch: '[{op: add, path: /metadata/labels/team, value: a}]'}]",
+		"patches: [{patch: 'spec: {replicas: 2}'}]",
+		"patches: [{target: {kind: RoleBinding}, patch: '[{op: add, path: /subjects/-, value: {kind: User, name: someone}}]'}]",
+	} {
+		configMap.Data["kustomization.yaml"] = invalid
+		assert.Nil(t, reconciler.Update(ctx, configMap))
+		assert.NotNil(t, params.SetupManifestPatches(ctx, dspa, reconciler.Client), invalid)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	mariadbExporterPrefix      = "ds-pipeline-mariadb-exporter-"
+	mariadbExporterPasswordKey = "password"
+)
+
+// mariadbMetricsTemplates are applied before the MariaDB Deployment, whose pod reads the exporter Secret and grants
+var mariadbMetricsTemplates = []string{
+	"mariadb/exporter-secret.yaml.tmpl",
+	"mariadb/exporter-grants.yaml.tmpl",
+	"mariadb/monitor.yaml.tmpl",
+}
+
+var serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+// MariaDBExporter is the mysqld-exporter sidecar of the MariaDB deployed by a DSPA, and the MariaDB account it
+// scrapes the server with
+type MariaDBExporter struct {
+	Image     string
+	Resources *dspav1alpha1.ResourceRequirements
+	Username  string
+	// Base64 encoded password of the account, kept in the exporter Secret across reconciles
+	Password string
+}
+
+// SetupMariaDBMetrics populates the mysqld-exporter sidecar of the MariaDB deployed by the DSPA. It must run after
+// SetupDBParams.
+func (p *DSPAParams) SetupMariaDBMetrics(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, client client.Client, log logr.Logger) error {
+	p.MariaDBExporter = nil
+	if !p.UsingMariaDB(dsp) || p.MariaDB.Metrics == nil || !p.MariaDB.Metrics.Enabled {
+		return nil
+	}
+	exporter := &MariaDBExporter{
+		Image:     p.MariaDB.Metrics.Image,
+		Resources: p.MariaDB.Metrics.Resources,
+		Username:  config.MariaDBExporterUser,
+	}
+	if err := p.setImageDefault(config.MariaDBExporterImagePath, &exporter.Image); err != nil {
+		return err
+	}
+	setResourcesDefault(config.MariaDBExporterResourceRequirements, &exporter.Resources)
+
+	password, err := p.RetrieveOrCreateSecret(ctx, client, config.DerivedName(mariadbExporterPrefix, p.Name),
+		mariadbExporterPasswordKey, config.GeneratedDBPasswordLength, log)
+	if err != nil {
+		return err
+	}
+	exporter.Password = password
+	p.MariaDBExporter = exporter
+	return nil
+}
+
+// reconcileMariaDBMetrics applies the Secret and grants of the mysqld-exporter account, and the ServiceMonitor
+// scraping the sidecar, and deletes them once metrics are disabled. The grants are a start script of the MariaDB
+// image, run by the server on every start, so the account is created without DSPO connecting to the database as root.
+func (r *DSPAReconciler) reconcileMariaDBMetrics(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	if params.MariaDBExporter == nil {
+		nn := types.NamespacedName{Name: config.DerivedName(mariadbExporterPrefix, dsp.Name), Namespace: dsp.Namespace}
+		if err := r.DeleteResourceIfItExists(ctx, &corev1.Secret{}, nn); err != nil {
+			return err
+		}
+		if err := r.DeleteResourceIfItExists(ctx, &corev1.ConfigMap{}, nn); err != nil {
+			return err
+		}
+		// Clusters without the Prometheus Operator have no ServiceMonitor to delete
+		monitor := &unstructured.Unstructured{}
+		monitor.SetGroupVersionKind(serviceMonitorGVK)
+		nn.Name = config.DerivedName(config.MariaDBHostPrefix+"-", dsp.Name)
+		if err := r.DeleteResourceIfItExists(ctx, monitor, nn); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+		return nil
+	}
+
+	log.Info("Applying mariaDB metrics resources.")
+	for _, template := range mariadbMetricsTemplates {
+		err := r.Apply(dsp, params, template)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
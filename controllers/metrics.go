@@ -17,8 +17,14 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"sync/atomic"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // Prometheus metrics gauges
@@ -83,6 +89,145 @@ var (
 			"dspa_namespace",
 		},
 	)
+	UnreportedRunsMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "data_science_pipelines_application_unreported_runs",
+			Help: "Data Science Pipelines Application - Number of finished runs whose final status is not yet reported to the APIServer",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+		},
+	)
+	RunReportLagMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "data_science_pipelines_application_run_report_lag_seconds",
+			Help: "Data Science Pipelines Application - Seconds since the oldest unreported run finished",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+		},
+	)
+	QueuedRunsMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "data_science_pipelines_application_queued_runs",
+			Help: "Data Science Pipelines Application - Number of unfinished runs none of whose steps started yet",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+		},
+	)
+	PendingStepsMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "data_science_pipelines_application_pending_steps",
+			Help: "Data Science Pipelines Application - Number of unfinished steps whose pod is not running yet, by reason (Pending, ExceededResourceQuota, ExceededNodeResources)",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+			"reason",
+		},
+	)
+	QueueWaitMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "data_science_pipelines_application_queue_wait_seconds",
+			Help: "Data Science Pipelines Application - Average seconds runs started within the last hour waited for their first step to start",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+		},
+	)
+	ComponentStartupMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "data_science_pipelines_application_component_startup_seconds",
+			Help: "Data Science Pipelines Application - Seconds the most recently started pod of a component took to be scheduled and to become ready, by phase (scheduled, ready)",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+			"component",
+			"phase",
+		},
+	)
+	StepsFinishedMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "data_science_pipelines_application_steps_finished_total",
+			Help: "Data Science Pipelines Application - Number of pipeline steps handled by the step exit handler, by status and reason",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+			"status",
+			"reason",
+		},
+	)
+)
+
+// ReconcileDurationMetric is only registered with detailed metrics, as it adds a series per DSPA and result
+var ReconcileDurationMetric = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "data_science_pipelines_application_reconcile_duration_seconds",
+		Help:    "Data Science Pipelines Application - Duration of the reconciles, by result (success, error, requeue)",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+	},
+	[]string{
+		"dspa_name",
+		"dspa_namespace",
+		"result",
+	},
+)
+
+// The API metrics are only registered with detailed metrics, they count the Kubernetes API requests the operator
+// issues while reconciling each DSPA, see instrumentAPIClient
+var (
+	APIRequestsMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "data_science_pipelines_application_api_requests_total",
+			Help: "Data Science Pipelines Application - Kubernetes API requests issued while reconciling the DSPA, by verb and code, conflicts being code 409",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+			"verb",
+			"code",
+		},
+	)
+	ReconcileAPIRequestsMetric = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "data_science_pipelines_application_reconcile_api_requests",
+			Help:    "Data Science Pipelines Application - Kubernetes API requests issued per reconcile",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+		},
+	)
+	APIThrottleMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "data_science_pipelines_application_api_throttle_seconds_total",
+			Help: "Data Science Pipelines Application - Seconds the Kubernetes API requests issued while reconciling the DSPA waited on the client rate limiter",
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+		},
+	)
+	ApplyDurationMetric = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "data_science_pipelines_application_apply_duration_seconds",
+			Help:    "Data Science Pipelines Application - Duration of the apply of the manifests of each template",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+		},
+		[]string{
+			"dspa_name",
+			"dspa_namespace",
+			"template",
+		},
+	)
 )
 
 // InitMetrics initialize prometheus metrics
@@ -92,5 +237,50 @@ func InitMetrics() {
 		APIServerReadyMetric,
 		PersistenceAgentReadyMetric,
 		ScheduledWorkflowReadyMetric,
-		CrReadyMetric)
+		CrReadyMetric,
+		UnreportedRunsMetric,
+		RunReportLagMetric,
+		QueuedRunsMetric,
+		PendingStepsMetric,
+		QueueWaitMetric,
+		ComponentStartupMetric,
+		StepsFinishedMetric)
+}
+
+// InitDetailedMetrics initialize the prometheus metrics used to debug reconcile storms
+func InitDetailedMetrics() {
+	metrics.Registry.MustRegister(ReconcileDurationMetric,
+		APIRequestsMetric,
+		ReconcileAPIRequestsMetric,
+		APIThrottleMetric,
+		ApplyDurationMetric)
+	instrumentAPIClient()
+}
+
+// detailedMetricsReconciler times the reconciles of the DSPAReconciler it wraps, and counts their API requests
+type detailedMetricsReconciler struct {
+	*DSPAReconciler
+}
+
+func (r *DSPAReconciler) instrumented() reconcile.Reconciler {
+	if !r.DetailedMetrics {
+		return r
+	}
+	return &detailedMetricsReconciler{r}
+}
+
+func (r *detailedMetricsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	start := time.Now()
+	stats := &APIStats{name: req.Name, namespace: req.Namespace}
+	result, err := r.DSPAReconciler.Reconcile(withAPIStats(ctx, stats), req)
+	ReconcileAPIRequestsMetric.WithLabelValues(req.Name, req.Namespace).Observe(float64(atomic.LoadInt64(&stats.requests)))
+
+	label := "success"
+	if err != nil {
+		label = "error"
+	} else if result.Requeue || result.RequeueAfter > 0 {
+		label = "requeue"
+	}
+	ReconcileDurationMetric.WithLabelValues(req.Name, req.Namespace, label).Observe(time.Since(start).Seconds())
+	return result, err
 }
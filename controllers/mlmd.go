@@ -32,6 +32,9 @@ var mlmdTemplates = []string{
 	"ml-metadata/metadata-writer.serviceaccount.yaml.tmpl",
 }
 
+// mlmdGRPCHeadlessService is a resource deployed conditionally
+const mlmdGRPCHeadlessService = "ml-metadata/metadata-grpc.headless-service.yaml.tmpl"
+
 func (r *DSPAReconciler) ReconcileMLMD(dsp *dspav1alpha1.DataSciencePipelinesApplication,
 	params *DSPAParams) error {
 
@@ -46,6 +49,18 @@ func (r *DSPAReconciler) ReconcileMLMD(dsp *dspav1alpha1.DataSciencePipelinesApp
 				return err
 			}
 		}
+
+		if params.MLMD.GRPC.Headless {
+			err := r.Apply(dsp, params, mlmdGRPCHeadlessService)
+			if err != nil {
+				return err
+			}
+		} else {
+			err := r.DeleteResource(params, mlmdGRPCHeadlessService)
+			if err != nil {
+				return err
+			}
+		}
 		log.Info("Finished applying MLMD Resources")
 	}
 	return nil
@@ -0,0 +1,96 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MlmdEnvoyProxy holds the settings of the MLMD Envoy proxy, rendered in its ConfigMap.
+type MlmdEnvoyProxy struct {
+	ConnectTimeout string
+	RequestTimeout string
+	IdleTimeout    string
+	MaxConnections int32
+	MaxRequests    int32
+	JWKSHost       string
+	JWKSPort       string
+	JWKSTLS        bool
+	JWKSCABundle   string
+}
+
+// SetupMLMDEnvoy derives the MLMD Envoy proxy settings from spec.mlmd.envoy. The circuit breakers scale with the
+// gRPC replicas, so adding replicas doesn't leave Envoy queueing requests at its default limits.
+func (p *DSPAParams) SetupMLMDEnvoy() error {
+	envoy := p.MLMD.Envoy
+	p.MlmdEnvoyProxy = MlmdEnvoyProxy{
+		ConnectTimeout: config.MlmdEnvoyConnectTimeout,
+		MaxRequests:    p.MLMD.GRPC.Replicas * config.MlmdEnvoyReplicaMaxRequests,
+	}
+	p.MlmdEnvoyProxy.MaxConnections = p.MlmdEnvoyProxy.MaxRequests
+	if envoy.MaxConnections > 0 {
+		p.MlmdEnvoyProxy.MaxConnections = envoy.MaxConnections
+	}
+	if envoy.Timeouts != nil {
+		if envoy.Timeouts.Connect != nil {
+			p.MlmdEnvoyProxy.ConnectTimeout = envoyDuration(envoy.Timeouts.Connect)
+		}
+		p.MlmdEnvoyProxy.RequestTimeout = envoyDuration(envoy.Timeouts.Request)
+		p.MlmdEnvoyProxy.IdleTimeout = envoyDuration(envoy.Timeouts.Idle)
+	}
+	if envoy.JWT != nil {
+		host, port, tls, err := parseJWKSURI(envoy.JWT.JWKSURI)
+		if err != nil {
+			return fmt.Errorf("MLMD Envoy %w", err)
+		}
+		p.MlmdEnvoyProxy.JWKSHost = host
+		p.MlmdEnvoyProxy.JWKSPort = port
+		p.MlmdEnvoyProxy.JWKSTLS = tls
+		p.MlmdEnvoyProxy.JWKSCABundle = config.MlmdEnvoyCABundlePath
+	}
+	return nil
+}
+
+// parseJWKSURI returns the host and port Envoy fetches the JSON Web Key Set from, and whether it's served over TLS.
+func parseJWKSURI(uri string) (string, string, bool, error) {
+	jwksURI, err := url.Parse(uri)
+	if err != nil || jwksURI.Hostname() == "" || (jwksURI.Scheme != "http" && jwksURI.Scheme != "https") {
+		return "", "", false, fmt.Errorf("jwksUri [%s] must be an http or https URL", uri)
+	}
+	tls := jwksURI.Scheme == "https"
+	port := jwksURI.Port()
+	if port == "" {
+		port = "80"
+		if tls {
+			port = "443"
+		}
+	}
+	return jwksURI.Hostname(), port, tls, nil
+}
+
+// envoyDuration formats a duration as Envoy expects them, in seconds, or returns "" when unset.
+func envoyDuration(duration *metav1.Duration) string {
+	if duration == nil {
+		return ""
+	}
+	return strconv.FormatFloat(duration.Duration.Round(time.Millisecond).Seconds(), 'f', -1, 64) + "s"
+}
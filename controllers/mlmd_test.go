@@ -19,10 +19,15 @@ package controllers
 
 import (
 	"testing"
+	"time"
 
 	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
 )
 
 func TestDeployMLMD(t *testing.T) {
@@ -276,3 +281,113 @@ func TestDefaultDeployBehaviorMLMD(t *testing.T) {
 	assert.False(t, created)
 	assert.Nil(t, err)
 }
+
+func TestDeployMLMDHeadless(t *testing.T) {
+	expectedHeadlessName := "ds-pipeline-metadata-grpc-headless-testdspa"
+	dspa := newMLMDTestDSPA(nil)
+	dspa.Spec.MLMD.GRPC = &dspav1alpha1.GRPC{Headless: true}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileMLMD(dspa, params))
+
+	// Ensure the headless Service is created next to the ClusterIP one, and Envoy resolves every replica through it
+	service := &v1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedHeadlessName, Namespace: dspa.Namespace}, service))
+	assert.Equal(t, v1.ClusterIPNone, service.Spec.ClusterIP)
+	created, err := reconciler.IsResourceCreated(ctx, &v1.Service{}, "ds-pipeline-metadata-grpc-testdspa", dspa.Namespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	cm := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-metadata-envoy-config-testdspa", Namespace: dspa.Namespace}, cm))
+	assert.Contains(t, cm.Data["envoy.yaml"], "type: strict_dns")
+	assert.Contains(t, cm.Data["envoy.yaml"], `address: "`+expectedHeadlessName+`", port_value: 8080`)
+	assert.Equal(t, expectedHeadlessName, GetDerivedNames(dspa, params).MLMDGRPCHeadlessService)
+
+	// Ensure the headless Service is removed once disabled
+	dspa.Spec.MLMD.GRPC.Headless = false
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileMLMD(dspa, params))
+	created, err = reconciler.IsResourceCreated(ctx, &v1.Service{}, expectedHeadlessName, dspa.Namespace)
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
+
+func TestDeployMLMDReplicas(t *testing.T) {
+	dspa := newMLMDTestDSPA(nil)
+	dspa.Spec.MLMD.GRPC = &dspav1alpha1.GRPC{Replicas: 3}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileMLMD(dspa, params))
+	assert.Nil(t, reconciler.ReconcileDatabase(ctx, dspa, params))
+
+	// Ensure the replicas are deployed, and Envoy balances over all of them through the headless Service
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-metadata-grpc-testdspa", Namespace: dspa.Namespace}, deployment))
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	created, err := reconciler.IsResourceCreated(ctx, &v1.Service{}, "ds-pipeline-metadata-grpc-headless-testdspa", dspa.Namespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	cm := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-metadata-envoy-config-testdspa", Namespace: dspa.Namespace}, cm))
+	assert.Contains(t, cm.Data["envoy.yaml"], "type: strict_dns")
+	assert.Contains(t, cm.Data["envoy.yaml"], "max_requests: 3072")
+
+	// Ensure the managed MariaDB accepts the connections of the extra replicas
+	assert.Equal(t, int32(150), params.MlmdDBConnections())
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "mariadb-testdspa", Namespace: dspa.Namespace}, deployment))
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{Name: "MYSQL_MAX_CONNECTIONS", Value: "251"})
+}
+
+func TestDeployMLMDEnvoySettings(t *testing.T) {
+	dspa := newMLMDTestDSPA(nil)
+	dspa.Spec.MLMD.Envoy = &dspav1alpha1.Envoy{
+		AccessLog: &dspav1alpha1.EnvoyAccessLog{Format: "[%START_TIME%] \"%REQ(:PATH)%\" %RESPONSE_CODE%\n"},
+		Timeouts: &dspav1alpha1.EnvoyTimeouts{
+			Connect: &metav1.Duration{Duration: 5 * time.Second},
+			Request: &metav1.Duration{Duration: 90 * time.Second},
+			Idle:    &metav1.Duration{Duration: 1500 * time.Millisecond},
+		},
+		MaxConnections: 64,
+		JWT: &dspav1alpha1.EnvoyJWT{
+			Issuer:    "https://keycloak.example.com/realms/ml",
+			JWKSURI:   "https://keycloak.example.com/realms/ml/protocol/openid-connect/certs",
+			Audiences: []string{"pipelines"},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileMLMD(dspa, params))
+
+	// Ensure the settings are rendered in a valid Envoy config
+	cm := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-metadata-envoy-config-testdspa", Namespace: dspa.Namespace}, cm))
+	envoyConfig := map[string]interface{}{}
+	assert.Nil(t, yaml.Unmarshal([]byte(cm.Data["envoy.yaml"]), &envoyConfig))
+	envoyYAML := cm.Data["envoy.yaml"]
+	assert.Contains(t, envoyYAML, `format: "[%START_TIME%] \"%REQ(:PATH)%\" %RESPONSE_CODE%\n"`)
+	assert.Contains(t, envoyYAML, "connect_timeout: 5s")
+	assert.Contains(t, envoyYAML, "timeout: 90s")
+	assert.Contains(t, envoyYAML, "idle_timeout: 1.5s")
+	assert.Contains(t, envoyYAML, "max_connections: 64")
+	assert.Contains(t, envoyYAML, "max_requests: 1024")
+	assert.Contains(t, envoyYAML, "name: envoy.filters.http.jwt_authn")
+	assert.Contains(t, envoyYAML, `address: "keycloak.example.com", port_value: 443`)
+	assert.Contains(t, envoyYAML, `sni: "keycloak.example.com"`)
+}
+
+func TestDeployMLMDEnvoyDefaults(t *testing.T) {
+	dspa := newMLMDTestDSPA(nil)
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileMLMD(dspa, params))
+
+	// Ensure nothing optional is rendered by default
+	cm := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-metadata-envoy-config-testdspa", Namespace: dspa.Namespace}, cm))
+	envoyYAML := cm.Data["envoy.yaml"]
+	assert.Contains(t, envoyYAML, "connect_timeout: 30s")
+	assert.NotContains(t, envoyYAML, "access_log:")
+	assert.NotContains(t, envoyYAML, "idle_timeout")
+	assert.NotContains(t, envoyYAML, "jwt_authn")
+	assert.NotContains(t, envoyYAML, "jwks-cluster")
+}
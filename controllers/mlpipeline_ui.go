@@ -20,12 +20,14 @@ import (
 	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
 )
 
+const mlPipelineUIRouteTemplate = "mlpipelines-ui/route.yaml.tmpl"
+
 var mlPipelineUITemplates = []string{
 	"mlpipelines-ui/configmap.yaml.tmpl",
 	"mlpipelines-ui/deployment.yaml.tmpl",
 	"mlpipelines-ui/role.yaml.tmpl",
 	"mlpipelines-ui/rolebinding.yaml.tmpl",
-	"mlpipelines-ui/route.yaml.tmpl",
+	mlPipelineUIRouteTemplate,
 	"mlpipelines-ui/sa-ds-pipeline-ui.yaml.tmpl",
 	"mlpipelines-ui/sa_ds-pipelines-viewer.yaml.tmpl",
 	"mlpipelines-ui/service.yaml.tmpl",
@@ -43,6 +45,10 @@ func (r *DSPAReconciler) ReconcileUI(dsp *dspav1alpha1.DataSciencePipelinesAppli
 
 	log.Info("Applying MlPipelineUI Resources")
 	for _, template := range mlPipelineUITemplates {
+		// The Route goes through the oauth-proxy, which dev mode doesn't deploy
+		if params.DevMode && template == mlPipelineUIRouteTemplate {
+			continue
+		}
 		err := r.Apply(dsp, params, template)
 		if err != nil {
 			return err
@@ -0,0 +1,107 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// manifestClient is the client applying the manifests of the DSPA
+func (r *DSPAReconciler) manifestClient(params *DSPAParams) client.Client {
+	var c client.Client = r.Client
+	if params.APIStats != nil {
+		c = &apiStatsClient{Client: c, stats: params.APIStats}
+	}
+	c = &adoptingClient{
+		Client: c,
+		log:    r.Log.WithValues("namespace", params.Namespace).WithValues("dspa_name", params.Name),
+		adopt:  params.AdoptExistingResources,
+		live:   map[string]*unstructured.Unstructured{},
+	}
+	return &noopUpdateSkippingClient{Client: c, live: map[string]*unstructured.Unstructured{}}
+}
+
+// noopUpdateSkippingClient skips the updates of manifestival that leave the live object as it is. Manifestival
+// updates the live object whenever the three-way merge of a manifest yields a patch, including patches that don't
+// change anything once defaulted and normalized by the API server, e.g. a cpu of 0.5 over a live 500m, so the
+// objects it reads are compared semantically with the objects it updates.
+type noopUpdateSkippingClient struct {
+	client.Client
+	live map[string]*unstructured.Unstructured
+}
+
+func liveKey(u *unstructured.Unstructured) string {
+	return u.GroupVersionKind().String() + "/" + u.GetNamespace() + "/" + u.GetName()
+}
+
+func (c *noopUpdateSkippingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	if u, ok := obj.(*unstructured.Unstructured); ok && err == nil {
+		c.live[liveKey(u)] = u.DeepCopy()
+	}
+	return err
+}
+
+func (c *noopUpdateSkippingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		if live, found := c.live[liveKey(u)]; found && semanticallyEqual(live, u) {
+			return nil
+		}
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// semanticallyEqual compares a live object with its update, ignoring the last applied configuration manifestival
+// records. Objects of the Kubernetes types are compared as typed objects, so that quantities and int-or-strings
+// compare by value, other objects and objects with fields unknown to their type compare as they are.
+func semanticallyEqual(live, update *unstructured.Unstructured) bool {
+	objects := []*unstructured.Unstructured{live.DeepCopy(), update.DeepCopy()}
+	for _, u := range objects {
+		annotations := u.GetAnnotations()
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		u.SetAnnotations(annotations)
+	}
+
+	gvk := live.GroupVersionKind()
+	if gvk != update.GroupVersionKind() {
+		return false
+	}
+	var typed []runtime.Object
+	for _, u := range objects {
+		obj, err := scheme.Scheme.New(gvk)
+		if err != nil {
+			break
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(u.Object, obj, true); err != nil {
+			break
+		}
+		typed = append(typed, obj)
+	}
+	if len(typed) == len(objects) {
+		return equality.Semantic.DeepEqual(typed[0], typed[1])
+	}
+	return equality.Semantic.DeepEqual(objects[0].Object, objects[1].Object)
+}
@@ -0,0 +1,92 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateCountingClient counts the updates it issues
+type updateCountingClient struct {
+	client.Client
+	updates int
+}
+
+func (c *updateCountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.updates++
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+const testNoopUpdateDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: noop-{{.Name}}
+  namespace: {{.Namespace}}
+spec:
+  replicas: %d
+  selector:
+    matchLabels:
+      app: noop
+  template:
+    metadata:
+      labels:
+        app: noop
+    spec:
+      containers:
+        - name: noop
+          image: noop
+          resources:
+            requests:
+              cpu: %s
+`
+
+func TestNoopUpdates(t *testing.T) {
+	ctx, params, reconciler := CreateNewTestObjects()
+	counter := &updateCountingClient{Client: reconciler.Client}
+	reconciler.Client = counter
+	reconciler.TemplatesPath = t.TempDir() + "/"
+	params.Name, params.Namespace = "testdspa", "testnamespace"
+	apply := func(replicas int, cpu string) {
+		content := fmt.Sprintf(testNoopUpdateDeployment, replicas, cpu)
+		assert.Nil(t, os.WriteFile(filepath.Join(reconciler.TemplatesPath, "deployment.yaml.tmpl"), []byte(content), 0644))
+		assert.Nil(t, reconciler.ApplyWithoutOwner(params, "deployment.yaml.tmpl"))
+	}
+
+	apply(1, "500m")
+	assert.Equal(t, 0, counter.updates)
+
+	// Ensure patches that don't change the live object semantically are skipped
+	apply(1, "0.5")
+	assert.Equal(t, 0, counter.updates)
+
+	// Ensure patches that change the live object are applied
+	apply(2, "0.5")
+	assert.Equal(t, 1, counter.updates)
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "noop-testdspa", Namespace: "testnamespace"}, deployment))
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+}
@@ -0,0 +1,102 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// NotificationDispatcher delivers the run status webhooks and commit statuses of DSPAs outside of Reconcile, so a slow
+// or unreachable endpoint delays neither the reconciles of DSPAs nor the notifications of other DSPAs. Reconcile queues
+// its DSPA, and a bounded number of workers drain the queue, each DSPA being handled by at most one worker at a time.
+// Delivery progress is persisted on the PipelineRuns, so a pass cut short by its timeout resumes where it stopped.
+type NotificationDispatcher struct {
+	reconciler *DSPAReconciler
+	workers    int
+	timeout    time.Duration
+	queue      workqueue.Interface
+}
+
+func NewNotificationDispatcher(reconciler *DSPAReconciler, workers int, timeout time.Duration) *NotificationDispatcher {
+	return &NotificationDispatcher{
+		reconciler: reconciler,
+		workers:    workers,
+		timeout:    timeout,
+		queue:      workqueue.New(),
+	}
+}
+
+// Enqueue queues a delivery pass for the DSPA, unless one is queued already
+func (d *NotificationDispatcher) Enqueue(dsp *dspav1alpha1.DataSciencePipelinesApplication) {
+	d.queue.Add(types.NamespacedName{Name: dsp.Name, Namespace: dsp.Namespace})
+}
+
+// Start implements manager.Runnable, running the workers until ctx is done
+func (d *NotificationDispatcher) Start(ctx context.Context) error {
+	for i := 0; i < d.workers; i++ {
+		go func() {
+			for d.processNext(ctx) {
+			}
+		}()
+	}
+	<-ctx.Done()
+	d.queue.ShutDown()
+	return nil
+}
+
+func (d *NotificationDispatcher) processNext(ctx context.Context) bool {
+	item, shutdown := d.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer d.queue.Done(item)
+	d.deliver(ctx, item.(types.NamespacedName))
+	return true
+}
+
+func (d *NotificationDispatcher) deliver(ctx context.Context, nn types.NamespacedName) {
+	log := d.reconciler.Log.WithValues("namespace", nn.Namespace).WithValues("dspa_name", nn.Name)
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{}
+	if err := d.reconciler.Get(ctx, nn, dspa); err != nil {
+		if !apierrs.IsNotFound(err) {
+			log.Info(fmt.Sprintf("Encountered error when fetching the DSPA to deliver its notifications: [%s]", err))
+		}
+		return
+	}
+	if dspa.DeletionTimestamp != nil {
+		return
+	}
+	if len(dspa.Spec.RunStatusWebhooks) > 0 {
+		if err := d.reconciler.DeliverRunStatusWebhooks(ctx, dspa); err != nil {
+			log.Info(fmt.Sprintf("Encountered error when delivering run status webhooks: [%s]", err))
+		}
+	}
+	if len(dspa.Spec.CommitStatusReporters) > 0 {
+		if err := d.reconciler.ReportCommitStatuses(ctx, dspa, time.Now()); err != nil {
+			log.Info(fmt.Sprintf("Encountered error when reporting commit statuses: [%s]", err))
+		}
+	}
+}
@@ -0,0 +1,72 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNotificationDispatcher(t *testing.T) {
+	var delivered int32
+	ci := newTestWebhookServer(t, func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+	})
+
+	now := time.Now().Truncate(time.Second)
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			RunStatusWebhooks: []dspav1alpha1.RunStatusWebhook{{Name: "ci", URL: ci.URL, MaxAttempts: 3}},
+		},
+		Status: dspav1alpha1.DSPAStatus{RunStatusWebhooks: []dspav1alpha1.RunStatusWebhookStatus{
+			{Name: "ci", Since: metav1.NewTime(now.Add(-time.Hour))},
+		}},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	finished := now.Add(-time.Minute)
+	pipelineRun := newTestPipelineRun("succeeded", "testnamespace", &finished, false)
+	_ = unstructured.SetNestedSlice(pipelineRun.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": "True", "reason": "Succeeded"},
+	}, "status", "conditions")
+	assert.Nil(t, reconciler.Create(ctx, pipelineRun))
+
+	// Ensure queued DSPAs are delivered by the workers, outside of the caller
+	dispatcher := NewNotificationDispatcher(reconciler, 1, time.Minute)
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- dispatcher.Start(ctx) }()
+	dispatcher.Enqueue(dspa)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&delivered) == 1 }, 10*time.Second, 10*time.Millisecond)
+
+	// Ensure delivered runs aren't delivered again, and the workers stop with the manager
+	dispatcher.Enqueue(dspa)
+	dispatcher.Enqueue(dspa)
+	cancel()
+	assert.Nil(t, <-done)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&delivered))
+}
@@ -78,6 +78,27 @@ func TestDeployPersistenceAgent(t *testing.T) {
 	assert.True(t, created)
 	assert.Nil(t, err)
 
+	// Ensure PersistenceAgent is only scheduled on Linux nodes
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, deployment.Spec.Template.Spec.NodeSelector)
+
+	// Ensure sync tuning falls back to defaults
+	assert.Equal(t, int32(1), *deployment.Spec.Replicas)
+	assert.Nil(t, deployment.Spec.Template.Spec.TopologySpreadConstraints)
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Command, "--ttlSecondsAfterWorkflowFinish=86400")
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Command, "--clientQPS=5")
+
+	// Ensure a TTL of 0 is honored rather than replaced with the default
+	ttl := int64(0)
+	dspa.Spec.PersistenceAgent.TTLSecondsAfterWorkflowFinish = &ttl
+	params = &DSPAParams{}
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcilePersistenceAgent(dspa, params))
+	deployment = &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, expectedPersistenceAgentName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Command, "--ttlSecondsAfterWorkflowFinish=0")
+
 	// Ensure readiness is handled
 	persistenceAgentReady, err := reconciler.handleReadyCondition(ctx, dspa, params.PersistentAgentDefaultResourceName, config.PersistenceAgentReady)
 	assert.Equal(t, "Deploying", persistenceAgentReady.Reason)
@@ -0,0 +1,90 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SetupPlacements maps the name of every replicated component Deployment to its Placement.
+func (p *DSPAParams) SetupPlacements() {
+	p.Placements = make(map[string]*dspav1alpha1.Placement)
+	if p.APIServer != nil {
+		p.Placements[p.APIServerDefaultResourceName] = p.APIServer.Placement
+	}
+	if p.MLMD != nil {
+		p.Placements[config.DerivedName("ds-pipeline-metadata-grpc-", p.Name)] = p.MLMD.GRPC.Placement
+	}
+}
+
+// injectPlacements spreads the pods of replicated component Deployments rendering more than one replica across the
+// topology keys of their Placement, zones and nodes by default, and prefers scheduling them on distinct nodes.
+func injectPlacements(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" {
+			return nil
+		}
+		placement, ok := params.Placements[u.GetName()]
+		if !ok || (placement != nil && placement.Disabled) {
+			return nil
+		}
+		replicas, _, err := unstructured.NestedInt64(u.Object, "spec", "replicas")
+		if err != nil || replicas <= 1 {
+			return err
+		}
+		matchLabels, _, err := unstructured.NestedMap(u.Object, "spec", "selector", "matchLabels")
+		if err != nil {
+			return err
+		}
+
+		topologyKeys := []string{config.ZoneTopologyKey, config.HostnameTopologyKey}
+		whenUnsatisfiable := "ScheduleAnyway"
+		if placement != nil {
+			if len(placement.TopologyKeys) > 0 {
+				topologyKeys = placement.TopologyKeys
+			}
+			if placement.RequireSpread {
+				whenUnsatisfiable = "DoNotSchedule"
+			}
+		}
+		var constraints []interface{}
+		for _, topologyKey := range topologyKeys {
+			constraints = append(constraints, map[string]interface{}{
+				"maxSkew":           int64(1),
+				"topologyKey":       topologyKey,
+				"whenUnsatisfiable": whenUnsatisfiable,
+				"labelSelector":     map[string]interface{}{"matchLabels": matchLabels},
+			})
+		}
+		err = unstructured.SetNestedSlice(u.Object, constraints, "spec", "template", "spec", "topologySpreadConstraints")
+		if err != nil {
+			return err
+		}
+
+		antiAffinity := []interface{}{map[string]interface{}{
+			"weight": int64(100),
+			"podAffinityTerm": map[string]interface{}{
+				"topologyKey":   config.HostnameTopologyKey,
+				"labelSelector": map[string]interface{}{"matchLabels": matchLabels},
+			},
+		}}
+		return unstructured.SetNestedSlice(u.Object, antiAffinity, "spec", "template", "spec", "affinity", "podAntiAffinity",
+			"preferredDuringSchedulingIgnoredDuringExecution")
+	}
+}
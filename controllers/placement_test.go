@@ -0,0 +1,110 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDeployReplicatedAPIServer(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedAPIServerName := apiServerDefaultResourceNamePrefix + testDSPAName
+
+	// Construct DSPASpec with a replicated API Server
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy:   true,
+				Replicas: 3,
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Run test reconciliation
+	err = reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.Nil(t, err)
+
+	// Assert the replicas are spread across zones and nodes, preferably on distinct nodes
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, expectedAPIServerName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	constraints := deployment.Spec.Template.Spec.TopologySpreadConstraints
+	assert.Len(t, constraints, 2)
+	assert.Equal(t, "topology.kubernetes.io/zone", constraints[0].TopologyKey)
+	assert.Equal(t, "kubernetes.io/hostname", constraints[1].TopologyKey)
+	assert.Equal(t, corev1.ScheduleAnyway, constraints[0].WhenUnsatisfiable)
+	assert.Equal(t, deployment.Spec.Selector.MatchLabels, constraints[0].LabelSelector.MatchLabels)
+	antiAffinity := deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, antiAffinity, 1)
+	assert.Equal(t, "kubernetes.io/hostname", antiAffinity[0].PodAffinityTerm.TopologyKey)
+}
+
+func TestDeployMLMDWithPlacement(t *testing.T) {
+	// Construct DSPASpec with replicated MLMD gRPC servers requiring a spread across racks
+	dspa := newMLMDTestDSPA(nil)
+	dspa.Spec.MLMD.GRPC = &dspav1alpha1.GRPC{
+		Replicas:  2,
+		Placement: &dspav1alpha1.Placement{TopologyKeys: []string{"example.com/rack"}, RequireSpread: true},
+	}
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Run test reconciliation
+	err = reconciler.ReconcileMLMD(dspa, params)
+	assert.Nil(t, err)
+
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, "ds-pipeline-metadata-grpc-testdspa", dspa.Namespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	constraints := deployment.Spec.Template.Spec.TopologySpreadConstraints
+	assert.Len(t, constraints, 1)
+	assert.Equal(t, "example.com/rack", constraints[0].TopologyKey)
+	assert.Equal(t, corev1.DoNotSchedule, constraints[0].WhenUnsatisfiable)
+}
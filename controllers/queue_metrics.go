@@ -0,0 +1,146 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// pendingStepReasons are the reasons Tekton sets on the Succeeded condition of a TaskRun whose pod isn't running yet:
+// waiting to be scheduled or pulling images, blocked by a ResourceQuota of the namespace, or unschedulable for lack of
+// node resources.
+var pendingStepReasons = []string{"Pending", "ExceededResourceQuota", "ExceededNodeResources"}
+
+// QueueStats describes the runs and steps of a DSPA waiting to execute.
+type QueueStats struct {
+	// QueuedRuns is the number of unfinished runs none of whose steps started yet
+	QueuedRuns int
+	// PendingSteps is the number of unfinished steps whose pod isn't running yet, by pendingStepReasons
+	PendingSteps map[string]int
+	// QueueWait is the average time runs that started within config.QueueWaitWindow waited for their first step
+	QueueWait time.Duration
+}
+
+// stepStartTime returns when the first container of the TaskRun started, or false if none did yet.
+func stepStartTime(taskRun unstructured.Unstructured) (time.Time, bool) {
+	steps, _, _ := unstructured.NestedSlice(taskRun.Object, "status", "steps")
+	var startedAt time.Time
+	for _, s := range steps {
+		step, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, state := range []string{"running", "terminated"} {
+			stepStartedAt, found, _ := unstructured.NestedString(step, state, "startedAt")
+			if !found {
+				continue
+			}
+			started, err := time.Parse(time.RFC3339, stepStartedAt)
+			if err == nil && (startedAt.IsZero() || started.Before(startedAt)) {
+				startedAt = started
+			}
+		}
+	}
+	return startedAt, !startedAt.IsZero()
+}
+
+// RunQueueStats computes the QueueStats of the DSPA namespace from its PipelineRuns and their TaskRuns.
+func (r *DSPAReconciler) RunQueueStats(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, now time.Time) (*QueueStats, error) {
+	pipelineRuns, err := r.listPipelineRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	taskRuns, err := r.listPipelineTaskRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &QueueStats{PendingSteps: make(map[string]int)}
+	for _, reason := range pendingStepReasons {
+		stats.PendingSteps[reason] = 0
+	}
+	firstStepStarts := make(map[string]time.Time)
+	for _, taskRun := range taskRuns {
+		pipelineRun := taskRun.GetLabels()[pipelineRunLabel]
+		if startedAt, started := stepStartTime(taskRun); started {
+			if first, ok := firstStepStarts[pipelineRun]; !ok || startedAt.Before(first) {
+				firstStepStarts[pipelineRun] = startedAt
+			}
+			continue
+		}
+		if _, finished := pipelineRunCompletionTime(taskRun); finished {
+			continue
+		}
+		if _, reason, ok := succeededCondition(taskRun); ok {
+			if _, pending := stats.PendingSteps[reason]; pending {
+				stats.PendingSteps[reason]++
+			}
+		}
+	}
+
+	var waited time.Duration
+	started := 0
+	for _, pipelineRun := range pipelineRuns {
+		firstStepStart, ok := firstStepStarts[pipelineRun.GetName()]
+		if !ok {
+			if _, finished := pipelineRunCompletionTime(pipelineRun); !finished {
+				stats.QueuedRuns++
+			}
+			continue
+		}
+		if now.Sub(firstStepStart) > config.QueueWaitWindow {
+			continue
+		}
+		waited += firstStepStart.Sub(pipelineRun.GetCreationTimestamp().Time)
+		started++
+	}
+	if started > 0 {
+		stats.QueueWait = waited / time.Duration(started)
+	}
+	return stats, nil
+}
+
+// PublishQueueMetrics publishes the QueueStats of the DSPA, so runs held back by quotas or cluster capacity can be
+// alerted on.
+func (r *DSPAReconciler) PublishQueueMetrics(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	stats, err := r.RunQueueStats(ctx, dsp, time.Now())
+	if err != nil {
+		log.V(1).Info("Unable to list PipelineRuns and TaskRuns, skipping queue metrics", "error", err.Error())
+		return
+	}
+	QueuedRunsMetric.WithLabelValues(dsp.Name, dsp.Namespace).Set(float64(stats.QueuedRuns))
+	for reason, pending := range stats.PendingSteps {
+		PendingStepsMetric.WithLabelValues(dsp.Name, dsp.Namespace, reason).Set(float64(pending))
+	}
+	QueueWaitMetric.WithLabelValues(dsp.Name, dsp.Namespace).Set(stats.QueueWait.Seconds())
+}
+
+// DeleteQueueMetrics removes the queue metrics of the DSPA once they're no longer published, so alerts don't fire on
+// the last published values.
+func DeleteQueueMetrics(dsp *dspav1alpha1.DataSciencePipelinesApplication) {
+	QueuedRunsMetric.DeleteLabelValues(dsp.Name, dsp.Namespace)
+	for _, reason := range pendingStepReasons {
+		PendingStepsMetric.DeleteLabelValues(dsp.Name, dsp.Namespace, reason)
+	}
+	QueueWaitMetric.DeleteLabelValues(dsp.Name, dsp.Namespace)
+}
@@ -0,0 +1,91 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestQueuedTaskRun(name, pipelineRun, reason string, stepStartedAt *time.Time) *unstructured.Unstructured {
+	taskRun := &unstructured.Unstructured{}
+	taskRun.SetAPIVersion("tekton.dev/v1beta1")
+	taskRun.SetKind("TaskRun")
+	taskRun.SetName(name)
+	taskRun.SetNamespace("testnamespace")
+	taskRun.SetLabels(map[string]string{pipelineRunLabel: pipelineRun})
+	_ = unstructured.SetNestedSlice(taskRun.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": "Unknown", "reason": reason},
+	}, "status", "conditions")
+	if stepStartedAt != nil {
+		_ = unstructured.SetNestedSlice(taskRun.Object, []interface{}{
+			map[string]interface{}{"name": "main", "running": map[string]interface{}{"startedAt": stepStartedAt.Format(time.RFC3339)}},
+		}, "status", "steps")
+	}
+	return taskRun
+}
+
+func TestRunQueueStats(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+	now := time.Now().Truncate(time.Second)
+	finished := now.Add(-time.Minute)
+
+	// Two runs started after waiting 1 and 3 minutes, one waiting on quota, one on node resources, and one finished
+	startedFast := now.Add(-9 * time.Minute)
+	startedSlow := now.Add(-7 * time.Minute)
+	pipelineRuns := []*unstructured.Unstructured{
+		newTestPipelineRun("started-fast", "testnamespace", nil, false),
+		newTestPipelineRun("started-slow", "testnamespace", nil, false),
+		newTestPipelineRun("quota", "testnamespace", nil, false),
+		newTestPipelineRun("capacity", "testnamespace", nil, false),
+		newTestPipelineRun("finished", "testnamespace", &finished, false),
+	}
+	for _, pipelineRun := range pipelineRuns {
+		pipelineRun.SetCreationTimestamp(metav1.NewTime(now.Add(-10 * time.Minute)))
+		assert.Nil(t, reconciler.Create(ctx, pipelineRun))
+	}
+	for _, taskRun := range []*unstructured.Unstructured{
+		newTestQueuedTaskRun("started-fast-train", "started-fast", "Running", &startedFast),
+		newTestQueuedTaskRun("started-slow-train", "started-slow", "Running", &startedSlow),
+		newTestQueuedTaskRun("started-slow-evaluate", "started-slow", "ExceededResourceQuota", nil),
+		newTestQueuedTaskRun("quota-train", "quota", "ExceededResourceQuota", nil),
+		newTestQueuedTaskRun("capacity-train", "capacity", "ExceededNodeResources", nil),
+	} {
+		assert.Nil(t, reconciler.Create(ctx, taskRun))
+	}
+
+	stats, err := reconciler.RunQueueStats(ctx, dspa, now)
+	assert.Nil(t, err)
+	assert.Equal(t, &QueueStats{
+		QueuedRuns: 2,
+		PendingSteps: map[string]int{
+			"Pending":               0,
+			"ExceededResourceQuota": 2,
+			"ExceededNodeResources": 1,
+		},
+		QueueWait: 2 * time.Minute,
+	}, stats)
+}
@@ -0,0 +1,91 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// defaultWritablePaths are mounted as emptyDirs into every container when the root filesystem is read-only.
+var defaultWritablePaths = []string{"/tmp"}
+
+// containerWritablePaths are the additional paths, by container name, that component images write to at runtime.
+var containerWritablePaths = map[string][]string{
+	// The MariaDB image renders its server config into /etc/my.cnf.d on startup.
+	"mariadb": {"/etc/my.cnf.d", "/var/run/mysqld"},
+	// Minio creates its config directory under $HOME, which is / for the arbitrary UIDs OpenShift assigns.
+	"minio": {"/.minio"},
+}
+
+// writableVolumeName returns the emptyDir volume name of a container's writable path, e.g. mariadb-etc-my-cnf-d.
+func writableVolumeName(container, path string) string {
+	name := strings.NewReplacer("/", "-", ".", "-", "_", "-").Replace(strings.Trim(path, "/"))
+	return fmt.Sprintf("%s-%s", container, strings.Trim(name, "-"))
+}
+
+// injectReadOnlyRootFilesystem makes the root filesystem of every container of component workloads read-only and
+// mounts emptyDirs on the paths the container needs to write to.
+func injectReadOnlyRootFilesystem(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if !params.ReadOnlyRootFilesystem || (u.GetKind() != "Deployment" && u.GetKind() != "DaemonSet") {
+			return nil
+		}
+		volumes, _, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "volumes")
+		if err != nil {
+			return err
+		}
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, found, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", field)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+			for i, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := container["name"].(string)
+				if err := unstructured.SetNestedField(container, true, "securityContext", "readOnlyRootFilesystem"); err != nil {
+					return err
+				}
+				mounts, _, err := unstructured.NestedSlice(container, "volumeMounts")
+				if err != nil {
+					return err
+				}
+				for _, path := range append(append([]string{}, defaultWritablePaths...), containerWritablePaths[name]...) {
+					volumeName := writableVolumeName(name, path)
+					mounts = append(mounts, map[string]interface{}{"name": volumeName, "mountPath": path})
+					volumes = append(volumes, map[string]interface{}{"name": volumeName, "emptyDir": map[string]interface{}{}})
+				}
+				if err := unstructured.SetNestedSlice(container, mounts, "volumeMounts"); err != nil {
+					return err
+				}
+				containers[i] = container
+			}
+			if err := unstructured.SetNestedSlice(u.Object, containers, "spec", "template", "spec", field); err != nil {
+				return err
+			}
+		}
+		return unstructured.SetNestedSlice(u.Object, volumes, "spec", "template", "spec", "volumes")
+	}
+}
@@ -0,0 +1,85 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDeployWithReadOnlyRootFilesystem(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedMariaDBName := "mariadb-" + testDSPAName
+
+	// Construct DSPASpec with a read-only root filesystem
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+			ReadOnlyRootFilesystem: true,
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Run test reconciliation
+	err = reconciler.ReconcileDatabase(ctx, dspa, params)
+	assert.Nil(t, err)
+
+	// Assert MariaDB Deployment has a read-only root filesystem with writable emptyDirs
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, expectedMariaDBName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.True(t, *container.SecurityContext.ReadOnlyRootFilesystem)
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "mariadb-persistent-storage", MountPath: "/var/lib/mysql"})
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "mariadb-tmp", MountPath: "/tmp"})
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "mariadb-etc-my-cnf-d", MountPath: "/etc/my.cnf.d"})
+	assert.Contains(t, deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name:         "mariadb-tmp",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+}
+
+func TestWritableVolumeName(t *testing.T) {
+	assert.Equal(t, "mariadb-tmp", writableVolumeName("mariadb", "/tmp"))
+	assert.Equal(t, "mariadb-var-run-mysqld", writableVolumeName("mariadb", "/var/run/mysqld"))
+	assert.Equal(t, "minio-minio", writableVolumeName("minio", "/.minio"))
+}
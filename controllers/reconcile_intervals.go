@@ -0,0 +1,97 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// healthCheckResult is the outcome of the last Database and Object Storage health checks of a DSPA
+type healthCheckResult struct {
+	generation             int64
+	checkedAt              time.Time
+	dbAvailable            bool
+	objStoreAvailable      bool
+	databaseDiagnosis      *Diagnosis
+	objectStorageDiagnosis *Diagnosis
+	dataResidencyDiagnosis *Diagnosis
+}
+
+func checkIntervalBounds(field string, interval *time.Duration, min time.Duration) error {
+	if *interval < min || *interval > config.MaxReconcileIntervals {
+		return fmt.Errorf("reconcileIntervals %s (%s) must be between %s and %s", field, *interval, min, config.MaxReconcileIntervals)
+	}
+	return nil
+}
+
+// SetupReconcileIntervals validates the resync and health check periods of the DSPA, 0 means they are not set.
+func (p *DSPAParams) SetupReconcileIntervals(dsp *dspav1alpha1.DataSciencePipelinesApplication) error {
+	p.ResyncPeriod, p.HealthCheckPeriod = 0, 0
+	intervals := dsp.Spec.ReconcileIntervals
+	if intervals == nil {
+		return nil
+	}
+	if intervals.ResyncPeriod != nil {
+		p.ResyncPeriod = intervals.ResyncPeriod.Duration
+		if err := checkIntervalBounds("resyncPeriod", &p.ResyncPeriod, config.MinResyncPeriod); err != nil {
+			return err
+		}
+	}
+	if intervals.HealthCheckPeriod != nil {
+		p.HealthCheckPeriod = intervals.HealthCheckPeriod.Duration
+		if err := checkIntervalBounds("healthCheckPeriod", &p.HealthCheckPeriod, config.MinHealthCheckPeriod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDependencies runs the Database and Object Storage health checks of the DSPA, or reports the results of the last
+// ones if they ran less than the DSPA's healthCheckPeriod ago, for the same spec, and no verification was requested.
+func (r *DSPAReconciler) checkDependencies(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams, now time.Time) (bool, bool) {
+
+	key := types.NamespacedName{Name: dsp.Name, Namespace: dsp.Namespace}
+	if cached, ok := r.healthChecks.Load(key); ok && params.HealthCheckPeriod > 0 && dsp.GetAnnotations()[verifyAnnotation] != "now" {
+		last := cached.(healthCheckResult)
+		if last.generation == dsp.Generation && now.Sub(last.checkedAt) < params.HealthCheckPeriod {
+			r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name).V(1).Info(
+				fmt.Sprintf("Reporting health checks from %s, next checks in %s", last.checkedAt.Format(time.RFC3339),
+					params.HealthCheckPeriod-now.Sub(last.checkedAt)))
+			params.DatabaseDiagnosis = last.databaseDiagnosis
+			params.ObjectStorageDiagnosis = last.objectStorageDiagnosis
+			params.DataResidencyDiagnosis = last.dataResidencyDiagnosis
+			return last.dbAvailable, last.objStoreAvailable
+		}
+	}
+
+	result := healthCheckResult{generation: dsp.Generation, checkedAt: now}
+	result.dbAvailable = r.isDatabaseAccessible(ctx, dsp, params)
+	result.objStoreAvailable = r.isObjectStorageAccessible(ctx, dsp, params)
+	result.databaseDiagnosis = params.DatabaseDiagnosis
+	result.objectStorageDiagnosis = params.ObjectStorageDiagnosis
+	result.dataResidencyDiagnosis = r.checkDataResidency(ctx, dsp, params)
+	params.DataResidencyDiagnosis = result.dataResidencyDiagnosis
+	r.healthChecks.Store(key, result)
+	r.recordHealthCheckEvents(dsp, params, result)
+	return result.dbAvailable, result.objStoreAvailable
+}
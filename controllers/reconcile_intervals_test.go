@@ -0,0 +1,97 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetupReconcileIntervals(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{}
+	params := &DSPAParams{}
+
+	// Assert intervals are unset by default
+	assert.Nil(t, params.SetupReconcileIntervals(dspa))
+	assert.Zero(t, params.ResyncPeriod)
+	assert.Zero(t, params.HealthCheckPeriod)
+
+	dspa.Spec.ReconcileIntervals = &dspav1alpha1.ReconcileIntervals{
+		ResyncPeriod:      &metav1.Duration{Duration: 10 * time.Minute},
+		HealthCheckPeriod: &metav1.Duration{Duration: 5 * time.Minute},
+	}
+	assert.Nil(t, params.SetupReconcileIntervals(dspa))
+	assert.Equal(t, 10*time.Minute, params.ResyncPeriod)
+	assert.Equal(t, 5*time.Minute, params.HealthCheckPeriod)
+
+	// Assert intervals out of bounds are rejected
+	dspa.Spec.ReconcileIntervals.ResyncPeriod.Duration = time.Second
+	assert.ErrorContains(t, params.SetupReconcileIntervals(dspa), "resyncPeriod (1s) must be between 30s and 24h0m0s")
+	dspa.Spec.ReconcileIntervals.ResyncPeriod.Duration = time.Minute
+	dspa.Spec.ReconcileIntervals.HealthCheckPeriod.Duration = 48 * time.Hour
+	assert.ErrorContains(t, params.SetupReconcileIntervals(dspa), "healthCheckPeriod (48h0m0s) must be between 10s and 24h0m0s")
+}
+
+func TestCheckDependenciesWithHealthCheckPeriod(t *testing.T) {
+	defaultConnectAndQueryDatabase := ConnectAndQueryDatabase
+	t.Cleanup(func() { ConnectAndQueryDatabase = defaultConnectAndQueryDatabase })
+	checks := 0
+	ConnectAndQueryDatabase = func(host, port, username, password, dbname string, dbConnectionTimeout time.Duration) error {
+		checks++
+		return nil
+	}
+
+	// Construct DSPA with an Object Storage that isn't health checked
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace", Generation: 1},
+		Spec: dspav1alpha1.DSPASpec{
+			ObjectStorage: &dspav1alpha1.ObjectStorage{DisableHealthCheck: true},
+		},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+	params := &DSPAParams{HealthCheckPeriod: 5 * time.Minute}
+	now := time.Now()
+
+	// Assert the health checks only run again once the period elapsed
+	dbAvailable, objStoreAvailable := reconciler.checkDependencies(ctx, dspa, params, now)
+	assert.True(t, dbAvailable)
+	assert.True(t, objStoreAvailable)
+	assert.Equal(t, 1, checks)
+	reconciler.checkDependencies(ctx, dspa, params, now.Add(time.Minute))
+	assert.Equal(t, 1, checks)
+	reconciler.checkDependencies(ctx, dspa, params, now.Add(6*time.Minute))
+	assert.Equal(t, 2, checks)
+
+	// Assert spec changes and verification requests run the health checks right away
+	dspa.Generation = 2
+	reconciler.checkDependencies(ctx, dspa, params, now.Add(7*time.Minute))
+	assert.Equal(t, 3, checks)
+	dspa.Annotations = map[string]string{verifyAnnotation: "now"}
+	reconciler.checkDependencies(ctx, dspa, params, now.Add(8*time.Minute))
+	assert.Equal(t, 4, checks)
+
+	// Assert the health checks run on every reconcile by default
+	dspa.Annotations = nil
+	params.HealthCheckPeriod = 0
+	reconciler.checkDependencies(ctx, dspa, params, now.Add(9*time.Minute))
+	assert.Equal(t, 5, checks)
+}
@@ -0,0 +1,145 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Field indexes of the DSPAs by the names of the Secrets and ConfigMaps their spec references
+const (
+	referencedSecretsIndex    = ".spec.referencedSecrets"
+	referencedConfigMapsIndex = ".spec.referencedConfigMaps"
+)
+
+func appendSecretKeyValue(names []string, secret *dspav1alpha1.SecretKeyValue) []string {
+	if secret == nil || secret.Name == "" {
+		return names
+	}
+	return append(names, secret.Name)
+}
+
+// referencedSecrets returns the names of the Secrets provided by the user in the DSPA spec
+func referencedSecrets(dsp *dspav1alpha1.DataSciencePipelinesApplication) []string {
+	var names []string
+	if database := dsp.Spec.Database; database != nil {
+		if database.MariaDB != nil {
+			names = appendSecretKeyValue(names, database.MariaDB.PasswordSecret)
+		}
+		if database.ExternalDB != nil {
+			names = appendSecretKeyValue(names, database.ExternalDB.PasswordSecret)
+		}
+	}
+	if storage := dsp.Spec.ObjectStorage; storage != nil {
+		if storage.Minio != nil && storage.Minio.S3CredentialSecret != nil && storage.Minio.SecretName != "" {
+			names = append(names, storage.Minio.SecretName)
+		}
+		if storage.ExternalStorage != nil && storage.ExternalStorage.S3CredentialSecret != nil && storage.ExternalStorage.SecretName != "" {
+			names = append(names, storage.ExternalStorage.SecretName)
+		}
+	}
+	for _, webhook := range dsp.Spec.RunStatusWebhooks {
+		names = appendSecretKeyValue(names, webhook.SigningSecret)
+	}
+	for _, reporter := range dsp.Spec.CommitStatusReporters {
+		names = appendSecretKeyValue(names, reporter.TokenSecret)
+	}
+	if proxy := dsp.Spec.EgressProxy; proxy != nil {
+		names = appendSecretKeyValue(names, proxy.UsernameSecret)
+		names = appendSecretKeyValue(names, proxy.PasswordSecret)
+	}
+	// Route certificates are copied into the Routes
+	if dsp.Spec.APIServer != nil && dsp.Spec.APIServer.RouteTLS != nil && dsp.Spec.APIServer.RouteTLS.SecretName != "" {
+		names = append(names, dsp.Spec.APIServer.RouteTLS.SecretName)
+	}
+	if dsp.Spec.MlPipelineUI != nil && dsp.Spec.MlPipelineUI.RouteTLS != nil && dsp.Spec.MlPipelineUI.RouteTLS.SecretName != "" {
+		names = append(names, dsp.Spec.MlPipelineUI.RouteTLS.SecretName)
+	}
+	return names
+}
+
+// referencedConfigMaps returns the names of the ConfigMaps provided by the user in the DSPA spec
+func referencedConfigMaps(dsp *dspav1alpha1.DataSciencePipelinesApplication) []string {
+	var names []string
+	if apiServer := dsp.Spec.APIServer; apiServer != nil {
+		if apiServer.CABundle != nil && apiServer.CABundle.ConfigMapName != "" {
+			names = append(names, apiServer.CABundle.ConfigMapName)
+		}
+		if apiServer.ArtifactScriptConfigMap != nil && apiServer.ArtifactScriptConfigMap.Name != "" {
+			names = append(names, apiServer.ArtifactScriptConfigMap.Name)
+		}
+	}
+	if ui := dsp.Spec.MlPipelineUI; ui != nil && ui.ConfigMapName != "" {
+		names = append(names, ui.ConfigMapName)
+	}
+	if proxy := dsp.Spec.EgressProxy; proxy != nil && proxy.CABundle != nil && proxy.CABundle.ConfigMapName != "" {
+		names = append(names, proxy.CABundle.ConfigMapName)
+	}
+	if patches := dsp.Spec.ManifestPatches; patches != nil && patches.ConfigMapName != "" {
+		names = append(names, patches.ConfigMapName)
+	}
+	return names
+}
+
+// setupReferenceIndexes indexes the DSPAs by the Secrets and ConfigMaps they reference
+func setupReferenceIndexes(mgr ctrl.Manager) error {
+	indexes := map[string]func(*dspav1alpha1.DataSciencePipelinesApplication) []string{
+		referencedSecretsIndex:    referencedSecrets,
+		referencedConfigMapsIndex: referencedConfigMaps,
+	}
+	for index, referenced := range indexes {
+		referenced := referenced
+		err := mgr.GetFieldIndexer().IndexField(context.Background(), &dspav1alpha1.DataSciencePipelinesApplication{}, index,
+			func(o client.Object) []string {
+				return referenced(o.(*dspav1alpha1.DataSciencePipelinesApplication))
+			})
+		if err != nil {
+			return fmt.Errorf("unable to index DSPAs by %s: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// dspasReferencing maps a Secret or ConfigMap to the DSPAs of its namespace referencing it through the index, so
+// credential or CA bundle changes are reconciled right away instead of on the next resync.
+func (r *DSPAReconciler) dspasReferencing(index string) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		dspas := &dspav1alpha1.DataSciencePipelinesApplicationList{}
+		err := r.List(context.Background(), dspas, client.InNamespace(o.GetNamespace()), client.MatchingFields{index: o.GetName()})
+		if err != nil {
+			r.Log.Error(err, fmt.Sprintf("Could not list DSPAs referencing [%s/%s]", o.GetNamespace(), o.GetName()))
+			return []reconcile.Request{}
+		}
+
+		var requests []reconcile.Request
+		for _, dspa := range dspas.Items {
+			r.Log.V(1).Info(fmt.Sprintf("Reconcile event triggered by [%s: %s] referenced by [DSPA: %s]", index, o.GetName(), dspa.Name))
+			namespacedName := types.NamespacedName{Name: dspa.Name, Namespace: dspa.Namespace}
+			// Credentials may have been fixed, so health check again even within the healthCheckPeriod
+			r.healthChecks.Delete(namespacedName)
+			requests = append(requests, reconcile.Request{NamespacedName: namespacedName})
+		}
+		return requests
+	}
+}
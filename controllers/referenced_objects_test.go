@@ -0,0 +1,70 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferencedSecretsAndConfigMaps(t *testing.T) {
+	// Assert DSPAs relying on generated credentials reference nothing
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{Deploy: true},
+			Database:  &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+		},
+	}
+	assert.Empty(t, referencedSecrets(dspa))
+	assert.Empty(t, referencedConfigMaps(dspa))
+
+	// Assert every user provided Secret and ConfigMap is referenced
+	dspa.Spec = dspav1alpha1.DSPASpec{
+		APIServer: &dspav1alpha1.APIServer{
+			Deploy:                  true,
+			CABundle:                &dspav1alpha1.CABundle{ConfigMapName: "ca-bundle", ConfigMapKey: "ca.crt"},
+			ArtifactScriptConfigMap: &dspav1alpha1.ArtifactScriptConfigMap{Name: "artifact-script", Key: "artifact_script"},
+			RouteTLS:                &dspav1alpha1.RouteTLS{SecretName: "api-certificate"},
+		},
+		MlPipelineUI: &dspav1alpha1.MlPipelineUI{Deploy: true, ConfigMapName: "ui-config", RouteTLS: &dspav1alpha1.RouteTLS{SecretName: "ui-certificate"}},
+		Database: &dspav1alpha1.Database{
+			ExternalDB: &dspav1alpha1.ExternalDB{PasswordSecret: &dspav1alpha1.SecretKeyValue{Name: "db-password", Key: "password"}},
+		},
+		ObjectStorage: &dspav1alpha1.ObjectStorage{
+			ExternalStorage: &dspav1alpha1.ExternalStorage{S3CredentialSecret: &dspav1alpha1.S3CredentialSecret{SecretName: "s3-credentials"}},
+		},
+		RunStatusWebhooks: []dspav1alpha1.RunStatusWebhook{
+			{Name: "ci", SigningSecret: &dspav1alpha1.SecretKeyValue{Name: "webhook-signing", Key: "key"}},
+			{Name: "unsigned"},
+		},
+		CommitStatusReporters: []dspav1alpha1.CommitStatusReporter{
+			{TokenSecret: &dspav1alpha1.SecretKeyValue{Name: "github-token", Key: "token"}},
+		},
+		EgressProxy: &dspav1alpha1.EgressProxy{
+			URL:            "http://proxy.example.com:3128",
+			UsernameSecret: &dspav1alpha1.SecretKeyValue{Name: "proxy-credentials", Key: "username"},
+			PasswordSecret: &dspav1alpha1.SecretKeyValue{Name: "proxy-credentials", Key: "password"},
+			CABundle:       &dspav1alpha1.CABundle{ConfigMapName: "proxy-ca", ConfigMapKey: "ca.crt"},
+		},
+		ManifestPatches: &dspav1alpha1.ManifestPatches{ConfigMapName: "manifest-patches"},
+	}
+	assert.Equal(t, []string{"db-password", "s3-credentials", "webhook-signing", "github-token", "proxy-credentials", "proxy-credentials", "api-certificate", "ui-certificate"}, referencedSecrets(dspa))
+	assert.Equal(t, []string{"ca-bundle", "artifact-script", "ui-config", "proxy-ca", "manifest-patches"}, referencedConfigMaps(dspa))
+}
@@ -0,0 +1,181 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// renderFailureConfigMapPrefix names the ConfigMap recording why the manifests of a DSPA failed to render
+const renderFailureConfigMapPrefix = "ds-pipeline-render-failure-"
+
+// componentTemplates lists the templates the reconcile of the DSPA applies for its database, object storage and
+// components, following the conditions of their Reconcile functions. The templates of cluster scoped resources,
+// applied without owner, are listed separately. The params are those of ExtractParams.
+func (p *DSPAParams) componentTemplates(dsp *dspav1alpha1.DataSciencePipelinesApplication) (owned, unowned []string) {
+	if !p.UsingExternalDB(dsp) && p.MariaDB != nil && p.MariaDB.Deploy {
+		owned = append(owned, dbSecret)
+		for _, template := range mariadbTemplates {
+			if !p.DevMode || template != mariadbPVCTemplate {
+				owned = append(owned, template)
+			}
+		}
+	}
+	if !p.UsingExternalStorage(dsp) && p.Minio != nil && p.Minio.Deploy {
+		owned = append(owned, storageSecret)
+		for _, template := range minioTemplates {
+			if p.DevMode && template == minioPVCTemplate {
+				continue
+			}
+			if template != storageRoute || (dsp.Spec.ObjectStorage != nil && dsp.Spec.ObjectStorage.EnableExternalRoute) {
+				owned = append(owned, template)
+			}
+		}
+	}
+
+	owned = append(owned, commonTemplates...)
+	unowned = append(unowned, commonCusterRolebindingTemplate)
+	if dsp.Spec.APIServer != nil && dsp.Spec.APIServer.Deploy {
+		owned = append(owned, apiServerTemplates...)
+		if p.APIServer.EnableRoute {
+			owned = append(owned, serverRoute)
+		}
+		if p.UsingAPIServerGRPC(dsp) {
+			owned = append(owned, apiServerGRPCTemplates...)
+		}
+		if p.UsingAPIGateway(dsp) {
+			owned = append(owned, apiGatewayTemplates[p.APIGateway.Provider]...)
+		}
+		if dsp.Spec.APIServer.EnableSamplePipeline {
+			owned = append(owned, samplePipelineTemplates["sample-config"], samplePipelineTemplates["sample-pipeline"])
+		}
+	}
+	if dsp.Spec.PersistenceAgent != nil && dsp.Spec.PersistenceAgent.Deploy {
+		owned = append(owned, persistenceAgentTemplates...)
+	}
+	if dsp.Spec.ScheduledWorkflow != nil && dsp.Spec.ScheduledWorkflow.Deploy {
+		owned = append(owned, scheduledWorkflowTemplates...)
+	}
+	if dsp.Spec.MlPipelineUI != nil && dsp.Spec.MlPipelineUI.Deploy {
+		for _, template := range mlPipelineUITemplates {
+			if !p.DevMode || template != mlPipelineUIRouteTemplate {
+				owned = append(owned, template)
+			}
+		}
+	}
+	if p.UsingMLMD(dsp) {
+		owned = append(owned, mlmdTemplates...)
+		if p.MLMD.GRPC.Headless {
+			owned = append(owned, mlmdGRPCHeadlessService)
+		}
+	}
+	if p.UsingImagePrepuller(dsp) {
+		owned = append(owned, imagePrepullerTemplates...)
+	}
+	if p.UsingCacheServer(dsp) {
+		owned = append(owned, cacheServerTemplates...)
+		unowned = append(unowned, cacheServerWebhookTemplate)
+	}
+	if p.UsingHeadroom(dsp) {
+		owned = append(owned, headroomDeploymentTemplate)
+		unowned = append(unowned, headroomPriorityClassTemplate)
+	}
+	return owned, unowned
+}
+
+// CheckManifests renders the manifests of the database, object storage and components of the DSPA before any of them
+// is applied, so that a template failing to render, e.g. after an operator upgrade or with a bad operator config,
+// doesn't leave the DSPA partially upgraded. On failure, the returned diagnosis holds the deployed resources back as
+// they are, and the render errors and the workload changes held back are recorded in the render failure ConfigMap,
+// which is deleted once the manifests render again.
+func (r *DSPAReconciler) CheckManifests(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) (*Diagnosis, error) {
+
+	var renderErrors []string
+	if dsp.Spec.APIServer != nil && dsp.Spec.APIServer.Deploy {
+		checksum, err := r.apiServerConfigChecksum(ctx, params)
+		if err != nil {
+			renderErrors = append(renderErrors, fmt.Sprintf("API Server config checksum: %s", err))
+		}
+		params.APIServerConfigChecksum = checksum
+	}
+	owned, unowned := params.componentTemplates(dsp)
+	for _, template := range owned {
+		if _, err := r.render(dsp, params, template); err != nil {
+			renderErrors = append(renderErrors, fmt.Sprintf("%s: %s", template, err))
+		}
+	}
+	for _, template := range unowned {
+		if _, err := r.renderWithoutOwner(params, template); err != nil {
+			renderErrors = append(renderErrors, fmt.Sprintf("%s: %s", template, err))
+		}
+	}
+
+	nn := types.NamespacedName{Name: config.DerivedName(renderFailureConfigMapPrefix, dsp.Name), Namespace: dsp.Namespace}
+	if len(renderErrors) == 0 {
+		return nil, r.DeleteResourceIfItExists(ctx, &corev1.ConfigMap{}, nn)
+	}
+
+	// The workloads that still render show what the failed revision would have changed
+	var heldBack []string
+	for _, workload := range upgradeWorkloads {
+		if !workload.deployed(dsp, params) {
+			continue
+		}
+		restarts, err := r.workloadRestarts(ctx, dsp, params, workload.template)
+		if err == nil {
+			heldBack = append(heldBack, restarts...)
+		}
+	}
+
+	report := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace}}
+	err := r.Get(ctx, nn, report)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return nil, err
+	}
+	report.Data = map[string]string{
+		"operatorVersion": config.OperatorVersion,
+		"errors":          strings.Join(renderErrors, "\n"),
+		"heldBackChanges": strings.Join(heldBack, "\n"),
+	}
+	if apierrs.IsNotFound(err) {
+		if err := controllerutil.SetControllerReference(dsp, report, r.Scheme); err != nil {
+			return nil, err
+		}
+		err = r.Create(ctx, report)
+	} else {
+		err = r.Update(ctx, report)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Diagnosis{
+		Reason: config.RenderFailed,
+		Message: fmt.Sprintf("%d manifest(s) failed to render, keeping the deployed resources as they are, see ConfigMap [%s]: %s",
+			len(renderErrors), nn.Name, renderErrors[0]),
+	}, nil
+}
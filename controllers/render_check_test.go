@@ -0,0 +1,101 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// copyTemplates copies the templates to a temporary directory, for a test to break some of them
+func copyTemplates(t *testing.T, templatesPath string) string {
+	dir := t.TempDir()
+	err := filepath.WalkDir(templatesPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, path[len(templatesPath):])
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0644)
+	})
+	assert.Nil(t, err)
+	return dir + "/"
+}
+
+func TestCheckManifests(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace", UID: "testuid"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+
+	// Ensure manifests that render are let through
+	diagnosis, err := reconciler.CheckManifests(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Nil(t, diagnosis)
+
+	// Ensure a template failing to render holds the DSPA back, and is recorded along with the changes held back
+	templatesPath := reconciler.TemplatesPath
+	reconciler.TemplatesPath = copyTemplates(t, templatesPath)
+	broken := filepath.Join(reconciler.TemplatesPath, "apiserver/role_ds-pipeline.yaml.tmpl")
+	assert.Nil(t, os.WriteFile(broken, []byte("name: {{.NoSuchParam}}\n"), 0644))
+	params.APIServer.Image = "quay.io/opendatahub/ds-pipelines-api-server:next"
+
+	diagnosis, err = reconciler.CheckManifests(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.NotNil(t, diagnosis)
+	assert.Equal(t, config.RenderFailed, diagnosis.Reason)
+	assert.Contains(t, diagnosis.Message, "apiserver/role_ds-pipeline.yaml.tmpl")
+
+	report := &corev1.ConfigMap{}
+	nn := types.NamespacedName{Name: "ds-pipeline-render-failure-testdspa", Namespace: "testnamespace"}
+	assert.Nil(t, reconciler.Get(ctx, nn, report))
+	assert.Equal(t, "testdspa", report.OwnerReferences[0].Name)
+	assert.Contains(t, report.Data["errors"], "can't evaluate field NoSuchParam")
+	assert.Contains(t, report.Data["heldBackChanges"], "-> quay.io/opendatahub/ds-pipelines-api-server:next")
+
+	// Ensure the record is deleted once the manifests render again
+	reconciler.TemplatesPath = templatesPath
+	diagnosis, err = reconciler.CheckManifests(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Nil(t, diagnosis)
+	assert.True(t, apierrs.IsNotFound(reconciler.Get(ctx, nn, &corev1.ConfigMap{})))
+}
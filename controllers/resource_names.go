@@ -0,0 +1,254 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resourceNamePrefixes are prepended to the DSPA name to name the namespaced resources it manages. As the API Server
+// prefix is also the start of the other ones, e.g. DSPAs "sample" and "ui-sample" would both manage a "ds-pipeline-ui-sample".
+var resourceNamePrefixes = []string{
+	apiServerDefaultResourceNamePrefix,
+	persistenceAgentDefaultResourceNamePrefix,
+	scheduledWorkflowDefaultResourceNamePrefix,
+	imagePrepullerDefaultResourceNamePrefix,
+	cacheServerDefaultResourceNamePrefix,
+	headroomDefaultResourceNamePrefix,
+	apiServerGRPCDefaultResourceNamePrefix,
+	"ds-pipeline-grpc-config-",
+	"ds-pipeline-grpc-tls-",
+	apiGatewayDefaultResourceNamePrefix,
+	"ds-pipeline-gateway-auth-",
+	"ds-pipeline-cache-server-tls-",
+	config.ArtifactScriptConfigMapNamePrefix,
+	config.MLPipelineUIConfigMapPrefix,
+	config.DefaultDBSecretNamePrefix,
+	config.DefaultObjectStorageSecretNamePrefix,
+	debugAccessRolePrefix,
+	debugProxyConfigPrefix,
+	debugProxyPrefix,
+	backupCronJobPrefix,
+	restoreJobPrefix,
+	artifactRetentionPrefix,
+	mariadbExporterPrefix,
+	"ds-pipeline-known-good-images-",
+	"ds-pipeline-metadata-envoy-config-",
+	"ds-pipeline-metadata-envoy-",
+	"ds-pipeline-metadata-grpc-headless-",
+	"ds-pipeline-metadata-grpc-",
+	"ds-pipeline-metadata-writer-",
+	renderFailureConfigMapPrefix,
+	"ds-pipeline-security-posture-",
+	"ds-pipeline-ui-",
+	"ds-pipeline-user-access-",
+	"ds-pipeline-version-manifest-",
+	"ds-pipelines-envoy-",
+	"ds-pipelines-mariadb-sa-",
+	"ds-pipelines-minio-sa-",
+	"ds-pipelines-viewer-",
+	"ds-pipelines-",
+	config.MariaDBHostPrefix + "-",
+	config.MinioHostPrefix + "-",
+	"pipeline-runner-",
+	"sample-config-",
+	"sample-pipeline-",
+}
+
+func resourceNames(dspaName string) map[string]bool {
+	names := make(map[string]bool, len(resourceNamePrefixes))
+	for _, prefix := range resourceNamePrefixes {
+		names[config.DerivedName(prefix, dspaName)] = true
+	}
+	return names
+}
+
+// FindNameCollision returns a description of the first resource name the DSPA shares with an older DSPA of its
+// namespace, or "" if there is none. The newer DSPA is not deployed, so it can't take over resources of the older one.
+func (r *DSPAReconciler) FindNameCollision(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) (string, error) {
+	dspas := &dspav1alpha1.DataSciencePipelinesApplicationList{}
+	if err := r.List(ctx, dspas, client.InNamespace(dsp.Namespace)); err != nil {
+		return "", err
+	}
+
+	names := resourceNames(dsp.Name)
+	for _, other := range dspas.Items {
+		older := other.CreationTimestamp.Before(&dsp.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&dsp.CreationTimestamp) && other.Name < dsp.Name)
+		if other.Name == dsp.Name || !older {
+			continue
+		}
+		for _, prefix := range resourceNamePrefixes {
+			if name := config.DerivedName(prefix, other.Name); names[name] {
+				return fmt.Sprintf("DSPA [%s] can't be deployed, its resource name [%s] is already used by DSPA [%s] of the "+
+					"same namespace. Rename the DSPA so it doesn't start with another DSPA's component prefix.", dsp.Name, name, other.Name), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// DeleteLegacyClusterRoleBinding deletes the UI auth delegator ClusterRoleBinding named before the namespace and DSPA
+// name were separated by a dot, as "<namespace>-<name>" collides across namespaces, e.g. "a-b"/"c" and "a"/"b-c". Only a
+// binding for the DSPA's own ServiceAccounts is deleted.
+func (r *DSPAReconciler) DeleteLegacyClusterRoleBinding(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) error {
+	binding := &rbacv1.ClusterRoleBinding{}
+	name := fmt.Sprintf("ds-pipeline-ui-auth-delegator-%s-%s", dsp.Namespace, dsp.Name)
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, binding); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	for _, subject := range binding.Subjects {
+		if subject.Namespace != dsp.Namespace || (subject.Name != config.DerivedName("ds-pipeline-ui-", dsp.Name) && subject.Name != config.DerivedName("ds-pipeline-", dsp.Name)) {
+			return nil
+		}
+	}
+	r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name).Info(fmt.Sprintf("Deleting legacy ClusterRoleBinding [%s]", name))
+	return client.IgnoreNotFound(r.Delete(ctx, binding))
+}
+
+// Prefixes of the workloads named <prefix><DSPA name> before their names were derived, which differs for the DSPA
+// names that are truncated and hashed
+var (
+	legacyDeploymentPrefixes = []string{
+		apiServerDefaultResourceNamePrefix,
+		persistenceAgentDefaultResourceNamePrefix,
+		scheduledWorkflowDefaultResourceNamePrefix,
+		config.MariaDBHostPrefix + "-",
+		config.MinioHostPrefix + "-",
+		"ds-pipeline-ui-",
+		"ds-pipeline-metadata-envoy-",
+		"ds-pipeline-metadata-grpc-",
+		"ds-pipeline-metadata-writer-",
+	}
+	legacyRoutePrefixes = []string{
+		apiServerDefaultResourceNamePrefix,
+		config.MinioHostPrefix + "-",
+		"ds-pipeline-ui-",
+	}
+)
+
+// SetupPVCNames determines the names of the PVCs of MariaDB and Minio. PVCs named before their names were derived
+// keep being used, so their data isn't lost, as long as the DSPA controls them.
+func (p *DSPAParams) SetupPVCNames(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, c client.Client) error {
+	p.MariaDBPVCName = config.DerivedName(config.MariaDBHostPrefix+"-", dsp.Name)
+	p.MinioPVCName = config.DerivedName(config.MinioHostPrefix+"-", dsp.Name)
+	pvcNames := map[string]*string{
+		config.MariaDBHostPrefix + "-": &p.MariaDBPVCName,
+		config.MinioHostPrefix + "-":   &p.MinioPVCName,
+	}
+	for prefix, pvcName := range pvcNames {
+		legacy := prefix + dsp.Name
+		if legacy == *pvcName {
+			continue
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := c.Get(ctx, types.NamespacedName{Name: legacy, Namespace: dsp.Namespace}, pvc)
+		if err != nil && !apierrs.IsNotFound(err) {
+			return err
+		} else if err == nil && metav1.IsControlledBy(pvc, dsp) {
+			*pvcName = legacy
+		}
+	}
+	return nil
+}
+
+// DeleteLegacyWorkloads deletes the Deployments and Routes of the DSPA that were named before their names were
+// derived, as they are replaced by the ones with derived names. Only resources the DSPA controls are deleted.
+func (r *DSPAReconciler) DeleteLegacyWorkloads(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	var legacy []client.Object
+	for _, prefix := range legacyDeploymentPrefixes {
+		if name := prefix + dsp.Name; name != config.DerivedName(prefix, dsp.Name) {
+			legacy = append(legacy, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: dsp.Namespace}})
+		}
+	}
+	for _, prefix := range legacyRoutePrefixes {
+		if name := prefix + dsp.Name; name != config.DerivedRouteName(prefix, dsp.Name, dsp.Namespace) {
+			legacy = append(legacy, &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: dsp.Namespace}})
+		}
+	}
+	for _, obj := range legacy {
+		err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if apierrs.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !metav1.IsControlledBy(obj, dsp) {
+			continue
+		}
+		log.Info(fmt.Sprintf("Deleting legacy %T [%s]", obj, obj.GetName()))
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDerivedNames returns the names of the Services and Routes of the components the DSPA deploys, which are
+// truncated and hashed for long DSPA names, or nil if none are deployed.
+func GetDerivedNames(dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) *dspav1alpha1.DerivedNames {
+	if params.NameCollision != "" {
+		return nil
+	}
+	names := &dspav1alpha1.DerivedNames{}
+	if params.APIServer != nil && params.APIServer.Deploy {
+		names.APIServerService = params.APIServerServiceName
+		if params.APIServer.EnableRoute {
+			names.APIServerRoute = config.DerivedRouteName(apiServerDefaultResourceNamePrefix, dsp.Name, dsp.Namespace)
+		}
+		if params.UsingAPIServerGRPC(dsp) {
+			names.APIServerGRPCRoute = config.DerivedRouteName(apiServerGRPCDefaultResourceNamePrefix, dsp.Name, dsp.Namespace)
+		}
+	}
+	if params.MlPipelineUI != nil && params.MlPipelineUI.Deploy {
+		names.MlPipelineUIService = config.DerivedName("ds-pipeline-ui-", dsp.Name)
+		names.MlPipelineUIRoute = config.DerivedRouteName("ds-pipeline-ui-", dsp.Name, dsp.Namespace)
+	}
+	if params.MariaDB != nil && params.MariaDB.Deploy {
+		names.MariaDBService = config.DerivedName(config.MariaDBHostPrefix+"-", dsp.Name)
+	}
+	if params.Minio != nil && params.Minio.Deploy {
+		names.MinioService = config.DerivedName(config.MinioHostPrefix+"-", dsp.Name)
+		if dsp.Spec.ObjectStorage != nil && dsp.Spec.ObjectStorage.EnableExternalRoute {
+			names.MinioRoute = config.DerivedRouteName(config.MinioHostPrefix+"-", dsp.Name, dsp.Namespace)
+		}
+	}
+	if params.MLMD != nil && params.MLMD.Deploy {
+		names.MLMDGRPCService = config.DerivedName("ds-pipeline-metadata-grpc-", dsp.Name)
+		if params.MLMD.GRPC.Headless {
+			names.MLMDGRPCHeadlessService = config.DerivedName("ds-pipeline-metadata-grpc-headless-", dsp.Name)
+		}
+		names.MLMDEnvoyService = config.DerivedName("ds-pipeline-metadata-envoy-", dsp.Name)
+	}
+	if *names == (dspav1alpha1.DerivedNames{}) {
+		return nil
+	}
+	return names
+}
@@ -0,0 +1,215 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TestResourceNamePrefixes ensures name collisions are checked for every resource named after the DSPA in the templates
+func TestResourceNamePrefixes(t *testing.T) {
+	templates, err := filepath.Glob("../config/internal/*/*.tmpl")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, templates)
+
+	namePattern := regexp.MustCompile(`(?m)^ {2,4}name: "?\{\{derivedName "([a-z-]+-)" \.Name\}\}"?$`)
+	// Names concatenated in the template are not shortened for long DSPA names
+	concatenatedNamePattern := regexp.MustCompile(`[a-z0-9]-\{\{ ?\.Name ?\}\}`)
+	for _, template := range templates {
+		content, err := os.ReadFile(template)
+		assert.Nil(t, err)
+		for _, match := range namePattern.FindAllStringSubmatch(string(content), -1) {
+			assert.Contains(t, resourceNamePrefixes, match[1], "missing from resourceNamePrefixes, used in %s", template)
+		}
+		assert.NotRegexp(t, concatenatedNamePattern, string(content), "name not derived with derivedName in %s", template)
+	}
+}
+
+func TestDerivedName(t *testing.T) {
+	longName := strings.Repeat("a", 63)
+
+	// Assert names that fit are unchanged, so existing resources keep their names
+	assert.Equal(t, "ds-pipeline-ui-sample", config.DerivedName("ds-pipeline-ui-", "sample"))
+	assert.Equal(t, "ds-pipeline-ui-sample", config.DerivedRouteName("ds-pipeline-ui-", "sample", "testnamespace"))
+
+	// Assert longer names are truncated to a DNS label, and stay unique and stable
+	derived := config.DerivedName("ds-pipeline-ui-", longName)
+	assert.Len(t, derived, config.MaxDerivedNameLength)
+	assert.True(t, strings.HasPrefix(derived, "ds-pipeline-ui-aaaa"))
+	assert.Equal(t, derived, config.DerivedName("ds-pipeline-ui-", longName))
+	assert.NotEqual(t, derived, config.DerivedName("ds-pipeline-ui-", longName[1:]+"b"))
+	assert.NotEqual(t, derived, config.DerivedName("ds-pipeline-", "ui-"+longName[:62]))
+
+	// Assert the generated Route host <route name>-<namespace> fits a DNS label
+	namespace := "a-long-testnamespace-of-the-pipelines-team"
+	route := config.DerivedRouteName("ds-pipeline-ui-", "sample", namespace)
+	assert.LessOrEqual(t, len(route+"-"+namespace), config.MaxDerivedNameLength)
+	assert.NotEqual(t, route, config.DerivedRouteName("ds-pipeline-", "sample", namespace))
+}
+
+func TestDeployUIWithLongName(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 63), Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			MlPipelineUI:  &dspav1alpha1.MlPipelineUI{Deploy: true, Image: "test-image:latest"},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileUI(dspa, params))
+
+	// Assert the UI is exposed with the derived names published in the status
+	names := GetDerivedNames(dspa, params)
+	assert.NotNil(t, names)
+	assert.Equal(t, config.DerivedName("mariadb-", dspa.Name), names.MariaDBService)
+	assert.Empty(t, names.MinioService)
+	service := &v1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: names.MlPipelineUIService, Namespace: dspa.Namespace}, service))
+	assert.Len(t, service.Name, config.MaxDerivedNameLength)
+	assert.Len(t, service.Labels["app"], config.MaxDerivedNameLength)
+	route := &routev1.Route{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: names.MlPipelineUIRoute, Namespace: dspa.Namespace}, route))
+	assert.Equal(t, service.Name, route.Spec.To.Name)
+	assert.LessOrEqual(t, len(route.Name+"-"+dspa.Namespace), config.MaxDerivedNameLength)
+}
+
+func TestFindNameCollision(t *testing.T) {
+	ctx, _, reconciler := CreateNewTestObjects()
+	created := metav1.NewTime(time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC))
+	for i, name := range []string{"sample", "ui-sample", "shadow"} {
+		dspa := &dspav1alpha1.DataSciencePipelinesApplication{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "testnamespace",
+			CreationTimestamp: metav1.NewTime(created.Add(time.Duration(i) * time.Hour)),
+		}}
+		assert.Nil(t, reconciler.Create(ctx, dspa))
+	}
+	dspa := func(name string, hours int) *dspav1alpha1.DataSciencePipelinesApplication {
+		return &dspav1alpha1.DataSciencePipelinesApplication{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "testnamespace",
+			CreationTimestamp: metav1.NewTime(created.Add(time.Duration(hours) * time.Hour)),
+		}}
+	}
+
+	// Assert the newer DSPA, whose API Server would take over the UI of the older one, is rejected
+	collision, err := reconciler.FindNameCollision(ctx, dspa("ui-sample", 1))
+	assert.Nil(t, err)
+	assert.Contains(t, collision, "resource name [ds-pipeline-ui-sample] is already used by DSPA [sample]")
+
+	// Assert the older DSPA, and DSPAs with unrelated names are deployed
+	collision, err = reconciler.FindNameCollision(ctx, dspa("sample", 0))
+	assert.Nil(t, err)
+	assert.Empty(t, collision)
+	collision, err = reconciler.FindNameCollision(ctx, dspa("shadow", 2))
+	assert.Nil(t, err)
+	assert.Empty(t, collision)
+}
+
+func TestDeleteLegacyClusterRoleBinding(t *testing.T) {
+	ctx, _, reconciler := CreateNewTestObjects()
+	legacyBinding := func(name, namespace string, subjects ...string) *rbacv1.ClusterRoleBinding {
+		binding := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "system:auth-delegator"},
+		}
+		for _, subject := range subjects {
+			binding.Subjects = append(binding.Subjects, rbacv1.Subject{Kind: "ServiceAccount", Namespace: namespace, Name: subject})
+		}
+		return binding
+	}
+
+	// Namespace "a-b" with DSPA "c" used the same name as namespace "a" with DSPA "b-c"
+	assert.Nil(t, reconciler.Create(ctx, legacyBinding("ds-pipeline-ui-auth-delegator-a-b-c", "a", "ds-pipeline-ui-b-c", "ds-pipeline-b-c")))
+
+	// Assert bindings of other DSPAs are kept
+	other := &dspav1alpha1.DataSciencePipelinesApplication{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "a-b"}}
+	assert.Nil(t, reconciler.DeleteLegacyClusterRoleBinding(ctx, other))
+	created, err := reconciler.IsResourceCreated(ctx, &rbacv1.ClusterRoleBinding{}, "ds-pipeline-ui-auth-delegator-a-b-c", "")
+	assert.Nil(t, err)
+	assert.True(t, created)
+
+	// Assert the DSPA's own legacy binding is deleted
+	owner := &dspav1alpha1.DataSciencePipelinesApplication{ObjectMeta: metav1.ObjectMeta{Name: "b-c", Namespace: "a"}}
+	assert.Nil(t, reconciler.DeleteLegacyClusterRoleBinding(ctx, owner))
+	created, err = reconciler.IsResourceCreated(ctx, &rbacv1.ClusterRoleBinding{}, "ds-pipeline-ui-auth-delegator-a-b-c", "")
+	assert.Nil(t, err)
+	assert.False(t, created)
+}
+
+func TestMigrateLegacyResources(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 63), Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: true, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	controlled := func(name string) metav1.ObjectMeta {
+		meta := metav1.ObjectMeta{Name: name, Namespace: dspa.Namespace}
+		controller := true
+		meta.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: dspav1alpha1.GroupVersion.String(),
+			Kind:       "DataSciencePipelinesApplication",
+			Name:       dspa.Name,
+			UID:        dspa.UID,
+			Controller: &controller,
+		}}
+		return meta
+	}
+	assert.Nil(t, reconciler.Create(ctx, &v1.PersistentVolumeClaim{ObjectMeta: controlled("mariadb-" + dspa.Name)}))
+	assert.Nil(t, reconciler.Create(ctx, &appsv1.Deployment{ObjectMeta: controlled("ds-pipeline-ui-" + dspa.Name)}))
+	assert.Nil(t, reconciler.Create(ctx, &routev1.Route{ObjectMeta: controlled("ds-pipeline-ui-" + dspa.Name)}))
+	assert.Nil(t, reconciler.Create(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-" + dspa.Name, Namespace: dspa.Namespace}}))
+
+	// Assert the legacy PVC the DSPA controls keeps being used, and PVCs that don't exist get derived names
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Equal(t, "mariadb-"+dspa.Name, params.MariaDBPVCName)
+	assert.Equal(t, config.DerivedName("minio-", dspa.Name), params.MinioPVCName)
+
+	// Assert the legacy workloads the DSPA controls are deleted, and others are kept
+	assert.Nil(t, reconciler.DeleteLegacyWorkloads(ctx, dspa))
+	created, err := reconciler.IsResourceCreated(ctx, &appsv1.Deployment{}, "ds-pipeline-ui-"+dspa.Name, dspa.Namespace)
+	assert.Nil(t, err)
+	assert.False(t, created)
+	created, err = reconciler.IsResourceCreated(ctx, &routev1.Route{}, "ds-pipeline-ui-"+dspa.Name, dspa.Namespace)
+	assert.Nil(t, err)
+	assert.False(t, created)
+	created, err = reconciler.IsResourceCreated(ctx, &appsv1.Deployment{}, "ds-pipeline-"+dspa.Name, dspa.Namespace)
+	assert.Nil(t, err)
+	assert.True(t, created)
+}
@@ -0,0 +1,180 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const knownGoodImagesTemplate = "common/known-good-images.configmap.yaml.tmpl"
+
+// Keys of the known-good images ConfigMap holding the images observed before they are recorded as known-good, always
+// rendered so that applying the ConfigMap clears them
+const (
+	knownGoodCandidatesKey      = "candidates"
+	knownGoodCandidatesSinceKey = "candidatesSince"
+)
+
+// rollbackAnnotation is set to "true" on a DSPA to run its components with the last known-good images
+const rollbackAnnotation = "datasciencepipelinesapplications.opendatahub.io/rollback"
+
+func rollingBack(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	return dsp.GetAnnotations()[rollbackAnnotation] == "true"
+}
+
+// ApplyKnownGoodImages replaces the component images of a DSPA annotated for rollback with the last known-good ones,
+// e.g. when a new API Server version misbehaves, without reinstalling the older operator. Components without a
+// known-good image keep the current one. Only images are rolled back, the rest of the components' configuration is
+// rendered by the running operator.
+func (r *DSPAReconciler) ApplyKnownGoodImages(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	if !rollingBack(dsp) {
+		return nil
+	}
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	knownGood := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: config.DerivedName("ds-pipeline-known-good-images-", dsp.Name), Namespace: dsp.Namespace}, knownGood)
+	if err != nil {
+		return fmt.Errorf("unable to roll back DSPA [%s], no known-good images found: %w", dsp.Name, err)
+	}
+	for component, image := range params.componentImageFields(dsp) {
+		if knownGoodImage := knownGood.Data[component+".image"]; knownGoodImage != "" && knownGoodImage != *image {
+			log.Info(fmt.Sprintf("Rolling back [%s] image from [%s] to [%s]", component, *image, knownGoodImage))
+			*image = knownGoodImage
+		}
+	}
+	return params.SetupImagePrepuller()
+}
+
+// componentsRolledOut returns true if every Deployment of the DSPA runs its latest spec on all replicas.
+func (r *DSPAReconciler) componentsRolledOut(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) (bool, error) {
+	deployments := &appsv1.DeploymentList{}
+	err := r.List(ctx, deployments, client.InNamespace(dsp.Namespace), client.MatchingLabels{
+		"component": "data-science-pipelines",
+		"dspa":      dsp.Name,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, deployment := range deployments.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ObservedGeneration < deployment.Generation ||
+			deployment.Status.UpdatedReplicas != replicas || deployment.Status.AvailableReplicas != replicas {
+			return false, nil
+		}
+	}
+	return len(deployments.Items) > 0, nil
+}
+
+// candidateImages returns the images observed before they are recorded as known-good, from the space separated
+// <component>=<image> pairs of the candidates key.
+func candidateImages(knownGood *corev1.ConfigMap) map[string]string {
+	images := make(map[string]string)
+	for _, pair := range strings.Fields(knownGood.Data[knownGoodCandidatesKey]) {
+		if component, image, found := strings.Cut(pair, "="); found {
+			images[component] = image
+		}
+	}
+	return images
+}
+
+// RecordKnownGoodImages maintains the ds-pipeline-known-good-images ConfigMap, so a rollback returns to images that
+// were working. New component images are only recorded as candidates: the known-good images stay those of the
+// previous set until the candidates have been Ready and fully rolled out for the whole observation window, and the
+// candidates are dropped, restarting their window, whenever the DSPA isn't.
+func (r *DSPAReconciler) RecordKnownGoodImages(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams, conditions []metav1.Condition, now time.Time) error {
+
+	if rollingBack(dsp) || params.PendingUpgrade != nil {
+		return nil
+	}
+	knownGood := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: config.DerivedName("ds-pipeline-known-good-images-", dsp.Name), Namespace: dsp.Namespace}, knownGood)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	params.KnownGoodImages = make(map[string]string)
+	params.KnownGoodCandidateImages = nil
+	params.KnownGoodImagesPromotedAt = time.Time{}
+	for key, image := range knownGood.Data {
+		if component := strings.TrimSuffix(key, ".image"); component != key {
+			params.KnownGoodImages[component] = image
+		}
+	}
+	candidates := candidateImages(knownGood)
+
+	healthy := util.GetConditionByType(config.CrReady, conditions).Status == metav1.ConditionTrue
+	if healthy {
+		healthy, err = r.componentsRolledOut(ctx, dsp)
+		if err != nil {
+			return err
+		}
+	}
+	current := params.componentImages(dsp)
+	for component, image := range current {
+		if strings.TrimSpace(image) == "" {
+			delete(current, component)
+		}
+	}
+
+	switch {
+	case !healthy || reflect.DeepEqual(current, params.KnownGoodImages):
+		if len(candidates) == 0 {
+			return nil
+		}
+	case !reflect.DeepEqual(current, candidates):
+		params.KnownGoodCandidateImages = current
+		params.KnownGoodCandidatesSince = now
+	default:
+		since, err := time.Parse(time.RFC3339, knownGood.Data[knownGoodCandidatesSinceKey])
+		if err != nil {
+			return err
+		}
+		window := config.GetDurationConfigWithDefault(config.KnownGoodObservationWindowConfigName, config.DefaultKnownGoodObservationWindow)
+		if promoteAt := since.Add(window); now.Before(promoteAt) {
+			params.KnownGoodImagesPromotedAt = promoteAt
+			return nil
+		}
+		params.KnownGoodImages = current
+	}
+	return r.Apply(dsp, params, knownGoodImagesTemplate)
+}
+
+// requeueForKnownGoodImages requeues the DSPA when its candidate images are due to be recorded as known-good, if the
+// result doesn't requeue it earlier.
+func requeueForKnownGoodImages(result ctrl.Result, params *DSPAParams, now time.Time) ctrl.Result {
+	return requeueAt(result, params.KnownGoodImagesPromotedAt, now)
+}
@@ -0,0 +1,158 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRollbackToKnownGoodImages(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	knownGoodName := "ds-pipeline-known-good-images-" + testDSPAName
+
+	// Construct DSPASpec with a deployed APIServer
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy: true,
+				Image:  "api-server:v1.0.0",
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Create an APIServer Deployment that is still rolling out
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ds-pipeline-" + testDSPAName,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"component": "data-science-pipelines", "dspa": testDSPAName},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 2},
+	}
+	assert.Nil(t, reconciler.Create(ctx, deployment))
+	ready := []metav1.Condition{{Type: config.CrReady, Status: metav1.ConditionTrue}}
+
+	// Assert images are not recorded until every component has rolled out
+	now := time.Now().Truncate(time.Second)
+	err = reconciler.RecordKnownGoodImages(ctx, dspa, params, ready, now)
+	assert.Nil(t, err)
+	created, err := reconciler.IsResourceCreated(ctx, &v1.ConfigMap{}, knownGoodName, testNamespace)
+	assert.Nil(t, err)
+	assert.False(t, created)
+
+	// Assert images of rolled out, Ready components are observed before they are recorded as known-good
+	deployment.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	assert.Nil(t, reconciler.Status().Update(ctx, deployment))
+	err = reconciler.RecordKnownGoodImages(ctx, dspa, params, ready, now)
+	assert.Nil(t, err)
+	knownGood := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: knownGoodName, Namespace: testNamespace}, knownGood))
+	assert.NotContains(t, knownGood.Data, "apiServer.image")
+	assert.Contains(t, knownGood.Data["candidates"], "apiServer=api-server:v1.0.0")
+	err = reconciler.RecordKnownGoodImages(ctx, dspa, params, ready, now.Add(30*time.Minute))
+	assert.Nil(t, err)
+	assert.True(t, now.Add(config.DefaultKnownGoodObservationWindow).Equal(params.KnownGoodImagesPromotedAt))
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: knownGoodName, Namespace: testNamespace}, knownGood))
+	assert.NotContains(t, knownGood.Data, "apiServer.image")
+
+	// Assert the images are recorded once they were Ready for the whole observation window
+	err = reconciler.RecordKnownGoodImages(ctx, dspa, params, ready, now.Add(config.DefaultKnownGoodObservationWindow))
+	assert.Nil(t, err)
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: knownGoodName, Namespace: testNamespace}, knownGood))
+	assert.Equal(t, "api-server:v1.0.0", knownGood.Data["apiServer.image"])
+	assert.Empty(t, knownGood.Data["candidates"])
+
+	// Assert updated images don't replace the known-good ones while they are observed, and are dropped once they
+	// aren't Ready
+	dspa.Spec.APIServer.Image = "api-server:v1.1.0"
+	err = params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+	err = reconciler.RecordKnownGoodImages(ctx, dspa, params, ready, now.Add(2*time.Hour))
+	assert.Nil(t, err)
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: knownGoodName, Namespace: testNamespace}, knownGood))
+	assert.Equal(t, "api-server:v1.0.0", knownGood.Data["apiServer.image"])
+	assert.Contains(t, knownGood.Data["candidates"], "apiServer=api-server:v1.1.0")
+	notReady := []metav1.Condition{{Type: config.CrReady, Status: metav1.ConditionFalse}}
+	err = reconciler.RecordKnownGoodImages(ctx, dspa, params, notReady, now.Add(3*time.Hour))
+	assert.Nil(t, err)
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: knownGoodName, Namespace: testNamespace}, knownGood))
+	assert.Equal(t, "api-server:v1.0.0", knownGood.Data["apiServer.image"])
+	assert.Empty(t, knownGood.Data["candidates"])
+
+	// Assert DSPAs not annotated for rollback keep their images
+	err = reconciler.ApplyKnownGoodImages(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Equal(t, "api-server:v1.1.0", params.APIServer.Image)
+
+	// Assert the known-good images are run on rollback, and are not overwritten meanwhile
+	dspa.Annotations = map[string]string{rollbackAnnotation: "true"}
+	err = reconciler.ApplyKnownGoodImages(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Equal(t, "api-server:v1.0.0", params.APIServer.Image)
+	params.APIServer.Image = "api-server:v1.1.0"
+	err = reconciler.RecordKnownGoodImages(ctx, dspa, params, ready, now.Add(5*time.Hour))
+	assert.Nil(t, err)
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: knownGoodName, Namespace: testNamespace}, knownGood))
+	assert.Equal(t, "api-server:v1.0.0", knownGood.Data["apiServer.image"])
+	assert.Empty(t, knownGood.Data["candidates"])
+}
+
+func TestRollbackWithoutKnownGoodImages(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "testdspa",
+			Namespace:   "testnamespace",
+			Annotations: map[string]string{rollbackAnnotation: "true"},
+		},
+	}
+
+	// Assert rollbacks fail until known-good images have been recorded
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := reconciler.ApplyKnownGoodImages(ctx, dspa, params)
+	assert.ErrorContains(t, err, "no known-good images found")
+}
@@ -0,0 +1,130 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	mf "github.com/manifestival/manifestival"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RouteEndpoint is the hostname of a Route or Ingress, and the annotations it's published in DNS with.
+type RouteEndpoint struct {
+	Component   string
+	Name        string
+	Namespace   string
+	Hostname    string
+	Annotations map[string]string
+}
+
+// SetupRouteHostnames generates the endpoint of every Route, and of the API gateway Ingress, by resource name. The
+// hostnames are generated from the externalDNS of the DSPA, or else from the operator's DSPO.RouteHostnameTemplate,
+// and Routes keep the hostname generated by the router without either.
+func (p *DSPAParams) SetupRouteHostnames() error {
+	p.RouteEndpoints = nil
+	source := config.RouteHostnameTemplateConfigName
+	hostnameTemplate := config.GetStringConfigWithDefault(config.RouteHostnameTemplateConfigName, "")
+	if p.ExternalDNS != nil {
+		source = "externalDNS hostnameTemplate"
+		hostnameTemplate = p.ExternalDNS.HostnameTemplate
+		if hostnameTemplate == "" {
+			hostnameTemplate = defaultExternalDNSHostnameTemplate
+		}
+		hostnameTemplate += "." + p.ExternalDNS.Domain
+	}
+	if hostnameTemplate == "" {
+		return nil
+	}
+	hostname, err := template.New("hostname").Option("missingkey=error").Parse(hostnameTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", source, err)
+	}
+
+	routes := map[string]string{
+		"api":   config.DerivedRouteName("ds-pipeline-", p.Name, p.Namespace),
+		"grpc":  config.DerivedRouteName("ds-pipeline-grpc-", p.Name, p.Namespace),
+		"ui":    config.DerivedRouteName("ds-pipeline-ui-", p.Name, p.Namespace),
+		"minio": config.DerivedRouteName("minio-", p.Name, p.Namespace),
+	}
+	hosts := make(map[string]string)
+	if p.APIServer != nil && p.APIServer.GRPC != nil && p.APIServer.GRPC.Host != "" {
+		hosts["grpc"] = p.APIServer.GRPC.Host
+	}
+
+	p.RouteEndpoints = make(map[string]*RouteEndpoint)
+	for component, route := range routes {
+		endpoint := &RouteEndpoint{Component: component, Name: p.Name, Namespace: p.Namespace, Hostname: hosts[component]}
+		if endpoint.Hostname == "" {
+			var rendered strings.Builder
+			if err := hostname.Execute(&rendered, endpoint); err != nil {
+				return fmt.Errorf("invalid %s: %w", source, err)
+			}
+			endpoint.Hostname = rendered.String()
+		}
+		if errs := validation.IsDNS1123Subdomain(endpoint.Hostname); len(errs) > 0 {
+			return fmt.Errorf("invalid hostname [%s] generated for the %s Route: %s", endpoint.Hostname, component, strings.Join(errs, ", "))
+		}
+		for _, label := range strings.Split(endpoint.Hostname, ".") {
+			if len(label) > validation.DNS1123LabelMaxLength {
+				return fmt.Errorf("hostname [%s] generated for the %s Route has a label longer than %d characters, shorten it with the %s",
+					endpoint.Hostname, component, validation.DNS1123LabelMaxLength, source)
+			}
+		}
+		p.RouteEndpoints[route] = endpoint
+	}
+	if p.APIGateway != nil {
+		p.RouteEndpoints[p.APIGatewayDefaultResourceName] = &RouteEndpoint{
+			Component: "api", Name: p.Name, Namespace: p.Namespace, Hostname: p.APIGateway.Host,
+		}
+	}
+	return p.setupExternalDNSAnnotations()
+}
+
+// injectRouteHostnames sets the host of Routes to their generated hostname, and adds the annotations of their
+// endpoint to Routes and the API gateway Ingress.
+func injectRouteHostnames(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Route" && u.GetKind() != "Ingress" {
+			return nil
+		}
+		endpoint := params.RouteEndpoints[u.GetName()]
+		if endpoint == nil {
+			return nil
+		}
+		if u.GetKind() == "Route" {
+			if err := unstructured.SetNestedField(u.Object, endpoint.Hostname, "spec", "host"); err != nil {
+				return err
+			}
+		}
+		if len(endpoint.Annotations) == 0 {
+			return nil
+		}
+		annotations := u.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		for key, value := range endpoint.Annotations {
+			annotations[key] = value
+		}
+		u.SetAnnotations(annotations)
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSetupRouteHostnamesFromOperatorTemplate(t *testing.T) {
+	viper.Set(config.RouteHostnameTemplateConfigName, "{{.Component}}.{{.Name}}.{{.Namespace}}.pipelines.example.com")
+	t.Cleanup(func() { viper.Set(config.RouteHostnameTemplateConfigName, "") })
+	params := &DSPAParams{Name: "testdspa", Namespace: "testnamespace"}
+
+	// Ensure every DSPA gets hostnames from the operator template, without external-dns annotations
+	assert.Nil(t, params.SetupRouteHostnames())
+	api := params.RouteEndpoints["ds-pipeline-testdspa"]
+	assert.Equal(t, "api.testdspa.testnamespace.pipelines.example.com", api.Hostname)
+	assert.Empty(t, api.Annotations)
+	assert.Equal(t, "grpc.testdspa.testnamespace.pipelines.example.com", params.RouteEndpoints["ds-pipeline-grpc-testdspa"].Hostname)
+
+	// Ensure the externalDNS of the DSPA takes precedence
+	params.ExternalDNS = &dspav1alpha1.ExternalDNS{Domain: "example.org"}
+	assert.Nil(t, params.SetupRouteHostnames())
+	api = params.RouteEndpoints["ds-pipeline-testdspa"]
+	assert.Equal(t, "api-testdspa-testnamespace.example.org", api.Hostname)
+	assert.Equal(t, "api-testdspa-testnamespace.example.org", api.Annotations[externalDNSHostnameAnnotation])
+
+	// Ensure labels of the operator template longer than 63 characters are rejected
+	params.ExternalDNS = nil
+	viper.Set(config.RouteHostnameTemplateConfigName, "{{.Component}}-{{.Name}}.pipelines.example.com")
+	params.Name = "a-very-long-dspa-name-that-does-not-fit-into-one-dns-label-xyz"
+	assert.NotNil(t, params.SetupRouteHostnames())
+}
+
+func TestInjectRouteHostnames(t *testing.T) {
+	params := &DSPAParams{
+		Name:        "testdspa",
+		Namespace:   "testnamespace",
+		ExternalDNS: &dspav1alpha1.ExternalDNS{Domain: "pipelines.example.com"},
+	}
+	assert.Nil(t, params.SetupRouteHostnames())
+	transform := injectRouteHostnames(params)
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "Route",
+		"metadata": map[string]interface{}{"name": "ds-pipeline-ui-testdspa", "annotations": map[string]interface{}{"kubernetes.io/tls-acme": "true"}},
+		"spec":     map[string]interface{}{},
+	}}
+	assert.Nil(t, transform(route))
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	assert.Equal(t, "ui-testdspa-testnamespace.pipelines.example.com", host)
+	assert.Equal(t, map[string]string{
+		"kubernetes.io/tls-acme":      "true",
+		externalDNSHostnameAnnotation: "ui-testdspa-testnamespace.pipelines.example.com",
+	}, route.GetAnnotations())
+
+	// Ensure other resources are left alone
+	service := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "Service",
+		"metadata": map[string]interface{}{"name": "ds-pipeline-ui-testdspa"},
+	}}
+	assert.Nil(t, transform(service))
+	assert.Empty(t, service.GetAnnotations())
+}
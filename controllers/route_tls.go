@@ -0,0 +1,114 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RouteCertificate is the certificate a Reencrypt Route serves, read from the Secret of its RouteTLS.
+type RouteCertificate struct {
+	Certificate   string
+	Key           string
+	CACertificate string
+}
+
+type componentRouteTLS struct {
+	component string
+	route     string
+	routeTLS  **dspav1alpha1.RouteTLS
+}
+
+// SetupRouteTLS defaults the TLS termination of the API Server and UI Routes, and reads the certificates the Routes
+// serve with Reencrypt termination, by Route name.
+func (p *DSPAParams) SetupRouteTLS(ctx context.Context, client client.Client) error {
+	p.RouteCertificates = make(map[string]*RouteCertificate)
+	var routes []componentRouteTLS
+	if p.APIServer != nil {
+		routes = append(routes, componentRouteTLS{"apiServer", config.DerivedRouteName("ds-pipeline-", p.Name, p.Namespace), &p.APIServer.RouteTLS})
+	}
+	if p.MlPipelineUI != nil {
+		routes = append(routes, componentRouteTLS{"mlpipelineUI", config.DerivedRouteName("ds-pipeline-ui-", p.Name, p.Namespace), &p.MlPipelineUI.RouteTLS})
+	}
+
+	for _, r := range routes {
+		if *r.routeTLS == nil {
+			*r.routeTLS = &dspav1alpha1.RouteTLS{}
+		}
+		routeTLS := *r.routeTLS
+		setStringDefault("Reencrypt", &routeTLS.Termination)
+		if routeTLS.Termination == "Passthrough" && routeTLS.SecretName == "" {
+			return fmt.Errorf("%s routeTLS Passthrough termination requires a secretName, the clients verify the oauth-proxy's certificate", r.component)
+		}
+		if routeTLS.Termination != "Reencrypt" || routeTLS.SecretName == "" {
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		err := client.Get(ctx, types.NamespacedName{Name: routeTLS.SecretName, Namespace: p.Namespace}, secret)
+		if err != nil {
+			return fmt.Errorf("unable to read the %s Route certificate from secret [%s]: %w", r.component, routeTLS.SecretName, err)
+		}
+		certificate := &RouteCertificate{
+			Certificate:   string(secret.Data[corev1.TLSCertKey]),
+			Key:           string(secret.Data[corev1.TLSPrivateKeyKey]),
+			CACertificate: string(secret.Data["ca.crt"]),
+		}
+		if certificate.Certificate == "" || certificate.Key == "" {
+			return fmt.Errorf("secret [%s] of the %s Route certificate must hold a %s and a %s", routeTLS.SecretName, r.component,
+				corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+		}
+		p.RouteCertificates[r.route] = certificate
+	}
+	return nil
+}
+
+// injectRouteCertificates sets the certificate Reencrypt Routes serve from their RouteCertificate. Routes without one
+// serve the router's default certificate.
+func injectRouteCertificates(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Route" {
+			return nil
+		}
+		certificate := params.RouteCertificates[u.GetName()]
+		if certificate == nil {
+			return nil
+		}
+		fields := map[string]string{
+			"certificate":   certificate.Certificate,
+			"key":           certificate.Key,
+			"caCertificate": certificate.CACertificate,
+		}
+		for field, value := range fields {
+			if value == "" {
+				continue
+			}
+			if err := unstructured.SetNestedField(u.Object, value, "spec", "tls", field); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSetupRouteTLS(t *testing.T) {
+	ctx, params, reconciler := CreateNewTestObjects()
+	params.Name = "testdspa"
+	params.Namespace = "testnamespace"
+	params.APIServer = &dspav1alpha1.APIServer{Deploy: true}
+	params.MlPipelineUI = &dspav1alpha1.MlPipelineUI{Deploy: true, RouteTLS: &dspav1alpha1.RouteTLS{SecretName: "ui-certificate"}}
+
+	// Ensure a missing certificate Secret is reported
+	assert.NotNil(t, params.SetupRouteTLS(ctx, reconciler.Client))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ui-certificate", Namespace: "testnamespace"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("testcert"), corev1.TLSPrivateKeyKey: []byte("testkey")},
+	}
+	assert.Nil(t, reconciler.Create(ctx, secret))
+	assert.Nil(t, params.SetupRouteTLS(ctx, reconciler.Client))
+
+	// Ensure Routes default to Reencrypt, and only certificates of Reencrypt Routes are read
+	assert.Equal(t, "Reencrypt", params.APIServer.RouteTLS.Termination)
+	assert.Equal(t, "Reencrypt", params.MlPipelineUI.RouteTLS.Termination)
+	assert.Equal(t, map[string]*RouteCertificate{
+		"ds-pipeline-ui-testdspa": {Certificate: "testcert", Key: "testkey"},
+	}, params.RouteCertificates)
+	params.MlPipelineUI.RouteTLS.Termination = "Passthrough"
+	assert.Nil(t, params.SetupRouteTLS(ctx, reconciler.Client))
+	assert.Empty(t, params.RouteCertificates)
+
+	// Ensure Passthrough requires a certificate, and certificates require a key
+	params.APIServer.RouteTLS = &dspav1alpha1.RouteTLS{Termination: "Passthrough"}
+	assert.NotNil(t, params.SetupRouteTLS(ctx, reconciler.Client))
+	params.APIServer.RouteTLS = &dspav1alpha1.RouteTLS{SecretName: "ui-certificate"}
+	delete(secret.Data, corev1.TLSPrivateKeyKey)
+	assert.Nil(t, reconciler.Update(ctx, secret))
+	assert.NotNil(t, params.SetupRouteTLS(ctx, reconciler.Client))
+}
+
+func TestInjectRouteCertificates(t *testing.T) {
+	params := &DSPAParams{RouteCertificates: map[string]*RouteCertificate{
+		"ds-pipeline-testdspa": {Certificate: "testcert", Key: "testkey", CACertificate: "testca"},
+	}}
+	transform := injectRouteCertificates(params)
+
+	newRoute := func(name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":     "Route",
+			"metadata": map[string]interface{}{"name": name},
+			"spec":     map[string]interface{}{"tls": map[string]interface{}{"termination": "Reencrypt"}},
+		}}
+	}
+	route := newRoute("ds-pipeline-testdspa")
+	assert.Nil(t, transform(route))
+	tls, _, _ := unstructured.NestedStringMap(route.Object, "spec", "tls")
+	assert.Equal(t, map[string]string{
+		"termination":   "Reencrypt",
+		"certificate":   "testcert",
+		"key":           "testkey",
+		"caCertificate": "testca",
+	}, tls)
+
+	// Ensure Routes without a certificate keep the router's default certificate
+	route = newRoute("ds-pipeline-ui-testdspa")
+	assert.Nil(t, transform(route))
+	tls, _, _ = unstructured.NestedStringMap(route.Object, "spec", "tls")
+	assert.Equal(t, map[string]string{"termination": "Reencrypt"}, tls)
+}
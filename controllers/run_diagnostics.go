@@ -0,0 +1,161 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// runDiagnosticsAnnotation is set on a failed PipelineRun to the object store key of its diagnostics bundle
+const runDiagnosticsAnnotation = "datasciencepipelinesapplications.opendatahub.io/diagnostics"
+
+// runDiagnosticsObjectKey returns the object store key of the diagnostics bundle of the PipelineRun.
+func runDiagnosticsObjectKey(pipelineRun unstructured.Unstructured) string {
+	return path.Join("diagnostics", pipelineRun.GetName(), "diagnostics.tgz")
+}
+
+// snapshotYAML returns the object as YAML, without its managed fields.
+func snapshotYAML(obj unstructured.Unstructured) ([]byte, error) {
+	snapshot := obj.DeepCopy()
+	snapshot.SetManagedFields(nil)
+	return yaml.Marshal(snapshot.Object)
+}
+
+// stepContainers returns the containers of the steps and sidecars of the TaskRun, as reported in its status.
+func stepContainers(taskRun unstructured.Unstructured) []string {
+	var containers []string
+	for _, field := range []string{"steps", "sidecars"} {
+		statuses, _, _ := unstructured.NestedSlice(taskRun.Object, "status", field)
+		for _, s := range statuses {
+			status, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if container, _ := status["container"].(string); container != "" {
+				containers = append(containers, container)
+			}
+		}
+	}
+	return containers
+}
+
+// readStepLogs returns the logs of a container of a step pod, bounded as by GetStepLogs.
+func (r *DSPAReconciler) readStepLogs(ctx context.Context, namespace, pod, container string) ([]byte, error) {
+	logs, err := GetStepLogs(ctx, r.Clientset, namespace, pod, container)
+	if err != nil {
+		return nil, err
+	}
+	defer logs.Close()
+	return io.ReadAll(logs)
+}
+
+// runDiagnostics returns the files of the diagnostics bundle of the PipelineRun: the PipelineRun, its TaskRuns, and
+// the logs of every container of the step pods that still exist.
+func (r *DSPAReconciler) runDiagnostics(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	pipelineRun unstructured.Unstructured, taskRuns []unstructured.Unstructured) ([]archiveFile, error) {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	snapshot, err := snapshotYAML(pipelineRun)
+	if err != nil {
+		return nil, err
+	}
+	files := []archiveFile{{name: "pipelinerun.yaml", contents: snapshot}}
+	for _, taskRun := range taskRuns {
+		if taskRun.GetLabels()[pipelineRunLabel] != pipelineRun.GetName() {
+			continue
+		}
+		snapshot, err := snapshotYAML(taskRun)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, archiveFile{name: path.Join("taskruns", taskRun.GetName()+".yaml"), contents: snapshot})
+
+		pod, _, _ := unstructured.NestedString(taskRun.Object, "status", "podName")
+		if pod == "" {
+			continue
+		}
+		for _, container := range stepContainers(taskRun) {
+			logs, err := r.readStepLogs(ctx, taskRun.GetNamespace(), pod, container)
+			if err != nil {
+				log.V(1).Info(fmt.Sprintf("Unable to retrieve the logs of container [%s] of step pod [%s], leaving them out of the diagnostics of PipelineRun [%s]: %s", container, pod, pipelineRun.GetName(), err))
+				continue
+			}
+			files = append(files, archiveFile{name: path.Join("logs", taskRun.GetName(), container+".log"), contents: logs})
+		}
+	}
+	return files, nil
+}
+
+// CaptureRunDiagnostics uploads a diagnostics bundle of the PipelineRuns of the DSPA namespace that failed within the
+// lookback window to the DSPA object store, so failures can be investigated after the PipelineRun and its pods are
+// garbage collected. Captured PipelineRuns are annotated with the key of their bundle, so they're only captured once.
+func (r *DSPAReconciler) CaptureRunDiagnostics(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams, taskRuns []unstructured.Unstructured, now time.Time) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	pipelineRuns, err := r.listPipelineRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, pipelineRun := range pipelineRuns {
+		finishedAt, finished := pipelineRunCompletionTime(pipelineRun)
+		if !finished || now.Sub(finishedAt) > config.RunNotificationLookback {
+			continue
+		}
+		if status, _, ok := succeededCondition(pipelineRun); !ok || status != "Failed" {
+			continue
+		}
+		if _, captured := pipelineRun.GetAnnotations()[runDiagnosticsAnnotation]; captured {
+			continue
+		}
+
+		files, err := r.runDiagnostics(ctx, dsp, pipelineRun, taskRuns)
+		if err != nil {
+			return err
+		}
+		archive, err := tarGzFiles(files, now)
+		if err != nil {
+			return err
+		}
+		key := runDiagnosticsObjectKey(pipelineRun)
+		if err := r.uploadToDSPAObjStore(ctx, dsp, params, key, archive); err != nil {
+			return fmt.Errorf("unable to upload the diagnostics of PipelineRun [%s] to [%s]: %w", pipelineRun.GetName(), key, err)
+		}
+		log.Info(fmt.Sprintf("Captured the diagnostics of failed PipelineRun [%s] in [%s]", pipelineRun.GetName(), key))
+
+		patch := client.MergeFrom(pipelineRun.DeepCopy())
+		annotations := pipelineRun.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[runDiagnosticsAnnotation] = key
+		pipelineRun.SetAnnotations(annotations)
+		if err := r.Patch(ctx, &pipelineRun, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+func TestCaptureRunDiagnostics(t *testing.T) {
+	uploads := make(map[string][]byte)
+	defer func(getStepLogs func(context.Context, kubernetes.Interface, string, string, string) (io.ReadCloser, error),
+		upload func(context.Context, logr.Logger, string, string, string, []byte, []byte, bool, []byte, []byte) error) {
+		GetStepLogs = getStepLogs
+		UploadToObjStore = upload
+	}(GetStepLogs, UploadToObjStore)
+	GetStepLogs = func(ctx context.Context, clientset kubernetes.Interface, namespace, pod, container string) (io.ReadCloser, error) {
+		if container == "sidecar-gone" {
+			return nil, errors.New("container not found")
+		}
+		return io.NopCloser(strings.NewReader(container + " logs of " + pod)), nil
+	}
+	UploadToObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket, key string, accesskey, secretkey []byte, secure bool, pemCerts []byte, contents []byte) error {
+		uploads[key] = contents
+		return nil
+	}
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:       &dspav1alpha1.APIServer{},
+			Database:        &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage:   &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+			StepExitHandler: &dspav1alpha1.StepExitHandler{CaptureRunDiagnostics: true},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	now := time.Now().Truncate(time.Second)
+	finished := now.Add(-time.Minute)
+	failed := newTestPipelineRun("somerun", "testnamespace", &finished, false)
+	succeeded := newTestPipelineRun("otherrun", "testnamespace", &finished, false)
+	for status, run := range map[string]*unstructured.Unstructured{"False": failed, "True": succeeded} {
+		_ = unstructured.SetNestedSlice(run.Object, []interface{}{
+			map[string]interface{}{"type": "Succeeded", "status": status},
+		}, "status", "conditions")
+		assert.Nil(t, reconciler.Create(ctx, run))
+	}
+	train := newTestTaskRun("somerun-train", "train", "False", finished)
+	_ = unstructured.SetNestedSlice(train.Object, []interface{}{
+		map[string]interface{}{"name": "main", "container": "step-main"},
+		map[string]interface{}{"name": "copy-artifacts", "container": "step-copy-artifacts"},
+	}, "status", "steps")
+	_ = unstructured.SetNestedSlice(train.Object, []interface{}{
+		map[string]interface{}{"name": "gone", "container": "sidecar-gone"},
+	}, "status", "sidecars")
+	other := newTestTaskRun("otherrun-train", "train", "True", finished)
+	other.SetLabels(map[string]string{pipelineRunLabel: "otherrun"})
+	taskRuns := []unstructured.Unstructured{*train, *other}
+
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, reconciler.CaptureRunDiagnostics(ctx, dspa, params, taskRuns, now))
+	}
+
+	// Ensure only the failed run is captured, with its TaskRuns and the logs of every container that still exists
+	assert.Len(t, uploads, 1)
+	gz, err := gzip.NewReader(bytes.NewReader(uploads["diagnostics/somerun/diagnostics.tgz"]))
+	assert.Nil(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		contents, _ := io.ReadAll(tr)
+		files[header.Name] = string(contents)
+	}
+	assert.Len(t, files, 4)
+	assert.Contains(t, files["pipelinerun.yaml"], "name: somerun")
+	assert.Contains(t, files["taskruns/somerun-train.yaml"], "name: somerun-train")
+	assert.Equal(t, "step-main logs of somerun-train-pod", files["logs/somerun-train/step-main.log"])
+	assert.Equal(t, "step-copy-artifacts logs of somerun-train-pod", files["logs/somerun-train/step-copy-artifacts.log"])
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(failed.GroupVersionKind())
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "somerun", Namespace: "testnamespace"}, got))
+	assert.Equal(t, "diagnostics/somerun/diagnostics.tgz", got.GetAnnotations()[runDiagnosticsAnnotation])
+}
@@ -0,0 +1,99 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// persistedFinalStateLabel is set on a PipelineRun by the Persistence Agent once its final state is reported to the APIServer
+const persistedFinalStateLabel = "pipeline/persistedFinalState"
+
+var pipelineRunListGVK = schema.GroupVersionKind{
+	Group:   "tekton.dev",
+	Version: "v1beta1",
+	Kind:    "PipelineRunList",
+}
+
+// listPipelineRuns lists the Tekton PipelineRuns of the DSPA namespace, which DSP runs are executed as.
+func (r *DSPAReconciler) listPipelineRuns(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	pipelineRuns := &unstructured.UnstructuredList{}
+	pipelineRuns.SetGroupVersionKind(pipelineRunListGVK)
+	err := r.List(ctx, pipelineRuns, client.InNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+	return pipelineRuns.Items, nil
+}
+
+// pipelineRunCompletionTime returns when the PipelineRun finished, or false if it is still running.
+func pipelineRunCompletionTime(pipelineRun unstructured.Unstructured) (time.Time, bool) {
+	completionTime, found, err := unstructured.NestedString(pipelineRun.Object, "status", "completionTime")
+	if err != nil || !found {
+		return time.Time{}, false
+	}
+	finishedAt, err := time.Parse(time.RFC3339, completionTime)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return finishedAt, true
+}
+
+// RunReportBacklog returns the number of finished PipelineRuns in the DSPA namespace whose final state has not
+// yet been reported by the Persistence Agent, along with how long ago the oldest of them finished.
+func (r *DSPAReconciler) RunReportBacklog(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, now time.Time) (int, time.Duration, error) {
+	pipelineRuns, err := r.listPipelineRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	unreported := 0
+	var lag time.Duration
+	for _, pipelineRun := range pipelineRuns {
+		if pipelineRun.GetLabels()[persistedFinalStateLabel] == "true" {
+			continue
+		}
+		finishedAt, finished := pipelineRunCompletionTime(pipelineRun)
+		if !finished {
+			continue
+		}
+		unreported++
+		if now.Sub(finishedAt) > lag {
+			lag = now.Sub(finishedAt)
+		}
+	}
+	return unreported, lag, nil
+}
+
+// PublishRunReportMetrics publishes the Persistence Agent report backlog of the DSPA, so the lag between a run
+// finishing and its status being updated in the APIServer can be alerted on.
+func (r *DSPAReconciler) PublishRunReportMetrics(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	unreported, lag, err := r.RunReportBacklog(ctx, dsp, time.Now())
+	if err != nil {
+		log.V(1).Info("Unable to list PipelineRuns, skipping run report metrics", "error", err.Error())
+		return
+	}
+	UnreportedRunsMetric.WithLabelValues(dsp.Name, dsp.Namespace).Set(float64(unreported))
+	RunReportLagMetric.WithLabelValues(dsp.Name, dsp.Namespace).Set(lag.Seconds())
+}
@@ -0,0 +1,74 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestPipelineRun(name, namespace string, completionTime *time.Time, reported bool) *unstructured.Unstructured {
+	pipelineRun := &unstructured.Unstructured{}
+	pipelineRun.SetAPIVersion("tekton.dev/v1beta1")
+	pipelineRun.SetKind("PipelineRun")
+	pipelineRun.SetName(name)
+	pipelineRun.SetNamespace(namespace)
+	if reported {
+		pipelineRun.SetLabels(map[string]string{persistedFinalStateLabel: "true"})
+	}
+	if completionTime != nil {
+		_ = unstructured.SetNestedField(pipelineRun.Object, completionTime.Format(time.RFC3339), "status", "completionTime")
+	}
+	return pipelineRun
+}
+
+func TestRunReportBacklog(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{}
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	ctx, _, reconciler := CreateNewTestObjects()
+	now := time.Now().Truncate(time.Second)
+	finishedLongAgo := now.Add(-15 * time.Minute)
+	finishedRecently := now.Add(-time.Minute)
+
+	// Only finished runs without a persisted final state count towards the backlog
+	pipelineRuns := []*unstructured.Unstructured{
+		newTestPipelineRun("running", testNamespace, nil, false),
+		newTestPipelineRun("reported", testNamespace, &finishedLongAgo, true),
+		newTestPipelineRun("unreported-old", testNamespace, &finishedLongAgo, false),
+		newTestPipelineRun("unreported-new", testNamespace, &finishedRecently, false),
+		newTestPipelineRun("other-namespace", "othernamespace", &finishedLongAgo, false),
+	}
+	for _, pipelineRun := range pipelineRuns {
+		err := reconciler.Create(ctx, pipelineRun)
+		assert.Nil(t, err)
+	}
+
+	unreported, lag, err := reconciler.RunReportBacklog(ctx, dspa, now)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, unreported)
+	assert.Equal(t, 15*time.Minute, lag)
+}
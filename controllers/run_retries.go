@@ -0,0 +1,149 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// runRetriesAnnotation holds the number of times the operator retried a failed PipelineRun
+	runRetriesAnnotation = "datasciencepipelinesapplications.opendatahub.io/retries"
+	// runRetriedAtAnnotation records when the PipelineRun was last retried, so each failure is retried once
+	runRetriedAtAnnotation = "datasciencepipelinesapplications.opendatahub.io/retried-at"
+)
+
+var runRetryClient = &http.Client{Timeout: 30 * time.Second}
+
+// runRetrySkippedReasons are the reasons of the Succeeded condition of PipelineRuns stopped on purpose, by a user or a
+// timeout, which are not retried.
+var runRetrySkippedReasons = map[string]bool{
+	"Cancelled":            true,
+	"PipelineRunCancelled": true,
+	"StoppedRunFinally":    true,
+	"CancelledRunFinally":  true,
+	"PipelineRunStopped":   true,
+	"PipelineRunTimeout":   true,
+}
+
+// UsingRunRetries returns true if the DSPA retries failed runs through its API Server.
+func (p *DSPAParams) UsingRunRetries(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	return dsp.Spec.APIServer != nil && dsp.Spec.APIServer.Deploy && dsp.Spec.APIServer.RunRetryPolicy != nil
+}
+
+// runRetryBackoff returns how long to wait after a run that was already retried the given number of times fails again
+// before retrying it.
+func runRetryBackoff(policy *dspav1alpha1.RunRetryPolicy, retries int) time.Duration {
+	backoff := config.DefaultRunRetryBackoff
+	if policy.Backoff != nil {
+		backoff = policy.Backoff.Duration
+	}
+	maxBackoff := config.DefaultRunRetryMaxBackoff
+	if policy.MaxBackoff != nil {
+		maxBackoff = policy.MaxBackoff.Duration
+	}
+	for i := 0; i < retries && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// retryRun calls the retry API of the DSP API Server, which reruns the failed steps of the run.
+func retryRun(ctx context.Context, baseURL, runID string) error {
+	endpoint := fmt.Sprintf("%s/apis/v1beta1/runs/%s/retry", baseURL, url.PathEscape(runID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := runRetryClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("retry of run [%s] failed with status [%d]", runID, resp.StatusCode)
+	}
+	return nil
+}
+
+// RetryFailedRuns retries the failed PipelineRuns of the DSPA namespace through the API Server, up to the MaxRetries
+// of the DSPA's RunRetryPolicy, once the backoff has passed since they failed. Runs that failed longer ago than the
+// backoff and the lookback window are left alone, so enabling the policy doesn't retry the whole history of the
+// namespace. Retries are recorded on the PipelineRun once the API Server accepted them, so a failed call is attempted
+// again on the next reconcile without using up a retry.
+func (r *DSPAReconciler) RetryFailedRuns(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, now time.Time) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	policy := dsp.Spec.APIServer.RunRetryPolicy
+
+	pipelineRuns, err := r.listPipelineRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return err
+	}
+	baseURL := apiServerURL(dsp.Namespace, dsp.Name, apiServerHTTPPort(dsp))
+	for _, pipelineRun := range pipelineRuns {
+		runID := pipelineRun.GetLabels()[runIDLabel]
+		finishedAt, finished := pipelineRunCompletionTime(pipelineRun)
+		status, reason, ok := succeededCondition(pipelineRun)
+		if runID == "" || !finished || !ok || status != "Failed" || runRetrySkippedReasons[reason] {
+			continue
+		}
+		annotations := pipelineRun.GetAnnotations()
+		if _, timedOut := annotations[timedOutAnnotation]; timedOut {
+			continue
+		}
+		retries, _ := strconv.Atoi(annotations[runRetriesAnnotation])
+		if retries >= policy.MaxRetries {
+			continue
+		}
+		if retriedAt, err := time.Parse(time.RFC3339, annotations[runRetriedAtAnnotation]); err == nil && !finishedAt.After(retriedAt) {
+			// The API Server hasn't rerun the run since it was last retried
+			continue
+		}
+		backoff := runRetryBackoff(policy, retries)
+		failedFor := now.Sub(finishedAt)
+		if failedFor < backoff || failedFor > backoff+config.RunNotificationLookback {
+			continue
+		}
+
+		log.Info(fmt.Sprintf("Retrying run [%s] of PipelineRun [%s], retry %d of %d", runID, pipelineRun.GetName(), retries+1, policy.MaxRetries))
+		if err := retryRun(ctx, baseURL, runID); err != nil {
+			return err
+		}
+		patch := client.MergeFrom(pipelineRun.DeepCopy())
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[runRetriesAnnotation] = strconv.Itoa(retries + 1)
+		annotations[runRetriedAtAnnotation] = now.Format(time.RFC3339)
+		pipelineRun.SetAnnotations(annotations)
+		if err := r.Patch(ctx, &pipelineRun, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestFailedPipelineRun(name, reason string, completionTime time.Time, annotations map[string]string) *unstructured.Unstructured {
+	pipelineRun := newTestPipelineRun(name, "testnamespace", &completionTime, false)
+	pipelineRun.SetLabels(map[string]string{runIDLabel: name + "-id"})
+	pipelineRun.SetAnnotations(annotations)
+	_ = unstructured.SetNestedSlice(pipelineRun.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": "False", "reason": reason},
+	}, "status", "conditions")
+	return pipelineRun
+}
+
+func TestRunRetryBackoff(t *testing.T) {
+	policy := &dspav1alpha1.RunRetryPolicy{MaxRetries: 5}
+	assert.Equal(t, time.Minute, runRetryBackoff(policy, 0))
+	assert.Equal(t, 4*time.Minute, runRetryBackoff(policy, 2))
+
+	policy.Backoff = &metav1.Duration{Duration: 10 * time.Minute}
+	policy.MaxBackoff = &metav1.Duration{Duration: 30 * time.Minute}
+	assert.Equal(t, 20*time.Minute, runRetryBackoff(policy, 1))
+	assert.Equal(t, 30*time.Minute, runRetryBackoff(policy, 4))
+}
+
+func TestRetryFailedRuns(t *testing.T) {
+	var retried []string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		retried = append(retried, r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	defaultAPIServerURL := apiServerURL
+	apiServerURL = func(namespace, name, port string) string { return server.URL }
+	t.Cleanup(func() { apiServerURL = defaultAPIServerURL })
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy: true,
+				RunRetryPolicy: &dspav1alpha1.RunRetryPolicy{
+					MaxRetries: 2,
+					Backoff:    &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.True(t, params.UsingRunRetries(dspa))
+	now := time.Now().Truncate(time.Second)
+
+	runs := []*unstructured.Unstructured{
+		newTestFailedPipelineRun("failed", "Failed", now.Add(-10*time.Minute), nil),
+		newTestFailedPipelineRun("backing-off", "Failed", now.Add(-time.Minute), nil),
+		// Retried once 20m ago, so waits 10m after failing again
+		newTestFailedPipelineRun("failed-again", "Failed", now.Add(-15*time.Minute), map[string]string{
+			runRetriesAnnotation:   "1",
+			runRetriedAtAnnotation: now.Add(-20 * time.Minute).Format(time.RFC3339),
+		}),
+		// Retried, but not rerun by the API Server yet
+		newTestFailedPipelineRun("retry-pending", "Failed", now.Add(-15*time.Minute), map[string]string{
+			runRetriesAnnotation:   "1",
+			runRetriedAtAnnotation: now.Add(-10 * time.Minute).Format(time.RFC3339),
+		}),
+		newTestFailedPipelineRun("out-of-retries", "Failed", now.Add(-time.Hour), map[string]string{
+			runRetriesAnnotation:   "2",
+			runRetriedAtAnnotation: now.Add(-2 * time.Hour).Format(time.RFC3339),
+		}),
+		newTestFailedPipelineRun("cancelled", "PipelineRunCancelled", now.Add(-10*time.Minute), nil),
+		newTestFailedPipelineRun("timed-out", "Failed", now.Add(-10*time.Minute), map[string]string{timedOutAnnotation: "72h0m0s"}),
+		newTestFailedPipelineRun("historical", "Failed", now.Add(-48*time.Hour), nil),
+	}
+	for _, run := range runs {
+		assert.Nil(t, reconciler.Create(ctx, run))
+	}
+	assert.Nil(t, reconciler.RetryFailedRuns(ctx, dspa, now))
+
+	// Ensure only failed runs past their backoff and with retries left are retried
+	assert.ElementsMatch(t, []string{
+		"/apis/v1beta1/runs/failed-id/retry",
+		"/apis/v1beta1/runs/failed-again-id/retry",
+	}, retried)
+	expectedRetries := map[string]string{
+		"failed":         "1",
+		"backing-off":    "",
+		"failed-again":   "2",
+		"retry-pending":  "1",
+		"out-of-retries": "2",
+		"cancelled":      "",
+		"timed-out":      "",
+		"historical":     "",
+	}
+	for _, run := range runs {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(run.GroupVersionKind())
+		assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: run.GetName(), Namespace: run.GetNamespace()}, got))
+		assert.Equal(t, expectedRetries[run.GetName()], got.GetAnnotations()[runRetriesAnnotation], run.GetName())
+	}
+
+	// Ensure a retry isn't repeated before the API Server reruns the run
+	retried = nil
+	assert.Nil(t, reconciler.RetryFailedRuns(ctx, dspa, now.Add(time.Minute)))
+	assert.Empty(t, retried)
+
+	// Ensure a retry the API Server rejects isn't recorded, so it's attempted again
+	failing := newTestFailedPipelineRun("retry-rejected", "Failed", now.Add(-10*time.Minute), nil)
+	assert.Nil(t, reconciler.Create(ctx, failing))
+	status = http.StatusServiceUnavailable
+	assert.ErrorContains(t, reconciler.RetryFailedRuns(ctx, dspa, now.Add(time.Minute)), "failed with status [503]")
+	assert.Equal(t, []string{"/apis/v1beta1/runs/retry-rejected-id/retry"}, retried)
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: failing.GetName(), Namespace: failing.GetNamespace()}, failing))
+	assert.NotContains(t, failing.GetAnnotations(), runRetriesAnnotation)
+}
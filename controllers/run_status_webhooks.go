@@ -0,0 +1,410 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// runStatusWebhookAnnotationPrefix is suffixed with the webhook name to track deliveries on each PipelineRun,
+	// the annotation holds the number of failed attempts until the event is delivered or dropped
+	runStatusWebhookAnnotationPrefix = "webhooks.datasciencepipelinesapplications.opendatahub.io/"
+	runStatusWebhookDelivered        = "delivered"
+	runStatusWebhookDropped          = "dropped"
+	runStatusWebhookSignatureHeader  = "X-DSP-Signature-256"
+	runIDLabel                       = "pipeline/runid"
+)
+
+var runStatusWebhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// sharedAddressSpace is the carrier-grade NAT range, which some cluster networks use for their internal addresses
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// webhookTargetRestricted returns true for the addresses webhooks may not target, so DSPA editors can't have the
+// operator call endpoints on its network: loopback, link-local, e.g. cloud metadata endpoints, and private networks,
+// which the Services and pods of the cluster are on. Extracted to a var for mocking in testing.
+var webhookTargetRestricted = func(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsPrivate() || sharedAddressSpace.Contains(ip)
+}
+
+// webhookHostAllowed returns true for the hosts the operator config allows webhooks to target whatever their
+// address, e.g. a self-hosted GitHub Enterprise or GitLab on a private network.
+func webhookHostAllowed(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range config.GetStringSliceConfigWithDefault(config.WebhookAllowedHostsConfigName, nil) {
+		if host == strings.ToLower(strings.TrimSuffix(allowed, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWebhookURL returns an error if the webhook URL isn't https, or targets a cluster-internal host or a
+// restricted address not allowed by the operator config. Hostnames are resolved to addresses, and checked again, when
+// connecting.
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("url [%s] must be an https URL", rawURL)
+	}
+	return validateOutboundHost(rawURL, u.Hostname())
+}
+
+// validateEgressProxyURL returns an error if the egress proxy URL of a DSPA targets a cluster-internal host or a
+// restricted address not allowed by the operator config, as the operator connects to it like to webhooks.
+func validateEgressProxyURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	return validateOutboundHost(rawURL, u.Hostname())
+}
+
+// validateOutboundHost returns an error if the host of the URL is cluster-internal or a restricted address, unless the
+// operator config allows it.
+func validateOutboundHost(rawURL, host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if webhookHostAllowed(host) {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if webhookTargetRestricted(ip) {
+			return fmt.Errorf("url [%s] targets the restricted address [%s]", rawURL, host)
+		}
+		return nil
+	}
+	// Names without a dot are resolved through the search domains of the cluster, e.g. to Services
+	if host == "localhost" || !strings.Contains(host, ".") || strings.HasSuffix(host, ".svc") ||
+		strings.HasSuffix(host, ".local") || strings.HasSuffix(host, ".internal") {
+		return fmt.Errorf("url [%s] targets the cluster-internal host [%s]", rawURL, host)
+	}
+	return nil
+}
+
+// restrictTransport returns a copy of transport refusing to connect to restricted addresses. The addresses hostnames
+// resolve to are checked, and connected to, right before dialing, so they can't be changed in between. The proxies of
+// the transport are only exempt when trusted, i.e. set up by the cluster administrators, as the proxy resolves the
+// webhook host itself; other proxies are checked like webhook hosts.
+func restrictTransport(transport *http.Transport, trustProxies bool) *http.Transport {
+	restricted := transport.Clone()
+	var proxies sync.Map
+	if proxy := transport.Proxy; proxy != nil && trustProxies {
+		restricted.Proxy = func(req *http.Request) (*url.URL, error) {
+			proxyURL, err := proxy(req)
+			if proxyURL != nil {
+				port := proxyURL.Port()
+				if port == "" {
+					port = map[string]string{"http": "80", "https": "443", "socks5": "1080"}[proxyURL.Scheme]
+				}
+				proxies.Store(net.JoinHostPort(proxyURL.Hostname(), port), true)
+			}
+			return proxyURL, err
+		}
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	restricted.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if _, proxied := proxies.Load(addr); proxied {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if webhookHostAllowed(host) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			if webhookTargetRestricted(ip.IP) {
+				return nil, fmt.Errorf("host [%s] resolves to the restricted address [%s]", host, ip.IP)
+			}
+		}
+		return dialer.DialContext(ctx, network, net.JoinHostPort(ips[0].IP.String(), port))
+	}
+	return restricted
+}
+
+// webhookHTTPClient returns the egress client of the DSPA, refusing to connect to restricted addresses. Only the proxy
+// of the operator's environment, e.g. the cluster-wide proxy, is trusted: the egress proxy of a DSPA is set by its
+// editors, so its address is restricted too.
+func (r *DSPAReconciler) webhookHTTPClient(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) (*http.Client, error) {
+	httpClient, err := r.egressHTTPClient(ctx, dsp)
+	if err != nil {
+		return nil, err
+	}
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	return &http.Client{Timeout: httpClient.Timeout, Transport: restrictTransport(transport, dsp.Spec.EgressProxy == nil)}, nil
+}
+
+// RunStatusEvent is the payload POSTed to run status webhooks.
+type RunStatusEvent struct {
+	DSPA           string `json:"dspa"`
+	Namespace      string `json:"namespace"`
+	PipelineRun    string `json:"pipelineRun"`
+	RunID          string `json:"runId,omitempty"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason,omitempty"`
+	CompletionTime string `json:"completionTime"`
+}
+
+// succeededCondition returns the status, "Succeeded" or "Failed", and reason of the Succeeded condition of a Tekton
+// run, or false if it has none.
+func succeededCondition(run unstructured.Unstructured) (string, string, bool) {
+	conditions, _, _ := unstructured.NestedSlice(run.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Succeeded" {
+			continue
+		}
+		status := "Failed"
+		if condition["status"] == "True" {
+			status = "Succeeded"
+		}
+		reason, _ := condition["reason"].(string)
+		return status, reason, true
+	}
+	return "", "", false
+}
+
+// newRunStatusEvent builds the event of a PipelineRun that reached a terminal state, or returns false if it hasn't.
+func newRunStatusEvent(dsp *dspav1alpha1.DataSciencePipelinesApplication, pipelineRun unstructured.Unstructured) (*RunStatusEvent, bool) {
+	finishedAt, finished := pipelineRunCompletionTime(pipelineRun)
+	if !finished {
+		return nil, false
+	}
+	status, reason, ok := succeededCondition(pipelineRun)
+	if !ok {
+		return nil, false
+	}
+	return &RunStatusEvent{
+		DSPA:           dsp.Name,
+		Namespace:      dsp.Namespace,
+		PipelineRun:    pipelineRun.GetName(),
+		RunID:          pipelineRun.GetLabels()[runIDLabel],
+		Status:         status,
+		Reason:         reason,
+		CompletionTime: finishedAt.Format(time.RFC3339),
+	}, true
+}
+
+// signRunStatusPayload returns the HMAC-SHA256 signature of payload in the format sent in the signature header.
+func signRunStatusPayload(key, payload []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func sendRunStatusWebhook(ctx context.Context, httpClient *http.Client, url string, signingKey, payload []byte) error {
+	if err := validateWebhookURL(url); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if signingKey != nil {
+		req.Header.Set(runStatusWebhookSignatureHeader, signRunStatusPayload(signingKey, payload))
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status [%d]", resp.StatusCode)
+	}
+	return nil
+}
+
+// getSecretKeyValue returns the value stored under the key of the referenced secret.
+func (r *DSPAReconciler) getSecretKeyValue(ctx context.Context, namespace string, secretKeyValue *dspav1alpha1.SecretKeyValue) ([]byte, error) {
+	secret := &v1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: secretKeyValue.Name, Namespace: namespace}, secret)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := secret.Data[secretKeyValue.Key]
+	if !ok {
+		return nil, fmt.Errorf("key [%s] not found in secret [%s]", secretKeyValue.Key, secretKeyValue.Name)
+	}
+	return value, nil
+}
+
+// DeliverRunStatusWebhooks notifies the DSPA's run status webhooks of PipelineRuns that reached a terminal state
+// since the webhook was added, per status.runStatusWebhooks, so enabling a webhook doesn't replay the whole history of
+// the namespace. Delivery state is tracked per webhook in an annotation on the PipelineRun, and failed deliveries are
+// retried on the next call until the webhook's MaxAttempts is reached. It is called by the NotificationDispatcher,
+// outside of Reconcile.
+func (r *DSPAReconciler) DeliverRunStatusWebhooks(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) error {
+	since := make(map[string]time.Time)
+	for _, webhookStatus := range dsp.Status.RunStatusWebhooks {
+		since[webhookStatus.Name] = webhookStatus.Since.Time
+	}
+	pipelineRuns, err := r.listPipelineRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return err
+	}
+
+	signingKeys, err := r.runStatusWebhookSigningKeys(ctx, dsp, dsp.Spec.RunStatusWebhooks)
+	if err != nil {
+		return err
+	}
+	httpClient, err := r.webhookHTTPClient(ctx, dsp)
+	if err != nil {
+		return err
+	}
+	defer httpClient.CloseIdleConnections()
+
+	for _, pipelineRun := range pipelineRuns {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		event, terminal := newRunStatusEvent(dsp, pipelineRun)
+		if !terminal {
+			continue
+		}
+		// Webhooks not yet in the status were added after the DSPA was last reconciled, they are delivered to once it is
+		finishedAt, _ := pipelineRunCompletionTime(pipelineRun)
+		var webhooks []dspav1alpha1.RunStatusWebhook
+		for _, webhook := range dsp.Spec.RunStatusWebhooks {
+			if webhookSince, found := since[webhook.Name]; found && !finishedAt.Before(webhookSince) {
+				webhooks = append(webhooks, webhook)
+			}
+		}
+		if len(webhooks) == 0 {
+			continue
+		}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		patch := client.MergeFrom(pipelineRun.DeepCopy())
+		annotations := pipelineRun.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		changed := r.deliverRunStatusWebhooks(ctx, httpClient, dsp, webhooks, signingKeys, annotations, "run status event of ["+event.PipelineRun+"]", payload)
+		if !changed {
+			continue
+		}
+		pipelineRun.SetAnnotations(annotations)
+		if err := r.Patch(ctx, &pipelineRun, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runStatusWebhookStatuses returns the delivery progress of the run status webhooks of the DSPA, keeping when each
+// of them was added.
+func runStatusWebhookStatuses(dsp *dspav1alpha1.DataSciencePipelinesApplication, now time.Time) []dspav1alpha1.RunStatusWebhookStatus {
+	var statuses []dspav1alpha1.RunStatusWebhookStatus
+	for _, webhook := range dsp.Spec.RunStatusWebhooks {
+		webhookStatus := dspav1alpha1.RunStatusWebhookStatus{Name: webhook.Name, Since: metav1.NewTime(now)}
+		for _, existing := range dsp.Status.RunStatusWebhooks {
+			if existing.Name == webhook.Name {
+				webhookStatus.Since = existing.Since
+			}
+		}
+		statuses = append(statuses, webhookStatus)
+	}
+	return statuses
+}
+
+// runStatusWebhookSigningKeys returns the signing keys of the webhooks that sign their payloads, by webhook name.
+func (r *DSPAReconciler) runStatusWebhookSigningKeys(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, webhooks []dspav1alpha1.RunStatusWebhook) (map[string][]byte, error) {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	signingKeys := make(map[string][]byte)
+	for _, webhook := range webhooks {
+		if webhook.SigningSecret == nil {
+			continue
+		}
+		key, err := r.getSecretKeyValue(ctx, dsp.Namespace, webhook.SigningSecret)
+		if err != nil {
+			log.Error(err, fmt.Sprintf("Unable to retrieve signing key for run status webhook [%s]", webhook.Name))
+			return nil, err
+		}
+		signingKeys[webhook.Name] = key
+	}
+	return signingKeys, nil
+}
+
+// deliverRunStatusWebhooks POSTs payload to each webhook it hasn't been delivered or dropped for yet, tracking the
+// delivery state of each webhook in annotations. Returns true if annotations changed.
+func (r *DSPAReconciler) deliverRunStatusWebhooks(ctx context.Context, httpClient *http.Client, dsp *dspav1alpha1.DataSciencePipelinesApplication, webhooks []dspav1alpha1.RunStatusWebhook,
+	signingKeys map[string][]byte, annotations map[string]string, event string, payload []byte) bool {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	changed := false
+	for _, webhook := range webhooks {
+		annotation := runStatusWebhookAnnotationPrefix + webhook.Name
+		state := annotations[annotation]
+		if state == runStatusWebhookDelivered || state == runStatusWebhookDropped {
+			continue
+		}
+		attempts, _ := strconv.Atoi(state)
+		attempts++
+
+		err := sendRunStatusWebhook(ctx, httpClient, webhook.URL, signingKeys[webhook.Name], payload)
+		switch {
+		case err == nil:
+			annotations[annotation] = runStatusWebhookDelivered
+		case attempts >= webhook.MaxAttempts:
+			log.Info(fmt.Sprintf("Dropping %s for webhook [%s] after %d attempts: %s", event, webhook.Name, attempts, err))
+			annotations[annotation] = runStatusWebhookDropped
+		default:
+			log.V(1).Info(fmt.Sprintf("Failed to deliver %s to webhook [%s], will retry: %s", event, webhook.Name, err))
+			annotations[annotation] = strconv.Itoa(attempts)
+		}
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,185 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newTestWebhookServer starts an https server webhooks on loopback addresses may be delivered to for the test
+func newTestWebhookServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewTLSServer(handler)
+	defaultClient, defaultRestricted := runStatusWebhookClient, webhookTargetRestricted
+	t.Cleanup(func() {
+		server.Close()
+		runStatusWebhookClient, webhookTargetRestricted = defaultClient, defaultRestricted
+	})
+	// Every httptest server shares the same certificate, so the client of one trusts them all
+	runStatusWebhookClient = server.Client()
+	webhookTargetRestricted = func(ip net.IP) bool { return !ip.IsLoopback() }
+	return server
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.Nil(t, validateWebhookURL("https://ci.example.com/hooks/dsp"))
+	for rawURL, problem := range map[string]string{
+		"http://ci.example.com/hooks/dsp":                   "must be an https URL",
+		"https://169.254.169.254/latest/meta-data":          "targets the restricted address [169.254.169.254]",
+		"https://10.0.0.1/hook":                             "targets the restricted address [10.0.0.1]",
+		"https://[::1]:8443/hook":                           "targets the restricted address [::1]",
+		"https://ds-pipeline-testdspa:8443/apis":            "targets the cluster-internal host [ds-pipeline-testdspa]",
+		"https://minio.testnamespace.svc.cluster.local/":    "targets the cluster-internal host [minio.testnamespace.svc.cluster.local]",
+		"https://metadata.google.internal/computeMetadata/": "targets the cluster-internal host [metadata.google.internal]",
+	} {
+		assert.ErrorContains(t, validateWebhookURL(rawURL), problem)
+	}
+
+	// Ensure hostnames resolving to restricted addresses are refused when connecting
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer server.Close()
+	httpClient := &http.Client{Transport: restrictTransport(server.Client().Transport.(*http.Transport), false)}
+	_, err := httpClient.Get(strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	assert.ErrorContains(t, err, "host [localhost] resolves to the restricted address")
+}
+
+func TestDeliverRunStatusWebhooks(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+
+	// Record events received by a CI system, and fail deliveries to a broken endpoint
+	var received []RunStatusEvent
+	ci := newTestWebhookServer(t, func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		event := RunStatusEvent{}
+		assert.Nil(t, json.Unmarshal(body, &event))
+		received = append(received, event)
+		assert.Equal(t, signRunStatusPayload([]byte("hmackey"), body), req.Header.Get(runStatusWebhookSignatureHeader))
+	})
+	broken := newTestWebhookServer(t, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			RunStatusWebhooks: []dspav1alpha1.RunStatusWebhook{
+				{
+					Name:          "ci",
+					URL:           ci.URL,
+					SigningSecret: &dspav1alpha1.SecretKeyValue{Name: "webhook-secret", Key: "key"},
+					MaxAttempts:   3,
+				},
+				{
+					Name:        "broken",
+					URL:         broken.URL,
+					MaxAttempts: 2,
+				},
+			},
+		},
+	}
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	ctx, _, reconciler := CreateNewTestObjects()
+	secret := &v1.Secret{Data: map[string][]byte{"key": []byte("hmackey")}}
+	secret.Name = "webhook-secret"
+	secret.Namespace = testNamespace
+	assert.Nil(t, reconciler.Create(ctx, secret))
+
+	now := time.Now().Truncate(time.Second)
+	finished := now.Add(-time.Minute)
+	delayed := now.Add(-3 * time.Hour)
+	historical := now.Add(-48 * time.Hour)
+	dspa.Status.RunStatusWebhooks = runStatusWebhookStatuses(dspa, now.Add(-24*time.Hour))
+	succeeded := newTestPipelineRun("succeeded", testNamespace, &finished, false)
+	_ = unstructured.SetNestedSlice(succeeded.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": "True", "reason": "Succeeded"},
+	}, "status", "conditions")
+	succeeded.SetLabels(map[string]string{runIDLabel: "run-1"})
+	old := newTestPipelineRun("old", testNamespace, &historical, false)
+	_ = unstructured.SetNestedSlice(old.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": "False", "reason": "Failed"},
+	}, "status", "conditions")
+	late := newTestPipelineRun("late", testNamespace, &delayed, false)
+	_ = unstructured.SetNestedSlice(late.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": "False", "reason": "Failed"},
+	}, "status", "conditions")
+	running := newTestPipelineRun("running", testNamespace, nil, false)
+	for _, pipelineRun := range []*unstructured.Unstructured{succeeded, old, late, running} {
+		assert.Nil(t, reconciler.Create(ctx, pipelineRun))
+	}
+
+	// Only the runs finished since the webhooks were added are delivered, however long ago, and only once
+	for i := 0; i < 3; i++ {
+		err := reconciler.DeliverRunStatusWebhooks(ctx, dspa)
+		assert.Nil(t, err)
+	}
+	assert.Len(t, received, 2)
+	assert.ElementsMatch(t, []string{"late", "succeeded"}, []string{received[0].PipelineRun, received[1].PipelineRun})
+	if received[0].PipelineRun == "late" {
+		received = received[1:]
+	}
+	assert.Equal(t, RunStatusEvent{
+		DSPA:           testDSPAName,
+		Namespace:      testNamespace,
+		PipelineRun:    "succeeded",
+		RunID:          "run-1",
+		Status:         "Succeeded",
+		Reason:         "Succeeded",
+		CompletionTime: finished.Format(time.RFC3339),
+	}, received[0])
+
+	// Ensure delivery state is tracked per webhook, and the broken webhook is dropped after MaxAttempts
+	pipelineRun := &unstructured.Unstructured{}
+	pipelineRun.SetGroupVersionKind(succeeded.GroupVersionKind())
+	err := reconciler.Get(ctx, types.NamespacedName{Name: "succeeded", Namespace: testNamespace}, pipelineRun)
+	assert.Nil(t, err)
+	assert.Equal(t, runStatusWebhookDelivered, pipelineRun.GetAnnotations()[runStatusWebhookAnnotationPrefix+"ci"])
+	assert.Equal(t, runStatusWebhookDropped, pipelineRun.GetAnnotations()[runStatusWebhookAnnotationPrefix+"broken"])
+}
+
+func TestRunStatusWebhookStatuses(t *testing.T) {
+	added := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	now := time.Now().Truncate(time.Second)
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{RunStatusWebhooks: []dspav1alpha1.RunStatusWebhook{{Name: "ci"}, {Name: "chatops"}}},
+		Status: dspav1alpha1.DSPAStatus{RunStatusWebhooks: []dspav1alpha1.RunStatusWebhookStatus{
+			{Name: "ci", Since: added},
+			{Name: "removed", Since: added},
+		}},
+	}
+
+	// Ensure webhooks keep when they were added, new ones are added now, and removed ones are dropped
+	assert.Equal(t, []dspav1alpha1.RunStatusWebhookStatus{
+		{Name: "ci", Since: added},
+		{Name: "chatops", Since: metav1.NewTime(now)},
+	}, runStatusWebhookStatuses(dspa, now))
+}
@@ -0,0 +1,161 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// timedOutAnnotation records the default timeout a PipelineRun or TaskRun was terminated after
+	timedOutAnnotation   = "datasciencepipelinesapplications.opendatahub.io/timed-out-after"
+	pipelineRunLabel     = "tekton.dev/pipelineRun"
+	taskRunCancelled     = "TaskRunCancelled"
+	defaultTerminateMode = "Cancelled"
+)
+
+var taskRunListGVK = schema.GroupVersionKind{
+	Group:   "tekton.dev",
+	Version: "v1beta1",
+	Kind:    "TaskRunList",
+}
+
+// listPipelineTaskRuns lists the Tekton TaskRuns of the DSPA namespace that run the steps of a PipelineRun.
+func (r *DSPAReconciler) listPipelineTaskRuns(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	taskRuns := &unstructured.UnstructuredList{}
+	taskRuns.SetGroupVersionKind(taskRunListGVK)
+	err := r.List(ctx, taskRuns, client.InNamespace(namespace), client.HasLabels{pipelineRunLabel})
+	if err != nil {
+		return nil, err
+	}
+	return taskRuns.Items, nil
+}
+
+// UsingRunTimeouts returns true if the DSPA terminates runs or steps without a timeout.
+func (p *DSPAParams) UsingRunTimeouts(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	return dsp.Spec.APIServer != nil && (dsp.Spec.APIServer.DefaultRunTimeout != nil || dsp.Spec.APIServer.DefaultStepTimeout != nil)
+}
+
+// hasTimeout returns true if the Tekton run sets a non zero timeout, which Tekton enforces itself. Tekton defaults
+// runs without one to the cluster's default timeout, so only runs explicitly disabling it have "0s".
+func hasTimeout(run unstructured.Unstructured, fields ...[]string) bool {
+	for _, field := range fields {
+		timeout, found, err := unstructured.NestedString(run.Object, field...)
+		if err != nil || !found {
+			continue
+		}
+		if duration, err := time.ParseDuration(timeout); err != nil || duration > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// runningFor returns how long the Tekton run has been running, or false if it hasn't started or has finished.
+func runningFor(run unstructured.Unstructured, now time.Time) (time.Duration, bool) {
+	if _, finished := pipelineRunCompletionTime(run); finished {
+		return 0, false
+	}
+	if status, _, _ := unstructured.NestedString(run.Object, "spec", "status"); status != "" {
+		return 0, false
+	}
+	startTime, found, err := unstructured.NestedString(run.Object, "status", "startTime")
+	if err != nil || !found {
+		return 0, false
+	}
+	startedAt, err := time.Parse(time.RFC3339, startTime)
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(startedAt), true
+}
+
+// terminateRun sets the spec.status of the Tekton run, for Tekton to stop it, recording the timeout it ran past.
+func (r *DSPAReconciler) terminateRun(ctx context.Context, run unstructured.Unstructured, status string, timeout time.Duration) error {
+	patch := client.MergeFrom(run.DeepCopy())
+	if err := unstructured.SetNestedField(run.Object, status, "spec", "status"); err != nil {
+		return err
+	}
+	annotations := run.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[timedOutAnnotation] = timeout.String()
+	run.SetAnnotations(annotations)
+	return r.Patch(ctx, &run, patch)
+}
+
+// EnforceRunTimeouts terminates the runs and steps of the DSPA namespace that have been running for longer than the
+// DSPA's default timeouts, and don't set a timeout of their own. Runs are terminated with the API Server's
+// TerminateStatus, so finally tasks run as they would when the run is terminated from the API.
+func (r *DSPAReconciler) EnforceRunTimeouts(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, now time.Time) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	apiServer := dsp.Spec.APIServer
+
+	if apiServer.DefaultRunTimeout != nil {
+		pipelineRuns, err := r.listPipelineRuns(ctx, dsp.Namespace)
+		if err != nil {
+			return err
+		}
+		terminateStatus := apiServer.TerminateStatus
+		if terminateStatus == "" {
+			terminateStatus = defaultTerminateMode
+		}
+		timeout := apiServer.DefaultRunTimeout.Duration
+		for _, pipelineRun := range pipelineRuns {
+			if hasTimeout(pipelineRun, []string{"spec", "timeout"}, []string{"spec", "timeouts", "pipeline"}) {
+				continue
+			}
+			running, ok := runningFor(pipelineRun, now)
+			if !ok || running <= timeout {
+				continue
+			}
+			log.Info(fmt.Sprintf("Terminating PipelineRun [%s], running for longer than the default run timeout [%s]", pipelineRun.GetName(), timeout))
+			if err := r.terminateRun(ctx, pipelineRun, terminateStatus, timeout); err != nil {
+				return err
+			}
+		}
+	}
+
+	if apiServer.DefaultStepTimeout != nil {
+		taskRuns, err := r.listPipelineTaskRuns(ctx, dsp.Namespace)
+		if err != nil {
+			return err
+		}
+		timeout := apiServer.DefaultStepTimeout.Duration
+		for _, taskRun := range taskRuns {
+			if hasTimeout(taskRun, []string{"spec", "timeout"}) {
+				continue
+			}
+			running, ok := runningFor(taskRun, now)
+			if !ok || running <= timeout {
+				continue
+			}
+			log.Info(fmt.Sprintf("Cancelling TaskRun [%s], running for longer than the default step timeout [%s]", taskRun.GetName(), timeout))
+			if err := r.terminateRun(ctx, taskRun, taskRunCancelled, timeout); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestTektonRun(kind, name string, startTime time.Time, timeout string) *unstructured.Unstructured {
+	run := &unstructured.Unstructured{}
+	run.SetAPIVersion("tekton.dev/v1beta1")
+	run.SetKind(kind)
+	run.SetName(name)
+	run.SetNamespace("testnamespace")
+	if kind == "TaskRun" {
+		run.SetLabels(map[string]string{pipelineRunLabel: "somerun"})
+	}
+	_ = unstructured.SetNestedField(run.Object, startTime.Format(time.RFC3339), "status", "startTime")
+	if timeout != "" {
+		_ = unstructured.SetNestedField(run.Object, timeout, "spec", "timeout")
+	}
+	return run
+}
+
+func TestEnforceRunTimeouts(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				TerminateStatus:    "CancelledRunFinally",
+				DefaultRunTimeout:  &metav1.Duration{Duration: 72 * time.Hour},
+				DefaultStepTimeout: &metav1.Duration{Duration: 24 * time.Hour},
+			},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.True(t, params.UsingRunTimeouts(dspa))
+	now := time.Now().Truncate(time.Second)
+
+	runs := []*unstructured.Unstructured{
+		newTestTektonRun("PipelineRun", "hanging", now.Add(-100*time.Hour), "0s"),
+		newTestTektonRun("PipelineRun", "explicit-timeout", now.Add(-100*time.Hour), "168h0m0s"),
+		newTestTektonRun("PipelineRun", "recent", now.Add(-time.Hour), "0s"),
+		newTestTektonRun("TaskRun", "hanging-step", now.Add(-30*time.Hour), "0s"),
+		newTestTektonRun("TaskRun", "step-with-timeout", now.Add(-30*time.Hour), "48h0m0s"),
+	}
+	for _, run := range runs {
+		assert.Nil(t, reconciler.Create(ctx, run))
+	}
+	assert.Nil(t, reconciler.EnforceRunTimeouts(ctx, dspa, now))
+
+	// Ensure only runs and steps without a timeout running past the defaults are terminated
+	expectedStatuses := map[string]string{
+		"PipelineRun/hanging":          "CancelledRunFinally",
+		"PipelineRun/explicit-timeout": "",
+		"PipelineRun/recent":           "",
+		"TaskRun/hanging-step":         taskRunCancelled,
+		"TaskRun/step-with-timeout":    "",
+	}
+	for _, run := range runs {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(run.GroupVersionKind())
+		assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: run.GetName(), Namespace: run.GetNamespace()}, got))
+		status, _, _ := unstructured.NestedString(got.Object, "spec", "status")
+		expected := expectedStatuses[run.GetKind()+"/"+run.GetName()]
+		assert.Equal(t, expected, status, run.GetKind()+"/"+run.GetName())
+		if expected != "" {
+			assert.NotEmpty(t, got.GetAnnotations()[timedOutAnnotation])
+		}
+	}
+}
@@ -0,0 +1,276 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+	// The time zones of the blackout windows don't depend on the tzdata of the operator image
+	_ "time/tzdata"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// schedulePolicyWebhookTemplate is cluster scoped, so it can't be owned by the DSPA and is deleted explicitly
+const schedulePolicyWebhookTemplate = "schedule-policy/webhook.yaml.tmpl"
+
+// SchedulePolicyWebhookPath is the path of the operator's webhook server the schedule policy webhooks call
+const SchedulePolicyWebhookPath = "/hold-pipeline-runs"
+
+const (
+	// heldRunLabel is set on the PipelineRuns queued during a blackout window, to the name of the DSPA holding them
+	heldRunLabel          = "datasciencepipelinesapplications.opendatahub.io/held-by-schedule-policy"
+	pipelineRunPending    = "PipelineRunPending"
+	submittedRunsQueue    = "Queue"
+	submittedRunsReject   = "Reject"
+	blackoutTimeOfDayForm = "15:04"
+)
+
+var blackoutWindowDays = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// schedulePolicyNow is the time the schedule policy webhooks admit runs at
+var schedulePolicyNow = time.Now
+
+// blackoutActive returns true if now is within one of the blackout windows of the policy, along with when that
+// changes: the end of the window now is in, or the start of the next one.
+func blackoutActive(policy *dspav1alpha1.SchedulePolicy, now time.Time) (bool, time.Time, error) {
+	var active bool
+	var changesAt time.Time
+	for i, window := range policy.BlackoutWindows {
+		zone := window.TimeZone
+		if zone == "" {
+			zone = "UTC"
+		}
+		location, err := time.LoadLocation(zone)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("schedulePolicy blackoutWindows[%d] timeZone [%s] is not a known time zone", i, zone)
+		}
+		start, err := time.Parse(blackoutTimeOfDayForm, window.Start)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("schedulePolicy blackoutWindows[%d] start [%s] is not a time of the day, e.g. 22:00", i, window.Start)
+		}
+		end, err := time.Parse(blackoutTimeOfDayForm, window.End)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("schedulePolicy blackoutWindows[%d] end [%s] is not a time of the day, e.g. 06:00", i, window.End)
+		}
+		days := make(map[time.Weekday]bool)
+		for _, day := range window.Days {
+			weekday, ok := blackoutWindowDays[day]
+			if !ok {
+				return false, time.Time{}, fmt.Errorf("schedulePolicy blackoutWindows[%d] day [%s] is not one of Mon, Tue, Wed, Thu, Fri, Sat, Sun", i, day)
+			}
+			days[weekday] = true
+		}
+
+		// Windows start at most a day before now if they end after midnight, and at most a week after now
+		local := now.In(location)
+		for offset := -1; offset <= 7; offset++ {
+			day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, location)
+			if len(days) > 0 && !days[day.Weekday()] {
+				continue
+			}
+			windowStart := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, location)
+			windowEnd := time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, location)
+			if !windowEnd.After(windowStart) {
+				windowEnd = windowEnd.AddDate(0, 0, 1)
+			}
+			switch {
+			case !now.Before(windowStart) && now.Before(windowEnd):
+				if !active || windowEnd.Before(changesAt) {
+					changesAt = windowEnd
+				}
+				active = true
+			case windowStart.After(now) && !active && (changesAt.IsZero() || windowStart.Before(changesAt)):
+				changesAt = windowStart
+			}
+		}
+	}
+	return active, changesAt, nil
+}
+
+// SetupSchedulePolicy determines whether the DSPA is within one of its blackout windows, pausing its Scheduled
+// Workflow controller, and when that changes.
+func (p *DSPAParams) SetupSchedulePolicy(dsp *dspav1alpha1.DataSciencePipelinesApplication, now time.Time) error {
+	p.SchedulesPaused, p.SchedulePolicyChangesAt = false, time.Time{}
+	if dsp.Spec.SchedulePolicy == nil {
+		return nil
+	}
+	active, changesAt, err := blackoutActive(dsp.Spec.SchedulePolicy, now)
+	if err != nil {
+		return err
+	}
+	p.SchedulesPaused, p.SchedulePolicyChangesAt = active, changesAt
+	return nil
+}
+
+// UsingSchedulePolicyWebhook returns true if the DSPA queues or rejects the runs submitted during its blackout windows.
+func (p *DSPAParams) UsingSchedulePolicyWebhook(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	policy := dsp.Spec.SchedulePolicy
+	return policy != nil && (policy.SubmittedRuns == submittedRunsQueue || policy.SubmittedRuns == submittedRunsReject)
+}
+
+func (r *DSPAReconciler) ReconcileSchedulePolicy(dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	params.SchedulePolicyWebhookPath = SchedulePolicyWebhookPath
+	if !params.UsingSchedulePolicyWebhook(dsp) {
+		log.Info("Skipping Application of Schedule Policy Resources")
+		return r.DeleteResource(params, schedulePolicyWebhookTemplate)
+	}
+	if r.WebhookService == nil {
+		return fmt.Errorf("schedulePolicy submittedRuns [%s] requires the operator to serve its admission webhooks, "+
+			"it serves them once a serving certificate is mounted in its webhook certificate directory", dsp.Spec.SchedulePolicy.SubmittedRuns)
+	}
+
+	log.Info("Applying Schedule Policy Resources")
+	params.WebhookService = *r.WebhookService
+	err := r.ApplyWithoutOwner(params, schedulePolicyWebhookTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Schedule Policy Resources")
+	return nil
+}
+
+func (r *DSPAReconciler) CleanUpSchedulePolicy(params *DSPAParams) error {
+	params.SchedulePolicyWebhookPath = SchedulePolicyWebhookPath
+	return r.DeleteResource(params, schedulePolicyWebhookTemplate)
+}
+
+// ReleaseQueuedRuns starts the PipelineRuns the DSPA queued during a blackout window once the window ended, or the
+// schedule policy was removed.
+func (r *DSPAReconciler) ReleaseQueuedRuns(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) error {
+	if params.SchedulesPaused {
+		return nil
+	}
+	pipelineRuns := &unstructured.UnstructuredList{}
+	pipelineRuns.SetGroupVersionKind(pipelineRunListGVK)
+	err := r.List(ctx, pipelineRuns, client.InNamespace(dsp.Namespace), client.MatchingLabels{heldRunLabel: dsp.Name})
+	if err != nil {
+		return err
+	}
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+	for i := range pipelineRuns.Items {
+		run := &pipelineRuns.Items[i]
+		patch := client.MergeFrom(run.DeepCopy())
+		if status, _, _ := unstructured.NestedString(run.Object, "spec", "status"); status == pipelineRunPending {
+			unstructured.RemoveNestedField(run.Object, "spec", "status")
+		}
+		labels := run.GetLabels()
+		delete(labels, heldRunLabel)
+		run.SetLabels(labels)
+		if err := r.Patch(ctx, run, patch); err != nil {
+			return err
+		}
+		log.Info(fmt.Sprintf("Started PipelineRun [%s], queued during a blackout window", run.GetName()))
+	}
+	return nil
+}
+
+// requeueForSchedulePolicy requeues the DSPA when its blackout windows start or end, if the result doesn't requeue
+// it earlier.
+func requeueForSchedulePolicy(result ctrl.Result, params *DSPAParams, now time.Time) ctrl.Result {
+	return requeueAt(result, params.SchedulePolicyChangesAt, now)
+}
+
+// requeueAt requeues right after at, if it is set and the result doesn't requeue earlier.
+func requeueAt(result ctrl.Result, at, now time.Time) ctrl.Result {
+	if at.IsZero() {
+		return result
+	}
+	// Requeue right after the change, rather than right before it
+	after := at.Sub(now) + time.Second
+	if after < time.Second {
+		after = time.Second
+	}
+	return requeueAfter(result, after)
+}
+
+// SchedulePolicyWebhook queues or rejects the PipelineRuns submitted to a namespace during the blackout windows of
+// its DSPAs. Queued runs are created pending, and started by ReleaseQueuedRuns once the window ends.
+type SchedulePolicyWebhook struct {
+	Client client.Client
+	Log    logr.Logger
+}
+
+func (w *SchedulePolicyWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	run := &unstructured.Unstructured{}
+	if err := run.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	dspas := &dspav1alpha1.DataSciencePipelinesApplicationList{}
+	if err := w.Client.List(ctx, dspas, client.InNamespace(req.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	now := schedulePolicyNow()
+	for _, dspa := range dspas.Items {
+		policy := dspa.Spec.SchedulePolicy
+		if policy == nil || (policy.SubmittedRuns != submittedRunsQueue && policy.SubmittedRuns != submittedRunsReject) {
+			continue
+		}
+		// Invalid policies are reported by the reconcile of the DSPA
+		active, changesAt, err := blackoutActive(policy, now)
+		if err != nil || !active {
+			continue
+		}
+
+		if policy.SubmittedRuns == submittedRunsReject {
+			w.Log.Info("Rejected pipeline run during a blackout window", "namespace", req.Namespace, "dspa_name", dspa.Name,
+				"pipelinerun", run.GetName())
+			return admission.Denied(fmt.Sprintf("DSPA [%s] doesn't accept runs during its blackout windows, submit the run again after %s",
+				dspa.Name, changesAt.UTC().Format(time.RFC3339)))
+		}
+		// Runs created cancelled or already pending are left as they are
+		if status, _, _ := unstructured.NestedString(run.Object, "spec", "status"); status != "" {
+			return admission.Allowed("")
+		}
+		if err := unstructured.SetNestedField(run.Object, pipelineRunPending, "spec", "status"); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		labels := run.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[heldRunLabel] = dspa.Name
+		run.SetLabels(labels)
+		held, err := run.MarshalJSON()
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		w.Log.Info("Queued pipeline run during a blackout window", "namespace", req.Namespace, "dspa_name", dspa.Name,
+			"pipelinerun", run.GetName(), "until", changesAt.UTC().Format(time.RFC3339))
+		return admission.PatchResponseFromRaw(req.Object.Raw, held)
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,180 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newSchedulePolicyTestDSPA(policy *dspav1alpha1.SchedulePolicy) *dspav1alpha1.DataSciencePipelinesApplication {
+	return &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:         &dspav1alpha1.APIServer{Deploy: true},
+			ScheduledWorkflow: &dspav1alpha1.ScheduledWorkflow{Deploy: true, Image: "someimage"},
+			Database:          &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage:     &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+			SchedulePolicy:    policy,
+		},
+	}
+}
+
+func TestBlackoutActive(t *testing.T) {
+	nights := &dspav1alpha1.SchedulePolicy{BlackoutWindows: []dspav1alpha1.BlackoutWindow{
+		{Days: []string{"Sat"}, Start: "22:00", End: "06:00", TimeZone: "Europe/Berlin"},
+		{Start: "12:00", End: "12:30"},
+	}}
+	// Saturday 2023-10-07
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2023, 10, 7, hour, minute, 0, 0, time.UTC)
+	}
+	tests := map[string]struct {
+		now       time.Time
+		active    bool
+		changesAt time.Time
+	}{
+		"before the daily window":          {saturday(11, 0), false, saturday(12, 0)},
+		"in the daily window":              {saturday(12, 10), true, saturday(12, 30)},
+		"before the window of the weekday": {saturday(19, 0), false, saturday(20, 0)},
+		"in the window of the weekday":     {saturday(23, 0), true, saturday(28, 0)},
+		"after midnight in the window":     {saturday(27, 59), true, saturday(28, 0)},
+		"after the window of the weekday":  {saturday(28, 0), false, saturday(36, 0)},
+	}
+	for name, test := range tests {
+		active, changesAt, err := blackoutActive(nights, test.now)
+		assert.Nil(t, err, name)
+		assert.Equal(t, test.active, active, name)
+		assert.True(t, test.changesAt.Equal(changesAt), "%s: changes at %s", name, changesAt)
+	}
+
+	// Ensure invalid windows are reported rather than ignored
+	_, _, err := blackoutActive(&dspav1alpha1.SchedulePolicy{BlackoutWindows: []dspav1alpha1.BlackoutWindow{
+		{Start: "22:00", End: "06:00", TimeZone: "Mars/Olympus"},
+	}}, saturday(0, 0))
+	assert.ErrorContains(t, err, "timeZone [Mars/Olympus] is not a known time zone")
+}
+
+func TestDeploySchedulePolicy(t *testing.T) {
+	expectedWebhookName := "ds-pipeline-schedule-policy-testnamespace.testdspa"
+	dspa := newSchedulePolicyTestDSPA(&dspav1alpha1.SchedulePolicy{
+		BlackoutWindows: []dspav1alpha1.BlackoutWindow{{Start: "00:00", End: "00:00"}},
+		SubmittedRuns:   submittedRunsQueue,
+	})
+	ctx, params, reconciler := CreateNewTestObjects()
+	reconciler.WebhookService = &types.NamespacedName{Name: "dspo-service", Namespace: "dspo"}
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileScheduledWorkflow(dspa, params))
+	assert.Nil(t, reconciler.ReconcileSchedulePolicy(dspa, params))
+
+	// Ensure the Scheduled Workflow controller is scaled down during the window, and submitted runs are queued
+	assert.True(t, params.SchedulesPaused)
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: params.ScheduledWorkflowDefaultResourceName, Namespace: dspa.Namespace}, deployment))
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+	webhook := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedWebhookName}, webhook))
+	assert.Equal(t, SchedulePolicyWebhookPath, *webhook.Webhooks[0].ClientConfig.Service.Path)
+	assert.Equal(t, []string{"pipelineruns"}, webhook.Webhooks[0].Rules[0].Resources)
+
+	// Ensure the DSPA is requeued once the window ends
+	now := time.Now()
+	result := requeueForSchedulePolicy(ctrl.Result{RequeueAfter: 48 * time.Hour}, params, now)
+	assert.Equal(t, params.SchedulePolicyChangesAt.Sub(now)+time.Second, result.RequeueAfter)
+
+	// Ensure runs queued during the window are started, and the controller scaled up, once the policy is removed
+	held := newTestPipelineRun("held", dspa.Namespace, nil, false)
+	held.SetLabels(map[string]string{heldRunLabel: dspa.Name})
+	assert.Nil(t, unstructured.SetNestedField(held.Object, pipelineRunPending, "spec", "status"))
+	assert.Nil(t, reconciler.Create(ctx, held))
+	assert.Nil(t, reconciler.ReleaseQueuedRuns(ctx, dspa, params))
+	dspa.Spec.SchedulePolicy = nil
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileScheduledWorkflow(dspa, params))
+	assert.Nil(t, reconciler.ReconcileSchedulePolicy(dspa, params))
+	assert.Nil(t, reconciler.ReleaseQueuedRuns(ctx, dspa, params))
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "held", Namespace: dspa.Namespace}, held))
+	assert.NotContains(t, held.GetLabels(), heldRunLabel)
+	_, found, _ := unstructured.NestedString(held.Object, "spec", "status")
+	assert.False(t, found)
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: params.ScheduledWorkflowDefaultResourceName, Namespace: dspa.Namespace}, deployment))
+	assert.Equal(t, int32(1), *deployment.Spec.Replicas)
+	created, err := reconciler.IsResourceCreated(ctx, &admissionregistrationv1.MutatingWebhookConfiguration{}, expectedWebhookName, "")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
+
+func TestSchedulePolicyWebhook(t *testing.T) {
+	defaultSchedulePolicyNow := schedulePolicyNow
+	schedulePolicyNow = func() time.Time { return time.Date(2023, 10, 7, 23, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { schedulePolicyNow = defaultSchedulePolicyNow })
+
+	policy := &dspav1alpha1.SchedulePolicy{
+		BlackoutWindows: []dspav1alpha1.BlackoutWindow{{Start: "22:00", End: "06:00"}},
+		SubmittedRuns:   submittedRunsQueue,
+	}
+	dspa := newSchedulePolicyTestDSPA(policy)
+	ctx, _, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	webhook := &SchedulePolicyWebhook{Client: reconciler.Client, Log: reconciler.Log}
+	admit := func(run *unstructured.Unstructured) admission.Response {
+		raw, err := run.MarshalJSON()
+		assert.Nil(t, err)
+		return webhook.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: run.GetNamespace(),
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+	}
+
+	// Ensure runs submitted during the window are created pending, held by the DSPA
+	run := newTestPipelineRun("somerun", "testnamespace", nil, false)
+	assert.Nil(t, unstructured.SetNestedField(run.Object, "pipeline-runner-testdspa", "spec", "serviceAccountName"))
+	response := admit(run)
+	assert.True(t, response.Allowed)
+	patches := map[string]interface{}{}
+	for _, patch := range response.Patches {
+		patches[patch.Path] = patch.Value
+	}
+	assert.Equal(t, pipelineRunPending, patches["/spec/status"])
+	assert.Equal(t, map[string]interface{}{heldRunLabel: "testdspa"}, patches["/metadata/labels"])
+
+	// Ensure runs of other namespaces are left as they are
+	response = admit(newTestPipelineRun("somerun", "othernamespace", nil, false))
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Patches)
+
+	// Ensure runs are rejected during the window when the DSPA rejects them
+	policy.SubmittedRuns = submittedRunsReject
+	assert.Nil(t, reconciler.Update(ctx, dspa))
+	response = admit(newTestPipelineRun("somerun", "testnamespace", nil, false))
+	assert.False(t, response.Allowed)
+	assert.Contains(t, string(response.Result.Reason), "submit the run again after 2023-10-08T06:00:00Z")
+}
@@ -0,0 +1,175 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SetupScheduling maps the name of every component Deployment to its Scheduling.
+func (p *DSPAParams) SetupScheduling() {
+	p.Scheduling = make(map[string]*dspav1alpha1.Scheduling)
+	if p.APIServer != nil {
+		p.Scheduling[p.APIServerDefaultResourceName] = p.APIServer.Scheduling
+	}
+	if p.PersistenceAgent != nil {
+		p.Scheduling[p.PersistentAgentDefaultResourceName] = p.PersistenceAgent.Scheduling
+	}
+	if p.ScheduledWorkflow != nil {
+		p.Scheduling[p.ScheduledWorkflowDefaultResourceName] = p.ScheduledWorkflow.Scheduling
+	}
+	if p.MariaDB != nil {
+		p.Scheduling[config.DerivedName(config.MariaDBHostPrefix+"-", p.Name)] = p.MariaDB.Scheduling
+	}
+	if p.Minio != nil {
+		p.Scheduling[config.DerivedName(config.MinioHostPrefix+"-", p.Name)] = p.Minio.Scheduling
+	}
+	if p.MLMD != nil {
+		p.Scheduling[config.DerivedName("ds-pipeline-metadata-envoy-", p.Name)] = p.MLMD.Scheduling
+		p.Scheduling[config.DerivedName("ds-pipeline-metadata-grpc-", p.Name)] = p.MLMD.Scheduling
+		p.Scheduling[config.DerivedName("ds-pipeline-metadata-writer-", p.Name)] = p.MLMD.Scheduling
+	}
+}
+
+// injectScheduling adds the node selector, tolerations and affinity of their Scheduling to component Deployments. The
+// node selector of the templates, keeping pods on Linux nodes, and the architecture node affinity still apply, as does
+// the anti-affinity of their Placement.
+func injectScheduling(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" {
+			return nil
+		}
+		scheduling := params.Scheduling[u.GetName()]
+		if scheduling == nil {
+			return nil
+		}
+
+		if len(scheduling.NodeSelector) > 0 {
+			nodeSelector, _, err := unstructured.NestedStringMap(u.Object, "spec", "template", "spec", "nodeSelector")
+			if err != nil {
+				return err
+			}
+			if nodeSelector == nil {
+				nodeSelector = make(map[string]string)
+			}
+			for key, value := range scheduling.NodeSelector {
+				nodeSelector[key] = value
+			}
+			err = unstructured.SetNestedStringMap(u.Object, nodeSelector, "spec", "template", "spec", "nodeSelector")
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(scheduling.Tolerations) > 0 {
+			tolerations, _, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "tolerations")
+			if err != nil {
+				return err
+			}
+			for i := range scheduling.Tolerations {
+				toleration, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&scheduling.Tolerations[i])
+				if err != nil {
+					return err
+				}
+				tolerations = append(tolerations, toleration)
+			}
+			err = unstructured.SetNestedSlice(u.Object, tolerations, "spec", "template", "spec", "tolerations")
+			if err != nil {
+				return err
+			}
+		}
+
+		if scheduling.Affinity == nil {
+			return nil
+		}
+		affinity := &corev1.Affinity{}
+		rendered, found, err := unstructured.NestedMap(u.Object, "spec", "template", "spec", "affinity")
+		if err != nil {
+			return err
+		}
+		if found {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rendered, affinity); err != nil {
+				return err
+			}
+		}
+		mergeAffinity(affinity, scheduling.Affinity)
+		merged, err := runtime.DefaultUnstructuredConverter.ToUnstructured(affinity)
+		if err != nil {
+			return err
+		}
+		return unstructured.SetNestedMap(u.Object, merged, "spec", "template", "spec", "affinity")
+	}
+}
+
+// mergeAffinity adds the terms of the affinity of a Scheduling to the affinity rendered for a component.
+func mergeAffinity(affinity, added *corev1.Affinity) {
+	if added.NodeAffinity != nil {
+		if affinity.NodeAffinity == nil {
+			affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		nodeAffinity := affinity.NodeAffinity
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = mergeNodeSelectors(
+			nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, added.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+		nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			added.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
+	}
+	if added.PodAffinity != nil {
+		if affinity.PodAffinity == nil {
+			affinity.PodAffinity = &corev1.PodAffinity{}
+		}
+		podAffinity := affinity.PodAffinity
+		podAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(podAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			added.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			added.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
+	}
+	if added.PodAntiAffinity != nil {
+		if affinity.PodAntiAffinity == nil {
+			affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		podAntiAffinity := affinity.PodAntiAffinity
+		podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			added.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			added.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
+	}
+}
+
+// mergeNodeSelectors returns a node selector matching the nodes both node selectors match. Their terms are ORed, so
+// every term of one is combined with every term of the other.
+func mergeNodeSelectors(rendered, added *corev1.NodeSelector) *corev1.NodeSelector {
+	if rendered == nil || len(rendered.NodeSelectorTerms) == 0 {
+		return added
+	}
+	if added == nil || len(added.NodeSelectorTerms) == 0 {
+		return rendered
+	}
+	merged := &corev1.NodeSelector{}
+	for _, renderedTerm := range rendered.NodeSelectorTerms {
+		for _, addedTerm := range added.NodeSelectorTerms {
+			term := *renderedTerm.DeepCopy()
+			term.MatchExpressions = append(term.MatchExpressions, addedTerm.MatchExpressions...)
+			term.MatchFields = append(term.MatchFields, addedTerm.MatchFields...)
+			merged.NodeSelectorTerms = append(merged.NodeSelectorTerms, term)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,99 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+)
+
+const securityPostureTemplate = "common/security-posture.configmap.yaml.tmpl"
+
+// imageDigest returns the digest an image reference or image ID is pinned to, e.g. "sha256:...", if any.
+func imageDigest(image string) string {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// securityPosture returns the effective security settings of the DSPA as flat keys, so policies can audit them
+// without parsing the component workloads.
+func (p *DSPAParams) securityPosture(dsp *dspav1alpha1.DataSciencePipelinesApplication, imageIDs map[string][]string) map[string]string {
+	posture := map[string]string{
+		"objectStorage.external":   strconv.FormatBool(p.UsingExternalStorage(dsp)),
+		"objectStorage.endpoint":   p.ObjectStorageConnection.Endpoint,
+		"objectStorage.bucket":     p.ObjectStorageConnection.Bucket,
+		"objectStorage.secure":     strconv.FormatBool(p.ObjectStorageConnection.Secure != nil && *p.ObjectStorageConnection.Secure),
+		"database.external":        strconv.FormatBool(p.UsingExternalDB(dsp)),
+		"database.endpoint":        hostPort(p.DBConnection.Host, p.DBConnection.Port),
+		"readOnlyRootFilesystem":   strconv.FormatBool(p.ReadOnlyRootFilesystem),
+		"apiServer.customCABundle": strconv.FormatBool(p.APIServer != nil && p.APIServer.CABundle != nil),
+	}
+	if p.APIServer != nil && p.APIServer.Deploy && p.APIServer.EnableRoute {
+		posture["apiServer.routeTermination"] = p.APIServer.RouteTLS.Termination
+	}
+	if p.MlPipelineUI != nil && p.MlPipelineUI.Deploy && !p.DevMode {
+		posture["mlPipelineUI.routeTermination"] = p.MlPipelineUI.RouteTLS.Termination
+	}
+	if p.UsingAPIServerGRPC(dsp) {
+		posture["apiServerGRPC.routeTermination"] = p.APIServer.GRPC.Termination
+	}
+
+	for component, image := range p.componentImages(dsp) {
+		posture[component+".image"] = image
+		var digests []string
+		if digest := imageDigest(image); digest != "" {
+			digests = append(digests, digest)
+		}
+		for _, id := range imageIDs[image] {
+			if digest := imageDigest(id); digest != "" {
+				digests = append(digests, digest)
+			}
+		}
+		if len(digests) > 0 {
+			posture[component+".imageDigest"] = strings.Join(uniqueSorted(digests), ",")
+		}
+	}
+	return posture
+}
+
+// ReconcileSecurityPosture publishes the ds-pipeline-security-posture ConfigMap, listing the TLS settings, storage
+// endpoints and image digests of the DSPA. It's labeled so Gatekeeper can replicate it and audit pipeline stacks.
+func (r *DSPAReconciler) ReconcileSecurityPosture(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	log.Info("Applying Security Posture")
+
+	imageIDs, err := r.runningImageIDs(ctx, dsp)
+	if err != nil {
+		return err
+	}
+	params.SecurityPosture = params.securityPosture(dsp, imageIDs)
+
+	err = r.Apply(dsp, params, securityPostureTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Security Posture")
+	return nil
+}
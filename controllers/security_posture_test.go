@@ -0,0 +1,103 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestDeploySecurityPosture(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedSecurityPostureName := "ds-pipeline-security-posture-testdspa"
+
+	// Construct DSPASpec with a digest pinned PersistenceAgent and a tag pinned MariaDB
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			ReadOnlyRootFilesystem: true,
+			PersistenceAgent: &dspav1alpha1.PersistenceAgent{
+				Deploy: true,
+				Image:  "persistenceagent@sha256:abc",
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+					Image:  "mariadb:v1",
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Namespace = testNamespace
+	dspa.Name = testDSPAName
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Simulate a running MariaDB pod
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "mariadb", Image: "mariadb:v1"}}},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+			{Name: "mariadb", Image: "mariadb:v1", ImageID: "docker.io/library/mariadb@sha256:def"},
+		}},
+	}
+	pod.Name = "mariadb-pod"
+	pod.Namespace = testNamespace
+	pod.Labels = map[string]string{"component": "data-science-pipelines", "dspa": testDSPAName}
+	assert.Nil(t, reconciler.Create(ctx, pod))
+
+	// Run test reconciliation
+	err = reconciler.ReconcileSecurityPosture(ctx, dspa, params)
+	assert.Nil(t, err)
+
+	// Ensure the Security Posture lists the TLS settings, endpoints and digests of the DSPA, and can be selected by label
+	configMap := &v1.ConfigMap{}
+	created, err := reconciler.IsResourceCreated(ctx, configMap, expectedSecurityPostureName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, "true", configMap.Labels["datasciencepipelinesapplications.opendatahub.io/security-posture"])
+	assert.Equal(t, map[string]string{
+		"operatorVersion":              config.OperatorVersion,
+		"objectStorage.external":       "false",
+		"objectStorage.endpoint":       "http://minio-testdspa.testnamespace.svc.cluster.local:9000",
+		"objectStorage.bucket":         "mlpipeline",
+		"objectStorage.secure":         "false",
+		"database.external":            "false",
+		"database.endpoint":            "mariadb-testdspa.testnamespace.svc.cluster.local:3306",
+		"readOnlyRootFilesystem":       "true",
+		"apiServer.customCABundle":     "false",
+		"persistenceAgent.image":       "persistenceagent@sha256:abc",
+		"persistenceAgent.imageDigest": "sha256:abc",
+		"mariaDB.image":                "mariadb:v1",
+		"mariaDB.imageDigest":          "sha256:def",
+	}, configMap.Data)
+}
@@ -0,0 +1,119 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+// SetupSecurityProfiles maps the name of every component workload to its SecurityProfiles.
+func (p *DSPAParams) SetupSecurityProfiles() {
+	p.DefaultSeccompProfile = config.GetStringConfigWithDefault(config.DefaultSeccompProfileConfigName, "")
+	p.SecurityProfiles = make(map[string]*dspav1alpha1.SecurityProfiles)
+	if p.APIServer != nil {
+		p.SecurityProfiles[p.APIServerDefaultResourceName] = p.APIServer.SecurityProfiles
+	}
+	if p.PersistenceAgent != nil {
+		p.SecurityProfiles[p.PersistentAgentDefaultResourceName] = p.PersistenceAgent.SecurityProfiles
+	}
+	if p.ScheduledWorkflow != nil {
+		p.SecurityProfiles[p.ScheduledWorkflowDefaultResourceName] = p.ScheduledWorkflow.SecurityProfiles
+	}
+	if p.MariaDB != nil {
+		p.SecurityProfiles[config.DerivedName(config.MariaDBHostPrefix+"-", p.Name)] = p.MariaDB.SecurityProfiles
+	}
+	if p.Minio != nil {
+		p.SecurityProfiles[config.DerivedName(config.MinioHostPrefix+"-", p.Name)] = p.Minio.SecurityProfiles
+	}
+	if p.MlPipelineUI != nil {
+		p.SecurityProfiles[config.DerivedName("ds-pipeline-ui-", p.Name)] = p.MlPipelineUI.SecurityProfiles
+	}
+	if p.MLMD != nil {
+		p.SecurityProfiles[config.DerivedName("ds-pipeline-metadata-envoy-", p.Name)] = p.MLMD.Envoy.SecurityProfiles
+		p.SecurityProfiles[config.DerivedName("ds-pipeline-metadata-grpc-", p.Name)] = p.MLMD.GRPC.SecurityProfiles
+		p.SecurityProfiles[config.DerivedName("ds-pipeline-metadata-writer-", p.Name)] = p.MLMD.Writer.SecurityProfiles
+	}
+	if p.ImagePrepuller != nil {
+		p.SecurityProfiles[p.ImagePrepullerDefaultResourceName] = p.ImagePrepuller.SecurityProfiles
+	}
+	if p.CacheServer != nil {
+		p.SecurityProfiles[p.CacheServerDefaultResourceName] = p.CacheServer.SecurityProfiles
+	}
+	if p.Headroom != nil {
+		p.SecurityProfiles[p.HeadroomDefaultResourceName] = p.Headroom.SecurityProfiles
+	}
+}
+
+// injectSecurityProfiles sets the seccomp profile and AppArmor annotations of component workloads from their
+// SecurityProfiles, so hardened clusters don't need to patch deployments after reconciliation. Workloads without a
+// seccomp profile configured use the default seccomp profile type of the operator config, if any: the profile is
+// opt-in, as pods requesting one are rejected by the SCCs that don't allow it, e.g. restricted before OpenShift 4.11.
+func injectSecurityProfiles(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" && u.GetKind() != "DaemonSet" {
+			return nil
+		}
+		profiles := params.SecurityProfiles[u.GetName()]
+
+		var seccompProfile map[string]interface{}
+		if profiles != nil && profiles.SeccompProfile != nil {
+			seccompProfile = map[string]interface{}{"type": profiles.SeccompProfile.Type}
+			if profiles.SeccompProfile.LocalhostProfile != "" {
+				seccompProfile["localhostProfile"] = profiles.SeccompProfile.LocalhostProfile
+			}
+		} else if params.DefaultSeccompProfile != "" {
+			seccompProfile = map[string]interface{}{"type": params.DefaultSeccompProfile}
+		}
+		if seccompProfile != nil {
+			err := unstructured.SetNestedMap(u.Object, seccompProfile, "spec", "template", "spec", "securityContext", "seccompProfile")
+			if err != nil {
+				return err
+			}
+		}
+
+		if profiles == nil || profiles.AppArmorProfile == "" {
+			return nil
+		}
+		annotations, _, err := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "annotations")
+		if err != nil {
+			return err
+		}
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, _, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", field)
+			if err != nil {
+				return err
+			}
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if name, ok := container["name"].(string); ok {
+					annotations[appArmorAnnotationPrefix+name] = profiles.AppArmorProfile
+				}
+			}
+		}
+		return unstructured.SetNestedStringMap(u.Object, annotations, "spec", "template", "metadata", "annotations")
+	}
+}
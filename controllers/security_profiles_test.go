@@ -0,0 +1,113 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDeployWithSecurityProfiles(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedAPIServerName := apiServerDefaultResourceNamePrefix + testDSPAName
+	expectedPersistenceAgentName := persistenceAgentDefaultResourceNamePrefix + testDSPAName
+
+	// Construct DSPASpec with a localhost seccomp and AppArmor profile on the APIServer only
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy: true,
+				SecurityProfiles: &dspav1alpha1.SecurityProfiles{
+					SeccompProfile: &dspav1alpha1.SeccompProfile{
+						Type:             "Localhost",
+						LocalhostProfile: "profiles/ds-pipeline.json",
+					},
+					AppArmorProfile: "localhost/ds-pipeline",
+				},
+			},
+			PersistenceAgent: &dspav1alpha1.PersistenceAgent{
+				Deploy: true,
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Run test reconciliation
+	err = reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.Nil(t, err)
+	err = reconciler.ReconcilePersistenceAgent(dspa, params)
+	assert.Nil(t, err)
+
+	// Assert APIServer Deployment uses the configured profiles
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, expectedAPIServerName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	seccompProfile := deployment.Spec.Template.Spec.SecurityContext.SeccompProfile
+	assert.Equal(t, corev1.SeccompProfileTypeLocalhost, seccompProfile.Type)
+	assert.Equal(t, "profiles/ds-pipeline.json", *seccompProfile.LocalhostProfile)
+	assert.Equal(t, "localhost/ds-pipeline", deployment.Spec.Template.Annotations[appArmorAnnotationPrefix+"ds-pipeline-api-server"])
+
+	// Assert Persistence Agent Deployment has no seccomp profile nor AppArmor annotations by default
+	deployment = &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, expectedPersistenceAgentName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	securityContext := deployment.Spec.Template.Spec.SecurityContext
+	assert.True(t, securityContext == nil || securityContext.SeccompProfile == nil)
+	assert.NotContains(t, deployment.Spec.Template.Annotations, appArmorAnnotationPrefix+"ds-pipeline-persistenceagent")
+
+	// Opt into a default seccomp profile, and assert the Persistence Agent Deployment falls back to it
+	viper.Set(config.DefaultSeccompProfileConfigName, "RuntimeDefault")
+	t.Cleanup(func() { viper.Set(config.DefaultSeccompProfileConfigName, "") })
+	err = params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+	err = reconciler.ReconcilePersistenceAgent(dspa, params)
+	assert.Nil(t, err)
+	deployment = &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, expectedPersistenceAgentName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, deployment.Spec.Template.Spec.SecurityContext.SeccompProfile.Type)
+}
@@ -0,0 +1,119 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SetupServices maps the name of every component Service to its ComponentService.
+func (p *DSPAParams) SetupServices() {
+	p.Services = make(map[string]*dspav1alpha1.ComponentService)
+	if p.APIServer != nil {
+		p.Services[p.APIServerServiceName] = p.APIServer.Service
+	}
+	if p.MariaDB != nil {
+		p.Services[config.DerivedName(config.MariaDBHostPrefix+"-", p.Name)] = p.MariaDB.Service
+	}
+	if p.Minio != nil {
+		p.Services[config.DerivedName(config.MinioHostPrefix+"-", p.Name)] = p.Minio.Service
+	}
+	if p.MlPipelineUI != nil {
+		p.Services[config.DerivedName("ds-pipeline-ui-", p.Name)] = p.MlPipelineUI.Service
+	}
+	if p.MLMD != nil {
+		p.Services[config.DerivedName("ds-pipeline-metadata-envoy-", p.Name)] = p.MLMD.Envoy.Service
+		p.Services[config.DerivedName("ds-pipeline-metadata-grpc-", p.Name)] = p.MLMD.GRPC.Service
+	}
+}
+
+// ServicePort returns the port a component Service exposes as the named port, for the clients of the component.
+func (p *DSPAParams) ServicePort(service, port, defaultPort string) string {
+	return servicePort(p.Services[service], port, defaultPort)
+}
+
+func servicePort(service *dspav1alpha1.ComponentService, port, defaultPort string) string {
+	if service == nil {
+		return defaultPort
+	}
+	for _, override := range service.Ports {
+		if override.Name == port && override.Port != 0 {
+			return strconv.Itoa(int(override.Port))
+		}
+	}
+	return defaultPort
+}
+
+// injectServices sets the type, annotations and port overrides of component Services from their ComponentService, so
+// components can be reached on clusters without Routes or an Ingress controller.
+func injectServices(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Service" {
+			return nil
+		}
+		service := params.Services[u.GetName()]
+		if service == nil {
+			return nil
+		}
+
+		if service.Type != "" {
+			if err := unstructured.SetNestedField(u.Object, service.Type, "spec", "type"); err != nil {
+				return err
+			}
+		}
+		if len(service.Annotations) > 0 {
+			annotations := u.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			for key, value := range service.Annotations {
+				annotations[key] = value
+			}
+			u.SetAnnotations(annotations)
+		}
+
+		ports, _, err := unstructured.NestedSlice(u.Object, "spec", "ports")
+		if err != nil {
+			return err
+		}
+		for _, override := range service.Ports {
+			found := false
+			for _, p := range ports {
+				port, ok := p.(map[string]interface{})
+				if !ok || port["name"] != override.Name {
+					continue
+				}
+				found = true
+				if override.Port != 0 {
+					port["port"] = int64(override.Port)
+				}
+				if override.NodePort != 0 {
+					port["nodePort"] = int64(override.NodePort)
+				}
+			}
+			if !found {
+				return fmt.Errorf("service [%s] has no port named [%s]", u.GetName(), override.Name)
+			}
+		}
+		return unstructured.SetNestedSlice(u.Object, ports, "spec", "ports")
+	}
+}
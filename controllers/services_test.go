@@ -0,0 +1,102 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestInjectServices(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy: true,
+				Service: &dspav1alpha1.ComponentService{
+					Type:        "LoadBalancer",
+					Annotations: map[string]string{"metallb.universe.tf/address-pool": "pipelines"},
+					Ports:       []dspav1alpha1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}},
+				},
+			},
+			PersistenceAgent: &dspav1alpha1.PersistenceAgent{Deploy: true},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy:  true,
+					Service: &dspav1alpha1.ComponentService{Ports: []dspav1alpha1.ServicePort{{Name: "mysql", Port: 13306}}},
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+	assert.Nil(t, reconciler.ReconcilePersistenceAgent(dspa, params))
+
+	// Assert the API Server Service is exposed as configured, keeping the ports that aren't overridden
+	service := &v1.Service{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: params.APIServerServiceName, Namespace: dspa.Namespace}, service))
+	assert.Equal(t, v1.ServiceTypeLoadBalancer, service.Spec.Type)
+	assert.Equal(t, "pipelines", service.Annotations["metallb.universe.tf/address-pool"])
+	assert.Contains(t, service.Annotations, "service.alpha.openshift.io/serving-cert-secret-name")
+	for _, port := range service.Spec.Ports {
+		switch port.Name {
+		case "http":
+			assert.Equal(t, int32(80), port.Port)
+			assert.Equal(t, int32(30080), port.NodePort)
+			assert.Equal(t, "http", port.TargetPort.String())
+		case "grpc":
+			assert.Equal(t, int32(8887), port.Port)
+			assert.Zero(t, port.NodePort)
+		}
+	}
+
+	// Assert clients connect to the overridden Service ports
+	assert.Equal(t, "13306", params.DBConnection.Port)
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: params.PersistentAgentDefaultResourceName, Namespace: dspa.Namespace}, deployment))
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Command, "--mlPipelineServiceHttpPort=80")
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Command, "--mlPipelineServiceGRPCPort=8887")
+}
+
+func TestInjectServicesUnknownPort(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy:  true,
+				Service: &dspav1alpha1.ComponentService{Ports: []dspav1alpha1.ServicePort{{Name: "https", Port: 443}}},
+			},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// Assert overrides of ports the Service doesn't have are reported
+	err := reconciler.ReconcileAPIServer(ctx, dspa, params)
+	assert.ErrorContains(t, err, "has no port named [https]")
+}
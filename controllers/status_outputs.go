@@ -0,0 +1,142 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	routev1 "github.com/openshift/api/route/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// StatusOutputsSchemaVersion is the version of the schema of status.outputs. Bump it, and document the new version,
+// only when renaming, removing or changing the meaning of one of its fields.
+const StatusOutputsSchemaVersion = "v1"
+
+// StatusOutputs returns the endpoints, credentials Secrets and versions of the DSPA, for the tools provisioning it. The
+// endpoints are computed here only, dspaclient.GetEndpoints reads them from the status.
+func (r *DSPAReconciler) StatusOutputs(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) (*dspav1alpha1.StatusOutputs, error) {
+
+	outputs := &dspav1alpha1.StatusOutputs{
+		SchemaVersion:      StatusOutputsSchemaVersion,
+		ObservedGeneration: dsp.Generation,
+		Version: &dspav1alpha1.OutputVersion{
+			Operator:       config.OperatorVersion,
+			SourceRevision: config.SourceRevision,
+		},
+	}
+
+	endpoints, err := r.outputEndpoints(ctx, dsp, params)
+	if err != nil {
+		return nil, err
+	}
+	if *endpoints != (dspav1alpha1.OutputEndpoints{}) {
+		outputs.Endpoints = endpoints
+	}
+
+	secrets := &dspav1alpha1.CredentialsSecretRefs{
+		Database:      params.DBConnection.CredentialsSecret,
+		ObjectStorage: params.ObjectStorageConnection.CredentialsSecret,
+	}
+	if secrets.Database != nil || secrets.ObjectStorage != nil {
+		outputs.CredentialsSecretRefs = secrets
+	}
+
+	if params.APIServer != nil && params.APIServer.Deploy {
+		outputs.Version.APIServerImage = params.APIServer.Image
+	}
+	return outputs, nil
+}
+
+func (r *DSPAReconciler) outputEndpoints(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) (*dspav1alpha1.OutputEndpoints, error) {
+
+	endpoints := &dspav1alpha1.OutputEndpoints{}
+	serviceURL := func(name, port string) string {
+		scheme := "https"
+		if params.DevMode {
+			scheme = "http"
+		}
+		return fmt.Sprintf("%s://%s.%s.svc.cluster.local:%s", scheme, name, dsp.Namespace, port)
+	}
+
+	if params.APIServer != nil && params.APIServer.Deploy {
+		if params.DevMode {
+			endpoints.APIServer = serviceURL(params.APIServerServiceName, params.ServicePort(params.APIServerServiceName, "http", "8888"))
+		} else {
+			endpoints.APIServer = serviceURL(params.APIServerServiceName, params.ServicePort(params.APIServerServiceName, "oauth", "8443"))
+		}
+	}
+	if params.MlPipelineUI != nil && params.MlPipelineUI.Deploy {
+		name := config.DerivedName("ds-pipeline-ui-", dsp.Name)
+		endpoints.UI = serviceURL(name, params.ServicePort(name, "http", "8443"))
+	}
+	if params.MLMD != nil && params.MLMD.Deploy {
+		name := config.DerivedName("ds-pipeline-metadata-grpc-", dsp.Name)
+		endpoints.MLMDGRPC = fmt.Sprintf("%s.%s.svc.cluster.local:%s", name, dsp.Namespace,
+			params.ServicePort(name, "grpc-api", params.MLMD.GRPC.Port))
+	}
+
+	names := GetDerivedNames(dsp, params)
+	if names == nil {
+		return endpoints, nil
+	}
+	for _, route := range []struct {
+		name     string
+		endpoint *string
+	}{
+		{names.APIServerRoute, &endpoints.APIServerRoute},
+		{names.APIServerGRPCRoute, &endpoints.APIServerGRPCRoute},
+		{names.MlPipelineUIRoute, &endpoints.UIRoute},
+	} {
+		if route.name == "" {
+			continue
+		}
+		host, err := r.routeHost(ctx, dsp.Namespace, route.name)
+		if err != nil {
+			return nil, err
+		}
+		if host != "" {
+			*route.endpoint = "https://" + host
+		}
+	}
+	return endpoints, nil
+}
+
+// routeHost returns the host a router admitted for the Route, or its spec host if it wasn't admitted yet. It's "" if
+// the Route doesn't exist, has no host yet, or the cluster has no Routes.
+func (r *DSPAReconciler) routeHost(ctx context.Context, namespace, name string) (string, error) {
+	route := &routev1.Route{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, route)
+	if apierrs.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, ingress := range route.Status.Ingress {
+		if ingress.Host != "" {
+			return ingress.Host, nil
+		}
+	}
+	return route.Spec.Host, nil
+}
@@ -0,0 +1,87 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestStatusOutputs(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace", Generation: 3},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true, EnableRoute: true},
+			MlPipelineUI:  &dspav1alpha1.MlPipelineUI{Deploy: true, Image: "someimage"},
+			MLMD:          &dspav1alpha1.MLMD{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// Ensure Routes without a host are left out of the endpoints
+	outputs, err := reconciler.StatusOutputs(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusOutputsSchemaVersion, outputs.SchemaVersion)
+	assert.Equal(t, int64(3), outputs.ObservedGeneration)
+	assert.Equal(t, &dspav1alpha1.OutputEndpoints{
+		APIServer: "https://ds-pipeline-testdspa.testnamespace.svc.cluster.local:8443",
+		UI:        "https://ds-pipeline-ui-testdspa.testnamespace.svc.cluster.local:8443",
+		MLMDGRPC:  "ds-pipeline-metadata-grpc-testdspa.testnamespace.svc.cluster.local:8080",
+	}, outputs.Endpoints)
+	assert.Equal(t, config.OperatorVersion, outputs.Version.Operator)
+	assert.Equal(t, params.APIServer.Image, outputs.Version.APIServerImage)
+
+	// Ensure the Secrets of the credentials are referenced, not their values
+	assert.Equal(t, "ds-pipeline-db-testdspa", outputs.CredentialsSecretRefs.Database.Name)
+	assert.Equal(t, "password", outputs.CredentialsSecretRefs.Database.Key)
+	assert.Equal(t, params.ObjectStorageConnection.CredentialsSecret, outputs.CredentialsSecretRefs.ObjectStorage)
+
+	assert.Nil(t, reconciler.Create(ctx, &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-testdspa", Namespace: dspa.Namespace},
+		Spec:       routev1.RouteSpec{Host: "ds-pipeline-testdspa-testnamespace.apps.example.com"},
+	}))
+	outputs, err = reconciler.StatusOutputs(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://ds-pipeline-testdspa-testnamespace.apps.example.com", outputs.Endpoints.APIServerRoute)
+	assert.Empty(t, outputs.Endpoints.UIRoute)
+
+	// Ensure the host the router admitted wins over the spec host
+	route := &routev1.Route{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-testdspa", Namespace: dspa.Namespace}, route))
+	route.Status.Ingress = []routev1.RouteIngress{{Host: "pipelines.example.com"}}
+	assert.Nil(t, reconciler.Status().Update(ctx, route))
+	outputs, err = reconciler.StatusOutputs(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://pipelines.example.com", outputs.Endpoints.APIServerRoute)
+
+	// Ensure the oauth-proxies are bypassed in dev mode
+	dspa.Spec.DevMode = true
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	outputs, err = reconciler.StatusOutputs(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://ds-pipeline-testdspa.testnamespace.svc.cluster.local:8888", outputs.Endpoints.APIServer)
+}
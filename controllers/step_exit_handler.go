@@ -0,0 +1,322 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// stepExitAnnotation is set on a TaskRun once the step exit handler handled it, recording whether its logs were captured
+	stepExitAnnotation   = "datasciencepipelinesapplications.opendatahub.io/exit-handled"
+	stepExitLogsCaptured = "logs-captured"
+	stepExitHandled      = "handled"
+	pipelineTaskLabel    = "tekton.dev/pipelineTask"
+	// stepMainContainer runs the step's own command, the artifact step injected by the API Server runs after it
+	stepMainContainer = "step-main"
+)
+
+// StepStatusEvent is the payload POSTed to the run status webhooks notified of failed steps.
+type StepStatusEvent struct {
+	DSPA           string `json:"dspa"`
+	Namespace      string `json:"namespace"`
+	PipelineRun    string `json:"pipelineRun"`
+	RunID          string `json:"runId,omitempty"`
+	TaskRun        string `json:"taskRun"`
+	PipelineTask   string `json:"pipelineTask,omitempty"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason,omitempty"`
+	CompletionTime string `json:"completionTime"`
+	// Logs is the object store key of the captured step logs
+	Logs string `json:"logs,omitempty"`
+}
+
+// GetStepLogs streams the logs of a container of a step pod, the last config.StepLogsTailLines lines of them, and at
+// most config.StepLogsLimitBytes of those.
+var GetStepLogs = func(ctx context.Context, clientset kubernetes.Interface, namespace, pod, container string) (io.ReadCloser, error) {
+	tailLines := config.GetInt64ConfigWithDefault(config.StepLogsTailLinesConfigName, config.DefaultStepLogsTailLines)
+	limitBytes := config.GetInt64ConfigWithDefault(config.StepLogsLimitBytesConfigName, config.DefaultStepLogsLimitBytes)
+	return clientset.CoreV1().Pods(namespace).GetLogs(pod, &v1.PodLogOptions{
+		Container:  container,
+		TailLines:  &tailLines,
+		LimitBytes: &limitBytes,
+	}).Stream(ctx)
+}
+
+// stepLogsObjectKey returns the object store key of the step logs, where the API Server archives them with archiveLogs.
+func stepLogsObjectKey(taskRun unstructured.Unstructured) string {
+	pipelineTask := taskRun.GetLabels()[pipelineTaskLabel]
+	if pipelineTask == "" {
+		pipelineTask = taskRun.GetName()
+	}
+	return path.Join("artifacts", taskRun.GetLabels()[pipelineRunLabel], pipelineTask, "main-log.tgz")
+}
+
+type archiveFile struct {
+	name     string
+	contents []byte
+}
+
+// tarGzFiles returns a gzipped tarball holding the files, in order.
+func tarGzFiles(files []archiveFile, modTime time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.contents)), ModTime: modTime}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(file.contents); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// captureStepLogs uploads the logs of the main container of the step to the DSPA object store. Returns false if the
+// step pod is gone, e.g. pruned, as its logs can't be captured anymore. The logs are spooled to a temporary file, as the
+// tarball records their size before them, and the tarball is streamed to the object store.
+func (r *DSPAReconciler) captureStepLogs(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams, taskRun unstructured.Unstructured, now time.Time) (bool, error) {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	pod, _, _ := unstructured.NestedString(taskRun.Object, "status", "podName")
+	if pod == "" {
+		return false, nil
+	}
+	logs, err := GetStepLogs(ctx, r.Clientset, taskRun.GetNamespace(), pod, stepMainContainer)
+	if err != nil {
+		log.V(1).Info(fmt.Sprintf("Unable to retrieve the logs of step pod [%s], skipping log capture: %s", pod, err))
+		return false, nil
+	}
+	defer logs.Close()
+	spool, err := os.CreateTemp("", "step-logs-")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+	size, err := io.Copy(spool, logs)
+	if err != nil {
+		log.V(1).Info(fmt.Sprintf("Unable to read the logs of step pod [%s], skipping log capture: %s", pod, err))
+		return false, nil
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	archive, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeTarGzFile(writer, "step-main.log", spool, size, now))
+	}()
+	defer archive.Close()
+	key := stepLogsObjectKey(taskRun)
+	if err := r.streamToDSPAObjStore(ctx, dsp, params, key, archive); err != nil {
+		return false, fmt.Errorf("unable to upload the logs of TaskRun [%s] to [%s]: %w", taskRun.GetName(), key, err)
+	}
+	return true, nil
+}
+
+// writeTarGzFile writes a gzipped tarball holding a single file of the given size, read from contents.
+func writeTarGzFile(w io.Writer, name string, contents io.Reader, size int64, modTime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: size, ModTime: modTime}); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(tw, contents, size); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// uploadToDSPAObjStore stores contents under key in the bucket of the DSPA object store.
+func (r *DSPAReconciler) uploadToDSPAObjStore(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams, key string, contents []byte) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	endpoint, accesskey, secretkey, err := dspaObjStoreCredentials(params)
+	if err != nil {
+		return err
+	}
+	return UploadToObjStore(ctx, log, endpoint, params.ObjectStorageConnection.Bucket, key, accesskey, secretkey,
+		*params.ObjectStorageConnection.Secure, params.APICustomPemCerts, contents)
+}
+
+// streamToDSPAObjStore stores the contents read from reader under key in the bucket of the DSPA object store.
+func (r *DSPAReconciler) streamToDSPAObjStore(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams, key string, reader io.Reader) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	endpoint, accesskey, secretkey, err := dspaObjStoreCredentials(params)
+	if err != nil {
+		return err
+	}
+	return StreamToObjStore(ctx, log, endpoint, params.ObjectStorageConnection.Bucket, key, accesskey, secretkey,
+		*params.ObjectStorageConnection.Secure, params.APICustomPemCerts, reader)
+}
+
+// dspaObjStoreCredentials returns the endpoint and the decoded credentials of the DSPA object store.
+func dspaObjStoreCredentials(params *DSPAParams) (string, []byte, []byte, error) {
+	endpoint, err := joinHostPort(params.ObjectStorageConnection.Host, params.ObjectStorageConnection.Port)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	accesskey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.AccessKeyID)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("could not decode Object Storage Access Key ID: %w", err)
+	}
+	secretkey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.SecretAccessKey)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("could not decode Object Storage Secret Access Key: %w", err)
+	}
+	return endpoint, accesskey, secretkey, nil
+}
+
+// HandleStepExits runs the DSPA's step exit handler on the steps that finished within the lookback window: it captures
+// their logs, counts them in the StepsFinishedMetric and notifies webhooks of failed steps. Tekton skips the remaining
+// steps of a TaskRun once one fails, including the artifact step archiving the logs, and has no exit handler to
+// configure for every run, so this runs from the operator instead of within the step pod. Handled TaskRuns are annotated so they're only handled once, failed uploads are
+// retried on the next call. Diagnostics of failed runs are then captured with CaptureRunDiagnostics, if enabled.
+func (r *DSPAReconciler) HandleStepExits(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams, now time.Time) error {
+	handler := dsp.Spec.StepExitHandler
+
+	var webhooks []dspav1alpha1.RunStatusWebhook
+	for _, name := range handler.NotifyWebhooks {
+		found := false
+		for _, webhook := range dsp.Spec.RunStatusWebhooks {
+			if webhook.Name == name {
+				webhooks = append(webhooks, webhook)
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("stepExitHandler notifies unknown run status webhook [%s]", name)
+		}
+	}
+	signingKeys, err := r.runStatusWebhookSigningKeys(ctx, dsp, webhooks)
+	if err != nil {
+		return err
+	}
+	httpClient, err := r.webhookHTTPClient(ctx, dsp)
+	if err != nil {
+		return err
+	}
+	defer httpClient.CloseIdleConnections()
+	logsArchived := params.APIServer != nil && params.APIServer.ArchiveLogs
+
+	taskRuns, err := r.listPipelineTaskRuns(ctx, dsp.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, taskRun := range taskRuns {
+		finishedAt, finished := pipelineRunCompletionTime(taskRun)
+		if !finished {
+			continue
+		}
+		status, reason, ok := succeededCondition(taskRun)
+		if !ok {
+			continue
+		}
+		annotations := taskRun.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		state, handled := annotations[stepExitAnnotation]
+		notify := status == "Failed" && len(webhooks) > 0
+		if (handled && !notify) || (!handled && now.Sub(finishedAt) > config.RunNotificationLookback) {
+			continue
+		}
+
+		patch := client.MergeFrom(taskRun.DeepCopy())
+		changed := false
+		if !handled {
+			state = stepExitHandled
+			if handler.CaptureLogs && (status == "Failed" || !logsArchived) {
+				captured, err := r.captureStepLogs(ctx, dsp, params, taskRun, now)
+				if err != nil {
+					return err
+				}
+				if captured {
+					state = stepExitLogsCaptured
+				}
+			}
+			if handler.EmitMetrics {
+				StepsFinishedMetric.WithLabelValues(dsp.Name, dsp.Namespace, status, reason).Inc()
+			}
+			annotations[stepExitAnnotation] = state
+			changed = true
+		}
+		if notify {
+			event := &StepStatusEvent{
+				DSPA:           dsp.Name,
+				Namespace:      dsp.Namespace,
+				PipelineRun:    taskRun.GetLabels()[pipelineRunLabel],
+				RunID:          taskRun.GetLabels()[runIDLabel],
+				TaskRun:        taskRun.GetName(),
+				PipelineTask:   taskRun.GetLabels()[pipelineTaskLabel],
+				Status:         status,
+				Reason:         reason,
+				CompletionTime: finishedAt.Format(time.RFC3339),
+			}
+			if state == stepExitLogsCaptured {
+				event.Logs = stepLogsObjectKey(taskRun)
+			}
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			if r.deliverRunStatusWebhooks(ctx, httpClient, dsp, webhooks, signingKeys, annotations, "step status event of ["+event.TaskRun+"]", payload) {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		taskRun.SetAnnotations(annotations)
+		if err := r.Patch(ctx, &taskRun, patch); err != nil {
+			return err
+		}
+	}
+
+	if handler.CaptureRunDiagnostics {
+		return r.CaptureRunDiagnostics(ctx, dsp, params, taskRuns, now)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+func newTestTaskRun(name, pipelineTask, status string, completionTime time.Time) *unstructured.Unstructured {
+	taskRun := &unstructured.Unstructured{}
+	taskRun.SetAPIVersion("tekton.dev/v1beta1")
+	taskRun.SetKind("TaskRun")
+	taskRun.SetName(name)
+	taskRun.SetNamespace("testnamespace")
+	taskRun.SetLabels(map[string]string{pipelineRunLabel: "somerun", pipelineTaskLabel: pipelineTask, runIDLabel: "run-1"})
+	_ = unstructured.SetNestedField(taskRun.Object, name+"-pod", "status", "podName")
+	_ = unstructured.SetNestedField(taskRun.Object, completionTime.Format(time.RFC3339), "status", "completionTime")
+	_ = unstructured.SetNestedSlice(taskRun.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": status, "reason": map[string]string{"True": "Succeeded", "False": "Failed"}[status]},
+	}, "status", "conditions")
+	return taskRun
+}
+
+func TestHandleStepExits(t *testing.T) {
+	var received []StepStatusEvent
+	oncall := newTestWebhookServer(t, func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		event := StepStatusEvent{}
+		assert.Nil(t, json.Unmarshal(body, &event))
+		received = append(received, event)
+	})
+
+	uploads := make(map[string][]byte)
+	defer func(getStepLogs func(context.Context, kubernetes.Interface, string, string, string) (io.ReadCloser, error),
+		stream func(context.Context, logr.Logger, string, string, string, []byte, []byte, bool, []byte, io.Reader) error) {
+		GetStepLogs = getStepLogs
+		StreamToObjStore = stream
+	}(GetStepLogs, StreamToObjStore)
+	GetStepLogs = func(ctx context.Context, clientset kubernetes.Interface, namespace, pod, container string) (io.ReadCloser, error) {
+		assert.Equal(t, stepMainContainer, container)
+		return io.NopCloser(strings.NewReader("logs of " + pod)), nil
+	}
+	StreamToObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket, key string, accesskey, secretkey []byte, secure bool, pemCerts []byte, reader io.Reader) error {
+		contents, err := io.ReadAll(reader)
+		uploads[key] = contents
+		return err
+	}
+
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:         &dspav1alpha1.APIServer{ArchiveLogs: true},
+			Database:          &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage:     &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+			RunStatusWebhooks: []dspav1alpha1.RunStatusWebhook{{Name: "oncall", URL: oncall.URL, MaxAttempts: 3}},
+			StepExitHandler:   &dspav1alpha1.StepExitHandler{CaptureLogs: true, EmitMetrics: true, NotifyWebhooks: []string{"oncall"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	now := time.Now().Truncate(time.Second)
+	finished := now.Add(-time.Minute)
+	for _, taskRun := range []*unstructured.Unstructured{
+		newTestTaskRun("somerun-train", "train", "False", finished),
+		newTestTaskRun("somerun-prepare", "prepare", "True", finished),
+		newTestTaskRun("somerun-old", "old", "False", now.Add(-48*time.Hour)),
+	} {
+		assert.Nil(t, reconciler.Create(ctx, taskRun))
+	}
+
+	failedSteps := testutil.ToFloat64(StepsFinishedMetric.WithLabelValues("testdspa", "testnamespace", "Failed", "Failed"))
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, reconciler.HandleStepExits(ctx, dspa, params, now))
+	}
+
+	// Ensure only the logs of the failed step are captured, as the API Server archives the logs of successful ones
+	assert.Len(t, uploads, 1)
+	gz, err := gzip.NewReader(bytes.NewReader(uploads["artifacts/somerun/train/main-log.tgz"]))
+	assert.Nil(t, err)
+	tr := tar.NewReader(gz)
+	header, err := tr.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "step-main.log", header.Name)
+	logs, _ := io.ReadAll(tr)
+	assert.Equal(t, "logs of somerun-train-pod", string(logs))
+
+	// Ensure recently finished steps are only counted and notified once
+	assert.Equal(t, failedSteps+1, testutil.ToFloat64(StepsFinishedMetric.WithLabelValues("testdspa", "testnamespace", "Failed", "Failed")))
+	assert.Equal(t, []StepStatusEvent{{
+		DSPA:           "testdspa",
+		Namespace:      "testnamespace",
+		PipelineRun:    "somerun",
+		RunID:          "run-1",
+		TaskRun:        "somerun-train",
+		PipelineTask:   "train",
+		Status:         "Failed",
+		Reason:         "Failed",
+		CompletionTime: finished.Format(time.RFC3339),
+		Logs:           "artifacts/somerun/train/main-log.tgz",
+	}}, received)
+
+	expectedStates := map[string]string{
+		"somerun-train":   stepExitLogsCaptured,
+		"somerun-prepare": stepExitHandled,
+		"somerun-old":     "",
+	}
+	for name, expected := range expectedStates {
+		taskRun := &unstructured.Unstructured{}
+		taskRun.SetAPIVersion("tekton.dev/v1beta1")
+		taskRun.SetKind("TaskRun")
+		assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: "testnamespace"}, taskRun))
+		assert.Equal(t, expected, taskRun.GetAnnotations()[stepExitAnnotation], name)
+	}
+}
+
+func TestHandleStepExitsUnknownWebhook(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			StepExitHandler: &dspav1alpha1.StepExitHandler{NotifyWebhooks: []string{"missing"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.EqualError(t, reconciler.HandleStepExits(ctx, dspa, params, time.Now()), "stepExitHandler notifies unknown run status webhook [missing]")
+}
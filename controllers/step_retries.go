@@ -0,0 +1,139 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// stepRetriesWebhookTemplate is cluster scoped, so it can't be owned by the DSPA and is deleted explicitly
+const stepRetriesWebhookTemplate = "step-retries/webhook.yaml.tmpl"
+
+// StepRetriesWebhookPath is the path of the operator's webhook server the step retries webhooks call
+const StepRetriesWebhookPath = "/default-pipeline-run-retries"
+
+// UsingDefaultStepRetries returns true if the DSPA retries the failed steps of its runs by default.
+func (p *DSPAParams) UsingDefaultStepRetries(dsp *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	return dsp.Spec.APIServer != nil && dsp.Spec.APIServer.DefaultStepRetries > 0
+}
+
+func (r *DSPAReconciler) ReconcileStepRetries(dsp *dspav1alpha1.DataSciencePipelinesApplication, params *DSPAParams) error {
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	params.StepRetriesWebhookPath = StepRetriesWebhookPath
+	if !params.UsingDefaultStepRetries(dsp) {
+		log.Info("Skipping Application of Step Retries Resources")
+		return r.DeleteResource(params, stepRetriesWebhookTemplate)
+	}
+	if r.WebhookService == nil {
+		return fmt.Errorf("spec.apiServer.defaultStepRetries requires the operator to serve its admission webhooks, it " +
+			"serves them once a serving certificate is mounted in its webhook certificate directory")
+	}
+
+	log.Info("Applying Step Retries Resources")
+	params.WebhookService = *r.WebhookService
+	err := r.ApplyWithoutOwner(params, stepRetriesWebhookTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Step Retries Resources")
+	return nil
+}
+
+func (r *DSPAReconciler) CleanUpStepRetries(params *DSPAParams) error {
+	params.StepRetriesWebhookPath = StepRetriesWebhookPath
+	return r.DeleteResource(params, stepRetriesWebhookTemplate)
+}
+
+// setDefaultRetries sets the retries of the tasks of the pipeline embedded in the PipelineRun which don't set their
+// own, returning whether any task was changed.
+func setDefaultRetries(run *unstructured.Unstructured, retries int) (bool, error) {
+	changed := false
+	for _, field := range []string{"tasks", "finally"} {
+		tasks, found, err := unstructured.NestedSlice(run.Object, "spec", "pipelineSpec", field)
+		if err != nil || !found {
+			continue
+		}
+		for _, task := range tasks {
+			task, ok := task.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, found := task["retries"]; found {
+				continue
+			}
+			task["retries"] = int64(retries)
+			changed = true
+		}
+		if err := unstructured.SetNestedSlice(run.Object, tasks, "spec", "pipelineSpec", field); err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+// StepRetriesWebhook sets the default step retries of a DSPA on the tasks of the PipelineRuns it runs, the
+// PipelineRuns with its pipeline runner ServiceAccount. Tekton has no defaults for task retries, so they're set in the
+// pipeline the PipelineRun embeds.
+type StepRetriesWebhook struct {
+	Client client.Client
+	Log    logr.Logger
+}
+
+func (w *StepRetriesWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	run := &unstructured.Unstructured{}
+	if err := run.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	dspas := &dspav1alpha1.DataSciencePipelinesApplicationList{}
+	if err := w.Client.List(ctx, dspas, client.InNamespace(req.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	serviceAccount, _, _ := unstructured.NestedString(run.Object, "spec", "serviceAccountName")
+	for i := range dspas.Items {
+		dspa := &dspas.Items[i]
+		if dspa.Spec.APIServer == nil || dspa.Spec.APIServer.DefaultStepRetries <= 0 ||
+			serviceAccount != config.DerivedName("pipeline-runner-", dspa.Name) {
+			continue
+		}
+
+		// Runs referring to a Pipeline rather than embedding it are left as they are
+		changed, err := setDefaultRetries(run, dspa.Spec.APIServer.DefaultStepRetries)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if !changed {
+			return admission.Allowed("")
+		}
+		retried, err := run.MarshalJSON()
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		return admission.PatchResponseFromRaw(req.Object.Raw, retried)
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,120 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newStepRetriesTestDSPA(retries int) *dspav1alpha1.DataSciencePipelinesApplication {
+	return &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true, DefaultStepRetries: retries},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+}
+
+func TestDeployStepRetries(t *testing.T) {
+	expectedWebhookName := "ds-pipeline-step-retries-testnamespace.testdspa"
+	dspa := newStepRetriesTestDSPA(3)
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+
+	// Ensure the runs aren't retried if the operator doesn't serve the webhook
+	assert.ErrorContains(t, reconciler.ReconcileStepRetries(dspa, params), "requires the operator to serve its admission webhooks")
+
+	reconciler.WebhookService = &types.NamespacedName{Name: "dspo-service", Namespace: "dspo"}
+	assert.Nil(t, reconciler.ReconcileStepRetries(dspa, params))
+	webhook := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: expectedWebhookName}, webhook))
+	assert.Equal(t, StepRetriesWebhookPath, *webhook.Webhooks[0].ClientConfig.Service.Path)
+	assert.Equal(t, []string{"pipelineruns"}, webhook.Webhooks[0].Rules[0].Resources)
+	assert.Equal(t, admissionregistrationv1.Ignore, *webhook.Webhooks[0].FailurePolicy)
+
+	// Ensure the webhook is removed once the default retries are
+	dspa.Spec.APIServer.DefaultStepRetries = 0
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileStepRetries(dspa, params))
+	created, err := reconciler.IsResourceCreated(ctx, &admissionregistrationv1.MutatingWebhookConfiguration{}, expectedWebhookName, "")
+	assert.False(t, created)
+	assert.Nil(t, err)
+}
+
+func TestStepRetriesWebhook(t *testing.T) {
+	dspa := newStepRetriesTestDSPA(2)
+	ctx, _, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	webhook := &StepRetriesWebhook{Client: reconciler.Client, Log: reconciler.Log}
+	admit := func(run *unstructured.Unstructured) admission.Response {
+		raw, err := run.MarshalJSON()
+		assert.Nil(t, err)
+		return webhook.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: run.GetNamespace(),
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+	}
+
+	// Ensure the tasks of the runs of the DSPA are retried, keeping the retries set in the pipeline
+	run := newTestPipelineRun("somerun", "testnamespace", nil, false)
+	assert.Nil(t, unstructured.SetNestedField(run.Object, "pipeline-runner-testdspa", "spec", "serviceAccountName"))
+	assert.Nil(t, unstructured.SetNestedSlice(run.Object, []interface{}{
+		map[string]interface{}{"name": "train"},
+		map[string]interface{}{"name": "evaluate", "retries": int64(5)},
+	}, "spec", "pipelineSpec", "tasks"))
+	assert.Nil(t, unstructured.SetNestedSlice(run.Object, []interface{}{
+		map[string]interface{}{"name": "exit-handler"},
+	}, "spec", "pipelineSpec", "finally"))
+	response := admit(run)
+	assert.True(t, response.Allowed)
+	patches := map[string]interface{}{}
+	for _, patch := range response.Patches {
+		patches[patch.Path] = patch.Value
+	}
+	assert.Equal(t, map[string]interface{}{
+		"/spec/pipelineSpec/tasks/0/retries":   float64(2),
+		"/spec/pipelineSpec/finally/0/retries": float64(2),
+	}, patches)
+
+	// Ensure runs referring to a Pipeline, and runs of other DSPAs and namespaces are left as they are
+	unstructured.RemoveNestedField(run.Object, "spec", "pipelineSpec")
+	assert.Nil(t, unstructured.SetNestedField(run.Object, "somepipeline", "spec", "pipelineRef", "name"))
+	response = admit(run)
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Patches)
+	assert.Nil(t, unstructured.SetNestedField(run.Object, "pipeline-runner-otherdspa", "spec", "serviceAccountName"))
+	response = admit(run)
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Patches)
+	response = admit(newTestPipelineRun("somerun", "othernamespace", nil, false))
+	assert.True(t, response.Allowed)
+	assert.Empty(t, response.Patches)
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/minio/minio-go/v7"
@@ -35,10 +39,14 @@ import (
 
 const storageSecret = "minio/secret.yaml.tmpl"
 const storageRoute = "minio/route.yaml.tmpl"
+const minioPVCTemplate = "minio/pvc.yaml.tmpl"
+
+// objStoreStreamPartSize is the size of the parts of the uploads of unknown size, the minimum S3 allows
+const objStoreStreamPartSize = 5 * 1024 * 1024
 
 var minioTemplates = []string{
 	"minio/deployment.yaml.tmpl",
-	"minio/pvc.yaml.tmpl",
+	minioPVCTemplate,
 	"minio/service.yaml.tmpl",
 	"minio/minio-sa.yaml.tmpl",
 	storageRoute,
@@ -48,10 +56,19 @@ func joinHostPort(host, port string) (string, error) {
 	if host == "" {
 		return "", errors.New("Object Storage Connection missing host")
 	}
-	if port == "" {
-		return host, nil
+	return hostPort(host, port), nil
+}
+
+// hostPort joins host and port like net.JoinHostPort, bracketing IPv6 literals also when there is no port.
+func hostPort(host, port string) string {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if port != "" {
+		return net.JoinHostPort(host, port)
+	}
+	if strings.Contains(host, ":") {
+		return "[" + host + "]"
 	}
-	return fmt.Sprintf("%s:%s", host, port), nil
+	return host
 }
 
 func createCredentialProvidersChain(accessKey, secretKey string) *credentials.Credentials {
@@ -94,7 +111,10 @@ func getHttpsTransportWithCACert(log logr.Logger, pemCerts []byte) (*http.Transp
 	return transport, nil
 }
 
-var ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) bool {
+// errBucketNotFound is returned by ConnectAndQueryObjStore when the credentials are accepted, but the bucket does not exist
+var errBucketNotFound = errors.New("bucket does not exist")
+
+func newObjStoreClient(log logr.Logger, endpoint string, accesskey, secretkey []byte, secure bool, pemCerts []byte) (*minio.Client, error) {
 	cred := createCredentialProvidersChain(string(accesskey), string(secretkey))
 
 	opts := &minio.Options{
@@ -106,7 +126,7 @@ var ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoin
 		tr, err := getHttpsTransportWithCACert(log, pemCerts)
 		if err != nil {
 			log.Error(err, "Encountered error when processing custom ca bundle.")
-			return false
+			return nil, fmt.Errorf("%w: %s", errInvalidCABundle, err)
 		}
 		opts.Transport = tr
 	}
@@ -114,7 +134,15 @@ var ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoin
 	minioClient, err := minio.New(endpoint, opts)
 	if err != nil {
 		log.Info(fmt.Sprintf("Could not connect to object storage endpoint: %s", endpoint))
-		return false
+		return nil, err
+	}
+	return minioClient, nil
+}
+
+var ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) error {
+	minioClient, err := newObjStoreClient(log, endpoint, accesskey, secretkey, secure, pemCerts)
+	if err != nil {
+		return err
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
@@ -127,8 +155,11 @@ var ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoin
 
 		// In the case that the Error is NoSuchKey (or NoSuchBucket), we can verify that the endpoint worked and the object just doesn't exist
 		case minio.ErrorResponse:
-			if err.Code == "NoSuchKey" || err.Code == "NoSuchBucket" {
-				return true
+			if err.Code == "NoSuchKey" {
+				return nil
+			}
+			if err.Code == "NoSuchBucket" {
+				return errBucketNotFound
 			}
 		}
 
@@ -137,16 +168,60 @@ var ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoin
 				"If using an tls S3 connection with  self-signed certs, you may specify a custom CABundle "+
 				"to mount on the DSP API Server via the DSPA cr under the spec.cABundle field. If you have already "+
 				"provided a CABundle, verify the validity of the provided CABundle.")
-			return false
+			return err
 		}
 
 		// Every other error means the endpoint in inaccessible, or the credentials provided do not have, at a minimum GetObject, permissions
 		log.Info(fmt.Sprintf("Could not connect to (%s), Error: %s", endpoint, err.Error()))
-		return false
+		return err
 	}
 
 	// Getting here means the health check passed
-	return true
+	return nil
+}
+
+// UploadToObjStore stores contents under key in the bucket of the object store.
+var UploadToObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket, key string, accesskey, secretkey []byte, secure bool, pemCerts []byte, contents []byte) error {
+	minioClient, err := newObjStoreClient(log, endpoint, accesskey, secretkey, secure, pemCerts)
+	if err != nil {
+		return err
+	}
+
+	objStoreConnectionTimeout := config.GetDurationConfigWithDefault(config.ObjStoreConnectionTimeoutConfigName, config.DefaultObjStoreConnectionTimeout)
+	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
+	defer cancel()
+
+	_, err = minioClient.PutObject(ctx, bucket, key, bytes.NewReader(contents), int64(len(contents)), minio.PutObjectOptions{})
+	return err
+}
+
+// StreamToObjStore stores the contents read from reader, of unknown size, under key in the bucket of the object store.
+// They're uploaded in parts of objStoreStreamPartSize, so at most one part is held in memory.
+var StreamToObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket, key string, accesskey, secretkey []byte, secure bool, pemCerts []byte, reader io.Reader) error {
+	minioClient, err := newObjStoreClient(log, endpoint, accesskey, secretkey, secure, pemCerts)
+	if err != nil {
+		return err
+	}
+
+	objStoreConnectionTimeout := config.GetDurationConfigWithDefault(config.ObjStoreConnectionTimeoutConfigName, config.DefaultObjStoreConnectionTimeout)
+	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
+	defer cancel()
+
+	_, err = minioClient.PutObject(ctx, bucket, key, reader, -1, minio.PutObjectOptions{PartSize: objStoreStreamPartSize})
+	return err
+}
+
+// QueryBucketLocation returns the region the object store reports for the bucket.
+var QueryBucketLocation = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) (string, error) {
+	minioClient, err := newObjStoreClient(log, endpoint, accesskey, secretkey, secure, pemCerts)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, objStoreConnectionTimeout)
+	defer cancel()
+
+	return minioClient.GetBucketLocation(ctx, bucket)
 }
 
 func (r *DSPAReconciler) isObjectStorageAccessible(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
@@ -162,18 +237,21 @@ func (r *DSPAReconciler) isObjectStorageAccessible(ctx context.Context, dsp *dsp
 	endpoint, err := joinHostPort(params.ObjectStorageConnection.Host, params.ObjectStorageConnection.Port)
 	if err != nil {
 		log.Error(err, "Could not determine Object Storage Endpoint")
+		params.ObjectStorageDiagnosis = &Diagnosis{Reason: config.ObjStoreConnectionFailed, Message: err.Error()}
 		return false
 	}
 
 	accesskey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.AccessKeyID)
 	if err != nil {
 		log.Error(err, "Could not decode Object Storage Access Key ID")
+		params.ObjectStorageDiagnosis = &Diagnosis{Reason: config.ObjStoreAuthFailed, Message: "Could not decode Object Storage Access Key ID"}
 		return false
 	}
 
 	secretkey, err := base64.StdEncoding.DecodeString(params.ObjectStorageConnection.SecretAccessKey)
 	if err != nil {
 		log.Error(err, "Could not decode Object Storage Secret Access Key")
+		params.ObjectStorageDiagnosis = &Diagnosis{Reason: config.ObjStoreAuthFailed, Message: "Could not decode Object Storage Secret Access Key"}
 		return false
 	}
 
@@ -181,15 +259,19 @@ func (r *DSPAReconciler) isObjectStorageAccessible(ctx context.Context, dsp *dsp
 
 	log.V(1).Info(fmt.Sprintf("Object Store connection timeout: %s", objStoreConnectionTimeout))
 
-	verified := ConnectAndQueryObjStore(ctx, log, endpoint, params.ObjectStorageConnection.Bucket, accesskey, secretkey,
+	err = ConnectAndQueryObjStore(ctx, log, endpoint, params.ObjectStorageConnection.Bucket, accesskey, secretkey,
 		*params.ObjectStorageConnection.Secure, params.APICustomPemCerts, objStoreConnectionTimeout)
+	if err != nil {
+		params.ObjectStorageDiagnosis = diagnoseObjectStoreError(err, params.ObjectStorageConnection.Bucket)
+	}
 
-	if verified {
+	// The API Server creates missing buckets, so a missing bucket is reported but does not fail the health check
+	if err == nil || errors.Is(err, errBucketNotFound) {
 		log.Info("Object Storage Health Check Successful")
-	} else {
-		log.Info("Object Storage Health Check Failed")
+		return true
 	}
-	return verified
+	log.Info(fmt.Sprintf("Object Storage Health Check Failed, Reason: [%s]", params.ObjectStorageDiagnosis.Reason))
+	return false
 }
 
 // ReconcileStorage will set up Storage Connection.
@@ -221,6 +303,10 @@ func (r *DSPAReconciler) ReconcileStorage(ctx context.Context, dsp *dspav1alpha1
 		}
 		log.Info("Applying object storage resources.")
 		for _, template := range minioTemplates {
+			// Dev mode stores the objects in a tmpfs emptyDir instead
+			if params.DevMode && template == minioPVCTemplate {
+				continue
+			}
 			if dsp.Spec.ObjectStorage.EnableExternalRoute || template != storageRoute {
 				err := r.Apply(dsp, params, template)
 				if err != nil {
@@ -20,6 +20,7 @@ package controllers
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"testing"
 	"time"
 
@@ -270,8 +271,8 @@ func TestDefaultDeployBehaviorStorage(t *testing.T) {
 
 func TestIsDatabaseAccessibleTrue(t *testing.T) {
 	// Override the live connection function with a mock version
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) bool {
-		return true
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) error {
+		return nil
 	}
 
 	testNamespace := "testnamespace"
@@ -308,8 +309,8 @@ func TestIsDatabaseAccessibleTrue(t *testing.T) {
 
 func TestIsDatabaseNotAccessibleFalse(t *testing.T) {
 	// Override the live connection function with a mock version
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) bool {
-		return false
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) error {
+		return errors.New("connection refused")
 	}
 
 	testNamespace := "testnamespace"
@@ -345,9 +346,9 @@ func TestIsDatabaseNotAccessibleFalse(t *testing.T) {
 }
 
 func TestDisabledHealthCheckReturnsTrue(t *testing.T) {
-	// Override the live connection function with a mock version that would always return false if called
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) bool {
-		return false
+	// Override the live connection function with a mock version that would always fail if called
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) error {
+		return errors.New("connection refused")
 	}
 
 	testNamespace := "testnamespace"
@@ -380,14 +381,14 @@ func TestDisabledHealthCheckReturnsTrue(t *testing.T) {
 
 	verified := reconciler.isObjectStorageAccessible(ctx, dspa, params)
 	// if health check is disabled this should always return True
-	// even thought the mock connection function would return false if called
+	// even thought the mock connection function would fail if called
 	assert.True(t, verified)
 }
 
 func TestIsDatabaseAccessibleBadAccessKey(t *testing.T) {
 	// Override the live connection function with a mock version
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) bool {
-		return true
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) error {
+		return nil
 	}
 
 	testNamespace := "testnamespace"
@@ -424,8 +425,8 @@ func TestIsDatabaseAccessibleBadAccessKey(t *testing.T) {
 
 func TestIsDatabaseAccessibleBadSecretKey(t *testing.T) {
 	// Override the live connection function with a mock version
-	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) bool {
-		return true
+	ConnectAndQueryObjStore = func(ctx context.Context, log logr.Logger, endpoint, bucket string, accesskey, secretkey []byte, secure bool, pemCerts []byte, objStoreConnectionTimeout time.Duration) error {
+		return nil
 	}
 
 	testNamespace := "testnamespace"
@@ -471,6 +472,9 @@ func TestJoinHostPort(t *testing.T) {
 		"empty port":            {host: "somehost", port: "", expectedResult: "somehost", expectedError: false},
 		"empty host":            {host: "", port: "1234", expectedResult: "", expectedError: true},
 		"both empty":            {host: "", port: "", expectedResult: "", expectedError: true},
+		"ipv6 host and port":    {host: "fd00::1", port: "9000", expectedResult: "[fd00::1]:9000", expectedError: false},
+		"bracketed ipv6 host":   {host: "[fd00::1]", port: "9000", expectedResult: "[fd00::1]:9000", expectedError: false},
+		"ipv6 host no port":     {host: "fd00::1", port: "", expectedResult: "[fd00::1]", expectedError: false},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,205 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TelemetryConfigMapName is the ConfigMap of the operator namespace holding the last telemetry payload, so admins can
+// review exactly what is reported
+const TelemetryConfigMapName = "data-science-pipelines-operator-telemetry"
+
+const telemetryPayloadKey = "payload.json"
+
+// TelemetryPayload holds anonymized aggregate usage stats of the cluster's DSPAs. It never holds names, namespaces,
+// hosts or any other value identifying the cluster, its users or their data.
+type TelemetryPayload struct {
+	OperatorVersion string `json:"operatorVersion"`
+	DSPAs           int    `json:"dspas"`
+	// DatabaseTypes counts the DSPAs by database backend: mariadb or external
+	DatabaseTypes map[string]int `json:"databaseTypes"`
+	// ObjectStorageTypes counts the DSPAs by object storage backend: minio, aws-s3, gcs or other-s3
+	ObjectStorageTypes map[string]int `json:"objectStorageTypes"`
+	// ComponentVersions counts the deployed image tags of each component, from the DSPAs' version manifests
+	ComponentVersions map[string]map[string]int `json:"componentVersions"`
+}
+
+// TelemetryReporter periodically reports the TelemetryPayload of the cluster to Endpoint, and publishes it in the
+// TelemetryConfigMapName ConfigMap of Namespace. Telemetry is opt-in, the reporter only runs when DSPO.Telemetry.Enabled
+// is set in the operator config.
+type TelemetryReporter struct {
+	Client    client.Client
+	Log       logr.Logger
+	Namespace string
+	// Endpoint receives the payload as a JSON POST, the payload is only published in the ConfigMap if empty
+	Endpoint   string
+	Interval   time.Duration
+	HTTPClient *http.Client
+}
+
+// Start implements manager.Runnable, reporting every Interval until ctx is done
+func (t *TelemetryReporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+	for {
+		if err := t.Report(ctx); err != nil {
+			t.Log.Error(err, "Could not report telemetry")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so a cluster is reported once per interval
+func (t *TelemetryReporter) NeedLeaderElection() bool {
+	return true
+}
+
+// Report publishes the payload in the ConfigMap before sending it, so a failing endpoint doesn't hide what is sent.
+func (t *TelemetryReporter) Report(ctx context.Context) error {
+	payload, err := t.Payload(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = t.Client.Get(ctx, types.NamespacedName{Name: TelemetryConfigMapName, Namespace: t.Namespace}, cm)
+	if apierrs.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: TelemetryConfigMapName, Namespace: t.Namespace},
+			Data:       map[string]string{telemetryPayloadKey: string(data)},
+		}
+		err = t.Client.Create(ctx, cm)
+	} else if err == nil {
+		cm.Data = map[string]string{telemetryPayloadKey: string(data)}
+		err = t.Client.Update(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+
+	if t.Endpoint == "" {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Payload aggregates the usage stats of the cluster's DSPAs.
+func (t *TelemetryReporter) Payload(ctx context.Context) (*TelemetryPayload, error) {
+	dspas := &dspav1alpha1.DataSciencePipelinesApplicationList{}
+	if err := t.Client.List(ctx, dspas); err != nil {
+		return nil, err
+	}
+	payload := &TelemetryPayload{
+		OperatorVersion:    config.OperatorVersion,
+		DSPAs:              len(dspas.Items),
+		DatabaseTypes:      map[string]int{},
+		ObjectStorageTypes: map[string]int{},
+		ComponentVersions:  map[string]map[string]int{},
+	}
+	for _, dspa := range dspas.Items {
+		payload.DatabaseTypes[databaseType(&dspa)]++
+		payload.ObjectStorageTypes[objectStorageType(&dspa)]++
+
+		manifest := &corev1.ConfigMap{}
+		err := t.Client.Get(ctx, types.NamespacedName{Name: config.DerivedName("ds-pipeline-version-manifest-", dspa.Name), Namespace: dspa.Namespace}, manifest)
+		if apierrs.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for key, image := range manifest.Data {
+			if !strings.HasSuffix(key, ".image") {
+				continue
+			}
+			component := strings.TrimSuffix(key, ".image")
+			if payload.ComponentVersions[component] == nil {
+				payload.ComponentVersions[component] = map[string]int{}
+			}
+			payload.ComponentVersions[component][imageVersion(image)]++
+		}
+	}
+	return payload, nil
+}
+
+func databaseType(dspa *dspav1alpha1.DataSciencePipelinesApplication) string {
+	if dspa.Spec.Database != nil && dspa.Spec.Database.ExternalDB != nil {
+		return "external"
+	}
+	return "mariadb"
+}
+
+func objectStorageType(dspa *dspav1alpha1.DataSciencePipelinesApplication) string {
+	if dspa.Spec.ObjectStorage == nil || dspa.Spec.ObjectStorage.ExternalStorage == nil {
+		return "minio"
+	}
+	host := dspa.Spec.ObjectStorage.ExternalStorage.Host
+	switch {
+	case strings.HasSuffix(host, "amazonaws.com"):
+		return "aws-s3"
+	case strings.HasSuffix(host, "storage.googleapis.com"):
+		return "gcs"
+	default:
+		return "other-s3"
+	}
+}
+
+// imageVersion returns the tag or digest of the image, leaving out its registry and repository, which may be private.
+func imageVersion(image string) string {
+	if _, digest, ok := strings.Cut(image, "@"); ok {
+		return digest
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
@@ -0,0 +1,102 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestTelemetryReport(t *testing.T) {
+	ctx, _, reconciler := CreateNewTestObjects()
+	dspas := []*dspav1alpha1.DataSciencePipelinesApplication{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sandbox", Namespace: "team-a"},
+			Spec: dspav1alpha1.DSPASpec{
+				ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: true, Image: "someimage"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: "team-b"},
+			Spec: dspav1alpha1.DSPASpec{
+				Database: &dspav1alpha1.Database{ExternalDB: &dspav1alpha1.ExternalDB{Host: "db.team-b.internal"}},
+				ObjectStorage: &dspav1alpha1.ObjectStorage{ExternalStorage: &dspav1alpha1.ExternalStorage{
+					Host: "s3.us-east-1.amazonaws.com",
+				}},
+			},
+		},
+	}
+	for _, dspa := range dspas {
+		assert.Nil(t, reconciler.Create(ctx, dspa))
+	}
+	assert.Nil(t, reconciler.Create(ctx, &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds-pipeline-version-manifest-prod", Namespace: "team-b"},
+		Data: map[string]string{
+			"operatorVersion":        "v1.2.0",
+			"apiServer.image":        "registry.team-b.internal/kfp/api-server:v1.2.0",
+			"apiServer.imageID":      "registry.team-b.internal/kfp/api-server@sha256:0123",
+			"mlmdGRPC.image":         "quay.io/opendatahub/ds-pipelines-metadata-grpc@sha256:4567",
+			"persistenceAgent.image": "localhost:5000/persistenceagent",
+		},
+	}))
+
+	var received []byte
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received, _ = io.ReadAll(req.Body)
+	}))
+	defer endpoint.Close()
+	reporter := &TelemetryReporter{
+		Client:     reconciler.Client,
+		Log:        reconciler.Log,
+		Namespace:  "dspo",
+		Endpoint:   endpoint.URL,
+		Interval:   time.Hour,
+		HTTPClient: endpoint.Client(),
+	}
+	assert.Nil(t, reporter.Report(ctx))
+
+	// Ensure the payload is aggregated, without names, namespaces, hosts or registries
+	payload := &TelemetryPayload{}
+	assert.Nil(t, json.Unmarshal(received, payload))
+	assert.Equal(t, 2, payload.DSPAs)
+	assert.Equal(t, map[string]int{"mariadb": 1, "external": 1}, payload.DatabaseTypes)
+	assert.Equal(t, map[string]int{"minio": 1, "aws-s3": 1}, payload.ObjectStorageTypes)
+	assert.Equal(t, map[string]map[string]int{
+		"apiServer":        {"v1.2.0": 1},
+		"mlmdGRPC":         {"sha256:4567": 1},
+		"persistenceAgent": {"latest": 1},
+	}, payload.ComponentVersions)
+	for _, identifying := range []string{"team-a", "team-b", "sandbox", "prod", "internal", "quay.io"} {
+		assert.NotContains(t, string(received), identifying)
+	}
+
+	// Ensure the payload sent is published in the ConfigMap
+	cm := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: TelemetryConfigMapName, Namespace: "dspo"}, cm))
+	assert.Equal(t, string(received), cm.Data[telemetryPayloadKey])
+}
@@ -0,0 +1,42 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+)
+
+// templateFile returns the file the template is rendered from. The operator reads its templates from TemplatesPath,
+// unless the DSPO.TemplatesPath operator config points to another set of templates, e.g. a mounted ConfigMap or the
+// content of a template bundle image. Distributions can also replace single templates by mounting a file with the
+// same relative path in the DSPO.TemplateOverlaysPath directory. Both are read when rendering, so changes to the
+// operator config apply on the next reconcile.
+func (r *DSPAReconciler) templateFile(template string) string {
+	overlaysPath := config.GetStringConfigWithDefault(config.TemplateOverlaysPathConfigName, "")
+	if overlaysPath != "" {
+		overlay := filepath.Join(overlaysPath, template)
+		if _, err := os.Stat(overlay); err == nil {
+			return overlay
+		}
+	}
+	if templatesPath := config.GetStringConfigWithDefault(config.TemplatesPathConfigName, ""); templatesPath != "" {
+		return filepath.Join(templatesPath, template)
+	}
+	return r.TemplatesPath + template
+}
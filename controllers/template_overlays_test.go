@@ -0,0 +1,124 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testOverlayTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{derivedName "sample-config-" .Name}}
+  namespace: {{.Namespace}}
+data:
+  mlmdHost: {{ templateValue "mlmdHost" | default (printf "%s.%s.svc" (derivedName "ds-pipeline-metadata-grpc-" .Name) .Namespace) }}
+  image: {{ .APIServer.Image | required "an API Server image is required" }}
+  {{- if .APIServer.EnableSamplePipeline }}
+  samples: enabled
+  {{- end }}
+`
+
+func TestTemplateOverlays(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace", UID: "testuid"},
+	}
+	_, _, reconciler := CreateNewTestObjects()
+	params := &DSPAParams{Name: "testdspa", Namespace: "testnamespace", APIServer: &dspav1alpha1.APIServer{Image: "someimage"}}
+
+	overlaysPath := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(overlaysPath, "apiserver"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(overlaysPath, "apiserver/sample-config.yaml.tmpl"), []byte(testOverlayTemplate), 0644))
+	viper.Set(config.TemplateOverlaysPathConfigName, overlaysPath)
+	t.Cleanup(func() {
+		viper.Set(config.TemplateOverlaysPathConfigName, nil)
+		viper.Set(config.TemplateValuesConfigName, nil)
+	})
+
+	render := func() map[string]interface{} {
+		manifest, err := reconciler.render(dspa, params, samplePipelineTemplates["sample-config"])
+		assert.Nil(t, err)
+		assert.Len(t, manifest.Resources(), 1)
+		data, _, _ := unstructured.NestedMap(manifest.Resources()[0].Object, "data")
+		return data
+	}
+
+	// Ensure overlays replace the templates, and derive defaults for the values the operator config doesn't set
+	assert.Equal(t, map[string]interface{}{
+		"mlmdHost": "ds-pipeline-metadata-grpc-testdspa.testnamespace.svc",
+		"image":    "someimage",
+	}, render())
+
+	// Ensure the operator config values take precedence over the defaults
+	viper.Set(config.TemplateValuesConfigName, map[string]string{"mlmdHost": "mlmd.example.com"})
+	params.APIServer.EnableSamplePipeline = true
+	assert.Equal(t, map[string]interface{}{
+		"mlmdHost": "mlmd.example.com",
+		"image":    "someimage",
+		"samples":  "enabled",
+	}, render())
+
+	// Ensure required values fail the render if they are empty
+	params.APIServer.Image = ""
+	_, err := reconciler.render(dspa, params, samplePipelineTemplates["sample-config"])
+	assert.ErrorContains(t, err, "an API Server image is required")
+
+	// Ensure the templates without an overlay are still read from the operator templates
+	assert.Equal(t, "../config/internal/apiserver/route.yaml.tmpl", reconciler.templateFile(serverRoute))
+}
+
+func TestTemplatesPath(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace", UID: "testuid"},
+	}
+	_, _, reconciler := CreateNewTestObjects()
+	params := &DSPAParams{Name: "testdspa", Namespace: "testnamespace", APIServer: &dspav1alpha1.APIServer{Image: "someimage"}}
+
+	templatesPath := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(templatesPath, "apiserver"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(templatesPath, "apiserver/sample-config.yaml.tmpl"), []byte(testOverlayTemplate), 0644))
+	viper.Set(config.TemplatesPathConfigName, templatesPath)
+	t.Cleanup(func() {
+		viper.Set(config.TemplatesPathConfigName, nil)
+		viper.Set(config.TemplateOverlaysPathConfigName, nil)
+	})
+
+	// Ensure the templates are read from the configured source instead of the operator templates
+	manifest, err := reconciler.render(dspa, params, samplePipelineTemplates["sample-config"])
+	assert.Nil(t, err)
+	assert.Len(t, manifest.Resources(), 1)
+	_, err = reconciler.render(dspa, params, serverRoute)
+	assert.ErrorContains(t, err, filepath.Join(templatesPath, serverRoute))
+
+	// Ensure overlays still take precedence over the configured source
+	overlaysPath := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(overlaysPath, "apiserver"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(overlaysPath, serverRoute), []byte(testOverlayTemplate), 0644))
+	viper.Set(config.TemplateOverlaysPathConfigName, overlaysPath)
+	assert.Equal(t, filepath.Join(overlaysPath, serverRoute), reconciler.templateFile(serverRoute))
+	assert.Equal(t, filepath.Join(templatesPath, "apiserver/sample-config.yaml.tmpl"), reconciler.templateFile(samplePipelineTemplates["sample-config"]))
+}
@@ -0,0 +1,112 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// FakeDatabase is an in-memory stand-in for the MySQL server of a DSPA, answering the database health checks with the
+// errors the server would return, so their diagnosis can be tested. Its ConnectAndQuery and QueryWriteCapacity methods
+// replace controllers.ConnectAndQueryDatabase and controllers.QueryDatabaseWriteCapacity.
+type FakeDatabase struct {
+	Host string
+	Port string
+	// ReadOnly and MaxConnections are reported by QueryWriteCapacity
+	ReadOnly       bool
+	MaxConnections int
+	// Unreachable fails every connection, like a server that is down
+	Unreachable bool
+
+	mu        sync.Mutex
+	users     map[string]string
+	databases map[string]bool
+	queries   int
+}
+
+// NewFakeDatabase returns a writable database server at host:port, with the default max_connections of MySQL.
+func NewFakeDatabase(host, port string) *FakeDatabase {
+	return &FakeDatabase{
+		Host:           host,
+		Port:           port,
+		MaxConnections: 151,
+		users:          map[string]string{},
+		databases:      map[string]bool{},
+	}
+}
+
+// AddUser grants the user with the password access to every database of the server.
+func (d *FakeDatabase) AddUser(username, password string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.users[username] = password
+}
+
+// CreateDatabase creates the database, if it doesn't exist yet.
+func (d *FakeDatabase) CreateDatabase(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.databases[name] = true
+}
+
+// DropDatabase deletes the database.
+func (d *FakeDatabase) DropDatabase(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.databases, name)
+}
+
+// Queries returns the number of health check queries the server answered.
+func (d *FakeDatabase) Queries() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.queries
+}
+
+// ConnectAndQuery has the signature of controllers.ConnectAndQueryDatabase.
+func (d *FakeDatabase) ConnectAndQuery(host, port, username, password, dbname string, dbConnectionTimeout time.Duration) error {
+	return d.connect(host, port, username, password, dbname)
+}
+
+// QueryWriteCapacity has the signature of controllers.QueryDatabaseWriteCapacity.
+func (d *FakeDatabase) QueryWriteCapacity(host, port, username, password, dbname string, dbConnectionTimeout time.Duration) (bool, int, error) {
+	if err := d.connect(host, port, username, password, dbname); err != nil {
+		return false, 0, err
+	}
+	return d.ReadOnly, d.MaxConnections, nil
+}
+
+func (d *FakeDatabase) connect(host, port, username, password, dbname string) error {
+	if d.Unreachable || host != d.Host || port != d.Port {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if expected, ok := d.users[username]; !ok || expected != password {
+		return &mysql.MySQLError{Number: 1045, Message: fmt.Sprintf("Access denied for user '%s'@'%%' (using password: YES)", username)}
+	}
+	if !d.databases[dbname] {
+		return &mysql.MySQLError{Number: 1049, Message: fmt.Sprintf("Unknown database '%s'", dbname)}
+	}
+	d.queries++
+	return nil
+}
@@ -0,0 +1,384 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sigV4Algorithm      = "AWS4-HMAC-SHA256"
+	sigV4DateFormat     = "20060102T150405Z"
+	sigV4UnsignedBody   = "UNSIGNED-PAYLOAD"
+	sigV4StreamingBody  = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	fakeObjectStoreETag = `"d41d8cd98f00b204e9800998ecf8427e"`
+)
+
+// FakeObjectStore is an in-memory S3 compatible object store, serving path style requests signed with AWS Signature
+// V4 in their headers or in pre-signed URLs. It implements the bucket location, bucket creation, object HEAD, GET and
+// PUT, and multipart upload operations, which covers the health checks, uploads, streamed uploads and pre-signing done
+// by DSPO with minio-go.
+type FakeObjectStore struct {
+	AccessKey string
+	SecretKey string
+
+	server  *httptest.Server
+	mu      sync.Mutex
+	buckets map[string]map[string][]byte
+	// uploads holds the parts of the multipart uploads in progress, by upload ID and part number
+	uploads map[string]map[int][]byte
+}
+
+// NewFakeObjectStore starts an object store accepting the credentials, served over TLS with a self-signed certificate
+// if secure is set, see CACert.
+func NewFakeObjectStore(accessKey, secretKey string, secure bool) *FakeObjectStore {
+	s := &FakeObjectStore{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		buckets:   map[string]map[string][]byte{},
+		uploads:   map[string]map[int][]byte{},
+	}
+	if secure {
+		s.server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	} else {
+		s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	}
+	return s
+}
+
+// Close shuts the object store down.
+func (s *FakeObjectStore) Close() {
+	s.server.Close()
+}
+
+// Host returns the host the object store listens on.
+func (s *FakeObjectStore) Host() string {
+	host, _, _ := net.SplitHostPort(s.server.Listener.Addr().String())
+	return host
+}
+
+// Port returns the port the object store listens on.
+func (s *FakeObjectStore) Port() string {
+	_, port, _ := net.SplitHostPort(s.server.Listener.Addr().String())
+	return port
+}
+
+// Endpoint returns the host:port of the object store, as passed to minio.New.
+func (s *FakeObjectStore) Endpoint() string {
+	return s.server.Listener.Addr().String()
+}
+
+// CACert returns the PEM encoded certificate of an object store served over TLS, to be trusted as a CA bundle.
+func (s *FakeObjectStore) CACert() []byte {
+	if s.server.Certificate() == nil {
+		return nil
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.server.Certificate().Raw})
+}
+
+// CreateBucket creates an empty bucket, if it doesn't exist yet.
+func (s *FakeObjectStore) CreateBucket(bucket string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.buckets[bucket]; !ok {
+		s.buckets[bucket] = map[string][]byte{}
+	}
+}
+
+// Object returns the contents of the object, and false if it doesn't exist.
+func (s *FakeObjectStore) Object(bucket, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	contents, ok := s.buckets[bucket][key]
+	return contents, ok
+}
+
+// Keys returns the sorted keys of the objects in the bucket.
+func (s *FakeObjectStore) Keys(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for key := range s.buckets[bucket] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PutObject stores the object, creating its bucket if needed.
+func (s *FakeObjectStore) PutObject(bucket, key string, contents []byte) {
+	s.CreateBucket(bucket)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buckets[bucket][key] = contents
+}
+
+func (s *FakeObjectStore) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if code, message := s.authenticate(r); code != "" {
+		writeS3Error(w, r, http.StatusForbidden, code, message)
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	s.mu.Lock()
+	objects, bucketFound := s.buckets[bucket]
+	s.mu.Unlock()
+
+	switch {
+	case bucket == "":
+		writeS3Error(w, r, http.StatusNotImplemented, "NotImplemented", "Listing buckets is not implemented")
+	case key == "" && r.Method == http.MethodPut:
+		s.CreateBucket(bucket)
+		w.WriteHeader(http.StatusOK)
+	case !bucketFound:
+		writeS3Error(w, r, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+	case key == "" && r.Method == http.MethodGet && r.URL.Query().Has("location"):
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`)
+	case key != "" && r.Method == http.MethodPost && r.URL.Query().Has("uploads"):
+		s.mu.Lock()
+		uploadID := strconv.Itoa(len(s.uploads) + 1)
+		s.uploads[uploadID] = map[int][]byte{}
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>`,
+			bucket, key, uploadID)
+	case key != "" && r.Method == http.MethodPut && r.URL.Query().Has("uploadId"):
+		partNumber, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+		if err != nil {
+			writeS3Error(w, r, http.StatusBadRequest, "InvalidArgument", "Invalid part number")
+			return
+		}
+		contents, err := readS3Body(r)
+		if err != nil {
+			writeS3Error(w, r, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+		s.mu.Lock()
+		parts, found := s.uploads[r.URL.Query().Get("uploadId")]
+		if found {
+			parts[partNumber] = contents
+		}
+		s.mu.Unlock()
+		if !found {
+			writeS3Error(w, r, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist")
+			return
+		}
+		w.Header().Set("ETag", fakeObjectStoreETag)
+		w.WriteHeader(http.StatusOK)
+	case key != "" && r.Method == http.MethodPost && r.URL.Query().Has("uploadId"):
+		s.mu.Lock()
+		parts, found := s.uploads[r.URL.Query().Get("uploadId")]
+		delete(s.uploads, r.URL.Query().Get("uploadId"))
+		s.mu.Unlock()
+		if !found {
+			writeS3Error(w, r, http.StatusNotFound, "NoSuchUpload", "The specified upload does not exist")
+			return
+		}
+		var contents []byte
+		for partNumber := 1; partNumber <= len(parts); partNumber++ {
+			contents = append(contents, parts[partNumber]...)
+		}
+		s.PutObject(bucket, key, contents)
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>%s</ETag></CompleteMultipartUploadResult>`,
+			bucket, key, fakeObjectStoreETag)
+	case key != "" && r.Method == http.MethodPut:
+		contents, err := readS3Body(r)
+		if err != nil {
+			writeS3Error(w, r, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+		s.PutObject(bucket, key, contents)
+		w.Header().Set("ETag", fakeObjectStoreETag)
+		w.WriteHeader(http.StatusOK)
+	case key != "" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		s.mu.Lock()
+		contents, found := objects[key]
+		s.mu.Unlock()
+		if !found {
+			writeS3Error(w, r, http.StatusNotFound, "NoSuchKey", "The specified key does not exist")
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(contents)))
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("ETag", fakeObjectStoreETag)
+		w.Header().Set("Last-Modified", time.Unix(0, 0).UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(contents)
+		}
+	default:
+		writeS3Error(w, r, http.StatusNotImplemented, "NotImplemented", fmt.Sprintf("%s is not implemented", r.Method))
+	}
+}
+
+// authenticate verifies the Signature V4 of the request, returning the S3 error code if it is rejected.
+func (s *FakeObjectStore) authenticate(r *http.Request) (string, string) {
+	query := r.URL.Query()
+	var credential, signedHeaders, signature, date, payloadHash string
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		algorithm, fields, _ := strings.Cut(auth, " ")
+		if algorithm != sigV4Algorithm {
+			return "AccessDenied", "Only AWS Signature Version 4 is supported"
+		}
+		for _, field := range strings.Split(fields, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+			switch name {
+			case "Credential":
+				credential = value
+			case "SignedHeaders":
+				signedHeaders = value
+			case "Signature":
+				signature = value
+			}
+		}
+		date = r.Header.Get("X-Amz-Date")
+		payloadHash = r.Header.Get("X-Amz-Content-Sha256")
+	} else if query.Get("X-Amz-Algorithm") == sigV4Algorithm {
+		credential = query.Get("X-Amz-Credential")
+		signedHeaders = query.Get("X-Amz-SignedHeaders")
+		signature = query.Get("X-Amz-Signature")
+		date = query.Get("X-Amz-Date")
+		payloadHash = sigV4UnsignedBody
+		query.Del("X-Amz-Signature")
+
+		signedAt, err := time.Parse(sigV4DateFormat, date)
+		expires, _ := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if err != nil || time.Now().After(signedAt.Add(time.Duration(expires)*time.Second)) {
+			return "AccessDenied", "Request has expired"
+		}
+	} else {
+		return "AccessDenied", "Anonymous access is forbidden"
+	}
+
+	// Credential is <access key>/<date>/<region>/s3/aws4_request
+	accessKey, scope, _ := strings.Cut(credential, "/")
+	if accessKey != s.AccessKey {
+		return "InvalidAccessKeyId", "The Access Key Id you provided does not exist in our records"
+	}
+	scopeFields := strings.Split(scope, "/")
+	if len(scopeFields) != 4 || payloadHash == "" {
+		return "AuthorizationHeaderMalformed", "The authorization header is malformed"
+	}
+
+	var headers strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		values := r.Header.Values(name)
+		if name == "host" {
+			values = []string{r.Host}
+		}
+		for i, v := range values {
+			values[i] = strings.Join(strings.Fields(v), " ")
+		}
+		headers.WriteString(name + ":" + strings.Join(values, ",") + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		encodeS3Path(r.URL.Path),
+		strings.ReplaceAll(query.Encode(), "+", "%20"),
+		headers.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{sigV4Algorithm, date, scope, hex.EncodeToString(hashedRequest[:])}, "\n")
+
+	key := []byte("AWS4" + s.SecretKey)
+	for _, field := range scopeFields {
+		key = hmacSHA256(key, field)
+	}
+	if !hmac.Equal([]byte(hex.EncodeToString(hmacSHA256(key, stringToSign))), []byte(signature)) {
+		return "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided"
+	}
+	return "", ""
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// encodeS3Path encodes the path as minio-go does when signing, escaping everything but unreserved characters and '/'.
+func encodeS3Path(path string) string {
+	var encoded strings.Builder
+	for _, c := range []byte(path) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', strings.IndexByte("-_.~/", c) >= 0:
+			encoded.WriteByte(c)
+		default:
+			encoded.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+	}
+	return encoded.String()
+}
+
+// readS3Body reads the object of a PUT request, decoding the aws-chunked encoding minio-go uses over plain HTTP. The
+// chunk signatures are not verified, the seed signature of the request is.
+func readS3Body(r *http.Request) ([]byte, error) {
+	if r.Header.Get("X-Amz-Content-Sha256") != sigV4StreamingBody {
+		return io.ReadAll(r.Body)
+	}
+	var contents bytes.Buffer
+	reader := bufio.NewReader(r.Body)
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeField, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeField, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chunk size [%s]", sizeField)
+		}
+		if _, err := io.CopyN(&contents, reader, size); err != nil {
+			return nil, err
+		}
+		if _, err := reader.Discard(2); err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return contents.Bytes(), nil
+		}
+	}
+}
+
+func writeS3Error(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	// HEAD responses have no body, minio-go infers the error code from the status
+	if r.Method != http.MethodHead {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message><Resource>%s</Resource></Error>`,
+			code, message, url.PathEscape(r.URL.Path))
+	}
+}
@@ -0,0 +1,92 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	mf "github.com/manifestival/manifestival"
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// SetupUpdateStrategies maps the name of every component Deployment to its UpdateStrategy.
+func (p *DSPAParams) SetupUpdateStrategies() {
+	p.UpdateStrategies = make(map[string]*dspav1alpha1.UpdateStrategy)
+	if p.APIServer != nil {
+		p.UpdateStrategies[p.APIServerDefaultResourceName] = p.APIServer.UpdateStrategy
+	}
+	if p.PersistenceAgent != nil {
+		p.UpdateStrategies[p.PersistentAgentDefaultResourceName] = p.PersistenceAgent.UpdateStrategy
+	}
+	if p.ScheduledWorkflow != nil {
+		p.UpdateStrategies[p.ScheduledWorkflowDefaultResourceName] = p.ScheduledWorkflow.UpdateStrategy
+	}
+	if p.MariaDB != nil {
+		p.UpdateStrategies[config.DerivedName(config.MariaDBHostPrefix+"-", p.Name)] = p.MariaDB.UpdateStrategy
+	}
+	if p.Minio != nil {
+		p.UpdateStrategies[config.DerivedName(config.MinioHostPrefix+"-", p.Name)] = p.Minio.UpdateStrategy
+	}
+	if p.MlPipelineUI != nil {
+		p.UpdateStrategies[config.DerivedName("ds-pipeline-ui-", p.Name)] = p.MlPipelineUI.UpdateStrategy
+	}
+	if p.MLMD != nil {
+		p.UpdateStrategies[config.DerivedName("ds-pipeline-metadata-envoy-", p.Name)] = p.MLMD.Envoy.UpdateStrategy
+		p.UpdateStrategies[config.DerivedName("ds-pipeline-metadata-grpc-", p.Name)] = p.MLMD.GRPC.UpdateStrategy
+		p.UpdateStrategies[config.DerivedName("ds-pipeline-metadata-writer-", p.Name)] = p.MLMD.Writer.UpdateStrategy
+	}
+	if p.CacheServer != nil {
+		p.UpdateStrategies[p.CacheServerDefaultResourceName] = p.CacheServer.UpdateStrategy
+	}
+}
+
+// injectUpdateStrategies replaces the strategy of component Deployments with their UpdateStrategy, if set. Components
+// without one keep the strategy of their template.
+func injectUpdateStrategies(params *DSPAParams) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" {
+			return nil
+		}
+		strategy := params.UpdateStrategies[u.GetName()]
+		if strategy == nil {
+			return nil
+		}
+
+		rendered := map[string]interface{}{"type": strategy.Type}
+		if strategy.Type == "RollingUpdate" {
+			rollingUpdate := make(map[string]interface{})
+			if strategy.MaxSurge != nil {
+				rollingUpdate["maxSurge"] = intOrStringValue(*strategy.MaxSurge)
+			}
+			if strategy.MaxUnavailable != nil {
+				rollingUpdate["maxUnavailable"] = intOrStringValue(*strategy.MaxUnavailable)
+			}
+			if len(rollingUpdate) > 0 {
+				rendered["rollingUpdate"] = rollingUpdate
+			}
+		}
+		return unstructured.SetNestedMap(u.Object, rendered, "spec", "strategy")
+	}
+}
+
+// intOrStringValue returns the value of an IntOrString as it's set in unstructured objects.
+func intOrStringValue(value intstr.IntOrString) interface{} {
+	if value.Type == intstr.Int {
+		return int64(value.IntVal)
+	}
+	return value.StrVal
+}
@@ -0,0 +1,112 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDeployWithUpdateStrategy(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedPersistenceAgentName := persistenceAgentDefaultResourceNamePrefix + testDSPAName
+	expectedMariaDBName := "mariadb-" + testDSPAName
+
+	// Construct DSPASpec with a rolling update of the PersistenceAgent surging by half its replicas
+	maxSurge := intstr.FromString("50%")
+	maxUnavailable := intstr.FromInt(0)
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			PersistenceAgent: &dspav1alpha1.PersistenceAgent{
+				Deploy: true,
+				UpdateStrategy: &dspav1alpha1.UpdateStrategy{
+					Type:           "RollingUpdate",
+					MaxSurge:       &maxSurge,
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Run test reconciliation
+	err = reconciler.ReconcilePersistenceAgent(dspa, params)
+	assert.Nil(t, err)
+	err = reconciler.ReconcileDatabase(ctx, dspa, params)
+	assert.Nil(t, err)
+
+	// Assert the PersistenceAgent Deployment uses the configured strategy
+	deployment := &appsv1.Deployment{}
+	created, err := reconciler.IsResourceCreated(ctx, deployment, expectedPersistenceAgentName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+	assert.Equal(t, maxSurge, *deployment.Spec.Strategy.RollingUpdate.MaxSurge)
+	assert.Equal(t, maxUnavailable, *deployment.Spec.Strategy.RollingUpdate.MaxUnavailable)
+
+	// Assert the MariaDB Deployment keeps the strategy of its template
+	deployment = &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, expectedMariaDBName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, appsv1.RecreateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+}
+
+func TestInjectRecreateUpdateStrategy(t *testing.T) {
+	params := &DSPAParams{UpdateStrategies: map[string]*dspav1alpha1.UpdateStrategy{
+		"ds-pipeline-testdspa": {Type: "Recreate"},
+	}}
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "Deployment",
+		"metadata": map[string]interface{}{"name": "ds-pipeline-testdspa"},
+		"spec": map[string]interface{}{"strategy": map[string]interface{}{
+			"type":          "RollingUpdate",
+			"rollingUpdate": map[string]interface{}{"maxSurge": int64(1), "maxUnavailable": int64(0)},
+		}},
+	}}
+
+	// Ensure the rolling update settings of the template are dropped, as the Recreate type doesn't allow them
+	assert.Nil(t, injectUpdateStrategies(params)(deployment))
+	strategy, _, _ := unstructured.NestedMap(deployment.Object, "spec", "strategy")
+	assert.Equal(t, map[string]interface{}{"type": "Recreate"}, strategy)
+}
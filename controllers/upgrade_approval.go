@@ -0,0 +1,87 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// approvedUpgradeAnnotation is set on a DSPA with Manual upgrade approval to the operator version it may be upgraded to
+const approvedUpgradeAnnotation = "datasciencepipelinesapplications.opendatahub.io/approved-upgrade"
+
+// PendingUpgrade is an operator upgrade whose component changes are awaiting approval.
+type PendingUpgrade struct {
+	FromVersion  string
+	ToVersion    string
+	ImageChanges []string
+}
+
+func (u *PendingUpgrade) Message() string {
+	changes := "no component image changes"
+	if len(u.ImageChanges) > 0 {
+		changes = "component image changes: " + strings.Join(u.ImageChanges, ", ")
+	}
+	return fmt.Sprintf("Upgrade from operator version %s to %s is awaiting approval, annotate the DSPA with %s=%s to apply it. This upgrade has %s",
+		u.FromVersion, u.ToVersion, approvedUpgradeAnnotation, u.ToVersion, changes)
+}
+
+// GetPendingUpgrade compares the version manifest of the last applied components with what the running operator would
+// apply, and returns the upgrade if the DSPA requires Manual approval and it hasn't been approved yet. nil is returned
+// for DSPAs that haven't been deployed yet, or were last reconciled by the running operator version.
+func (r *DSPAReconciler) GetPendingUpgrade(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) (*PendingUpgrade, error) {
+
+	if dsp.Spec.UpgradeApproval != config.UpgradeApprovalManual || dsp.GetAnnotations()[approvedUpgradeAnnotation] == config.OperatorVersion {
+		return nil, nil
+	}
+
+	manifest := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: config.DerivedName("ds-pipeline-version-manifest-", dsp.Name), Namespace: dsp.Namespace}, manifest)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	fromVersion := manifest.Data["operatorVersion"]
+	if fromVersion == config.OperatorVersion {
+		return nil, nil
+	}
+
+	upgrade := &PendingUpgrade{FromVersion: fromVersion, ToVersion: config.OperatorVersion}
+	upgrade.ImageChanges = imageChanges(manifest, params.componentImages(dsp))
+	return upgrade, nil
+}
+
+// imageChanges lists the components whose image in the version manifest differs from the given images, sorted.
+func imageChanges(manifest *corev1.ConfigMap, images map[string]string) []string {
+	var changes []string
+	for component, image := range images {
+		if deployed := manifest.Data[component+".image"]; deployed != "" && deployed != image {
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", component, deployed, image))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
@@ -0,0 +1,116 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/util"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPendingUpgrade(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	defaultOperatorVersion := config.OperatorVersion
+	config.OperatorVersion = "v1.1.0"
+	t.Cleanup(func() { config.OperatorVersion = defaultOperatorVersion })
+
+	// Construct DSPASpec requiring Manual upgrade approval
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer: &dspav1alpha1.APIServer{
+				Deploy: true,
+				Image:  "api-server:v1.1.0",
+			},
+			PersistenceAgent: &dspav1alpha1.PersistenceAgent{
+				Deploy: true,
+				Image:  "persistence-agent:v1.0.0",
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+			UpgradeApproval: config.UpgradeApprovalManual,
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Name = testDSPAName
+	dspa.Namespace = testNamespace
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Assert DSPAs that haven't been deployed yet have no pending upgrade
+	upgrade, err := reconciler.GetPendingUpgrade(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Nil(t, upgrade)
+
+	// Assert components last applied by an older operator version await approval
+	manifest := &v1.ConfigMap{Data: map[string]string{
+		"operatorVersion":        "v1.0.0",
+		"apiServer.image":        "api-server:v1.0.0",
+		"persistenceAgent.image": "persistence-agent:v1.0.0",
+	}}
+	manifest.Name = "ds-pipeline-version-manifest-" + testDSPAName
+	manifest.Namespace = testNamespace
+	assert.Nil(t, reconciler.Create(ctx, manifest))
+	upgrade, err = reconciler.GetPendingUpgrade(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Equal(t, &PendingUpgrade{
+		FromVersion:  "v1.0.0",
+		ToVersion:    "v1.1.0",
+		ImageChanges: []string{"apiServer api-server:v1.0.0 -> api-server:v1.1.0"},
+	}, upgrade)
+
+	// Assert the pending upgrade is reported in the DSPA status
+	params.PendingUpgrade = upgrade
+	conditions, err := reconciler.GenerateStatus(ctx, dspa, params, true, true)
+	assert.Nil(t, err)
+	upgradePending := util.GetConditionByType(config.UpgradePending, conditions)
+	assert.Equal(t, metav1.ConditionTrue, upgradePending.Status)
+	assert.Equal(t, config.AwaitingApproval, upgradePending.Reason)
+	assert.Contains(t, upgradePending.Message, "apiServer api-server:v1.0.0 -> api-server:v1.1.0")
+
+	// Assert approved upgrades are applied
+	dspa.Annotations = map[string]string{approvedUpgradeAnnotation: "v1.1.0"}
+	upgrade, err = reconciler.GetPendingUpgrade(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Nil(t, upgrade)
+
+	// Assert upgrades are applied right away with Automatic approval
+	dspa.Annotations = nil
+	dspa.Spec.UpgradeApproval = "Automatic"
+	upgrade, err = reconciler.GetPendingUpgrade(ctx, dspa, params)
+	assert.Nil(t, err)
+	assert.Nil(t, upgrade)
+}
@@ -0,0 +1,333 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// UpgradeReportConfigMapName is the ConfigMap the upgrade dry run writes its report to
+const UpgradeReportConfigMapName = "data-science-pipelines-operator-upgrade-report"
+
+// upgradeWorkloads are the Deployment and DaemonSet templates of each component, along with whether the DSPA deploys
+// the component. The params are those of ExtractParams.
+var upgradeWorkloads = []struct {
+	template string
+	deployed func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool
+}{
+	{"apiserver/deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return dsp.Spec.APIServer != nil && dsp.Spec.APIServer.Deploy
+	}},
+	{"apiserver/grpc-envoy.deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return p.UsingAPIServerGRPC(dsp)
+	}},
+	{"persistence-agent/deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return dsp.Spec.PersistenceAgent != nil && dsp.Spec.PersistenceAgent.Deploy
+	}},
+	{"scheduled-workflow/deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return dsp.Spec.ScheduledWorkflow != nil && dsp.Spec.ScheduledWorkflow.Deploy
+	}},
+	{"mariadb/deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return !p.UsingExternalDB(dsp) && p.MariaDB != nil && p.MariaDB.Deploy
+	}},
+	{"minio/deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return !p.UsingExternalStorage(dsp) && p.Minio != nil && p.Minio.Deploy
+	}},
+	{"mlpipelines-ui/deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return dsp.Spec.MlPipelineUI != nil && dsp.Spec.MlPipelineUI.Deploy
+	}},
+	{"ml-metadata/metadata-envoy.deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return p.UsingMLMD(dsp)
+	}},
+	{"ml-metadata/metadata-grpc.deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return p.UsingMLMD(dsp)
+	}},
+	{"ml-metadata/metadata-writer.deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return p.UsingMLMD(dsp)
+	}},
+	{"image-prepuller/daemonset.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return p.UsingImagePrepuller(dsp)
+	}},
+	{"cache-server/deployment.yaml.tmpl", func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return p.UsingCacheServer(dsp)
+	}},
+	{headroomDeploymentTemplate, func(dsp *dspav1alpha1.DataSciencePipelinesApplication, p *DSPAParams) bool {
+		return p.UsingHeadroom(dsp)
+	}},
+}
+
+// UpgradeImpact lists what the running operator version would change on a DSPA, without applying it.
+type UpgradeImpact struct {
+	// FromVersion is the operator version that last applied the DSPA's components
+	FromVersion string `json:"fromVersion,omitempty"`
+	// ApprovalRequired is set for DSPAs with Manual upgrade approval, whose changes are held back until approved
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+	// ImageChanges lists the component image changes, compared to the version manifest
+	ImageChanges []string `json:"imageChanges,omitempty"`
+	// Restarts lists the workloads whose pod template would change, rolling out new pods, and what changes
+	Restarts []string `json:"restarts,omitempty"`
+	// Error is set if the impact on the DSPA couldn't be determined, e.g. the operator would fail to reconcile it
+	Error string `json:"error,omitempty"`
+}
+
+// Changed returns true if the upgrade would change the DSPA's components, or its impact couldn't be determined.
+func (i *UpgradeImpact) Changed() bool {
+	return len(i.ImageChanges) > 0 || len(i.Restarts) > 0 || i.Error != ""
+}
+
+// GetUpgradeImpact renders the workloads the running operator would apply to the DSPA, and compares them with the
+// deployed ones. Only the fields of the pod template that roll out new pods when changed are compared, i.e. container
+// images, commands, arguments and environment, and pod annotations such as the API Server config checksum.
+func (r *DSPAReconciler) GetUpgradeImpact(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) (*UpgradeImpact, error) {
+	impact := &UpgradeImpact{ApprovalRequired: dsp.Spec.UpgradeApproval == config.UpgradeApprovalManual &&
+		dsp.GetAnnotations()[approvedUpgradeAnnotation] != config.OperatorVersion}
+
+	params := &DSPAParams{}
+	if err := params.ExtractParams(ctx, dsp, r.Client, r.Log); err != nil {
+		impact.Error = fmt.Sprintf("Encountered error when parsing CR: [%s]", err)
+		return impact, nil
+	}
+	if err := r.ApplyKnownGoodImages(ctx, dsp, params); err != nil {
+		impact.Error = fmt.Sprintf("Encountered error when rolling back images: [%s]", err)
+		return impact, nil
+	}
+
+	manifest := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: config.DerivedName("ds-pipeline-version-manifest-", dsp.Name), Namespace: dsp.Namespace}, manifest)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return nil, err
+	}
+	impact.FromVersion = manifest.Data["operatorVersion"]
+	impact.ImageChanges = imageChanges(manifest, params.componentImages(dsp))
+
+	if dsp.Spec.APIServer != nil && dsp.Spec.APIServer.Deploy {
+		params.APIServerConfigChecksum, err = r.apiServerConfigChecksum(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, workload := range upgradeWorkloads {
+		if !workload.deployed(dsp, params) {
+			continue
+		}
+		restarts, err := r.workloadRestarts(ctx, dsp, params, workload.template)
+		if err != nil {
+			return nil, err
+		}
+		impact.Restarts = append(impact.Restarts, restarts...)
+	}
+	return impact, nil
+}
+
+// workloadRestarts compares the pod templates of the rendered workloads with the deployed ones. Workloads that aren't
+// deployed yet are skipped: they are new, not restarted.
+func (r *DSPAReconciler) workloadRestarts(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams, template string) ([]string, error) {
+	// Rendered as applied, so every transformer changing the pod templates is accounted for
+	tmplManifest, err := r.render(dsp, params, template)
+	if err != nil {
+		return nil, err
+	}
+
+	var restarts []string
+	for _, rendered := range tmplManifest.Resources() {
+		deployed := &unstructured.Unstructured{}
+		deployed.SetGroupVersionKind(rendered.GroupVersionKind())
+		err := r.Get(ctx, types.NamespacedName{Name: rendered.GetName(), Namespace: rendered.GetNamespace()}, deployed)
+		if apierrs.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		want, err := podTemplate(&rendered)
+		if err != nil {
+			return nil, err
+		}
+		got, err := podTemplate(deployed)
+		if err != nil {
+			return nil, err
+		}
+		if changes := podTemplateChanges(want, got); len(changes) > 0 {
+			restarts = append(restarts, fmt.Sprintf("%s/%s: %s", rendered.GetKind(), rendered.GetName(), strings.Join(changes, ", ")))
+		}
+	}
+	return restarts, nil
+}
+
+func podTemplate(u *unstructured.Unstructured) (*corev1.PodTemplateSpec, error) {
+	template := &corev1.PodTemplateSpec{}
+	raw, _, err := unstructured.NestedMap(u.Object, "spec", "template")
+	if err != nil {
+		return nil, err
+	}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(raw, template)
+	return template, err
+}
+
+// podTemplateChanges lists the fields of the wanted pod template that differ from the deployed one. Fields defaulted
+// by the API server are not compared, as they are unset in the templates.
+func podTemplateChanges(want, got *corev1.PodTemplateSpec) []string {
+	var changes []string
+	for key, value := range want.Annotations {
+		if got.Annotations[key] != value {
+			changes = append(changes, fmt.Sprintf("pod annotation %s", key))
+		}
+	}
+	sort.Strings(changes)
+
+	for _, field := range []struct {
+		kind      string
+		want, got []corev1.Container
+	}{
+		{"initContainer", want.Spec.InitContainers, got.Spec.InitContainers},
+		{"container", want.Spec.Containers, got.Spec.Containers},
+	} {
+		deployed := make(map[string]corev1.Container, len(field.got))
+		for _, container := range field.got {
+			deployed[container.Name] = container
+		}
+		for _, container := range field.want {
+			current, ok := deployed[container.Name]
+			if !ok {
+				changes = append(changes, fmt.Sprintf("%s %s added", field.kind, container.Name))
+				continue
+			}
+			delete(deployed, container.Name)
+			if current.Image != container.Image {
+				changes = append(changes, fmt.Sprintf("%s %s image %s -> %s", field.kind, container.Name, current.Image, container.Image))
+			}
+			if strings.Join(current.Command, " ") != strings.Join(container.Command, " ") {
+				changes = append(changes, fmt.Sprintf("%s %s command", field.kind, container.Name))
+			}
+			if strings.Join(current.Args, " ") != strings.Join(container.Args, " ") {
+				changes = append(changes, fmt.Sprintf("%s %s args", field.kind, container.Name))
+			}
+			for _, name := range envChanges(container.Env, current.Env) {
+				changes = append(changes, fmt.Sprintf("%s %s env %s", field.kind, container.Name, name))
+			}
+		}
+		var removed []string
+		for name := range deployed {
+			removed = append(removed, fmt.Sprintf("%s %s removed", field.kind, name))
+		}
+		sort.Strings(removed)
+		changes = append(changes, removed...)
+	}
+	return changes
+}
+
+// envChanges lists the sorted names of the environment variables added, removed or changed. Values read from
+// Secrets and ConfigMaps are compared by reference, not by content.
+func envChanges(want, got []corev1.EnvVar) []string {
+	values := func(env []corev1.EnvVar) map[string]string {
+		m := make(map[string]string, len(env))
+		for _, e := range env {
+			value := e.Value
+			if from := e.ValueFrom; from != nil {
+				switch {
+				case from.SecretKeyRef != nil:
+					value = fmt.Sprintf("secret %s/%s", from.SecretKeyRef.Name, from.SecretKeyRef.Key)
+				case from.ConfigMapKeyRef != nil:
+					value = fmt.Sprintf("configmap %s/%s", from.ConfigMapKeyRef.Name, from.ConfigMapKeyRef.Key)
+				case from.FieldRef != nil:
+					value = "field " + from.FieldRef.FieldPath
+				case from.ResourceFieldRef != nil:
+					value = "resource " + from.ResourceFieldRef.Resource
+				}
+			}
+			m[e.Name] = value
+		}
+		return m
+	}
+	wantValues, gotValues := values(want), values(got)
+
+	var changed []string
+	for name, value := range wantValues {
+		if current, ok := gotValues[name]; !ok || current != value {
+			changed = append(changed, name)
+		}
+	}
+	for name := range gotValues {
+		if _, ok := wantValues[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// WriteUpgradeReport writes the UpgradeImpact of every DSPA of the cluster to the UpgradeReportConfigMapName
+// ConfigMap of the namespace, without applying any change to the DSPAs. Each DSPA the upgrade would change has a
+// <namespace>.<name> key, the others are listed under the unchanged key.
+func (r *DSPAReconciler) WriteUpgradeReport(ctx context.Context, namespace string) error {
+	dspas := &dspav1alpha1.DataSciencePipelinesApplicationList{}
+	if err := r.List(ctx, dspas); err != nil {
+		return err
+	}
+
+	data := map[string]string{"operatorVersion": config.OperatorVersion}
+	var unchanged []string
+	for i := range dspas.Items {
+		dspa := &dspas.Items[i]
+		if !dspa.DeletionTimestamp.IsZero() {
+			continue
+		}
+		impact, err := r.GetUpgradeImpact(ctx, dspa)
+		if err != nil {
+			return fmt.Errorf("unable to determine the upgrade impact on DSPA [%s/%s]: %w", dspa.Namespace, dspa.Name, err)
+		}
+		key := dspa.Namespace + "." + dspa.Name
+		if !impact.Changed() {
+			unchanged = append(unchanged, key)
+			continue
+		}
+		report, err := yaml.Marshal(impact)
+		if err != nil {
+			return err
+		}
+		data[key] = string(report)
+	}
+	sort.Strings(unchanged)
+	data["unchanged"] = strings.Join(unchanged, "\n")
+
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: UpgradeReportConfigMapName, Namespace: namespace}, cm)
+	if apierrs.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: UpgradeReportConfigMapName, Namespace: namespace},
+			Data:       data,
+		}
+		return r.Create(ctx, cm)
+	} else if err != nil {
+		return err
+	}
+	cm.Data = data
+	return r.Update(ctx, cm)
+}
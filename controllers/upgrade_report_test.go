@@ -0,0 +1,75 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestUpgradeReport(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "testdspa", Namespace: "testnamespace"},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:     &dspav1alpha1.APIServer{Deploy: true},
+			Database:      &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: false, Image: "someimage"}},
+		},
+	}
+	ctx, params, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	assert.Nil(t, params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log))
+	assert.Nil(t, reconciler.ReconcileAPIServer(ctx, dspa, params))
+
+	// Ensure a DSPA the running operator version already reconciled is reported unchanged
+	impact, err := reconciler.GetUpgradeImpact(ctx, dspa)
+	assert.Nil(t, err)
+	assert.False(t, impact.Changed(), impact.Restarts)
+
+	// Ensure the workloads the upgrade would restart are reported, along with what changes
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-testdspa", Namespace: dspa.Namespace}, deployment))
+	container := &deployment.Spec.Template.Spec.Containers[0]
+	wantImage := container.Image
+	container.Image = "quay.io/opendatahub/ds-pipelines-api-server:previous"
+	container.Env = append(container.Env, v1.EnvVar{Name: "REMOVED_SETTING", Value: "true"})
+	deployment.Spec.Template.Annotations[apiServerConfigChecksumAnnotation] = "previous"
+	assert.Nil(t, reconciler.Update(ctx, deployment))
+
+	impact, err = reconciler.GetUpgradeImpact(ctx, dspa)
+	assert.Nil(t, err)
+	assert.True(t, impact.Changed())
+	assert.Equal(t, []string{"Deployment/ds-pipeline-testdspa: pod annotation " + apiServerConfigChecksumAnnotation +
+		", container ds-pipeline-api-server image quay.io/opendatahub/ds-pipelines-api-server:previous -> " + wantImage +
+		", container ds-pipeline-api-server env REMOVED_SETTING"}, impact.Restarts)
+
+	// Ensure the report lists the DSPA without changing it
+	assert.Nil(t, reconciler.WriteUpgradeReport(ctx, "dspo"))
+	report := &v1.ConfigMap{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: UpgradeReportConfigMapName, Namespace: "dspo"}, report))
+	assert.Contains(t, report.Data["testnamespace.testdspa"], "env REMOVED_SETTING")
+	assert.Equal(t, "", report.Data["unchanged"])
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "ds-pipeline-testdspa", Namespace: dspa.Namespace}, deployment))
+	assert.Equal(t, "quay.io/opendatahub/ds-pipelines-api-server:previous", deployment.Spec.Template.Spec.Containers[0].Image)
+}
@@ -0,0 +1,48 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// verifyAnnotation is set to "now" on a DSPA to re-run its health checks right away, e.g. after fixing credentials
+	verifyAnnotation = "datasciencepipelinesapplications.opendatahub.io/verify"
+	// verifiedAtAnnotation records when the health checks requested through verifyAnnotation ran
+	verifiedAtAnnotation = "datasciencepipelinesapplications.opendatahub.io/verified-at"
+)
+
+// AcknowledgeVerification replaces the verify annotation of a DSPA with the time its health checks ran. Annotating the
+// DSPA triggers a reconcile, which runs the health checks and refreshes the status conditions, so this is called once
+// the status has been updated.
+func (r *DSPAReconciler) AcknowledgeVerification(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	verifiedAt time.Time) error {
+
+	if dsp.GetAnnotations()[verifyAnnotation] != "now" {
+		return nil
+	}
+	r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name).Info("Verified dependencies on demand")
+
+	patch := client.MergeFrom(dsp.DeepCopy())
+	delete(dsp.Annotations, verifyAnnotation)
+	dsp.Annotations[verifiedAtAnnotation] = verifiedAt.UTC().Format(time.RFC3339)
+	return r.Patch(ctx, dsp, patch)
+}
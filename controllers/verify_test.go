@@ -0,0 +1,53 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestAcknowledgeVerification(t *testing.T) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testdspa",
+			Namespace: "testnamespace",
+		},
+	}
+	ctx, _, reconciler := CreateNewTestObjects()
+	assert.Nil(t, reconciler.Create(ctx, dspa))
+	verifiedAt := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	// Assert DSPAs without a verification request are left alone
+	assert.Nil(t, reconciler.AcknowledgeVerification(ctx, dspa, verifiedAt))
+	assert.Empty(t, dspa.Annotations)
+
+	// Assert verification requests are replaced with the time the health checks ran
+	dspa.Annotations = map[string]string{verifyAnnotation: "now"}
+	assert.Nil(t, reconciler.Update(ctx, dspa))
+	assert.Nil(t, reconciler.AcknowledgeVerification(ctx, dspa, verifiedAt))
+	verified := &dspav1alpha1.DataSciencePipelinesApplication{}
+	assert.Nil(t, reconciler.Get(ctx, types.NamespacedName{Name: "testdspa", Namespace: "testnamespace"}, verified))
+	assert.NotContains(t, verified.Annotations, verifyAnnotation)
+	assert.Equal(t, "2023-09-01T12:00:00Z", verified.Annotations[verifiedAtAnnotation])
+}
@@ -0,0 +1,190 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const versionManifestTemplate = "common/version-manifest.configmap.yaml.tmpl"
+
+// Provenance annotations set on every resource managed by DSPO
+const (
+	operatorVersionAnnotation = "datasciencepipelinesapplications.opendatahub.io/operator-version"
+	sourceRevisionAnnotation  = "datasciencepipelinesapplications.opendatahub.io/source-revision"
+	imagesAnnotation          = "datasciencepipelinesapplications.opendatahub.io/images"
+)
+
+// injectProvenance annotates resources with the operator build that manages them, and workloads
+// with the images of their containers, so running pods can be mapped to exact builds.
+func injectProvenance(u *unstructured.Unstructured) error {
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[operatorVersionAnnotation] = config.OperatorVersion
+	annotations[sourceRevisionAnnotation] = config.SourceRevision
+
+	if u.GetKind() == "Deployment" || u.GetKind() == "DaemonSet" {
+		var images []string
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, _, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", field)
+			if err != nil {
+				return err
+			}
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				images = append(images, fmt.Sprintf("%s=%s", container["name"], container["image"]))
+			}
+		}
+		annotations[imagesAnnotation] = strings.Join(images, ",")
+	}
+
+	u.SetAnnotations(annotations)
+	return nil
+}
+
+// componentImageFields returns the image fields of the DSPA components DSPO deploys, keyed by component.
+func (p *DSPAParams) componentImageFields(dsp *dspav1alpha1.DataSciencePipelinesApplication) map[string]*string {
+	images := make(map[string]*string)
+	if p.APIServer != nil && p.APIServer.Deploy {
+		images["apiServer"] = &p.APIServer.Image
+		images["artifact"] = &p.APIServer.ArtifactImage
+		images["cache"] = &p.APIServer.CacheImage
+		images["moveResults"] = &p.APIServer.MoveResultsImage
+		images["oauthProxy"] = &p.OAuthProxy
+	}
+	if p.UsingAPIServerGRPC(dsp) {
+		images["apiServerGRPCProxy"] = &p.APIServer.GRPC.Image
+	}
+	if p.PersistenceAgent != nil && p.PersistenceAgent.Deploy {
+		images["persistenceAgent"] = &p.PersistenceAgent.Image
+	}
+	if p.ScheduledWorkflow != nil && p.ScheduledWorkflow.Deploy {
+		images["scheduledWorkflow"] = &p.ScheduledWorkflow.Image
+	}
+	if !p.UsingExternalDB(dsp) && p.MariaDB != nil && p.MariaDB.Deploy {
+		images["mariaDB"] = &p.MariaDB.Image
+	}
+	if !p.UsingExternalStorage(dsp) && p.Minio != nil && p.Minio.Deploy {
+		images["minio"] = &p.Minio.Image
+	}
+	if p.MlPipelineUI != nil && p.MlPipelineUI.Deploy {
+		images["mlPipelineUI"] = &p.MlPipelineUI.Image
+	}
+	if p.UsingMLMD(dsp) {
+		images["mlmdEnvoy"] = &p.MLMD.Envoy.Image
+		images["mlmdGRPC"] = &p.MLMD.GRPC.Image
+		images["mlmdWriter"] = &p.MLMD.Writer.Image
+	}
+	if p.UsingImagePrepuller(dsp) {
+		images["imagePrepuller"] = &p.ImagePrepuller.Image
+	}
+	if p.UsingCacheServer(dsp) {
+		images["cacheServer"] = &p.CacheServer.Image
+	}
+	if p.UsingHeadroom(dsp) {
+		images["headroom"] = &p.Headroom.Image
+	}
+	return images
+}
+
+// componentImages returns the images of the DSPA components DSPO deploys, keyed by component.
+func (p *DSPAParams) componentImages(dsp *dspav1alpha1.DataSciencePipelinesApplication) map[string]string {
+	images := make(map[string]string)
+	for component, image := range p.componentImageFields(dsp) {
+		images[component] = *image
+	}
+	return images
+}
+
+// ReconcileVersionManifest publishes the ds-pipeline-version-manifest ConfigMap, listing the operator build and
+// the image of every deployed component, along with the image IDs (digests) the DSPA's pods are running.
+func (r *DSPAReconciler) ReconcileVersionManifest(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication,
+	params *DSPAParams) error {
+
+	log := r.Log.WithValues("namespace", dsp.Namespace).WithValues("dspa_name", dsp.Name)
+
+	log.Info("Applying Version Manifest")
+
+	imageIDs, err := r.runningImageIDs(ctx, dsp)
+	if err != nil {
+		return err
+	}
+
+	params.VersionManifest = make(map[string]string)
+	for component, image := range params.componentImages(dsp) {
+		params.VersionManifest[component+".image"] = image
+		if ids, ok := imageIDs[image]; ok {
+			params.VersionManifest[component+".imageID"] = strings.Join(uniqueSorted(ids), ",")
+		}
+	}
+
+	err = r.Apply(dsp, params, versionManifestTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Finished applying Version Manifest")
+	return nil
+}
+
+// runningImageIDs returns the image IDs the containers of the DSPA's pods are running, keyed by image.
+func (r *DSPAReconciler) runningImageIDs(ctx context.Context, dsp *dspav1alpha1.DataSciencePipelinesApplication) (map[string][]string, error) {
+	pods := &corev1.PodList{}
+	err := r.List(ctx, pods, client.InNamespace(dsp.Namespace), client.MatchingLabels{
+		"component": "data-science-pipelines",
+		"dspa":      dsp.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	imageIDs := make(map[string][]string)
+	for _, pod := range pods.Items {
+		statuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.ImageID != "" {
+				imageIDs[status.Image] = append(imageIDs[status.Image], status.ImageID)
+			}
+		}
+	}
+	return imageIDs, nil
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -0,0 +1,105 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestDeployVersionManifest(t *testing.T) {
+	testNamespace := "testnamespace"
+	testDSPAName := "testdspa"
+	expectedVersionManifestName := "ds-pipeline-version-manifest-testdspa"
+	expectedPersistenceAgentName := persistenceAgentDefaultResourceNamePrefix + testDSPAName
+
+	// Construct DSPASpec with deployed PersistenceAgent
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{
+		Spec: dspav1alpha1.DSPASpec{
+			PersistenceAgent: &dspav1alpha1.PersistenceAgent{
+				Deploy: true,
+				Image:  "persistenceagent:v1",
+			},
+			Database: &dspav1alpha1.Database{
+				MariaDB: &dspav1alpha1.MariaDB{
+					Deploy: true,
+					Image:  "mariadb:v1",
+				},
+			},
+			ObjectStorage: &dspav1alpha1.ObjectStorage{
+				Minio: &dspav1alpha1.Minio{
+					Deploy: false,
+					Image:  "someimage",
+				},
+			},
+		},
+	}
+
+	// Enrich DSPA with name+namespace
+	dspa.Namespace = testNamespace
+	dspa.Name = testDSPAName
+
+	// Create Context, Fake Controller and Params
+	ctx, params, reconciler := CreateNewTestObjects()
+	err := params.ExtractParams(ctx, dspa, reconciler.Client, reconciler.Log)
+	assert.Nil(t, err)
+
+	// Simulate a running PersistenceAgent pod
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "ds-pipeline-persistenceagent", Image: "persistenceagent:v1"}}},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+			{Name: "ds-pipeline-persistenceagent", Image: "persistenceagent:v1", ImageID: "persistenceagent@sha256:abc"},
+		}},
+	}
+	pod.Name = "persistenceagent-pod"
+	pod.Namespace = testNamespace
+	pod.Labels = map[string]string{"component": "data-science-pipelines", "dspa": testDSPAName}
+	assert.Nil(t, reconciler.Create(ctx, pod))
+
+	// Run test reconciliation
+	err = reconciler.ReconcilePersistenceAgent(dspa, params)
+	assert.Nil(t, err)
+	err = reconciler.ReconcileVersionManifest(ctx, dspa, params)
+	assert.Nil(t, err)
+
+	// Ensure the Version Manifest lists deployed component images and running digests
+	configMap := &v1.ConfigMap{}
+	created, err := reconciler.IsResourceCreated(ctx, configMap, expectedVersionManifestName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"operatorVersion":          config.OperatorVersion,
+		"sourceRevision":           config.SourceRevision,
+		"persistenceAgent.image":   "persistenceagent:v1",
+		"persistenceAgent.imageID": "persistenceagent@sha256:abc",
+		"mariaDB.image":            "mariadb:v1",
+	}, configMap.Data)
+
+	// Ensure managed resources carry provenance annotations
+	deployment := &appsv1.Deployment{}
+	created, err = reconciler.IsResourceCreated(ctx, deployment, expectedPersistenceAgentName, testNamespace)
+	assert.True(t, created)
+	assert.Nil(t, err)
+	assert.Equal(t, config.SourceRevision, deployment.Annotations[sourceRevisionAnnotation])
+	assert.Equal(t, "ds-pipeline-persistenceagent=persistenceagent:v1", deployment.Annotations[imagesAnnotation])
+}
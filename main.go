@@ -22,7 +22,9 @@ import (
 	"github.com/golang/glog"
 	"github.com/opendatahub-io/data-science-pipelines-operator/controllers/config"
 	"github.com/spf13/viper"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -34,14 +36,18 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -104,6 +110,11 @@ func main() {
 	var probeAddr string
 	var configPath string
 	var maxConcurrentReconciles int
+	var notificationWorkers int
+	var debugAddr string
+	var detailedMetrics bool
+	var upgradeReportNamespace string
+	var webhookCertDir string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.StringVar(&configPath, "config", "", "Path to JSON file containing config")
@@ -111,6 +122,14 @@ func main() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.IntVar(&maxConcurrentReconciles, "MaxConcurrentReconciles", config.DefaultMaxConcurrentReconciles, "Maximum concurrent reconciles")
+	flag.IntVar(&notificationWorkers, "notification-workers", config.DefaultNotificationWorkers, "Number of DSPAs whose run status webhooks and commit statuses are delivered concurrently")
+	flag.StringVar(&debugAddr, "debug-bind-address", "0", "The address the pprof endpoints, served over TLS and authorized through RBAC, bind to. Set to 0 to disable them.")
+	flag.BoolVar(&detailedMetrics, "detailed-metrics", false, "Publish per DSPA reconcile duration and API request metrics, to debug reconcile storms.")
+	flag.StringVar(&upgradeReportNamespace, "upgrade-dry-run", "",
+		"Write the changes this operator version would apply to every DSPA to the "+controllers.UpgradeReportConfigMapName+
+			" ConfigMap of the given namespace, and exit without reconciling any DSPA.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"),
+		"The directory of the tls.crt and tls.key of the admission webhook server and the debug endpoints. They are only served if they exist.")
 	opts := zap.Options{
 		Development: true,
 		TimeEncoder: zapcore.TimeEncoderOfLayout(time.RFC3339),
@@ -125,10 +144,31 @@ func main() {
 		glog.Fatal(err)
 	}
 
+	if upgradeReportNamespace != "" {
+		c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create client")
+			os.Exit(1)
+		}
+		reconciler := &controllers.DSPAReconciler{
+			Client:        c,
+			Scheme:        scheme,
+			Log:           ctrl.Log,
+			TemplatesPath: "config/internal/",
+		}
+		if err := reconciler.WriteUpgradeReport(ctrl.SetupSignalHandler(), upgradeReportNamespace); err != nil {
+			setupLog.Error(err, "unable to write upgrade report")
+			os.Exit(1)
+		}
+		setupLog.Info("wrote upgrade report", "namespace", upgradeReportNamespace, "configmap", controllers.UpgradeReportConfigMapName)
+		return
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
+		CertDir:                webhookCertDir,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "f9eb95d5.opendatahub.io",
@@ -138,16 +178,90 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.DSPAReconciler{
+	// The webhook server fails the manager without a serving certificate, it's only started once one is mounted
+	var webhookService *types.NamespacedName
+	if _, err := os.Stat(filepath.Join(webhookCertDir, "tls.crt")); err == nil && os.Getenv("OPERATOR_NAMESPACE") != "" {
+		webhookService = &types.NamespacedName{
+			Name:      config.GetStringConfigWithDefault(config.WebhookServiceConfigName, config.DefaultWebhookService),
+			Namespace: os.Getenv("OPERATOR_NAMESPACE"),
+		}
+		mgr.GetWebhookServer().Register(controllers.GuardrailsWebhookPath, &webhook.Admission{
+			Handler: &controllers.GuardrailsWebhook{Client: mgr.GetClient(), Log: ctrl.Log.WithName("guardrails")},
+		})
+		mgr.GetWebhookServer().Register(controllers.SchedulePolicyWebhookPath, &webhook.Admission{
+			Handler: &controllers.SchedulePolicyWebhook{Client: mgr.GetClient(), Log: ctrl.Log.WithName("schedule-policy")},
+		})
+		mgr.GetWebhookServer().Register(controllers.CostAttributionWebhookPath, &webhook.Admission{
+			Handler: &controllers.CostAttributionWebhook{Client: mgr.GetClient(), Log: ctrl.Log.WithName("cost-attribution")},
+		})
+		mgr.GetWebhookServer().Register(controllers.StepRetriesWebhookPath, &webhook.Admission{
+			Handler: &controllers.StepRetriesWebhook{Client: mgr.GetClient(), Log: ctrl.Log.WithName("step-retries")},
+		})
+		mgr.GetWebhookServer().Register(controllers.DSPAValidationWebhookPath, &webhook.Admission{
+			Handler: &controllers.DSPAValidationWebhook{Client: mgr.GetClient(), Log: ctrl.Log.WithName("dspa-validation")},
+		})
+	} else {
+		setupLog.Info("not serving the admission webhooks, no serving certificate found", "dir", webhookCertDir)
+	}
+
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create clientset")
+		os.Exit(1)
+	}
+	reconciler := &controllers.DSPAReconciler{
 		Client:                  mgr.GetClient(),
+		Clientset:               clientset,
 		Scheme:                  mgr.GetScheme(),
 		Log:                     ctrl.Log,
 		TemplatesPath:           "config/internal/",
 		MaxConcurrentReconciles: maxConcurrentReconciles,
-	}).SetupWithManager(mgr); err != nil {
+		DetailedMetrics:         detailedMetrics,
+		WebhookService:          webhookService,
+		OperatorNamespace:       os.Getenv("OPERATOR_NAMESPACE"),
+		Recorder:                mgr.GetEventRecorderFor("datasciencepipelinesapplication-controller"),
+	}
+	reconciler.Notifications = controllers.NewNotificationDispatcher(reconciler, notificationWorkers, config.NotificationPassTimeout)
+	if err := mgr.Add(reconciler.Notifications); err != nil {
+		setupLog.Error(err, "unable to set up notification delivery")
+		os.Exit(1)
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DSPAParams")
 		os.Exit(1)
 	}
+	if detailedMetrics {
+		controllers.InitDetailedMetrics()
+	}
+
+	if debugAddr != "0" {
+		// The debug endpoints are served with the serving certificate of the webhooks, so bearer tokens aren't sent in the clear
+		if _, err := os.Stat(filepath.Join(webhookCertDir, "tls.crt")); err != nil {
+			setupLog.Info("not serving the debug endpoints, no serving certificate found", "dir", webhookCertDir)
+		} else if err := mgr.Add(&controllers.DebugServer{Addr: debugAddr, CertDir: webhookCertDir, Client: mgr.GetClient(), Log: ctrl.Log.WithName("debug")}); err != nil {
+			setupLog.Error(err, "unable to set up debug endpoints")
+			os.Exit(1)
+		}
+	}
+
+	if config.GetBoolConfigWithDefault(config.TelemetryEnabledConfigName, false) {
+		if os.Getenv("OPERATOR_NAMESPACE") == "" {
+			setupLog.Error(fmt.Errorf("OPERATOR_NAMESPACE is not set"), "unable to set up telemetry, its payload is published in the operator namespace")
+			os.Exit(1)
+		}
+		reporter := &controllers.TelemetryReporter{
+			Client:     mgr.GetClient(),
+			Log:        ctrl.Log.WithName("telemetry"),
+			Namespace:  os.Getenv("OPERATOR_NAMESPACE"),
+			Endpoint:   config.GetStringConfigWithDefault(config.TelemetryEndpointConfigName, ""),
+			Interval:   config.GetDurationConfigWithDefault(config.TelemetryIntervalConfigName, config.DefaultTelemetryInterval),
+			HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		}
+		if err := mgr.Add(reporter); err != nil {
+			setupLog.Error(err, "unable to set up telemetry")
+			os.Exit(1)
+		}
+	}
 
 	//+kubebuilder:scaffold:builder
 
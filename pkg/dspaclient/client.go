@@ -0,0 +1,186 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dspaclient manages DSPAs from platform controllers and tools, without copying the DSPA API types:
+//
+//	c, err := dspaclient.New(ctrl.GetConfigOrDie())
+//	err = c.Create(ctx, dspabuilder.New("sample", "my-project").Build())
+//	dspa, err := c.WaitForReady(ctx, "my-project", "sample")
+//	endpoints, err := c.GetEndpoints(ctx, dspa)
+//
+// The package path and the exported identifiers are kept stable across operator releases.
+package dspaclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// readyCondition is the condition the operator reports True once every component of the DSPA is ready
+const readyCondition = "Ready"
+
+// DefaultPollInterval is how often WaitForReady gets the DSPA
+const DefaultPollInterval = 5 * time.Second
+
+// Scheme holds the DSPA types, along with the Kubernetes and Route types of their components.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
+	utilruntime.Must(routev1.AddToScheme(Scheme))
+	utilruntime.Must(dspav1alpha1.AddToScheme(Scheme))
+}
+
+// Client gets, lists, creates, updates and deletes DSPAs, and waits for them to be ready.
+type Client struct {
+	client client.Client
+	// PollInterval is how often WaitForReady gets the DSPA. Default: DefaultPollInterval
+	PollInterval time.Duration
+}
+
+// New returns a Client of the cluster of the config.
+func New(config *rest.Config) (*Client, error) {
+	c, err := client.New(config, client.Options{Scheme: Scheme})
+	if err != nil {
+		return nil, err
+	}
+	return NewForClient(c), nil
+}
+
+// NewForClient returns a Client using a controller-runtime client, e.g. the client of the manager of a controller.
+// Its scheme must hold the DSPA types.
+func NewForClient(c client.Client) *Client {
+	return &Client{client: c, PollInterval: DefaultPollInterval}
+}
+
+func (c *Client) Get(ctx context.Context, namespace, name string) (*dspav1alpha1.DataSciencePipelinesApplication, error) {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{}
+	if err := c.client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, dspa); err != nil {
+		return nil, err
+	}
+	return dspa, nil
+}
+
+// List lists the DSPAs of the namespace, or of every namespace if it's "".
+func (c *Client) List(ctx context.Context, namespace string, opts ...client.ListOption) ([]dspav1alpha1.DataSciencePipelinesApplication, error) {
+	dspas := &dspav1alpha1.DataSciencePipelinesApplicationList{}
+	if err := c.client.List(ctx, dspas, append(opts, client.InNamespace(namespace))...); err != nil {
+		return nil, err
+	}
+	return dspas.Items, nil
+}
+
+func (c *Client) Create(ctx context.Context, dspa *dspav1alpha1.DataSciencePipelinesApplication) error {
+	return c.client.Create(ctx, dspa)
+}
+
+func (c *Client) Update(ctx context.Context, dspa *dspav1alpha1.DataSciencePipelinesApplication) error {
+	return c.client.Update(ctx, dspa)
+}
+
+// Delete deletes the DSPA, if it exists. The operator then removes the components it deployed.
+func (c *Client) Delete(ctx context.Context, namespace, name string) error {
+	dspa := &dspav1alpha1.DataSciencePipelinesApplication{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	return client.IgnoreNotFound(c.client.Delete(ctx, dspa))
+}
+
+// IsReady returns true if the operator reported the DSPA ready, for its current spec.
+func IsReady(dspa *dspav1alpha1.DataSciencePipelinesApplication) bool {
+	ready := meta.FindStatusCondition(dspa.Status.Conditions, readyCondition)
+	return ready != nil && ready.Status == metav1.ConditionTrue && ready.ObservedGeneration >= dspa.Generation
+}
+
+// WaitForReady waits until the operator reports the DSPA ready, for its current spec, and returns it. Bound the wait
+// with the deadline of the context. Once the context is done, the error includes why the DSPA isn't ready.
+func (c *Client) WaitForReady(ctx context.Context, namespace, name string) (*dspav1alpha1.DataSciencePipelinesApplication, error) {
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	var dspa *dspav1alpha1.DataSciencePipelinesApplication
+	var lastErr error
+	err := wait.PollImmediateUntilWithContext(ctx, interval, func(ctx context.Context) (bool, error) {
+		dspa, lastErr = c.Get(ctx, namespace, name)
+		if lastErr != nil {
+			// The DSPA may not be in the cache of the client yet
+			return false, client.IgnoreNotFound(lastErr)
+		}
+		return IsReady(dspa), nil
+	})
+	if err == nil {
+		return dspa, nil
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("DSPA [%s] in namespace [%s] is not ready: %w", name, namespace, lastErr)
+	}
+	if dspa == nil {
+		return nil, fmt.Errorf("DSPA [%s] in namespace [%s] is not ready: %w", name, namespace, err)
+	}
+	if ready := meta.FindStatusCondition(dspa.Status.Conditions, readyCondition); ready != nil {
+		return dspa, fmt.Errorf("DSPA [%s] in namespace [%s] is not ready, %s: %s", name, namespace, ready.Reason, ready.Message)
+	}
+	return dspa, fmt.Errorf("DSPA [%s] in namespace [%s] is not ready, the operator hasn't reported its status", name, namespace)
+}
+
+// Endpoints are the URLs the components of a DSPA are reached at. Components the DSPA doesn't deploy or expose have
+// no URL.
+type Endpoints struct {
+	// In-cluster URL of the API Server REST API, served by its oauth-proxy, or over plain http in dev mode
+	APIServer string
+	// URL of the Route of the API Server REST API
+	APIServerRoute string
+	// URL of the Route of the API Server gRPC API
+	APIServerGRPCRoute string
+	// In-cluster URL of the UI
+	UI string
+	// URL of the Route of the UI
+	UIRoute string
+	// In-cluster address of the MLMD gRPC server, host:port
+	MLMDGRPC string
+}
+
+// GetEndpoints returns the endpoints of the components of the DSPA, as the operator reported them in
+// status.outputs.endpoints, so they match the Service ports and Route hosts it configured.
+func (c *Client) GetEndpoints(ctx context.Context, dspa *dspav1alpha1.DataSciencePipelinesApplication) (*Endpoints, error) {
+	outputs := dspa.Status.Outputs
+	if outputs == nil {
+		return nil, fmt.Errorf("DSPA [%s] in namespace [%s] doesn't report its endpoints yet", dspa.Name, dspa.Namespace)
+	}
+	endpoints := &Endpoints{}
+	if reported := outputs.Endpoints; reported != nil {
+		endpoints = &Endpoints{
+			APIServer:          reported.APIServer,
+			APIServerRoute:     reported.APIServerRoute,
+			APIServerGRPCRoute: reported.APIServerGRPCRoute,
+			UI:                 reported.UI,
+			UIRoute:            reported.UIRoute,
+			MLMDGRPC:           reported.MLMDGRPC,
+		}
+	}
+	return endpoints, nil
+}
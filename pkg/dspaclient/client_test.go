@@ -0,0 +1,131 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dspaclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/opendatahub-io/data-science-pipelines-operator/tests/dspabuilder"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestClient() *Client {
+	c := NewForClient(fake.NewClientBuilder().WithScheme(Scheme).Build())
+	c.PollInterval = 10 * time.Millisecond
+	return c
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient()
+	assert.Nil(t, c.Create(ctx, dspabuilder.New("sample", "my-project").Build()))
+	assert.Nil(t, c.Create(ctx, dspabuilder.New("other", "other-project").Build()))
+
+	dspa, err := c.Get(ctx, "my-project", "sample")
+	assert.Nil(t, err)
+	dspa.Spec.DevMode = true
+	assert.Nil(t, c.Update(ctx, dspa))
+	dspas, err := c.List(ctx, "my-project")
+	assert.Nil(t, err)
+	assert.Len(t, dspas, 1)
+	assert.True(t, dspas[0].Spec.DevMode)
+	dspas, err = c.List(ctx, "")
+	assert.Nil(t, err)
+	assert.Len(t, dspas, 2)
+
+	// Ensure deleting a DSPA that doesn't exist, e.g. on a retry, succeeds
+	assert.Nil(t, c.Delete(ctx, "my-project", "sample"))
+	assert.Nil(t, c.Delete(ctx, "my-project", "sample"))
+	dspas, err = c.List(ctx, "my-project")
+	assert.Nil(t, err)
+	assert.Empty(t, dspas)
+}
+
+func TestWaitForReady(t *testing.T) {
+	c := newTestClient()
+	dspa := dspabuilder.New("sample", "my-project").Build()
+	dspa.Generation = 2
+	meta.SetStatusCondition(&dspa.Status.Conditions, metav1.Condition{
+		Type: readyCondition, Status: metav1.ConditionFalse, Reason: "MinimumReplicasAvailable", Message: "Component [ds-pipeline-sample] is deploying.", ObservedGeneration: 2,
+	})
+	assert.Nil(t, c.Create(context.Background(), dspa))
+
+	// Ensure the error tells why the DSPA isn't ready once the wait times out
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := c.WaitForReady(ctx, "my-project", "sample")
+	assert.ErrorContains(t, err, "is not ready, MinimumReplicasAvailable: Component [ds-pipeline-sample] is deploying.")
+
+	// Ensure a Ready condition of a previous spec isn't taken as ready
+	meta.SetStatusCondition(&dspa.Status.Conditions, metav1.Condition{
+		Type: readyCondition, Status: metav1.ConditionTrue, Reason: "MinimumReplicasAvailable", ObservedGeneration: 1,
+	})
+	assert.False(t, IsReady(dspa))
+	dspa.Status.Conditions[0].ObservedGeneration = 2
+	assert.True(t, IsReady(dspa))
+
+	ready, err := c.Get(context.Background(), "my-project", "sample")
+	assert.Nil(t, err)
+	ready.Status = dspa.Status
+	assert.Nil(t, c.Update(context.Background(), ready))
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ready, err = c.WaitForReady(ctx, "my-project", "sample")
+	assert.Nil(t, err)
+	assert.Equal(t, "sample", ready.Name)
+}
+
+func TestGetEndpoints(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient()
+	dspa := dspabuilder.New("sample", "my-project").Build()
+
+	_, err := c.GetEndpoints(ctx, dspa)
+	assert.ErrorContains(t, err, "doesn't report its endpoints yet")
+
+	// Ensure the endpoints the operator reported are returned as they are
+	dspa.Status.Outputs = &dspav1alpha1.StatusOutputs{
+		SchemaVersion: "v1",
+		Endpoints: &dspav1alpha1.OutputEndpoints{
+			APIServer:      "https://ds-pipeline-sample.my-project.svc.cluster.local:8443",
+			APIServerRoute: "https://ds-pipeline-sample-my-project.apps.example.com",
+			UI:             "https://ds-pipeline-ui-sample.my-project.svc.cluster.local:8443",
+			MLMDGRPC:       "ds-pipeline-metadata-grpc-sample.my-project.svc.cluster.local:8080",
+		},
+	}
+	endpoints, err := c.GetEndpoints(ctx, dspa)
+	assert.Nil(t, err)
+	assert.Equal(t, &Endpoints{
+		APIServer:      "https://ds-pipeline-sample.my-project.svc.cluster.local:8443",
+		APIServerRoute: "https://ds-pipeline-sample-my-project.apps.example.com",
+		UI:             "https://ds-pipeline-ui-sample.my-project.svc.cluster.local:8443",
+		MLMDGRPC:       "ds-pipeline-metadata-grpc-sample.my-project.svc.cluster.local:8080",
+	}, endpoints)
+
+	// Ensure DSPAs exposing no component have no endpoints
+	dspa.Status.Outputs.Endpoints = nil
+	endpoints, err = c.GetEndpoints(ctx, dspa)
+	assert.Nil(t, err)
+	assert.Equal(t, &Endpoints{}, endpoints)
+}
@@ -0,0 +1,330 @@
+//go:build test_conformance
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/data-science-pipelines-operator/tests/runrequest"
+)
+
+// kfpClient calls the KFP v1beta1 REST API of the endpoint under test.
+type kfpClient struct {
+	endpoint string
+	token    string
+	http     *http.Client
+}
+
+func newKFPClient(endpoint, token string, insecureSkipTLSVerify bool) *kfpClient {
+	return &kfpClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		token:    token,
+		http: &http.Client{
+			Timeout: time.Minute,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipTLSVerify},
+			},
+		},
+	}
+}
+
+// apiError is returned for responses with a non 2xx status.
+type apiError struct {
+	Method string
+	Path   string
+	Status int
+	Body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d: %s", e.Method, e.Path, e.Status, e.Body)
+}
+
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*apiError)
+	return ok && apiErr.Status == http.StatusNotFound
+}
+
+func (c *kfpClient) do(ctx context.Context, method, path string, body io.Reader, contentType string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &apiError{Method: method, Path: path, Status: resp.StatusCode, Body: string(data)}
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s %s returned an invalid body: %w", method, path, err)
+	}
+	return nil
+}
+
+func (c *kfpClient) doJSON(ctx context.Context, method, path string, in, out interface{}) error {
+	if in == nil {
+		return c.do(ctx, method, path, nil, "", out)
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, method, path, bytes.NewReader(data), "application/json", out)
+}
+
+type apiPipeline struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	DefaultVersion *struct {
+		ID string `json:"id"`
+	} `json:"default_version"`
+}
+
+type apiResourceReference struct {
+	Key struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	} `json:"key"`
+	Relationship string `json:"relationship"`
+}
+
+type apiRun struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	PipelineSpec struct {
+		PipelineID string                 `json:"pipeline_id"`
+		Parameters []runrequest.Parameter `json:"parameters"`
+	} `json:"pipeline_spec"`
+	ResourceReferences []apiResourceReference `json:"resource_references"`
+	CreatedAt          time.Time              `json:"created_at"`
+	ScheduledAt        time.Time              `json:"scheduled_at"`
+	FinishedAt         time.Time              `json:"finished_at"`
+}
+
+type apiRunDetail struct {
+	Run             apiRun `json:"run"`
+	PipelineRuntime struct {
+		WorkflowManifest string `json:"workflow_manifest"`
+	} `json:"pipeline_runtime"`
+}
+
+type apiJob struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+func experimentReference(experimentID string) []apiResourceReference {
+	ref := apiResourceReference{Relationship: "OWNER"}
+	ref.Key.Type = "EXPERIMENT"
+	ref.Key.ID = experimentID
+	return []apiResourceReference{ref}
+}
+
+func (c *kfpClient) uploadPipeline(ctx context.Context, name, path string) (*apiPipeline, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("uploadfile", filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(contents); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+	pipeline := &apiPipeline{}
+	err = c.do(ctx, http.MethodPost, "/apis/v1beta1/pipelines/upload?name="+url.QueryEscape(name), &body, form.FormDataContentType(), pipeline)
+	return pipeline, err
+}
+
+func (c *kfpClient) createExperiment(ctx context.Context, name string) (string, error) {
+	experiment := &struct {
+		ID string `json:"id"`
+	}{}
+	err := c.doJSON(ctx, http.MethodPost, "/apis/v1beta1/experiments", map[string]string{"name": name}, experiment)
+	return experiment.ID, err
+}
+
+// createRun runs the pipeline in the experiment, overriding the defaults of the pipeline with the parameters.
+func (c *kfpClient) createRun(ctx context.Context, name, pipelineID, experimentID string, parameters []runrequest.Parameter) (*apiRun, error) {
+	builder := runrequest.New(name).WithPipeline(pipelineID).WithExperiment(experimentID)
+	for _, p := range parameters {
+		builder.WithParameter(p.Name, p.Value)
+	}
+	request, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	run := &apiRunDetail{}
+	err = c.doJSON(ctx, http.MethodPost, "/apis/v1beta1/runs", request, run)
+	return &run.Run, err
+}
+
+// retryRun reruns the failed steps of a finished run.
+func (c *kfpClient) retryRun(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodPost, "/apis/v1beta1/runs/"+id+"/retry", nil, nil)
+}
+
+func (c *kfpClient) getRun(ctx context.Context, id string) (*apiRunDetail, error) {
+	run := &apiRunDetail{}
+	err := c.doJSON(ctx, http.MethodGet, "/apis/v1beta1/runs/"+id, nil, run)
+	return run, err
+}
+
+// waitForRun polls the run until it finishes, returning an error if it didn't succeed. Argo backends report
+// Succeeded, Tekton backends Completed.
+func (c *kfpClient) waitForRun(ctx context.Context, id string, timeout time.Duration) (*apiRunDetail, error) {
+	run, err := c.waitForFinish(ctx, id, timeout, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if !succeeded(run.Run.Status) {
+		return nil, fmt.Errorf("run [%s] finished with status [%s]", id, run.Run.Status)
+	}
+	return run, nil
+}
+
+func succeeded(status string) bool {
+	return status == "Succeeded" || status == "Completed"
+}
+
+// waitForFinish polls the run until it reaches a final status, whether it succeeded or not. Retried runs keep their
+// final status until they rerun, so they are only considered finished once they finish after the time set in after.
+func (c *kfpClient) waitForFinish(ctx context.Context, id string, timeout time.Duration, after time.Time) (*apiRunDetail, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		run, err := c.getRun(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch run.Run.Status {
+		case "Succeeded", "Completed", "Failed", "Error", "Skipped", "Terminated":
+			if after.IsZero() || run.Run.FinishedAt.After(after) {
+				return run, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("run [%s] did not finish within %s, last status [%s]", id, timeout, run.Run.Status)
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (c *kfpClient) readArtifact(ctx context.Context, runID, nodeID, artifact string) ([]byte, error) {
+	out := &struct {
+		Data []byte `json:"data"`
+	}{}
+	err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/apis/v1beta1/runs/%s/nodes/%s/artifacts/%s:read", runID, nodeID, artifact), nil, out)
+	return out.Data, err
+}
+
+// listAll pages through a list endpoint of the API, page_size items at a time, calling visit with the items of every
+// page. Reading lists of namespaces with thousands of resources in a single call times out. filter is a KFP filter,
+// e.g. {"predicates":[{"key":"name","op":"EQUALS","string_value":"sample"}]}, ignored if empty.
+func (c *kfpClient) listAll(ctx context.Context, path, itemsField string, pageSize int, filter string, visit func(json.RawMessage) error) error {
+	seen := map[string]bool{}
+	token := ""
+	for {
+		query := url.Values{"page_size": {strconv.Itoa(pageSize)}}
+		if token != "" {
+			query.Set("page_token", token)
+		}
+		if filter != "" {
+			query.Set("filter", filter)
+		}
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+		page := map[string]json.RawMessage{}
+		if err := c.doJSON(ctx, http.MethodGet, path+separator+query.Encode(), nil, &page); err != nil {
+			return err
+		}
+		var items []json.RawMessage
+		if raw, ok := page[itemsField]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return fmt.Errorf("GET %s returned invalid %s: %w", path, itemsField, err)
+			}
+		}
+		for _, item := range items {
+			if err := visit(item); err != nil {
+				return err
+			}
+		}
+		token = ""
+		if raw, ok := page["next_page_token"]; ok {
+			if err := json.Unmarshal(raw, &token); err != nil {
+				return fmt.Errorf("GET %s returned an invalid next_page_token: %w", path, err)
+			}
+		}
+		if token == "" {
+			return nil
+		}
+		if seen[token] {
+			return fmt.Errorf("GET %s returned the page token [%s] twice", path, token)
+		}
+		seen[token] = true
+	}
+}
+
+func (c *kfpClient) delete(ctx context.Context, path string) error {
+	err := c.doJSON(ctx, http.MethodDelete, path, nil, nil)
+	if isNotFound(err) {
+		return nil
+	}
+	return err
+}
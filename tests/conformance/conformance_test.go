@@ -0,0 +1,506 @@
+//go:build test_conformance
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/data-science-pipelines-operator/tests/runrequest"
+)
+
+var (
+	endpoint              = flag.String("endpoint", "", "URL of the KFP API under test, e.g. the DSPA API Server Route")
+	token                 = flag.String("token", os.Getenv("KFP_TOKEN"), "Bearer token of the API, defaults to $KFP_TOKEN")
+	insecureSkipTLSVerify = flag.Bool("insecure-skip-tls-verify", false, "Do not verify the certificate of the endpoint")
+	cases                 = flag.String("cases", "", "Comma separated cases to run, all by default: upload,pagination,run,artifacts,recurring,metadata,retry,clone")
+	pipelinePath          = flag.String("pipeline", "../resources/dsp-operator/test-pipeline-run.yaml", "Pipeline to upload and run")
+	retryPipelinePath     = flag.String("retry-pipeline", "../resources/dsp-operator/test-pipeline-retry.yaml", "Pipeline failing unless the parameter set with -retry-parameter is false")
+	retryParameter        = flag.String("retry-parameter", "fail", "Parameter of the retry pipeline that makes it fail when true")
+	artifactTask          = flag.String("artifact-task", "flip-coin", "Task of the pipeline whose output artifact is read")
+	artifactName          = flag.String("artifact-name", "flip-coin-Output", "Output artifact of the task to read")
+	pageSize              = flag.Int("page-size", 100, "Page size of the list calls")
+	runTimeout            = flag.Duration("run-timeout", 20*time.Minute, "Time to wait for the pipeline run to finish")
+	reportJSON            = flag.String("report-json", "", "Path to write the JSON report to")
+	reportJUnit           = flag.String("report-junit", "", "Path to write the JUnit XML report to")
+)
+
+var report = &Report{StartedAt: time.Now()}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	report.Endpoint = *endpoint
+	code := m.Run()
+	if err := report.Write(*reportJSON, *reportJUnit); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not write the conformance report: %s\n", err)
+		code = 1
+	}
+	os.Exit(code)
+}
+
+// errNotSelected skips the cases left out with -cases.
+var errNotSelected = errors.New("case not selected")
+
+// suite holds the resources shared by the cases. Cases create the resources of the cases they depend on if those were
+// not selected, so any subset of the profile can run on its own.
+type suite struct {
+	client       *kfpClient
+	pipeline     *apiPipeline
+	experimentID string
+	run          *apiRunDetail
+	runErr       error
+	failedRun    *apiRunDetail
+	failedRunErr error
+	cleanups     []string
+}
+
+func (s *suite) ensurePipeline(ctx context.Context) (*apiPipeline, error) {
+	if s.pipeline != nil {
+		return s.pipeline, nil
+	}
+	pipeline, err := s.client.uploadPipeline(ctx, fmt.Sprintf("conformance-%d", time.Now().Unix()), *pipelinePath)
+	if err != nil {
+		return nil, err
+	}
+	s.pipeline = pipeline
+	s.cleanups = append(s.cleanups, "/apis/v1beta1/pipelines/"+pipeline.ID)
+	return pipeline, nil
+}
+
+func (s *suite) ensureExperiment(ctx context.Context) (string, error) {
+	if s.experimentID != "" {
+		return s.experimentID, nil
+	}
+	id, err := s.client.createExperiment(ctx, fmt.Sprintf("conformance-%d", time.Now().Unix()))
+	if err != nil {
+		return "", err
+	}
+	s.experimentID = id
+	s.cleanups = append(s.cleanups, "/apis/v1beta1/experiments/"+id)
+	return id, nil
+}
+
+// ensureCompletedRun runs the pipeline in the experiment, waiting for the run to succeed. A failed run fails every
+// case depending on it, without running the pipeline again.
+func (s *suite) ensureCompletedRun(ctx context.Context) (*apiRunDetail, error) {
+	if s.run != nil || s.runErr != nil {
+		return s.run, s.runErr
+	}
+	s.run, s.runErr = s.runPipeline(ctx)
+	return s.run, s.runErr
+}
+
+func (s *suite) runPipeline(ctx context.Context) (*apiRunDetail, error) {
+	pipeline, err := s.ensurePipeline(ctx)
+	if err != nil {
+		return nil, err
+	}
+	experimentID, err := s.ensureExperiment(ctx)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.client.createRun(ctx, "conformance-run", pipeline.ID, experimentID, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.cleanups = append(s.cleanups, "/apis/v1beta1/runs/"+run.ID)
+	return s.client.waitForRun(ctx, run.ID, *runTimeout)
+}
+
+// ensureFailedRun runs the retry pipeline with its failing parameter, waiting for the run to fail. Like the completed
+// run, it is shared by the cases depending on it.
+func (s *suite) ensureFailedRun(ctx context.Context) (*apiRunDetail, error) {
+	if s.failedRun != nil || s.failedRunErr != nil {
+		return s.failedRun, s.failedRunErr
+	}
+	s.failedRun, s.failedRunErr = s.runFailingPipeline(ctx)
+	return s.failedRun, s.failedRunErr
+}
+
+func (s *suite) runFailingPipeline(ctx context.Context) (*apiRunDetail, error) {
+	pipeline, err := s.client.uploadPipeline(ctx, fmt.Sprintf("conformance-retry-%d", time.Now().Unix()), *retryPipelinePath)
+	if err != nil {
+		return nil, err
+	}
+	s.cleanups = append(s.cleanups, "/apis/v1beta1/pipelines/"+pipeline.ID)
+	experimentID, err := s.ensureExperiment(ctx)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.client.createRun(ctx, "conformance-failing-run", pipeline.ID, experimentID,
+		[]runrequest.Parameter{{Name: *retryParameter, Value: "true"}})
+	if err != nil {
+		return nil, err
+	}
+	s.cleanups = append(s.cleanups, "/apis/v1beta1/runs/"+run.ID)
+	detail, err := s.client.waitForFinish(ctx, run.ID, *runTimeout, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if succeeded(detail.Run.Status) {
+		return nil, fmt.Errorf("run [%s] of the retry pipeline succeeded, it must fail when [%s] is true", run.ID, *retryParameter)
+	}
+	return detail, nil
+}
+
+func TestConformance(t *testing.T) {
+	if *endpoint == "" {
+		t.Fatal("-endpoint is required, e.g. go test -tags test_conformance ./tests/conformance -args -endpoint=https://<route>")
+	}
+	selected, err := SelectCases(*cases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &suite{client: newKFPClient(*endpoint, *token, *insecureSkipTLSVerify)}
+	ctx := context.Background()
+	t.Cleanup(func() {
+		// Runs are deleted before the experiment and pipeline they reference
+		for i := len(s.cleanups) - 1; i >= 0; i-- {
+			if err := s.client.delete(ctx, s.cleanups[i]); err != nil {
+				t.Logf("Could not clean up %s: %s", s.cleanups[i], err)
+			}
+		}
+	})
+
+	profile := map[string]func(context.Context, *suite) error{
+		CaseUpload:     testUpload,
+		CasePagination: testPagination,
+		CaseRun:        testRun,
+		CaseArtifacts:  testArtifacts,
+		CaseRecurring:  testRecurring,
+		CaseMetadata:   testMetadata,
+		CaseRetry:      testRetry,
+		CaseClone:      testClone,
+	}
+	for _, name := range Cases {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			if !selected[name] {
+				report.Record(name, 0, errNotSelected, true)
+				t.Skip(errNotSelected)
+			}
+			start := time.Now()
+			err := profile[name](ctx, s)
+			report.Record(name, time.Since(start), err, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// testUpload uploads the pipeline, which must then be readable with its default version.
+func testUpload(ctx context.Context, s *suite) error {
+	uploaded, err := s.ensurePipeline(ctx)
+	if err != nil {
+		return err
+	}
+	pipeline := &apiPipeline{}
+	if err := s.client.doJSON(ctx, http.MethodGet, "/apis/v1beta1/pipelines/"+uploaded.ID, nil, pipeline); err != nil {
+		return err
+	}
+	if pipeline.ID != uploaded.ID || pipeline.Name != uploaded.Name {
+		return fmt.Errorf("pipeline [%s] was read back as [%s] named [%s]", uploaded.ID, pipeline.ID, pipeline.Name)
+	}
+	if pipeline.DefaultVersion == nil || pipeline.DefaultVersion.ID == "" {
+		return fmt.Errorf("pipeline [%s] has no default version", pipeline.ID)
+	}
+	return nil
+}
+
+// testRun runs the pipeline in an experiment, the run must succeed and be listed in the experiment.
+func testRun(ctx context.Context, s *suite) error {
+	run, err := s.ensureCompletedRun(ctx)
+	if err != nil {
+		return err
+	}
+	listed := false
+	path := fmt.Sprintf("/apis/v1beta1/runs?resource_reference_key.type=EXPERIMENT&resource_reference_key.id=%s", s.experimentID)
+	err = s.client.listAll(ctx, path, "runs", *pageSize, "", func(item json.RawMessage) error {
+		r := &apiRun{}
+		if err := json.Unmarshal(item, r); err != nil {
+			return err
+		}
+		listed = listed || r.ID == run.Run.ID
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !listed {
+		return fmt.Errorf("run [%s] is not listed in experiment [%s]", run.Run.ID, s.experimentID)
+	}
+	return nil
+}
+
+// testPagination pages through the pipelines one at a time, every pipeline must be listed exactly once, then filters
+// them by name.
+func testPagination(ctx context.Context, s *suite) error {
+	uploaded, err := s.ensurePipeline(ctx)
+	if err != nil {
+		return err
+	}
+	listed := map[string]int{}
+	err = s.client.listAll(ctx, "/apis/v1beta1/pipelines", "pipelines", 1, "", func(item json.RawMessage) error {
+		pipeline := &apiPipeline{}
+		if err := json.Unmarshal(item, pipeline); err != nil {
+			return err
+		}
+		listed[pipeline.ID]++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for id, count := range listed {
+		if count > 1 {
+			return fmt.Errorf("pipeline [%s] was listed on %d pages", id, count)
+		}
+	}
+	if listed[uploaded.ID] != 1 {
+		return fmt.Errorf("pipeline [%s] was not listed when paging through %d pipelines", uploaded.ID, len(listed))
+	}
+
+	var filtered []string
+	filter := fmt.Sprintf(`{"predicates":[{"key":"name","op":"EQUALS","string_value":%q}]}`, uploaded.Name)
+	err = s.client.listAll(ctx, "/apis/v1beta1/pipelines", "pipelines", *pageSize, filter, func(item json.RawMessage) error {
+		pipeline := &apiPipeline{}
+		if err := json.Unmarshal(item, pipeline); err != nil {
+			return err
+		}
+		filtered = append(filtered, pipeline.ID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(filtered) != 1 || filtered[0] != uploaded.ID {
+		return fmt.Errorf("filtering pipelines by name [%s] listed %v instead of [%s]", uploaded.Name, filtered, uploaded.ID)
+	}
+	return nil
+}
+
+// testArtifacts reads an output artifact of the run through the API Server.
+func testArtifacts(ctx context.Context, s *suite) error {
+	run, err := s.ensureCompletedRun(ctx)
+	if err != nil {
+		return err
+	}
+	nodeID, err := findNode(run.PipelineRuntime.WorkflowManifest, *artifactTask)
+	if err != nil {
+		return err
+	}
+	data, err := s.client.readArtifact(ctx, run.Run.ID, nodeID, *artifactName)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("artifact [%s] of node [%s] is empty", *artifactName, nodeID)
+	}
+	return nil
+}
+
+// findNode returns the node ID of the task in the workflow manifest of a run: a TaskRun name for Tekton backends, a
+// node ID for Argo backends.
+func findNode(manifest, task string) (string, error) {
+	workflow := &struct {
+		Status struct {
+			TaskRuns map[string]struct {
+				PipelineTaskName string `json:"pipelineTaskName"`
+			} `json:"taskRuns"`
+			ChildReferences []struct {
+				Name             string `json:"name"`
+				PipelineTaskName string `json:"pipelineTaskName"`
+			} `json:"childReferences"`
+			Nodes map[string]struct {
+				DisplayName string `json:"displayName"`
+			} `json:"nodes"`
+		} `json:"status"`
+	}{}
+	if err := json.Unmarshal([]byte(manifest), workflow); err != nil {
+		return "", fmt.Errorf("could not parse the workflow manifest of the run: %w", err)
+	}
+	for name, taskRun := range workflow.Status.TaskRuns {
+		if taskRun.PipelineTaskName == task {
+			return name, nil
+		}
+	}
+	for _, child := range workflow.Status.ChildReferences {
+		if child.PipelineTaskName == task {
+			return child.Name, nil
+		}
+	}
+	for id, node := range workflow.Status.Nodes {
+		if node.DisplayName == task {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("task [%s] is not in the workflow manifest of the run", task)
+}
+
+// testRecurring creates a recurring run, then disables and deletes it.
+func testRecurring(ctx context.Context, s *suite) error {
+	pipeline, err := s.ensurePipeline(ctx)
+	if err != nil {
+		return err
+	}
+	experimentID, err := s.ensureExperiment(ctx)
+	if err != nil {
+		return err
+	}
+	job := &apiJob{}
+	err = s.client.doJSON(ctx, http.MethodPost, "/apis/v1beta1/jobs", map[string]interface{}{
+		"name":                "conformance-recurring-run",
+		"pipeline_spec":       map[string]string{"pipeline_id": pipeline.ID},
+		"resource_references": experimentReference(experimentID),
+		"max_concurrency":     1,
+		"trigger":             map[string]interface{}{"periodic_schedule": map[string]int{"interval_second": 3600}},
+		"enabled":             true,
+	}, job)
+	if err != nil {
+		return err
+	}
+	jobPath := "/apis/v1beta1/jobs/" + job.ID
+	s.cleanups = append(s.cleanups, jobPath)
+	if !job.Enabled {
+		return fmt.Errorf("recurring run [%s] was created disabled", job.ID)
+	}
+
+	if err := s.client.doJSON(ctx, http.MethodPost, jobPath+"/disable", nil, nil); err != nil {
+		return err
+	}
+	job = &apiJob{}
+	if err := s.client.doJSON(ctx, http.MethodGet, jobPath, nil, job); err != nil {
+		return err
+	}
+	if job.Enabled {
+		return fmt.Errorf("recurring run [%s] is still enabled after being disabled", job.ID)
+	}
+
+	if err := s.client.doJSON(ctx, http.MethodDelete, jobPath, nil, nil); err != nil {
+		return err
+	}
+	if err := s.client.doJSON(ctx, http.MethodGet, jobPath, nil, &apiJob{}); !isNotFound(err) {
+		return fmt.Errorf("recurring run [%s] is still readable after being deleted: %v", job.ID, err)
+	}
+	return nil
+}
+
+// testMetadata verifies the run records its timestamps, pipeline and experiment.
+func testMetadata(ctx context.Context, s *suite) error {
+	detail, err := s.ensureCompletedRun(ctx)
+	if err != nil {
+		return err
+	}
+	// The run is read again, as some backends only record the finish time after reporting the final status
+	detail, err = s.client.getRun(ctx, detail.Run.ID)
+	if err != nil {
+		return err
+	}
+	run := detail.Run
+	switch {
+	case run.CreatedAt.IsZero() || run.ScheduledAt.IsZero():
+		return fmt.Errorf("run [%s] has no created_at or scheduled_at", run.ID)
+	case run.FinishedAt.Before(run.CreatedAt):
+		return fmt.Errorf("run [%s] finished at %s, before it was created at %s", run.ID, run.FinishedAt, run.CreatedAt)
+	case run.PipelineSpec.PipelineID != s.pipeline.ID:
+		return fmt.Errorf("run [%s] references pipeline [%s] instead of [%s]", run.ID, run.PipelineSpec.PipelineID, s.pipeline.ID)
+	}
+	for _, ref := range run.ResourceReferences {
+		if ref.Key.Type == "EXPERIMENT" && ref.Key.ID == s.experimentID && ref.Relationship == "OWNER" {
+			return nil
+		}
+	}
+	return fmt.Errorf("run [%s] does not reference its experiment [%s]", run.ID, s.experimentID)
+}
+
+// testRetry retries the failed run, which must rerun in place and keep its ID. The retry pipeline fails again, as the
+// parameters of a retried run can't change.
+func testRetry(ctx context.Context, s *suite) error {
+	failed, err := s.ensureFailedRun(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.client.retryRun(ctx, failed.Run.ID); err != nil {
+		return err
+	}
+	retried, err := s.client.waitForFinish(ctx, failed.Run.ID, *runTimeout, failed.Run.FinishedAt)
+	if err != nil {
+		return err
+	}
+	if succeeded(retried.Run.Status) {
+		return fmt.Errorf("retried run [%s] succeeded, it must fail again with the same parameters", retried.Run.ID)
+	}
+	s.failedRun = retried
+	return nil
+}
+
+// testClone runs the pipeline of the failed run again with the failing parameter flipped, as cloning a run from the
+// UI does. The clone must succeed and record the modified parameter.
+func testClone(ctx context.Context, s *suite) error {
+	failed, err := s.ensureFailedRun(ctx)
+	if err != nil {
+		return err
+	}
+	source := failed.Run
+	if value, ok := parameterValue(source.PipelineSpec.Parameters, *retryParameter); !ok || value != "true" {
+		return fmt.Errorf("run [%s] does not record its parameter [%s] as true: %v", source.ID, *retryParameter, source.PipelineSpec.Parameters)
+	}
+	var parameters []runrequest.Parameter
+	for _, p := range source.PipelineSpec.Parameters {
+		if p.Name == *retryParameter {
+			p.Value = "false"
+		}
+		parameters = append(parameters, p)
+	}
+	clone, err := s.client.createRun(ctx, "conformance-clone", source.PipelineSpec.PipelineID, s.experimentID, parameters)
+	if err != nil {
+		return err
+	}
+	s.cleanups = append(s.cleanups, "/apis/v1beta1/runs/"+clone.ID)
+	if _, err := s.client.waitForRun(ctx, clone.ID, *runTimeout); err != nil {
+		return err
+	}
+	detail, err := s.client.getRun(ctx, clone.ID)
+	if err != nil {
+		return err
+	}
+	switch value, _ := parameterValue(detail.Run.PipelineSpec.Parameters, *retryParameter); {
+	case detail.Run.PipelineSpec.PipelineID != source.PipelineSpec.PipelineID:
+		return fmt.Errorf("clone [%s] references pipeline [%s] instead of [%s]", clone.ID, detail.Run.PipelineSpec.PipelineID, source.PipelineSpec.PipelineID)
+	case value != "false":
+		return fmt.Errorf("clone [%s] records its parameter [%s] as [%s] instead of false", clone.ID, *retryParameter, value)
+	}
+	return nil
+}
+
+func parameterValue(parameters []runrequest.Parameter, name string) (string, bool) {
+	for _, p := range parameters {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,189 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conformance validates that a deployment serves the KFP v1beta1 API as DSP does, so vendors and users can
+// check alternative backends. The profile runs with the test_conformance build tag, see the Conformance Profile section
+// of the README.
+package conformance
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// The cases of the conformance profile, in the order they run
+const (
+	CaseUpload     = "upload"
+	CasePagination = "pagination"
+	CaseRun        = "run"
+	CaseArtifacts  = "artifacts"
+	CaseRecurring  = "recurring"
+	CaseMetadata   = "metadata"
+	CaseRetry      = "retry"
+	CaseClone      = "clone"
+)
+
+// Cases are all the cases of the conformance profile.
+var Cases = []string{CaseUpload, CasePagination, CaseRun, CaseArtifacts, CaseRecurring, CaseMetadata, CaseRetry, CaseClone}
+
+// Result statuses of a case
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// SelectCases parses a comma separated list of cases, returning every case if the list is empty.
+func SelectCases(list string) (map[string]bool, error) {
+	selected := map[string]bool{}
+	if strings.TrimSpace(list) == "" {
+		for _, c := range Cases {
+			selected[c] = true
+		}
+		return selected, nil
+	}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		known := false
+		for _, c := range Cases {
+			known = known || c == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown conformance case [%s], expected one of %s", name, strings.Join(Cases, ","))
+		}
+		selected[name] = true
+	}
+	return selected, nil
+}
+
+// CaseResult is the outcome of a case of the profile.
+type CaseResult struct {
+	Name            string  `json:"name"`
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Message         string  `json:"message,omitempty"`
+}
+
+// Report is the outcome of a run of the conformance profile against Endpoint.
+type Report struct {
+	Endpoint  string       `json:"endpoint"`
+	StartedAt time.Time    `json:"startedAt"`
+	Cases     []CaseResult `json:"cases"`
+}
+
+// Record adds the result of a case to the report.
+func (r *Report) Record(name string, duration time.Duration, err error, skipped bool) {
+	result := CaseResult{Name: name, Status: StatusPassed, DurationSeconds: duration.Seconds()}
+	switch {
+	case skipped:
+		result.Status = StatusSkipped
+	case err != nil:
+		result.Status = StatusFailed
+	}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	r.Cases = append(r.Cases, result)
+}
+
+// Count returns the number of cases with the status.
+func (r *Report) Count(status string) int {
+	count := 0
+	for _, c := range r.Cases {
+		if c.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// JUnit returns the report as a JUnit XML test suite, as consumed by CI systems.
+func (r *Report) JUnit() ([]byte, error) {
+	suite := junitTestSuite{
+		Name:      "kfp-conformance",
+		Tests:     len(r.Cases),
+		Failures:  r.Count(StatusFailed),
+		Skipped:   r.Count(StatusSkipped),
+		Timestamp: r.StartedAt.UTC().Format(time.RFC3339),
+	}
+	total := 0.0
+	for _, c := range r.Cases {
+		testCase := junitTestCase{Name: c.Name, ClassName: "conformance", Time: fmt.Sprintf("%.3f", c.DurationSeconds)}
+		switch c.Status {
+		case StatusFailed:
+			testCase.Failure = &junitMessage{Message: c.Message}
+		case StatusSkipped:
+			testCase.Skipped = &junitMessage{Message: c.Message}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+		total += c.DurationSeconds
+	}
+	suite.Time = fmt.Sprintf("%.3f", total)
+	out, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// Write writes the report as JSON and JUnit XML to the paths that are set.
+func (r *Report) Write(jsonPath, junitPath string) error {
+	if jsonPath != "" {
+		out, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(jsonPath, out, 0644); err != nil {
+			return err
+		}
+	}
+	if junitPath != "" {
+		out, err := r.JUnit()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(junitPath, out, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectCases(t *testing.T) {
+	selected, err := SelectCases("")
+	assert.Nil(t, err)
+	assert.Len(t, selected, len(Cases))
+
+	selected, err = SelectCases("upload, recurring")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{CaseUpload: true, CaseRecurring: true}, selected)
+
+	_, err = SelectCases("upload,lineage")
+	assert.ErrorContains(t, err, "unknown conformance case [lineage]")
+}
+
+func TestReportWrite(t *testing.T) {
+	report := &Report{Endpoint: "https://ds-pipeline-sample.example.com", StartedAt: time.Unix(0, 0)}
+	report.Record(CaseUpload, 2*time.Second, nil, false)
+	report.Record(CaseRun, time.Minute, errors.New("run [abc] finished with status [Failed]"), false)
+	report.Record(CaseRecurring, 0, errors.New("case not selected"), true)
+	assert.Equal(t, 1, report.Count(StatusPassed))
+	assert.Equal(t, 1, report.Count(StatusFailed))
+	assert.Equal(t, 1, report.Count(StatusSkipped))
+
+	dir := t.TempDir()
+	jsonPath, junitPath := filepath.Join(dir, "report.json"), filepath.Join(dir, "junit.xml")
+	assert.Nil(t, report.Write(jsonPath, junitPath))
+
+	data, err := os.ReadFile(jsonPath)
+	assert.Nil(t, err)
+	written := &Report{}
+	assert.Nil(t, json.Unmarshal(data, written))
+	assert.Equal(t, report.Cases, written.Cases)
+
+	data, err = os.ReadFile(junitPath)
+	assert.Nil(t, err)
+	junit := string(data)
+	assert.True(t, strings.HasPrefix(junit, "<?xml"))
+	assert.Contains(t, junit, `<testsuite name="kfp-conformance" tests="3" failures="1" skipped="1" time="62.000"`)
+	assert.Contains(t, junit, `<failure message="run [abc] finished with status [Failed]"></failure>`)
+	assert.Contains(t, junit, `<skipped message="case not selected"></skipped>`)
+}
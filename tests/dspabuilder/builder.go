@@ -0,0 +1,154 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dspabuilder builds DSPAs for integration tests against DSPO, e.g. in downstream repositories, without
+// copying sample YAML around:
+//
+//	dspa := dspabuilder.New("sample", "my-project").
+//		WithExternalDB("mysql.example.com", "3306", "mlpipeline", "mlpipeline", dbPassword).
+//		WithExternalStorage("s3.amazonaws.com", "my-bucket", s3Credentials).
+//		WithTLS(&dspav1alpha1.CABundle{ConfigMapName: "custom-ca", ConfigMapKey: "ca.crt"}).
+//		Build()
+//
+// New starts from the minimal dspa_simple.yaml sample: MariaDB and Minio deployed in the namespace, along with the
+// components the CRD deploys by default. Fields the CRD defaults are set explicitly, so the DSPA can also be used with
+// clients that don't apply the CRD defaults, such as the controller-runtime fake client.
+package dspabuilder
+
+import (
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultMinioImage is the Minio image of the DSPA samples
+	DefaultMinioImage = "quay.io/opendatahub/minio:RELEASE.2019-08-14T20-37-41Z-license-compliance"
+	// DefaultUIImage is the UI image of the DSPA samples
+	DefaultUIImage = "quay.io/opendatahub/odh-ml-pipelines-frontend-container:beta-ui"
+)
+
+// Builder builds a DSPA. Its methods modify and return the Builder, so calls can be chained.
+type Builder struct {
+	dspa *dspav1alpha1.DataSciencePipelinesApplication
+	// tls is applied to the external object storage on Build, so it doesn't depend on the order of the calls
+	tls *bool
+}
+
+// New returns a Builder of a DSPA deploying MariaDB, Minio, the API Server, the Persistence Agent and the Scheduled
+// Workflow controller.
+func New(name, namespace string) *Builder {
+	return &Builder{dspa: &dspav1alpha1.DataSciencePipelinesApplication{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: dspav1alpha1.GroupVersion.String(),
+			Kind:       "DataSciencePipelinesApplication",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: dspav1alpha1.DSPASpec{
+			APIServer:         &dspav1alpha1.APIServer{Deploy: true, EnableRoute: true},
+			PersistenceAgent:  &dspav1alpha1.PersistenceAgent{Deploy: true},
+			ScheduledWorkflow: &dspav1alpha1.ScheduledWorkflow{Deploy: true},
+			Database:          &dspav1alpha1.Database{MariaDB: &dspav1alpha1.MariaDB{Deploy: true}},
+			ObjectStorage:     &dspav1alpha1.ObjectStorage{Minio: &dspav1alpha1.Minio{Deploy: true, Image: DefaultMinioImage}},
+		},
+	}}
+}
+
+// WithExternalDB replaces MariaDB with an external MySQL compatible database, whose password is read from the Secret.
+func (b *Builder) WithExternalDB(host, port, username, dbName string, passwordSecret dspav1alpha1.SecretKeyValue) *Builder {
+	b.dspa.Spec.Database = &dspav1alpha1.Database{ExternalDB: &dspav1alpha1.ExternalDB{
+		Host:           host,
+		Port:           port,
+		Username:       username,
+		DBName:         dbName,
+		PasswordSecret: &passwordSecret,
+	}}
+	return b
+}
+
+// WithExternalStorage replaces Minio with an external S3 compatible object storage, served over TLS unless WithoutTLS
+// is called.
+func (b *Builder) WithExternalStorage(host, bucket string, credentials dspav1alpha1.S3CredentialSecret) *Builder {
+	b.dspa.Spec.ObjectStorage = &dspav1alpha1.ObjectStorage{ExternalStorage: &dspav1alpha1.ExternalStorage{
+		Host:               host,
+		Bucket:             bucket,
+		Scheme:             "https",
+		S3CredentialSecret: &credentials,
+	}}
+	return b
+}
+
+// WithTLS connects to the external object storage over TLS. If the database or object storage certificates are not
+// trusted by the cluster, caBundle references the ConfigMap key holding their CA, which is mounted into the API Server.
+func (b *Builder) WithTLS(caBundle *dspav1alpha1.CABundle) *Builder {
+	secure := true
+	b.tls = &secure
+	b.dspa.Spec.APIServer.CABundle = caBundle
+	return b
+}
+
+// WithoutTLS connects to the external object storage without TLS, e.g. to a Minio deployed by the test itself.
+func (b *Builder) WithoutTLS() *Builder {
+	secure := false
+	b.tls = &secure
+	b.dspa.Spec.APIServer.CABundle = nil
+	return b
+}
+
+// WithUI deploys the ML Pipelines UI, with DefaultUIImage if image is empty.
+func (b *Builder) WithUI(image string) *Builder {
+	if image == "" {
+		image = DefaultUIImage
+	}
+	b.dspa.Spec.MlPipelineUI = &dspav1alpha1.MlPipelineUI{Deploy: true, Image: image}
+	return b
+}
+
+// WithMLMD deploys ML Metadata.
+func (b *Builder) WithMLMD() *Builder {
+	b.dspa.Spec.MLMD = &dspav1alpha1.MLMD{Deploy: true}
+	return b
+}
+
+// WithoutRoute doesn't expose the API Server outside the cluster, e.g. on clusters without Routes.
+func (b *Builder) WithoutRoute() *Builder {
+	b.dspa.Spec.APIServer.EnableRoute = false
+	return b
+}
+
+// WithDevMode deploys an ephemeral DSPA starting quickly, e.g. on kind, see spec.devMode.
+func (b *Builder) WithDevMode() *Builder {
+	b.dspa.Spec.DevMode = true
+	return b
+}
+
+// WithSpec applies changes the Builder has no method for to the spec.
+func (b *Builder) WithSpec(mutate func(spec *dspav1alpha1.DSPASpec)) *Builder {
+	mutate(&b.dspa.Spec)
+	return b
+}
+
+// Build returns the DSPA. Every call returns a new copy, so a Builder can build several variants of a DSPA.
+func (b *Builder) Build() *dspav1alpha1.DataSciencePipelinesApplication {
+	dspa := b.dspa.DeepCopy()
+	if storage := dspa.Spec.ObjectStorage.ExternalStorage; storage != nil && b.tls != nil {
+		secure := *b.tls
+		storage.Secure = &secure
+		storage.Scheme = "http"
+		if secure {
+			storage.Scheme = "https"
+		}
+	}
+	return dspa
+}
@@ -0,0 +1,87 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dspabuilder
+
+import (
+	"testing"
+
+	dspav1alpha1 "github.com/opendatahub-io/data-science-pipelines-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaults(t *testing.T) {
+	dspa := New("testdsp", "testnamespace").Build()
+
+	assert.Equal(t, "testdsp", dspa.Name)
+	assert.Equal(t, "testnamespace", dspa.Namespace)
+	assert.Equal(t, "DataSciencePipelinesApplication", dspa.Kind)
+	assert.True(t, dspa.Spec.APIServer.Deploy)
+	assert.True(t, dspa.Spec.APIServer.EnableRoute)
+	assert.True(t, dspa.Spec.PersistenceAgent.Deploy)
+	assert.True(t, dspa.Spec.ScheduledWorkflow.Deploy)
+	assert.True(t, dspa.Spec.Database.MariaDB.Deploy)
+	assert.Equal(t, DefaultMinioImage, dspa.Spec.ObjectStorage.Minio.Image)
+	assert.Nil(t, dspa.Spec.MlPipelineUI)
+	assert.Nil(t, dspa.Spec.MLMD)
+}
+
+func TestExternalDBAndStorage(t *testing.T) {
+	dspa := New("testdsp", "testnamespace").
+		WithExternalDB("mysql.example.com", "3306", "mlpipeline", "mlpipeline",
+			dspav1alpha1.SecretKeyValue{Name: "db-secret", Key: "password"}).
+		WithExternalStorage("s3.amazonaws.com", "testbucket", dspav1alpha1.S3CredentialSecret{
+			SecretName: "s3-secret", AccessKey: "AWS_ACCESS_KEY_ID", SecretKey: "AWS_SECRET_ACCESS_KEY",
+		}).
+		Build()
+
+	assert.Nil(t, dspa.Spec.Database.MariaDB)
+	assert.Equal(t, "mysql.example.com", dspa.Spec.Database.ExternalDB.Host)
+	assert.Equal(t, "db-secret", dspa.Spec.Database.ExternalDB.PasswordSecret.Name)
+	assert.Nil(t, dspa.Spec.ObjectStorage.Minio)
+	assert.Equal(t, "testbucket", dspa.Spec.ObjectStorage.ExternalStorage.Bucket)
+	assert.Equal(t, "https", dspa.Spec.ObjectStorage.ExternalStorage.Scheme)
+	assert.Equal(t, "s3-secret", dspa.Spec.ObjectStorage.ExternalStorage.S3CredentialSecret.SecretName)
+}
+
+func TestTLS(t *testing.T) {
+	credentials := dspav1alpha1.S3CredentialSecret{SecretName: "s3-secret"}
+	caBundle := &dspav1alpha1.CABundle{ConfigMapName: "custom-ca", ConfigMapKey: "ca.crt"}
+
+	// TLS set before the storage still applies to it
+	dspa := New("testdsp", "testnamespace").WithTLS(caBundle).WithExternalStorage("minio.test", "testbucket", credentials).Build()
+	assert.Equal(t, caBundle, dspa.Spec.APIServer.CABundle)
+	assert.True(t, *dspa.Spec.ObjectStorage.ExternalStorage.Secure)
+	assert.Equal(t, "https", dspa.Spec.ObjectStorage.ExternalStorage.Scheme)
+
+	dspa = New("testdsp", "testnamespace").WithExternalStorage("minio.test", "testbucket", credentials).WithTLS(caBundle).WithoutTLS().Build()
+	assert.Nil(t, dspa.Spec.APIServer.CABundle)
+	assert.False(t, *dspa.Spec.ObjectStorage.ExternalStorage.Secure)
+	assert.Equal(t, "http", dspa.Spec.ObjectStorage.ExternalStorage.Scheme)
+}
+
+func TestBuildReturnsCopies(t *testing.T) {
+	builder := New("testdsp", "testnamespace").WithUI("")
+	first := builder.Build()
+	first.Spec.MlPipelineUI.Image = "changed"
+
+	second := builder.WithMLMD().WithDevMode().Build()
+	assert.Equal(t, DefaultUIImage, second.Spec.MlPipelineUI.Image)
+	assert.True(t, second.Spec.MLMD.Deploy)
+	assert.True(t, second.Spec.DevMode)
+	assert.Nil(t, first.Spec.MLMD)
+}
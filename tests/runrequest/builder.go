@@ -0,0 +1,289 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package runrequest builds the bodies of KFP v1beta1 run creation requests, POSTed to /apis/v1beta1/runs, for tests
+// against the DSP API Server, e.g. in downstream repositories, without hand crafting JSON:
+//
+//	body, err := runrequest.New("training").
+//		WithPipeline(pipelineID).
+//		WithExperiment(experimentID).
+//		WithParameter("epochs", 10).
+//		WithParameter("layers", []int{64, 32}).
+//		WithParameter("learning_rate", optionalRate).
+//		JSON()
+//
+// Parameters of the v1beta1 API are strings. Values are converted the way the KFP SDK serializes them: structs, maps
+// and slices as JSON, other values with their default format, and nil values are left out, so optional parameters keep
+// the default of the pipeline.
+package runrequest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Relationships and types of the resource references of a run
+const (
+	RelationshipOwner   = "OWNER"
+	RelationshipCreator = "CREATOR"
+	TypeExperiment      = "EXPERIMENT"
+	TypePipelineVersion = "PIPELINE_VERSION"
+	TypeNamespace       = "NAMESPACE"
+)
+
+// Parameter is an input parameter of a run.
+type Parameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ResourceKey identifies a resource of the API.
+type ResourceKey struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// ResourceReference relates the run to another resource, e.g. the experiment owning it.
+type ResourceReference struct {
+	Key          ResourceKey `json:"key"`
+	Relationship string      `json:"relationship"`
+}
+
+// RuntimeConfig holds the runtime parameters and pipeline root of pipelines compiled in v2 compatible mode.
+type RuntimeConfig struct {
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`
+	PipelineRoot string                 `json:"pipeline_root,omitempty"`
+}
+
+// PipelineSpec is the pipeline a run executes, with its parameters.
+type PipelineSpec struct {
+	PipelineID       string         `json:"pipeline_id,omitempty"`
+	WorkflowManifest string         `json:"workflow_manifest,omitempty"`
+	Parameters       []Parameter    `json:"parameters,omitempty"`
+	RuntimeConfig    *RuntimeConfig `json:"runtime_config,omitempty"`
+}
+
+// RunRequest is the body of a run creation request.
+type RunRequest struct {
+	Name               string              `json:"name"`
+	Description        string              `json:"description,omitempty"`
+	PipelineSpec       PipelineSpec        `json:"pipeline_spec"`
+	ResourceReferences []ResourceReference `json:"resource_references,omitempty"`
+	ServiceAccount     string              `json:"service_account,omitempty"`
+}
+
+// Builder builds a RunRequest. Its methods modify and return the Builder, so calls can be chained, and errors are
+// returned by Build.
+type Builder struct {
+	request RunRequest
+	err     error
+}
+
+// New returns a Builder of a run named name.
+func New(name string) *Builder {
+	return &Builder{request: RunRequest{Name: name}}
+}
+
+// WithDescription sets the description of the run.
+func (b *Builder) WithDescription(description string) *Builder {
+	b.request.Description = description
+	return b
+}
+
+// WithPipeline runs the default version of the uploaded pipeline.
+func (b *Builder) WithPipeline(pipelineID string) *Builder {
+	b.request.PipelineSpec.PipelineID = pipelineID
+	return b
+}
+
+// WithPipelineVersion runs a version of an uploaded pipeline.
+func (b *Builder) WithPipelineVersion(versionID string) *Builder {
+	return b.withReference(TypePipelineVersion, versionID, RelationshipCreator)
+}
+
+// WithWorkflowManifest runs a compiled pipeline that wasn't uploaded.
+func (b *Builder) WithWorkflowManifest(manifest string) *Builder {
+	b.request.PipelineSpec.WorkflowManifest = manifest
+	return b
+}
+
+// WithExperiment creates the run in the experiment.
+func (b *Builder) WithExperiment(experimentID string) *Builder {
+	return b.withReference(TypeExperiment, experimentID, RelationshipOwner)
+}
+
+// WithNamespace creates the run in the namespace, for API Servers serving several namespaces.
+func (b *Builder) WithNamespace(namespace string) *Builder {
+	return b.withReference(TypeNamespace, namespace, RelationshipOwner)
+}
+
+func (b *Builder) withReference(resourceType, id, relationship string) *Builder {
+	ref := ResourceReference{Key: ResourceKey{Type: resourceType, ID: id}, Relationship: relationship}
+	for i, existing := range b.request.ResourceReferences {
+		if existing.Key.Type == resourceType {
+			b.request.ResourceReferences[i] = ref
+			return b
+		}
+	}
+	b.request.ResourceReferences = append(b.request.ResourceReferences, ref)
+	return b
+}
+
+// WithServiceAccount runs the pipeline with the service account instead of the default pipeline runner.
+func (b *Builder) WithServiceAccount(serviceAccount string) *Builder {
+	b.request.ServiceAccount = serviceAccount
+	return b
+}
+
+// WithParameter sets an input parameter of the run, replacing its earlier value. Nil values, e.g. unset optional
+// parameters, are left out.
+func (b *Builder) WithParameter(name string, value interface{}) *Builder {
+	formatted, ok, err := formatParameter(value)
+	if err != nil {
+		b.setErr(fmt.Errorf("parameter [%s]: %w", name, err))
+		return b
+	}
+	parameters := b.request.PipelineSpec.Parameters[:0:0]
+	for _, p := range b.request.PipelineSpec.Parameters {
+		if p.Name != name {
+			parameters = append(parameters, p)
+		}
+	}
+	if ok {
+		parameters = append(parameters, Parameter{Name: name, Value: formatted})
+	}
+	b.request.PipelineSpec.Parameters = parameters
+	return b
+}
+
+// WithRuntimeParameter sets a runtime parameter of a pipeline compiled in v2 compatible mode, which keeps its type.
+// Nil values are left out.
+func (b *Builder) WithRuntimeParameter(name string, value interface{}) *Builder {
+	value, ok := dereference(value)
+	if !ok {
+		if config := b.request.PipelineSpec.RuntimeConfig; config != nil {
+			delete(config.Parameters, name)
+		}
+		return b
+	}
+	if _, err := json.Marshal(value); err != nil {
+		b.setErr(fmt.Errorf("runtime parameter [%s]: %w", name, err))
+		return b
+	}
+	config := b.runtimeConfig()
+	if config.Parameters == nil {
+		config.Parameters = map[string]interface{}{}
+	}
+	config.Parameters[name] = value
+	return b
+}
+
+// WithPipelineRoot sets the root path of the artifacts of a pipeline compiled in v2 compatible mode.
+func (b *Builder) WithPipelineRoot(root string) *Builder {
+	b.runtimeConfig().PipelineRoot = root
+	return b
+}
+
+func (b *Builder) runtimeConfig() *RuntimeConfig {
+	if b.request.PipelineSpec.RuntimeConfig == nil {
+		b.request.PipelineSpec.RuntimeConfig = &RuntimeConfig{}
+	}
+	return b.request.PipelineSpec.RuntimeConfig
+}
+
+func (b *Builder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Build returns the request, or the first error of the calls to the Builder. The run must have a name and a pipeline:
+// an uploaded pipeline, one of its versions, or a workflow manifest.
+func (b *Builder) Build() (*RunRequest, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.request.Name == "" {
+		return nil, errors.New("the run has no name")
+	}
+	spec := b.request.PipelineSpec
+	hasVersion := false
+	for _, ref := range b.request.ResourceReferences {
+		hasVersion = hasVersion || ref.Key.Type == TypePipelineVersion
+	}
+	if spec.PipelineID == "" && spec.WorkflowManifest == "" && !hasVersion {
+		return nil, fmt.Errorf("run [%s] has no pipeline, pipeline version or workflow manifest", b.request.Name)
+	}
+	request := b.request
+	request.PipelineSpec.Parameters = append([]Parameter(nil), spec.Parameters...)
+	request.ResourceReferences = append([]ResourceReference(nil), b.request.ResourceReferences...)
+	if spec.RuntimeConfig != nil {
+		config := *spec.RuntimeConfig
+		if config.Parameters != nil {
+			config.Parameters = make(map[string]interface{}, len(spec.RuntimeConfig.Parameters))
+			for name, value := range spec.RuntimeConfig.Parameters {
+				config.Parameters[name] = value
+			}
+		}
+		request.PipelineSpec.RuntimeConfig = &config
+	}
+	return &request, nil
+}
+
+// JSON returns the request as the JSON body of a run creation request.
+func (b *Builder) JSON() ([]byte, error) {
+	request, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(request)
+}
+
+// dereference follows the pointers of the value, returning false for nil values.
+func dereference(value interface{}) (interface{}, bool) {
+	v := reflect.ValueOf(value)
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// formatParameter converts the value to the string of a v1beta1 parameter, returning false for nil values.
+func formatParameter(value interface{}) (string, bool, error) {
+	value, ok := dereference(value)
+	if !ok {
+		return "", false, nil
+	}
+	if s, isString := value.(string); isString {
+		return s, true, nil
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", false, err
+		}
+		return string(data), true, nil
+	}
+	return fmt.Sprint(value), true, nil
+}
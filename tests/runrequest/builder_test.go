@@ -0,0 +1,98 @@
+//go:build test_all || test_unit
+
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runrequest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type trainingConfig struct {
+	Optimizer string  `json:"optimizer"`
+	Rate      float64 `json:"rate"`
+}
+
+func TestBuildParameters(t *testing.T) {
+	var unsetSeed *int
+	epochs := 10
+	request, err := New("training").
+		WithPipeline("pipeline-id").
+		WithExperiment("experiment-id").
+		WithParameter("epochs", &epochs).
+		WithParameter("layers", []int{64, 32}).
+		WithParameter("config", trainingConfig{Optimizer: "adam", Rate: 0.01}).
+		WithParameter("labels", map[string]string{"team": "ml"}).
+		WithParameter("shuffle", true).
+		WithParameter("seed", unsetSeed).
+		WithParameter("dataset", "v1").
+		WithParameter("dataset", "v2").
+		Build()
+	assert.Nil(t, err)
+
+	assert.Equal(t, "pipeline-id", request.PipelineSpec.PipelineID)
+	assert.Equal(t, []ResourceReference{
+		{Key: ResourceKey{Type: TypeExperiment, ID: "experiment-id"}, Relationship: RelationshipOwner},
+	}, request.ResourceReferences)
+	// Ensure complex values are JSON, unset optional parameters are left out, and later values replace earlier ones
+	assert.Equal(t, []Parameter{
+		{Name: "epochs", Value: "10"},
+		{Name: "layers", Value: "[64,32]"},
+		{Name: "config", Value: `{"optimizer":"adam","rate":0.01}`},
+		{Name: "labels", Value: `{"team":"ml"}`},
+		{Name: "shuffle", Value: "true"},
+		{Name: "dataset", Value: "v2"},
+	}, request.PipelineSpec.Parameters)
+}
+
+func TestJSON(t *testing.T) {
+	body, err := New("training").
+		WithPipelineVersion("version-id").
+		WithNamespace("my-project").
+		WithServiceAccount("pipeline-runner-custom").
+		WithRuntimeParameter("epochs", 10).
+		WithRuntimeParameter("layers", []int{64, 32}).
+		WithPipelineRoot("s3://my-bucket/root").
+		JSON()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"name": "training",
+		"pipeline_spec": {
+			"runtime_config": {
+				"parameters": {"epochs": 10, "layers": [64, 32]},
+				"pipeline_root": "s3://my-bucket/root"
+			}
+		},
+		"resource_references": [
+			{"key": {"type": "PIPELINE_VERSION", "id": "version-id"}, "relationship": "CREATOR"},
+			{"key": {"type": "NAMESPACE", "id": "my-project"}, "relationship": "OWNER"}
+		],
+		"service_account": "pipeline-runner-custom"
+	}`, string(body))
+}
+
+func TestBuildErrors(t *testing.T) {
+	_, err := New("").WithPipeline("pipeline-id").Build()
+	assert.EqualError(t, err, "the run has no name")
+
+	_, err = New("training").WithExperiment("experiment-id").Build()
+	assert.EqualError(t, err, "run [training] has no pipeline, pipeline version or workflow manifest")
+
+	_, err = New("training").WithPipeline("pipeline-id").WithParameter("callback", map[string]interface{}{"f": func() {}}).Build()
+	assert.ErrorContains(t, err, "parameter [callback]")
+}